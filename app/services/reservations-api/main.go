@@ -3,6 +3,10 @@ package main
 import (
 	"os"
 
+	// Business time zones are loaded by IANA name. Embed the zone database
+	// so the binary doesn't depend on the image providing one.
+	_ "time/tzdata"
+
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/cmd"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/cmd/all"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/cmd/tasks"
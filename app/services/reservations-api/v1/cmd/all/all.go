@@ -5,8 +5,12 @@ import (
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/appointmentgrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/authgrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/businessgrp"
+	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/calendargrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/checkgrp"
+	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/resourcegrp"
+	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/servicegrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/usergrp"
+	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/waitlistgrp"
 	"github.com/ameghdadian/service/business/web/v1/mux"
 	"github.com/ameghdadian/service/foundation/web"
 )
@@ -46,6 +50,20 @@ func (add) Add(app *web.App, cfg mux.APIMuxConfig) {
 		DB:    cfg.DB,
 	})
 
+	servicegrp.Routes(app, servicegrp.Config{
+		Build: cfg.Build,
+		Log:   cfg.Log,
+		Auth:  cfg.Auth,
+		DB:    cfg.DB,
+	})
+
+	resourcegrp.Routes(app, resourcegrp.Config{
+		Build: cfg.Build,
+		Log:   cfg.Log,
+		Auth:  cfg.Auth,
+		DB:    cfg.DB,
+	})
+
 	appointmentgrp.Routes(app, appointmentgrp.Config{
 		Build:         cfg.Build,
 		Log:           cfg.Log,
@@ -56,9 +74,28 @@ func (add) Add(app *web.App, cfg mux.APIMuxConfig) {
 	})
 
 	agendagrp.Routes(app, agendagrp.Config{
-		Build: cfg.Build,
-		Log:   cfg.Log,
-		DB:    cfg.DB,
-		Auth:  cfg.Auth,
+		Build:         cfg.Build,
+		Log:           cfg.Log,
+		DB:            cfg.DB,
+		Auth:          cfg.Auth,
+		TaskClient:    cfg.TaskClient,
+		TaskInspector: cfg.TaskInspector,
+	})
+
+	waitlistgrp.Routes(app, waitlistgrp.Config{
+		Build:         cfg.Build,
+		Log:           cfg.Log,
+		DB:            cfg.DB,
+		Auth:          cfg.Auth,
+		TaskClient:    cfg.TaskClient,
+		TaskInspector: cfg.TaskInspector,
+	})
+
+	calendargrp.Routes(app, calendargrp.Config{
+		Build:         cfg.Build,
+		Log:           cfg.Log,
+		DB:            cfg.DB,
+		TaskClient:    cfg.TaskClient,
+		TaskInspector: cfg.TaskInspector,
 	})
 }
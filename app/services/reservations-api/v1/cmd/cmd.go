@@ -6,6 +6,7 @@ import (
 	"expvar"
 	"fmt"
 	"net/http"
+	"net/mail"
 	"os"
 	"os/signal"
 	"runtime"
@@ -16,9 +17,11 @@ import (
 	"github.com/ameghdadian/service/business/web/debug"
 	"github.com/ameghdadian/service/business/web/v1/auth"
 	"github.com/ameghdadian/service/business/web/v1/mux"
+	"github.com/ameghdadian/service/foundation/email"
 	"github.com/ameghdadian/service/foundation/keystore"
 	"github.com/ameghdadian/service/foundation/logger"
 	"github.com/ameghdadian/service/foundation/otel"
+	"github.com/ameghdadian/service/foundation/sms"
 	"github.com/ardanlabs/conf/v3"
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
@@ -320,6 +323,28 @@ func initWorkers(ctx context.Context, log *logger.Logger, build string, taskRout
 		Redis struct {
 			Addr string `conf:"default:redis.reservations-system.svc.cluster.local:6379"`
 		}
+		SMS struct {
+			Provider  string `conf:"default:stdout,help:one of stdout|file|http"`
+			File      string `conf:"default:sms.log"`
+			BaseURL   string `conf:"default:https://api.twilio.com"`
+			AccountID string
+			AuthToken string `conf:"mask"`
+			From      string
+		}
+		Email struct {
+			Addr     string `conf:"default:localhost:1025"`
+			Username string
+			Password string `conf:"mask"`
+			From     string `conf:"default:Reservations <no-reply@reservations.local>"`
+		}
+		Auth struct {
+			KeysFolder string `conf:"default:zarf/keys/"`
+			ActiveKID  string `conf:"default:963df661-d92e-4991-b519-77d838a21705"`
+			Issuer     string `conf:"default:service project"`
+		}
+		Unsubscribe struct {
+			BaseURL string `conf:"default:http://localhost:3000"`
+		}
 	}{
 		Version: conf.Version{
 			Build: build,
@@ -372,11 +397,89 @@ func initWorkers(ctx context.Context, log *logger.Logger, build string, taskRout
 		log.Info(ctx, "shutdown", "status", "stopping database support", "host", cfg.DB.Host)
 	}()
 
+	// ------------------------------------------------------------------------------
+	// Initialize SMS support
+
+	log.Info(ctx, "startup", "status", "initializing sms support", "provider", cfg.SMS.Provider)
+
+	var sender sms.Sender
+	switch cfg.SMS.Provider {
+	case "stdout":
+		sender = sms.NewWriterSender(os.Stdout)
+	case "file":
+		f, err := os.OpenFile(cfg.SMS.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("opening sms file: %w", err)
+		}
+		defer f.Close()
+		sender = sms.NewWriterSender(f)
+	case "http":
+		sender = sms.NewHTTPSender(sms.HTTPConfig{
+			BaseURL:   cfg.SMS.BaseURL,
+			AccountID: cfg.SMS.AccountID,
+			AuthToken: cfg.SMS.AuthToken,
+			From:      cfg.SMS.From,
+		})
+	default:
+		return fmt.Errorf("unknown sms provider %q", cfg.SMS.Provider)
+	}
+
+	// ------------------------------------------------------------------------------
+	// Initialize email support
+
+	log.Info(ctx, "startup", "status", "initializing email support", "addr", cfg.Email.Addr)
+
+	from, err := mail.ParseAddress(cfg.Email.From)
+	if err != nil {
+		return fmt.Errorf("parsing email sender address: %w", err)
+	}
+
+	mailer, err := email.NewSMTPSender(email.SMTPConfig{
+		Addr:     cfg.Email.Addr,
+		Username: cfg.Email.Username,
+		Password: cfg.Email.Password,
+		From:     *from,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing email sender: %w", err)
+	}
+
+	// ------------------------------------------------------------------------------
+	// Initialize unsubscribe support, signing the links in emails
+
+	log.Info(ctx, "startup", "status", "initializing unsubscribe support", "baseURL", cfg.Unsubscribe.BaseURL)
+
+	ks, err := keystore.NewFS(os.DirFS(cfg.Auth.KeysFolder))
+	if err != nil {
+		return fmt.Errorf("reading keys: %w", err)
+	}
+
+	ath, err := auth.New(auth.Config{
+		Log:       log,
+		KeyLookup: ks,
+		Issuer:    cfg.Auth.Issuer,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing auth: %w", err)
+	}
+
+	unsubscriber := auth.NewUnsubscriber(ath, cfg.Auth.ActiveKID, cfg.Unsubscribe.BaseURL)
+
+	// ------------------------------------------------------------------------------
+	// Initialize async task scheduler support, for tasks scheduling further tasks
+
+	opt := asynq.RedisClientOpt{Addr: cfg.Redis.Addr}
+	taskClient := asynq.NewClient(opt)
+	defer taskClient.Close()
+
+	taskInspector := asynq.NewInspector(opt)
+	defer taskInspector.Close()
+
 	// ------------------------------------------------------------------------------
 	// Initialize task worker server
 
 	srv := asynq.NewServer(
-		asynq.RedisClientOpt{Addr: cfg.Redis.Addr},
+		opt,
 		asynq.Config{
 			BaseContext:     func() context.Context { return ctx },
 			Concurrency:     cfg.Worker.NumOfWorkers,
@@ -393,9 +496,14 @@ func initWorkers(ctx context.Context, log *logger.Logger, build string, taskRout
 
 	asynqMux := asynq.NewServeMux()
 	cfgMux := mux.TaskMuxConfig{
-		DB:  db,
-		Log: log,
-		Mux: asynqMux,
+		DB:            db,
+		Log:           log,
+		Mux:           asynqMux,
+		TaskClient:    taskClient,
+		TaskInspector: taskInspector,
+		SMS:           sender,
+		Email:         mailer,
+		Unsubscriber:  unsubscriber,
 	}
 	mux.TaskMux(cfgMux, taskRouter)
 
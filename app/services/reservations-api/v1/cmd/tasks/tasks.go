@@ -2,6 +2,7 @@ package tasks
 
 import (
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/appointmentgrp"
+	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/waitlistgrp"
 	"github.com/ameghdadian/service/business/web/v1/mux"
 )
 
@@ -13,8 +14,24 @@ type add struct{}
 
 func (add) Add(cfg mux.TaskMuxConfig) {
 	appointmentgrp.RegisterTaskHandlers(appointmentgrp.TaskConfig{
-		DB:  cfg.DB,
-		Log: cfg.Log,
-		Mux: cfg.Mux,
+		DB:            cfg.DB,
+		Log:           cfg.Log,
+		Mux:           cfg.Mux,
+		TaskClient:    cfg.TaskClient,
+		TaskInspector: cfg.TaskInspector,
+		SMS:           cfg.SMS,
+		Email:         cfg.Email,
+		Unsubscriber:  cfg.Unsubscriber,
+	})
+
+	waitlistgrp.RegisterTaskHandlers(waitlistgrp.TaskConfig{
+		DB:            cfg.DB,
+		Log:           cfg.Log,
+		Mux:           cfg.Mux,
+		TaskClient:    cfg.TaskClient,
+		TaskInspector: cfg.TaskInspector,
+		SMS:           cfg.SMS,
+		Email:         cfg.Email,
+		Unsubscriber:  cfg.Unsubscriber,
 	})
 }
@@ -6,7 +6,10 @@ import (
 	"net/http"
 
 	"github.com/ameghdadian/service/business/core/agenda"
+	"github.com/ameghdadian/service/business/core/appointment"
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/ameghdadian/service/business/data/order"
 	"github.com/ameghdadian/service/business/data/page"
 	"github.com/ameghdadian/service/business/data/transaction"
@@ -19,18 +22,21 @@ import (
 )
 
 var (
-	ErrInvalidID = errors.New("ID is not in its proper format")
+	ErrInvalidID       = errors.New("ID is not in its proper format")
+	ErrServiceMismatch = errors.New("service is not offered by the business")
 )
 
 type handlers struct {
 	agdCore *agenda.Core
 	bsnCore *business.Core
+	svcCore *service.Core
 }
 
-func newApp(agdCore *agenda.Core, bsnCore *business.Core) *handlers {
+func newApp(agdCore *agenda.Core, bsnCore *business.Core, svcCore *service.Core) *handlers {
 	return &handlers{
 		agdCore: agdCore,
 		bsnCore: bsnCore,
+		svcCore: svcCore,
 	}
 }
 
@@ -46,9 +52,15 @@ func (h *handlers) executeUnderTransaction(ctx context.Context) (*handlers, erro
 			return nil, err
 		}
 
+		svcCore, err := h.svcCore.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
 		return &handlers{
 			agdCore: agdCore,
 			bsnCore: bsnCore,
+			svcCore: svcCore,
 		}, nil
 	}
 
@@ -88,6 +100,14 @@ func (h *handlers) createGeneralAgenda(ctx context.Context, r *http.Request) web
 
 	gAgd, err := h.agdCore.CreateGeneralAgenda(ctx, nAgd)
 	if err != nil {
+		switch {
+		case errors.Is(err, agenda.ErrInvalidHours), errors.Is(err, agenda.ErrResourceMismatch), errors.Is(err, agenda.ErrInvalidRange):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, agenda.ErrGeneralAgendaExists):
+			return errs.New(errs.Aborted, err)
+		case errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		}
 		return errs.Newf(errs.Internal, "create general agenda: app[%+v]: %s", app, err)
 	}
 
@@ -120,14 +140,68 @@ func (h *handlers) updateGeneralAgenda(ctx context.Context, r *http.Request) web
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	agd, err = h.agdCore.UpdateGenralAgenda(ctx, agd, uAgd)
+	qp := parseAffectedQueryParams(r)
+
+	action, err := agenda.ParseAction(qp.Action)
 	if err != nil {
+		return errs.NewFieldErrors("affected", err)
+	}
+
+	// Appointments are looked at before the change, as those fitting it no longer do.
+	var affected []appointment.Appointment
+	if action != agenda.ActionKeep {
+		affected, err = h.agdCore.AffectedByGeneralAgendaUpdate(ctx, agd, uAgd)
+	}
+	if err == nil {
+		agd, err = h.agdCore.UpdateGenralAgenda(ctx, agd, uAgd)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, agenda.ErrInvalidHours), errors.Is(err, agenda.ErrInvalidRange):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, agenda.ErrGeneralAgendaExists):
+			return errs.New(errs.Aborted, err)
+		}
 		return errs.Newf(errs.Internal, "update: generalAgendaID[%s]: %s", gAgdID, err)
 	}
 
+	if _, err := h.agdCore.HandleAffected(ctx, affected, action, qp.Reason); err != nil {
+		return errs.Newf(errs.Internal, "handleaffected: generalAgendaID[%s]: %s", gAgdID, err)
+	}
+
 	return toAppGeneralAgenda(agd)
 }
 
+// previewGeneralAgendaUpdate returns the appointments the update of the general
+// agenda would leave outside the agendas, without updating it.
+func (h *handlers) previewGeneralAgendaUpdate(ctx context.Context, r *http.Request) web.Encoder {
+	var app AppUpdateGeneralAgenda
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	agd, err := mid.GetGeneralAgenda(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "general agenda missing in context: %s", err)
+	}
+
+	uAgd, err := toCoreUpdateGeneralAgenda(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	affected, err := h.agdCore.AffectedByGeneralAgendaUpdate(ctx, agd, uAgd)
+	if err != nil {
+		switch {
+		case errors.Is(err, agenda.ErrInvalidHours), errors.Is(err, agenda.ErrInvalidRange):
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "affected: generalAgendaID[%s]: %s", agd.ID, err)
+	}
+
+	return AppAffected{Affected: toAppAffectedAppointments(affected)}
+}
+
 func (h *handlers) deleteGeneralAgenda(ctx context.Context, r *http.Request) web.Encoder {
 	h, err := h.executeUnderTransaction(ctx)
 	if err != nil {
@@ -233,14 +307,83 @@ func (h *handlers) createDailyAgenda(ctx context.Context, r *http.Request) web.E
 		return errs.Newf(errs.PermissionDenied, "you don't have the persmission for this action: %s", auth.ErrForbidden)
 	}
 
-	gAgd, err := h.agdCore.CreateDailyAgenda(ctx, nAgd)
+	qp := parseAffectedQueryParams(r)
+
+	action, err := agenda.ParseAction(qp.Action)
+	if err != nil {
+		return errs.NewFieldErrors("affected", err)
+	}
+
+	var affected []appointment.Appointment
+	if action != agenda.ActionKeep {
+		affected, err = h.agdCore.AffectedByDailyAgenda(ctx, nAgd)
+	}
+
+	var gAgd agenda.DailyAgenda
+	if err == nil {
+		gAgd, err = h.agdCore.CreateDailyAgenda(ctx, nAgd)
+	}
 	if err != nil {
+		switch {
+		case errors.Is(err, agenda.ErrInvalidWindows), errors.Is(err, agenda.ErrResourceMismatch):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, agenda.ErrDailyAgendaExists):
+			return errs.New(errs.Aborted, err)
+		case errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		}
 		return errs.Newf(errs.Internal, "create daily agenda: app[%+v]: %s", app, err)
 	}
 
+	if _, err := h.agdCore.HandleAffected(ctx, affected, action, qp.Reason); err != nil {
+		return errs.Newf(errs.Internal, "handleaffected: dailyAgendaID[%s]: %s", gAgd.ID, err)
+	}
+
 	return toAppDailyAgenda(gAgd)
 }
 
+// previewDailyAgenda returns the appointments the new daily agenda would leave outside
+// the agendas, without creating it.
+func (h *handlers) previewDailyAgenda(ctx context.Context, r *http.Request) web.Encoder {
+	var app AppNewDailyAgenda
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	nAgd, err := toCoreNewDailyAgenda(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	bsn, err := h.bsnCore.QueryByID(ctx, nAgd.BusinessID)
+	if err != nil {
+		switch {
+		case errors.Is(err, business.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "querybyid: bsnID[%s]: %s", nAgd.BusinessID, err)
+		}
+	}
+
+	usrClaimID := auth.GetClaims(ctx).Subject
+	if usrClaimID != bsn.OwnerID.String() {
+		return errs.Newf(errs.PermissionDenied, "you don't have the persmission for this action: %s", auth.ErrForbidden)
+	}
+
+	affected, err := h.agdCore.AffectedByDailyAgenda(ctx, nAgd)
+	if err != nil {
+		switch {
+		case errors.Is(err, agenda.ErrInvalidWindows), errors.Is(err, agenda.ErrResourceMismatch):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "affected: app[%+v]: %s", app, err)
+	}
+
+	return AppAffected{Affected: toAppAffectedAppointments(affected)}
+}
+
 func (h *handlers) updateDailyAgenda(ctx context.Context, r *http.Request) web.Encoder {
 	h, err := h.executeUnderTransaction(ctx)
 	if err != nil {
@@ -267,14 +410,66 @@ func (h *handlers) updateDailyAgenda(ctx context.Context, r *http.Request) web.E
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	agd, err = h.agdCore.UpdateDailyAgenda(ctx, agd, uAgd)
+	qp := parseAffectedQueryParams(r)
+
+	action, err := agenda.ParseAction(qp.Action)
 	if err != nil {
+		return errs.NewFieldErrors("affected", err)
+	}
+
+	var affected []appointment.Appointment
+	if action != agenda.ActionKeep {
+		affected, err = h.agdCore.AffectedByDailyAgendaUpdate(ctx, agd, uAgd)
+	}
+	if err == nil {
+		agd, err = h.agdCore.UpdateDailyAgenda(ctx, agd, uAgd)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, agenda.ErrInvalidWindows):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, agenda.ErrDailyAgendaExists):
+			return errs.New(errs.Aborted, err)
+		}
 		return errs.Newf(errs.Internal, "update: dailyAgendaID[%s]: %s", gAgdID, err)
 	}
 
+	if _, err := h.agdCore.HandleAffected(ctx, affected, action, qp.Reason); err != nil {
+		return errs.Newf(errs.Internal, "handleaffected: dailyAgendaID[%s]: %s", gAgdID, err)
+	}
+
 	return toAppDailyAgenda(agd)
 }
 
+// previewDailyAgendaUpdate returns the appointments the update of the daily agenda
+// would leave outside the agendas, without updating it.
+func (h *handlers) previewDailyAgendaUpdate(ctx context.Context, r *http.Request) web.Encoder {
+	var app AppUpdateDailyAgenda
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	agd, err := mid.GetDailyAgenda(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "daily agenda missing in context: %s", err)
+	}
+
+	uAgd, err := toCoreUpdateDailyAgenda(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	affected, err := h.agdCore.AffectedByDailyAgendaUpdate(ctx, agd, uAgd)
+	if err != nil {
+		if errors.Is(err, agenda.ErrInvalidWindows) {
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "affected: dailyAgendaID[%s]: %s", agd.ID, err)
+	}
+
+	return AppAffected{Affected: toAppAffectedAppointments(affected)}
+}
+
 func (h *handlers) deleteDailyAgenda(ctx context.Context, r *http.Request) web.Encoder {
 	h, err := h.executeUnderTransaction(ctx)
 	if err != nil {
@@ -346,3 +541,265 @@ func (h *handlers) queryDailyAgendaByID(ctx context.Context, r *http.Request) we
 	return toAppDailyAgenda(agd)
 
 }
+
+func (h *handlers) createClosure(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	var app AppNewClosure
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	nc, err := toCoreNewClosure(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	bsn, err := h.bsnCore.QueryByID(ctx, nc.BusinessID)
+	if err != nil {
+		switch {
+		case errors.Is(err, business.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "create closure: app[%+v]: %s", app, err)
+		}
+	}
+
+	usrClaimID := auth.GetClaims(ctx).Subject
+	if usrClaimID != bsn.OwnerID.String() {
+		return errs.Newf(errs.PermissionDenied, "you don't have the persmission for this action: %s", auth.ErrForbidden)
+	}
+
+	cl, err := h.agdCore.CreateClosure(ctx, nc)
+	if err != nil {
+		switch {
+		case errors.Is(err, agenda.ErrInvalidRange), errors.Is(err, agenda.ErrResourceMismatch):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "create closure: app[%+v]: %s", app, err)
+	}
+
+	return toAppClosure(cl)
+}
+
+func (h *handlers) updateClosure(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	var app AppUpdateClosure
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	cl, err := mid.GetClosure(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "closure missing in context: %s", err)
+	}
+
+	uc, err := toCoreUpdateClosure(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	cl, err = h.agdCore.UpdateClosure(ctx, cl, uc)
+	if err != nil {
+		if errors.Is(err, agenda.ErrInvalidRange) {
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "update: closureID[%s]: %s", cl.ID, err)
+	}
+
+	return toAppClosure(cl)
+}
+
+func (h *handlers) deleteClosure(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	cl, err := mid.GetClosure(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "closure missing in context: %s", err)
+	}
+
+	if err := h.agdCore.DeleteClosure(ctx, cl); err != nil {
+		return errs.Newf(errs.Internal, "delete: closureID[%s]: %s", cl.ID, err)
+	}
+
+	return nil
+}
+
+func (h *handlers) queryClosures(ctx context.Context, r *http.Request) web.Encoder {
+	qp := parseClosureQueryParams(r)
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseClosureFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(closureOrderByFields, qp.OrderBy, agenda.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	cls, err := h.agdCore.QueryClosures(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := h.agdCore.CountClosures(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return response.NewPageDocument(toAppClosureSlice(cls), total, page)
+}
+
+func (h *handlers) queryClosureByID(ctx context.Context, r *http.Request) web.Encoder {
+	clID, err := uuid.Parse(web.Param(r, "closure_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, ErrInvalidID)
+	}
+
+	cl, err := h.agdCore.QueryClosureByID(ctx, clID)
+	if err != nil {
+		if errors.Is(err, agenda.ErrClosureNotFound) {
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "queryclosurebyid: closureID[%s]: %s", clID, err)
+	}
+
+	return toAppClosure(cl)
+}
+
+// maxCalendarSize limits the size of imported calendars.
+const maxCalendarSize = 1 << 20
+
+// importClosures closes the business on the dates of the events of the iCalendar
+// data in the body of the request, reporting the appointments now falling on
+// closed dates.
+func (h *handlers) importClosures(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	bsnID, err := uuid.Parse(web.Param(r, "business_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, ErrInvalidID)
+	}
+
+	bsn, err := h.bsnCore.QueryByID(ctx, bsnID)
+	if err != nil {
+		switch {
+		case errors.Is(err, business.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "querybyid: bsnID[%s]: %s", bsnID, err)
+		}
+	}
+
+	usrClaimID := auth.GetClaims(ctx).Subject
+	if usrClaimID != bsn.OwnerID.String() {
+		return errs.Newf(errs.PermissionDenied, "you don't have the persmission for this action: %s", auth.ErrForbidden)
+	}
+
+	res, err := h.agdCore.ImportClosures(ctx, bsn.ID, http.MaxBytesReader(nil, r.Body, maxCalendarSize))
+	if err != nil {
+		switch {
+		case errors.Is(err, agenda.ErrInvalidCalendar), errors.Is(err, agenda.ErrClosureTooLong),
+			errors.Is(err, agenda.ErrRecurringEvent):
+			return errs.New(errs.InvalidArgument, err)
+		}
+		return errs.Newf(errs.Internal, "importclosures: bsnID[%s]: %s", bsn.ID, err)
+	}
+
+	return toAppImportResult(res)
+}
+
+// ---------------------------------------------------------------------------------------------------
+// ---------------------------------------------------------------------------------------------------
+
+func (h *handlers) queryAvailableSlots(ctx context.Context, r *http.Request) web.Encoder {
+	bsnID, err := uuid.Parse(web.Param(r, "business_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, ErrInvalidID)
+	}
+
+	bsn, err := h.bsnCore.QueryByID(ctx, bsnID)
+	if err != nil {
+		switch {
+		case errors.Is(err, business.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "querybyid: bsnID[%s]: %s", bsnID, err)
+		}
+	}
+
+	qp := parseSlotQueryParams(r)
+
+	// Dates of the range are days of the business, not of the caller.
+	from, to, err := parseSlotRange(qp, bsn.TimeZone.Location())
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	// Slots of a service last as long as the service and hold as many people.
+	var svc service.Service
+	if qp.ServiceID != "" {
+		svcID, err := uuid.Parse(qp.ServiceID)
+		if err != nil {
+			return errs.NewFieldErrors("service_id", err)
+		}
+
+		svc, err = h.svcCore.QueryByID(ctx, svcID)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrNotFound):
+				return errs.New(errs.NotFound, err)
+			default:
+				return errs.Newf(errs.Internal, "querybyid: svcID[%s]: %s", svcID, err)
+			}
+		}
+
+		if svc.BusinessID != bsnID {
+			return errs.NewFieldErrors("service_id", ErrServiceMismatch)
+		}
+	}
+
+	// Without a resource, slots of every resource of the business are given.
+	var rscID uuid.UUID
+	if qp.ResourceID != "" {
+		rscID, err = uuid.Parse(qp.ResourceID)
+		if err != nil {
+			return errs.NewFieldErrors("resource_id", err)
+		}
+	}
+
+	// to is an inclusive date, so slots are collected up to the start of the next day.
+	slots, err := h.agdCore.AvailableSlots(ctx, bsnID, rscID, from, to.AddDate(0, 0, 1), svc)
+	if err != nil {
+		switch {
+		case errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		case errors.Is(err, agenda.ErrResourceMismatch):
+			return errs.NewFieldErrors("resource_id", err)
+		}
+		return errs.Newf(errs.Internal, "availableslots: bsnID[%s]: %s", bsnID, err)
+	}
+
+	return toAppAvailableSlots(bsnID, from, to, slots)
+}
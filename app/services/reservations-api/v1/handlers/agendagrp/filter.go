@@ -1,6 +1,8 @@
 package agendagrp
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,6 +12,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxSlotDays is the widest range of days a slot query may cover.
+const maxSlotDays = 31
+
 func parseGeneralAgendaQueryParams(r *http.Request) (generalAgendaQueryParams, error) {
 	values := r.URL.Query()
 
@@ -19,6 +24,8 @@ func parseGeneralAgendaQueryParams(r *http.Request) (generalAgendaQueryParams, e
 		OrderBy:    values.Get("orderBy"),
 		ID:         values.Get("id"),
 		BusinessID: values.Get("business_id"),
+		ResourceID: values.Get("resource_id"),
+		Date:       values.Get("date"),
 	}
 
 	return filter, nil
@@ -33,6 +40,7 @@ func parseDailyAgendaQueryParams(r *http.Request) (dailyAgendaQueryParams, error
 		OrderBy:    values.Get("orderBy"),
 		ID:         values.Get("id"),
 		BusinessID: values.Get("business_id"),
+		ResourceID: values.Get("resource_id"),
 		Date:       values.Get("date"),
 		From:       values.Get("from"),
 		To:         values.Get("to"),
@@ -42,6 +50,84 @@ func parseDailyAgendaQueryParams(r *http.Request) (dailyAgendaQueryParams, error
 	return filter, nil
 }
 
+func parseClosureQueryParams(r *http.Request) closureQueryParams {
+	values := r.URL.Query()
+
+	return closureQueryParams{
+		Page:       values.Get("page"),
+		Rows:       values.Get("rows"),
+		OrderBy:    values.Get("orderBy"),
+		ID:         values.Get("id"),
+		BusinessID: values.Get("business_id"),
+		ResourceID: values.Get("resource_id"),
+		Date:       values.Get("date"),
+	}
+}
+
+func parseAffectedQueryParams(r *http.Request) affectedQueryParams {
+	values := r.URL.Query()
+
+	return affectedQueryParams{
+		Action: values.Get("affected"),
+		Reason: values.Get("reason"),
+	}
+}
+
+func parseSlotQueryParams(r *http.Request) slotQueryParams {
+	values := r.URL.Query()
+
+	return slotQueryParams{
+		From:       values.Get("from"),
+		To:         values.Get("to"),
+		ServiceID:  values.Get("service_id"),
+		ResourceID: values.Get("resource_id"),
+	}
+}
+
+// parseSlotRange parses the inclusive from and to dates of a slot query as dates
+// of the given location. An empty from defaults to today and an empty to defaults
+// to maxSlotDays after from.
+func parseSlotRange(qp slotQueryParams, loc *time.Location) (time.Time, time.Time, error) {
+	var fieldErrors errs.FieldErrors
+
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if qp.From != "" {
+		f, err := time.ParseInLocation(time.DateOnly, qp.From, loc)
+		switch err {
+		case nil:
+			from = f
+		default:
+			fieldErrors.Add("from", err)
+		}
+	}
+
+	to := from.AddDate(0, 0, maxSlotDays-1)
+	if qp.To != "" {
+		t, err := time.ParseInLocation(time.DateOnly, qp.To, loc)
+		switch err {
+		case nil:
+			to = t
+		default:
+			fieldErrors.Add("to", err)
+		}
+	}
+
+	if fieldErrors != nil {
+		return time.Time{}, time.Time{}, fieldErrors.ToError()
+	}
+
+	if to.Before(from) {
+		return time.Time{}, time.Time{}, errs.NewFieldErrors("to", errors.New("should not be before from"))
+	}
+
+	if !to.Before(from.AddDate(0, 0, maxSlotDays)) {
+		return time.Time{}, time.Time{}, errs.NewFieldErrors("to", fmt.Errorf("range should not exceed %d days", maxSlotDays))
+	}
+
+	return from, to, nil
+}
+
 func parseGeneralAgendaFilter(qp generalAgendaQueryParams) (agenda.GAQueryFilter, error) {
 	var fieldErrors errs.FieldErrors
 	var filter agenda.GAQueryFilter
@@ -66,6 +152,26 @@ func parseGeneralAgendaFilter(qp generalAgendaQueryParams) (agenda.GAQueryFilter
 		}
 	}
 
+	if qp.ResourceID != "" {
+		id, err := uuid.Parse(qp.ResourceID)
+		switch err {
+		case nil:
+			filter.WithResourceID(id)
+		default:
+			fieldErrors.Add("resource_id", err)
+		}
+	}
+
+	if qp.Date != "" {
+		d, err := time.Parse(time.DateOnly, qp.Date)
+		switch err {
+		case nil:
+			filter.WithDate(d)
+		default:
+			fieldErrors.Add("date", err)
+		}
+	}
+
 	if err := filter.Validate(); err != nil {
 		fieldErrors.Add("filter validation", err)
 	}
@@ -101,6 +207,16 @@ func parseDailyAgendaFilter(qp dailyAgendaQueryParams) (agenda.DAQueryFilter, er
 		}
 	}
 
+	if qp.ResourceID != "" {
+		id, err := uuid.Parse(qp.ResourceID)
+		switch err {
+		case nil:
+			filter.WithResourceID(id)
+		default:
+			fieldErrors.Add("resource_id", err)
+		}
+	}
+
 	if qp.Date != "" {
 		d, err := time.Parse(time.DateOnly, qp.Date)
 		switch err {
@@ -151,3 +267,58 @@ func parseDailyAgendaFilter(qp dailyAgendaQueryParams) (agenda.DAQueryFilter, er
 
 	return filter, nil
 }
+
+func parseClosureFilter(qp closureQueryParams) (agenda.ClosureQueryFilter, error) {
+	var fieldErrors errs.FieldErrors
+	var filter agenda.ClosureQueryFilter
+
+	if qp.ID != "" {
+		id, err := uuid.Parse(qp.ID)
+		switch err {
+		case nil:
+			filter.WithClosureID(id)
+		default:
+			fieldErrors.Add("id", err)
+		}
+	}
+
+	if qp.BusinessID != "" {
+		id, err := uuid.Parse(qp.BusinessID)
+		switch err {
+		case nil:
+			filter.WithBusinessID(id)
+		default:
+			fieldErrors.Add("business_id", err)
+		}
+	}
+
+	if qp.ResourceID != "" {
+		id, err := uuid.Parse(qp.ResourceID)
+		switch err {
+		case nil:
+			filter.WithResourceID(id)
+		default:
+			fieldErrors.Add("resource_id", err)
+		}
+	}
+
+	if qp.Date != "" {
+		d, err := time.Parse(time.DateOnly, qp.Date)
+		switch err {
+		case nil:
+			filter.WithDate(d)
+		default:
+			fieldErrors.Add("date", err)
+		}
+	}
+
+	if err := filter.Validate(); err != nil {
+		return agenda.ClosureQueryFilter{}, errs.NewFieldErrors("filter validation", err)
+	}
+
+	if fieldErrors != nil {
+		return agenda.ClosureQueryFilter{}, fieldErrors.ToError()
+	}
+
+	return filter, nil
+}
@@ -7,23 +7,19 @@ import (
 	"time"
 
 	"github.com/ameghdadian/service/business/core/agenda"
+	"github.com/ameghdadian/service/business/core/appointment"
 	"github.com/ameghdadian/service/foundation/errs"
 	"github.com/google/uuid"
 )
 
-const (
-	// This turns the time into ISO8601, changing the timezone accordingly.
-	// For example: "15:45:00 +3300" or for UTC time, "15:45:00 Z".
-	// For more description, refer to stdlib time:format.go:81.
-	appTimeFormat = time.TimeOnly + " Z0700"
-)
-
 type generalAgendaQueryParams struct {
 	Page       string
 	Rows       string
 	OrderBy    string
 	ID         string
 	BusinessID string
+	ResourceID string
+	Date       string
 }
 
 type dailyAgendaQueryParams struct {
@@ -32,23 +28,106 @@ type dailyAgendaQueryParams struct {
 	OrderBy    string
 	ID         string
 	BusinessID string
+	ResourceID string
 	Date       string
 	From       string
 	To         string
 	Days       string
 }
 
+type closureQueryParams struct {
+	Page       string
+	Rows       string
+	OrderBy    string
+	ID         string
+	BusinessID string
+	ResourceID string
+	Date       string
+}
+
+// affectedQueryParams tells what becomes of the appointments an agenda change leaves
+// outside the agendas, and why.
+type affectedQueryParams struct {
+	Action string
+	Reason string
+}
+
+type slotQueryParams struct {
+	From       string
+	To         string
+	ServiceID  string
+	ResourceID string
+}
+
 // ============================================================
 
+// AppOpeningHours is a period of a weekday, in the business time zone, given as
+// "15:04" clocks.
+type AppOpeningHours struct {
+	Day      int    `json:"day" validate:"gte=0,lte=6"`
+	OpensAt  string `json:"opens_at" validate:"required"`
+	ClosedAt string `json:"closed_at" validate:"required"`
+	Interval int    `json:"interval" validate:"required,gt=0,lte=86400"`
+}
+
+func toAppOpeningHours(hours []agenda.OpeningHours) []AppOpeningHours {
+	items := make([]AppOpeningHours, len(hours))
+	for i, h := range hours {
+		items[i] = AppOpeningHours{
+			Day:      int(h.Day.DayOfWeedk()),
+			OpensAt:  h.OpensAt.String(),
+			ClosedAt: h.ClosedAt.String(),
+			Interval: h.Interval,
+		}
+	}
+
+	return items
+}
+
+func toCoreOpeningHours(app []AppOpeningHours) ([]agenda.OpeningHours, error) {
+	hours := make([]agenda.OpeningHours, len(app))
+	for i, h := range app {
+		day, err := agenda.ParseDay(uint(h.Day))
+		if err != nil {
+			return nil, fmt.Errorf("parsing day: %w", err)
+		}
+
+		opn, err := agenda.ParseClock(h.OpensAt)
+		if err != nil {
+			return nil, fmt.Errorf("parsing opens at: %w", err)
+		}
+
+		cld, err := agenda.ParseClock(h.ClosedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parsing closed at: %w", err)
+		}
+
+		if !opn.Before(cld) {
+			return nil, errors.New("closed at time should be after Opens at time")
+		}
+
+		hours[i] = agenda.OpeningHours{
+			Day:      day,
+			OpensAt:  opn,
+			ClosedAt: cld,
+			Interval: h.Interval,
+		}
+	}
+
+	return hours, nil
+}
+
+// ---------------------------------------------------------------------------------
+
 type AppGeneralAgenda struct {
-	ID          string `json:"id"`
-	BusinessID  string `json:"business_id"`
-	OpensAt     string `json:"open_at"`
-	ClosedAt    string `json:"closed_at"`
-	Interval    int    `json:"interval"`
-	WorkingDays []int  `json:"working_days"`
-	DateCreated string `json:"-"`
-	DateUpdated string `json:"-"`
+	ID            string            `json:"id"`
+	BusinessID    string            `json:"business_id"`
+	ResourceID    string            `json:"resource_id,omitempty"`
+	Hours         []AppOpeningHours `json:"hours"`
+	EffectiveFrom string            `json:"effective_from,omitempty"`
+	EffectiveTo   string            `json:"effective_to,omitempty"`
+	DateCreated   string            `json:"-"`
+	DateUpdated   string            `json:"-"`
 }
 
 func (aa AppGeneralAgenda) Encode() ([]byte, string, error) {
@@ -57,21 +136,36 @@ func (aa AppGeneralAgenda) Encode() ([]byte, string, error) {
 }
 
 func toAppGeneralAgenda(agd agenda.GeneralAgenda) AppGeneralAgenda {
-	days := make([]int, len(agd.WorkingDays))
-	for i, d := range agd.WorkingDays {
-		days[i] = int(d.DayOfWeedk())
+	return AppGeneralAgenda{
+		ID:            agd.ID.String(),
+		BusinessID:    agd.BusinessID.String(),
+		ResourceID:    resourceIDString(agd.ResourceID),
+		Hours:         toAppOpeningHours(agd.Hours),
+		EffectiveFrom: effectiveDateString(agd.EffectiveFrom),
+		EffectiveTo:   effectiveDateString(agd.EffectiveTo),
+		DateCreated:   agd.DateCreated.Format(time.RFC3339),
+		DateUpdated:   agd.DateUpdated.Format(time.RFC3339),
 	}
+}
 
-	return AppGeneralAgenda{
-		ID:          agd.ID.String(),
-		BusinessID:  agd.BusinessID.String(),
-		OpensAt:     agd.OpensAt.Format(appTimeFormat),
-		ClosedAt:    agd.ClosedAt.Format(appTimeFormat),
-		Interval:    agd.Interval,
-		WorkingDays: days,
-		DateCreated: agd.DateCreated.Format(time.RFC3339),
-		DateUpdated: agd.DateUpdated.Format(time.RFC3339),
+// effectiveDateString formats an effective date of a general agenda, leaving it empty
+// when the agenda is in effect indefinitely on that side.
+func effectiveDateString(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.Format(time.DateOnly)
+}
+
+// parseEffectiveDate parses an effective date of a general agenda, where an empty
+// date leaves the agenda in effect indefinitely on that side.
+func parseEffectiveDate(date string) (time.Time, error) {
+	if date == "" {
+		return time.Time{}, nil
 	}
+
+	return time.Parse(time.DateOnly, date)
 }
 
 func toAppGeneralAgendaSlice(agds []agenda.GeneralAgenda) []AppGeneralAgenda {
@@ -85,12 +179,15 @@ func toAppGeneralAgendaSlice(agds []agenda.GeneralAgenda) []AppGeneralAgenda {
 
 // ---------------------------------------------------------------------------------
 
+// AppNewGeneralAgenda holds the weekly hours of the business, or of one of its
+// resources, from effective_from to effective_to. Either date may be left empty to
+// keep the agenda in effect indefinitely on that side.
 type AppNewGeneralAgenda struct {
-	BusinessID  string `json:"business_id" validate:"required,uuid"`
-	OpensAt     string `json:"opens_at" validate:"required,required_with=ClosedAt"`
-	ClosedAt    string `json:"closed_at" validate:"required,required_with=OpensAt"`
-	Interval    int    `json:"interval" validate:"required,gt=0,lte=86400"`
-	WorkingDays []int  `json:"working_days" validate:"required,max=7,dive,gte=0,lte=6"`
+	BusinessID    string            `json:"business_id" validate:"required,uuid"`
+	ResourceID    string            `json:"resource_id" validate:"omitempty,uuid"`
+	Hours         []AppOpeningHours `json:"hours" validate:"required,min=1,dive"`
+	EffectiveFrom string            `json:"effective_from"`
+	EffectiveTo   string            `json:"effective_to"`
 }
 
 func (app AppNewGeneralAgenda) Validate() error {
@@ -107,48 +204,43 @@ func toCoreNewGeneralAgenda(app AppNewGeneralAgenda) (agenda.NewGeneralAgenda, e
 		return agenda.NewGeneralAgenda{}, fmt.Errorf("parsing business id: %w", err)
 	}
 
-	days := make([]agenda.Day, len(app.WorkingDays))
-	for i, d := range app.WorkingDays {
-		day, err := agenda.ParseDay(uint(d))
-		if err != nil {
-			return agenda.NewGeneralAgenda{}, fmt.Errorf("parsing day: %w", err)
-		}
-		days[i] = day
-	}
-
-	opn, err := time.Parse(time.RFC3339, app.OpensAt)
+	rscID, err := parseResourceID(app.ResourceID)
 	if err != nil {
-		return agenda.NewGeneralAgenda{}, fmt.Errorf("parsing opens at: %w", err)
+		return agenda.NewGeneralAgenda{}, err
 	}
-	cld, err := time.Parse(time.RFC3339, app.ClosedAt)
+
+	hours, err := toCoreOpeningHours(app.Hours)
 	if err != nil {
-		return agenda.NewGeneralAgenda{}, fmt.Errorf("parsing closed at: %w", err)
+		return agenda.NewGeneralAgenda{}, err
 	}
 
-	if cld.Before(opn) {
-		return agenda.NewGeneralAgenda{}, errors.New("closed at time should be after Opens at time")
+	from, err := parseEffectiveDate(app.EffectiveFrom)
+	if err != nil {
+		return agenda.NewGeneralAgenda{}, fmt.Errorf("parsing effective from: %w", err)
 	}
 
-	if opn.Format(time.DateOnly) != cld.Format(time.DateOnly) {
-		return agenda.NewGeneralAgenda{}, errors.New("opening and closing hour can not be in two separate days")
+	to, err := parseEffectiveDate(app.EffectiveTo)
+	if err != nil {
+		return agenda.NewGeneralAgenda{}, fmt.Errorf("parsing effective to: %w", err)
 	}
 
 	return agenda.NewGeneralAgenda{
-		BusinessID:  bsnID,
-		OpensAt:     opn,
-		ClosedAt:    cld,
-		Interval:    app.Interval,
-		WorkingDays: days,
+		BusinessID:    bsnID,
+		ResourceID:    rscID,
+		Hours:         hours,
+		EffectiveFrom: from,
+		EffectiveTo:   to,
 	}, nil
 }
 
 // ---------------------------------------------------------------------------------
 
+// AppUpdateGeneralAgenda leaves the fields which are missing untouched. An empty
+// effective date keeps the agenda in effect indefinitely on that side.
 type AppUpdateGeneralAgenda struct {
-	OpensAt     *string `json:"opens_at" validate:"omitempty"`
-	ClosedAt    *string `json:"closed_at" validate:"omitempty"`
-	Interval    *int    `json:"interval" validate:"omitempty,gt=0,lte=86400"`
-	WorkingDays []int   `json:"working_days" validate:"omitempty,max=7,dive,gte=0,lte=6"`
+	Hours         []AppOpeningHours `json:"hours" validate:"omitempty,min=1,dive"`
+	EffectiveFrom *string           `json:"effective_from"`
+	EffectiveTo   *string           `json:"effective_to"`
 }
 
 func (app AppUpdateGeneralAgenda) Validate() error {
@@ -160,61 +252,100 @@ func (app AppUpdateGeneralAgenda) Validate() error {
 }
 
 func toCoreUpdateGeneralAgenda(app AppUpdateGeneralAgenda) (agenda.UpdateGeneralAgenda, error) {
-	var opn *time.Time
-	if app.OpensAt != nil {
-		o, err := time.Parse(time.RFC3339, *app.OpensAt)
+	var hours []agenda.OpeningHours
+	if app.Hours != nil {
+		var err error
+		hours, err = toCoreOpeningHours(app.Hours)
 		if err != nil {
-			return agenda.UpdateGeneralAgenda{}, fmt.Errorf("parsing opens at: %w", err)
+			return agenda.UpdateGeneralAgenda{}, err
 		}
-		opn = TimePointer(o)
 	}
-	var cld *time.Time
-	if app.ClosedAt != nil {
-		c, err := time.Parse(time.RFC3339, *app.ClosedAt)
+
+	uAgd := agenda.UpdateGeneralAgenda{
+		Hours: hours,
+	}
+
+	if app.EffectiveFrom != nil {
+		from, err := parseEffectiveDate(*app.EffectiveFrom)
 		if err != nil {
-			return agenda.UpdateGeneralAgenda{}, fmt.Errorf("parsing closed at: %w", err)
+			return agenda.UpdateGeneralAgenda{}, fmt.Errorf("parsing effective from: %w", err)
 		}
-		cld = TimePointer(c)
+		uAgd.EffectiveFrom = &from
 	}
 
-	if opn != nil && cld != nil {
-		if opn.Format(time.DateOnly) != cld.Format(time.DateOnly) {
-			return agenda.UpdateGeneralAgenda{}, errors.New("opening and closing hour can not be in two separate days")
+	if app.EffectiveTo != nil {
+		to, err := parseEffectiveDate(*app.EffectiveTo)
+		if err != nil {
+			return agenda.UpdateGeneralAgenda{}, fmt.Errorf("parsing effective to: %w", err)
 		}
+		uAgd.EffectiveTo = &to
 	}
 
-	var days []agenda.Day
-	if app.WorkingDays != nil {
-		for _, d := range app.WorkingDays {
-			day, err := agenda.ParseDay(uint(d))
-			if err != nil {
-				return agenda.UpdateGeneralAgenda{}, fmt.Errorf("parsing day: %w", err)
-			}
-			days = append(days, day)
+	return uAgd, nil
+}
+
+// =================================================================================
+// =================================================================================
+
+// AppWindow is a period of the day, in the business time zone, given as "15:04" clocks.
+type AppWindow struct {
+	OpensAt  string `json:"opens_at" validate:"required"`
+	ClosedAt string `json:"closed_at" validate:"required"`
+	Interval int    `json:"interval" validate:"required,gt=0,lte=86400"`
+}
+
+func toAppWindows(wins []agenda.Window) []AppWindow {
+	items := make([]AppWindow, len(wins))
+	for i, w := range wins {
+		items[i] = AppWindow{
+			OpensAt:  w.OpensAt.String(),
+			ClosedAt: w.ClosedAt.String(),
+			Interval: w.Interval,
 		}
 	}
 
-	return agenda.UpdateGeneralAgenda{
-		OpensAt:     opn,
-		ClosedAt:    cld,
-		Interval:    app.Interval,
-		WorkingDays: days,
-	}, nil
+	return items
+}
+
+func toCoreWindows(app []AppWindow) ([]agenda.Window, error) {
+	wins := make([]agenda.Window, len(app))
+	for i, w := range app {
+		opn, err := agenda.ParseClock(w.OpensAt)
+		if err != nil {
+			return nil, fmt.Errorf("parsing opens at: %w", err)
+		}
+
+		cld, err := agenda.ParseClock(w.ClosedAt)
+		if err != nil {
+			return nil, fmt.Errorf("parsing closed at: %w", err)
+		}
+
+		if !opn.Before(cld) {
+			return nil, errors.New("closed at time should be after Opens at time")
+		}
 
+		wins[i] = agenda.Window{
+			OpensAt:  opn,
+			ClosedAt: cld,
+			Interval: w.Interval,
+		}
+	}
+
+	return wins, nil
 }
 
-// =================================================================================
-// =================================================================================
+// ---------------------------------------------------------------------------------
 
 type AppDailyAgenda struct {
-	ID           string `json:"id"`
-	BusinessID   string `json:"business_id"`
-	OpensAt      string `json:"opens_at"`
-	ClosedAt     string `json:"closed_at"`
-	Interval     int    `json:"interval"`
-	Availability bool   `json:"availability"`
-	DateCreated  string `json:"-"`
-	DateUpdated  string `json:"-"`
+	ID           string      `json:"id"`
+	BusinessID   string      `json:"business_id"`
+	ResourceID   string      `json:"resource_id,omitempty"`
+	Date         string      `json:"date"`
+	Availability bool        `json:"availability"`
+	Windows      []AppWindow `json:"windows"`
+	SourceUID    string      `json:"source_uid,omitempty"`
+	DateCreated  string      `json:"-"`
+	DateUpdated  string      `json:"-"`
 }
 
 func (aa AppDailyAgenda) Encode() ([]byte, string, error) {
@@ -226,10 +357,11 @@ func toAppDailyAgenda(agd agenda.DailyAgenda) AppDailyAgenda {
 	return AppDailyAgenda{
 		ID:           agd.ID.String(),
 		BusinessID:   agd.BusinessID.String(),
-		OpensAt:      agd.OpensAt.Format(time.RFC3339),
-		ClosedAt:     agd.ClosedAt.Format(time.RFC3339),
-		Interval:     agd.Interval,
+		ResourceID:   resourceIDString(agd.ResourceID),
+		Date:         agd.Date.Format(time.DateOnly),
 		Availability: agd.Availability,
+		Windows:      toAppWindows(agd.Windows),
+		SourceUID:    agd.SourceUID,
 		DateCreated:  agd.DateCreated.Format(time.RFC3339),
 		DateUpdated:  agd.DateUpdated.Format(time.RFC3339),
 	}
@@ -246,12 +378,230 @@ func toAppDailyAgendaSlice(agds []agenda.DailyAgenda) []AppDailyAgenda {
 
 // ---------------------------------------------------------------------------------
 
+type AppClosure struct {
+	ID          string `json:"id"`
+	BusinessID  string `json:"business_id"`
+	ResourceID  string `json:"resource_id,omitempty"`
+	From        string `json:"from"`
+	To          string `json:"to"`
+	Yearly      bool   `json:"yearly"`
+	Reason      string `json:"reason"`
+	DateCreated string `json:"-"`
+	DateUpdated string `json:"-"`
+}
+
+func (ac AppClosure) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(ac)
+	return data, "application/json", err
+}
+
+func toAppClosure(cl agenda.Closure) AppClosure {
+	return AppClosure{
+		ID:          cl.ID.String(),
+		BusinessID:  cl.BusinessID.String(),
+		ResourceID:  resourceIDString(cl.ResourceID),
+		From:        cl.From.Format(time.DateOnly),
+		To:          cl.To.Format(time.DateOnly),
+		Yearly:      cl.Yearly,
+		Reason:      cl.Reason,
+		DateCreated: cl.DateCreated.Format(time.RFC3339),
+		DateUpdated: cl.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppClosureSlice(cls []agenda.Closure) []AppClosure {
+	col := make([]AppClosure, len(cls))
+	for i, cl := range cls {
+		col[i] = toAppClosure(cl)
+	}
+
+	return col
+}
+
+// AppNewClosure closes the business, or one of its resources, from one date to
+// another inclusive. Yearly closures recur on the same dates every year.
+type AppNewClosure struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	ResourceID string `json:"resource_id" validate:"omitempty,uuid"`
+	From       string `json:"from" validate:"required"`
+	To         string `json:"to" validate:"required"`
+	Yearly     bool   `json:"yearly"`
+	Reason     string `json:"reason"`
+}
+
+func (app AppNewClosure) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toCoreNewClosure(app AppNewClosure) (agenda.NewClosure, error) {
+	bsnID, err := uuid.Parse(app.BusinessID)
+	if err != nil {
+		return agenda.NewClosure{}, fmt.Errorf("parsing business id: %w", err)
+	}
+
+	rscID, err := parseResourceID(app.ResourceID)
+	if err != nil {
+		return agenda.NewClosure{}, err
+	}
+
+	from, err := time.Parse(time.DateOnly, app.From)
+	if err != nil {
+		return agenda.NewClosure{}, fmt.Errorf("parsing from: %w", err)
+	}
+
+	to, err := time.Parse(time.DateOnly, app.To)
+	if err != nil {
+		return agenda.NewClosure{}, fmt.Errorf("parsing to: %w", err)
+	}
+
+	return agenda.NewClosure{
+		BusinessID: bsnID,
+		ResourceID: rscID,
+		From:       from,
+		To:         to,
+		Yearly:     app.Yearly,
+		Reason:     app.Reason,
+	}, nil
+}
+
+type AppUpdateClosure struct {
+	From   *string `json:"from"`
+	To     *string `json:"to"`
+	Yearly *bool   `json:"yearly"`
+	Reason *string `json:"reason"`
+}
+
+func (app AppUpdateClosure) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toCoreUpdateClosure(app AppUpdateClosure) (agenda.UpdateClosure, error) {
+	var uc agenda.UpdateClosure
+
+	if app.From != nil {
+		from, err := time.Parse(time.DateOnly, *app.From)
+		if err != nil {
+			return agenda.UpdateClosure{}, fmt.Errorf("parsing from: %w", err)
+		}
+		uc.From = &from
+	}
+
+	if app.To != nil {
+		to, err := time.Parse(time.DateOnly, *app.To)
+		if err != nil {
+			return agenda.UpdateClosure{}, fmt.Errorf("parsing to: %w", err)
+		}
+		uc.To = &to
+	}
+
+	uc.Yearly = app.Yearly
+	uc.Reason = app.Reason
+
+	return uc, nil
+}
+
+// ---------------------------------------------------------------------------------
+
+// AppImportedClosure is the stretch of dates an imported event closes. Dates are
+// empty for cancelled events.
+type AppImportedClosure struct {
+	UID     string `json:"uid"`
+	Summary string `json:"summary"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+}
+
+// AppAffectedAppointment is an appointment falling outside the agendas after they
+// change, such as on a date an import closed.
+type AppAffectedAppointment struct {
+	ID          string `json:"id"`
+	UserID      string `json:"user_id"`
+	ResourceID  string `json:"resource_id,omitempty"`
+	ScheduledOn string `json:"scheduled_on"`
+	Status      string `json:"status"`
+	FlaggedAt   string `json:"flagged_at,omitempty"`
+}
+
+func toAppAffectedAppointments(apts []appointment.Appointment) []AppAffectedAppointment {
+	affected := make([]AppAffectedAppointment, len(apts))
+	for i, apt := range apts {
+		affected[i] = AppAffectedAppointment{
+			ID:          apt.ID.String(),
+			UserID:      apt.UserID.String(),
+			ResourceID:  resourceIDString(apt.ResourceID),
+			ScheduledOn: apt.ScheduledOn.Format(time.RFC3339),
+			Status:      apt.Status.Status(),
+		}
+		if !apt.FlaggedAt.IsZero() {
+			affected[i].FlaggedAt = apt.FlaggedAt.Format(time.RFC3339)
+		}
+	}
+
+	return affected
+}
+
+// AppAffected lists the appointments an agenda change would leave outside the agendas,
+// without making the change.
+type AppAffected struct {
+	Affected []AppAffectedAppointment `json:"affected_appointments"`
+}
+
+func (aa AppAffected) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(aa)
+	return data, "application/json", err
+}
+
+type AppImportResult struct {
+	Closures []AppImportedClosure     `json:"closures"`
+	Created  []AppDailyAgenda         `json:"created"`
+	Removed  []AppDailyAgenda         `json:"removed"`
+	Skipped  []AppDailyAgenda         `json:"skipped"`
+	Affected []AppAffectedAppointment `json:"affected_appointments"`
+}
+
+func (ar AppImportResult) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(ar)
+	return data, "application/json", err
+}
+
+func toAppImportResult(res agenda.ImportResult) AppImportResult {
+	closures := make([]AppImportedClosure, len(res.Closures))
+	for i, cl := range res.Closures {
+		closures[i] = AppImportedClosure{
+			UID:     cl.UID,
+			Summary: cl.Summary,
+		}
+		if !cl.From.IsZero() {
+			closures[i].From = cl.From.Format(time.DateOnly)
+			closures[i].To = cl.To.Format(time.DateOnly)
+		}
+	}
+
+	return AppImportResult{
+		Closures: closures,
+		Created:  toAppDailyAgendaSlice(res.Created),
+		Removed:  toAppDailyAgendaSlice(res.Removed),
+		Skipped:  toAppDailyAgendaSlice(res.Skipped),
+		Affected: toAppAffectedAppointments(res.Affected),
+	}
+}
+
+// ---------------------------------------------------------------------------------
+
 type AppNewDailyAgenda struct {
-	BusinessID   string `json:"business_id" validate:"required,uuid"`
-	OpensAt      string `json:"opens_at" validate:"required_if=Availability true"`
-	ClosedAt     string `json:"closed_at" validate:"required_if=Availability true"`
-	Interval     int    `json:"interval" validate:"gt=0,lte=86400,required_if=Availability true"`
-	Availability bool   `json:"availability" validate:"required"`
+	BusinessID   string      `json:"business_id" validate:"required,uuid"`
+	ResourceID   string      `json:"resource_id" validate:"omitempty,uuid"`
+	Date         string      `json:"date" validate:"required"`
+	Availability *bool       `json:"availability" validate:"required"`
+	Windows      []AppWindow `json:"windows" validate:"required_if=Availability true,dive"`
 }
 
 func (app AppNewDailyAgenda) Validate() error {
@@ -268,74 +618,118 @@ func toCoreNewDailyAgenda(app AppNewDailyAgenda) (agenda.NewDailyAgenda, error)
 		return agenda.NewDailyAgenda{}, fmt.Errorf("parsing business id: %w", err)
 	}
 
-	opn, err := time.Parse(time.RFC3339, app.OpensAt)
-	if err != nil {
-		return agenda.NewDailyAgenda{}, fmt.Errorf("parsing opens at: %w", err)
-	}
-	cld, err := time.Parse(time.RFC3339, app.ClosedAt)
+	rscID, err := parseResourceID(app.ResourceID)
 	if err != nil {
-		return agenda.NewDailyAgenda{}, fmt.Errorf("parsing closed at: %w", err)
+		return agenda.NewDailyAgenda{}, err
 	}
 
-	if cld.Before(opn) {
-		return agenda.NewDailyAgenda{}, errors.New("closed at time should be after Opens at time")
+	date, err := time.Parse(time.DateOnly, app.Date)
+	if err != nil {
+		return agenda.NewDailyAgenda{}, fmt.Errorf("parsing date: %w", err)
 	}
 
-	if opn.Format(time.DateOnly) != cld.Format(time.DateOnly) {
-		return agenda.NewDailyAgenda{}, errors.New("opening and closing hour can not be in two separate days")
+	wins, err := toCoreWindows(app.Windows)
+	if err != nil {
+		return agenda.NewDailyAgenda{}, err
 	}
 
 	return agenda.NewDailyAgenda{
 		BusinessID:   bsnID,
-		OpensAt:      opn,
-		ClosedAt:     cld,
-		Interval:     app.Interval,
-		Availability: app.Availability,
+		ResourceID:   rscID,
+		Date:         date,
+		Availability: *app.Availability,
+		Windows:      wins,
 	}, nil
 }
 
 // ---------------------------------------------------------------------------------
 
 type AppUpdateDailyAgenda struct {
-	OpensAt      *string `json:"opens_at" validate:"omitempty,required_if=Availability true"`
-	ClosedAt     *string `json:"closed_at" validate:"omitempty,required_if=Availability true"`
-	Interval     *int    `json:"interval" validate:"omitempty,gt=0,lte=86400,required_if=Availability true"`
-	Availability *bool   `json:"availability" validate:"required"`
+	Date         *string     `json:"date"`
+	Availability *bool       `json:"availability"`
+	Windows      []AppWindow `json:"windows" validate:"omitempty,dive"`
 }
 
-func toCoreUpdateDailyAgenda(app AppUpdateDailyAgenda) (agenda.UpdateDailyAgenda, error) {
-	var opn *time.Time
-	if app.OpensAt != nil {
-		o, err := time.Parse(time.RFC3339, *app.OpensAt)
-		if err != nil {
-			return agenda.UpdateDailyAgenda{}, fmt.Errorf("parsing opens at: %w", err)
-		}
-		opn = TimePointer(o)
+func (app AppUpdateDailyAgenda) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return err
 	}
-	var cld *time.Time
-	if app.ClosedAt != nil {
-		c, err := time.Parse(time.RFC3339, *app.ClosedAt)
+
+	return nil
+}
+
+func toCoreUpdateDailyAgenda(app AppUpdateDailyAgenda) (agenda.UpdateDailyAgenda, error) {
+	var date *time.Time
+	if app.Date != nil {
+		d, err := time.Parse(time.DateOnly, *app.Date)
 		if err != nil {
-			return agenda.UpdateDailyAgenda{}, fmt.Errorf("parsing closed at: %w", err)
+			return agenda.UpdateDailyAgenda{}, fmt.Errorf("parsing date: %w", err)
 		}
-		cld = TimePointer(c)
+		date = TimePointer(d)
 	}
 
-	if opn != nil {
-		if opn.Format(time.DateOnly) != cld.Format(time.DateOnly) {
-			return agenda.UpdateDailyAgenda{}, errors.New("opening and closing hour can not be in two separate days")
+	var wins []agenda.Window
+	if app.Windows != nil {
+		var err error
+		wins, err = toCoreWindows(app.Windows)
+		if err != nil {
+			return agenda.UpdateDailyAgenda{}, err
 		}
 	}
 
 	return agenda.UpdateDailyAgenda{
-		OpensAt:      opn,
-		ClosedAt:     cld,
-		Interval:     app.Interval,
+		Date:         date,
 		Availability: app.Availability,
+		Windows:      wins,
 	}, nil
 
 }
 
+// =================================================================================
+// =================================================================================
+
+type AppSlot struct {
+	ResourceID string `json:"resource_id,omitempty"`
+	StartsAt   string `json:"starts_at"`
+	EndsAt     string `json:"ends_at"`
+	Capacity   int    `json:"capacity"`
+	Remaining  int    `json:"remaining"`
+}
+
+type AppAvailableSlots struct {
+	BusinessID string    `json:"business_id"`
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	TimeZone   string    `json:"time_zone"`
+	Slots      []AppSlot `json:"slots"`
+}
+
+func (as AppAvailableSlots) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(as)
+	return data, "application/json", err
+}
+
+func toAppAvailableSlots(bsnID uuid.UUID, from time.Time, to time.Time, slots []agenda.Slot) AppAvailableSlots {
+	items := make([]AppSlot, len(slots))
+	for i, s := range slots {
+		items[i] = AppSlot{
+			ResourceID: resourceIDString(s.ResourceID),
+			StartsAt:   s.StartsAt.Format(time.RFC3339),
+			EndsAt:     s.EndsAt.Format(time.RFC3339),
+			Capacity:   s.Capacity,
+			Remaining:  s.Remaining,
+		}
+	}
+
+	return AppAvailableSlots{
+		BusinessID: bsnID.String(),
+		From:       from.Format(time.DateOnly),
+		To:         to.Format(time.DateOnly),
+		TimeZone:   from.Location().String(),
+		Slots:      items,
+	}
+}
+
 // ---------------------------------------------------------------------------------
 // ---------------------------------------------------------------------------------
 // ---------------------------------------------------------------------------------
@@ -343,3 +737,27 @@ func toCoreUpdateDailyAgenda(app AppUpdateDailyAgenda) (agenda.UpdateDailyAgenda
 func TimePointer(t time.Time) *time.Time {
 	return &t
 }
+
+// parseResourceID parses an optional resource id; an empty one is uuid.Nil, which
+// stands for the business as a whole.
+func parseResourceID(id string) (uuid.UUID, error) {
+	if id == "" {
+		return uuid.Nil, nil
+	}
+
+	rscID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("parsing resource id: %w", err)
+	}
+
+	return rscID, nil
+}
+
+// resourceIDString returns the id of a resource, or an empty string for uuid.Nil.
+func resourceIDString(rscID uuid.UUID) string {
+	if rscID == uuid.Nil {
+		return ""
+	}
+
+	return rscID.String()
+}
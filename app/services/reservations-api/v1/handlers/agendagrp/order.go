@@ -12,4 +12,11 @@ var generalAgendaOrderByFields = map[string]string{
 var dailyAgendaOrderByFields = map[string]string{
 	"id":          agenda.OrderByID,
 	"business_id": agenda.OrderByBusinessID,
+	"date":        agenda.OrderByDate,
+}
+
+var closureOrderByFields = map[string]string{
+	"id":          agenda.OrderByID,
+	"business_id": agenda.OrderByBusinessID,
+	"from":        agenda.OrderByFrom,
 }
@@ -5,8 +5,14 @@ import (
 
 	"github.com/ameghdadian/service/business/core/agenda"
 	"github.com/ameghdadian/service/business/core/agenda/stores/agendadb"
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/appointment/stores/appointmentdb"
 	"github.com/ameghdadian/service/business/core/business"
 	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/resource/stores/resourcedb"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/service/stores/servicedb"
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/business/core/user/stores/userdb"
 	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
@@ -14,40 +20,61 @@ import (
 	"github.com/ameghdadian/service/business/web/v1/mid"
 	"github.com/ameghdadian/service/foundation/logger"
 	"github.com/ameghdadian/service/foundation/web"
+	"github.com/hibiken/asynq"
 	"github.com/jmoiron/sqlx"
 )
 
 type Config struct {
-	Build string
-	Log   *logger.Logger
-	DB    *sqlx.DB
-	Auth  *auth.Auth
+	Build         string
+	Log           *logger.Logger
+	DB            *sqlx.DB
+	Auth          *auth.Auth
+	TaskClient    *asynq.Client
+	TaskInspector *asynq.Inspector
 }
 
 func Routes(app *web.App, cfg Config) {
 	const version = "v1"
 
+	aptTask := appointment.NewTask(cfg.TaskClient, cfg.TaskInspector)
+
 	usrCore := user.NewCore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB))
 	bsnCore := business.NewCore(cfg.Log, usrCore, businessdb.NewStore(cfg.Log, cfg.DB))
-	agdCore := agenda.NewCore(cfg.Log, bsnCore, agendadb.NewStore(cfg.Log, cfg.DB))
+	svcCore := service.NewCore(cfg.Log, bsnCore, servicedb.NewStore(cfg.Log, cfg.DB))
+	rscCore := resource.NewCore(cfg.Log, bsnCore, resourcedb.NewStore(cfg.Log, cfg.DB))
+	aptCore := appointment.NewCore(cfg.Log, usrCore, bsnCore, svcCore, rscCore, appointmentdb.NewStore(cfg.Log, cfg.DB), aptTask)
+	agdCore := agenda.NewCore(cfg.Log, bsnCore, rscCore, aptCore, agendadb.NewStore(cfg.Log, cfg.DB))
 
 	authen := mid.Authenticate(cfg.Auth)
 	ruleAdminOnly := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
 	ruleAuthorizedGenAgenda := mid.AuthorizeGeneralAgenda(cfg.Log, cfg.Auth, agdCore, bsnCore)
 	ruleAuthorizedDaiAgenda := mid.AuthorizeDailyAgenda(cfg.Log, cfg.Auth, agdCore, bsnCore)
+	ruleAuthorizedClosure := mid.AuthorizeClosure(cfg.Log, cfg.Auth, agdCore, bsnCore)
 	tran := mid.ExecuteInTransaction(cfg.Log, db.NewBeginner(cfg.DB))
 
-	hdl := newApp(agdCore, bsnCore)
+	hdl := newApp(agdCore, bsnCore, svcCore)
 	// General Agenda Handlers
 	app.Handle(http.MethodPost, version, "/agendas/general", hdl.createGeneralAgenda, authen, tran)
 	app.Handle(http.MethodPut, version, "/agendas/general/{agenda_id}", hdl.updateGeneralAgenda, authen, tran, ruleAuthorizedGenAgenda)
+	app.Handle(http.MethodPost, version, "/agendas/general/{agenda_id}/preview", hdl.previewGeneralAgendaUpdate, authen, ruleAuthorizedGenAgenda)
 	app.Handle(http.MethodDelete, version, "/agendas/general/{agenda_id}", hdl.deleteGeneralAgenda, authen, tran, ruleAuthorizedGenAgenda)
 	app.Handle(http.MethodGet, version, "/agendas/general", hdl.queryGeneralAgenda, authen, ruleAdminOnly)
 	app.Handle(http.MethodGet, version, "/agendas/general/{agenda_id}", hdl.queryGeneralAgendaByID, authen)
 	// Daily Agenda Handlers
 	app.Handle(http.MethodPost, version, "/agendas/daily", hdl.createDailyAgenda, authen, tran)
+	app.Handle(http.MethodPost, version, "/agendas/daily/preview", hdl.previewDailyAgenda, authen)
 	app.Handle(http.MethodPut, version, "/agendas/daily/{agenda_id}", hdl.updateDailyAgenda, authen, tran, ruleAuthorizedDaiAgenda)
+	app.Handle(http.MethodPost, version, "/agendas/daily/{agenda_id}/preview", hdl.previewDailyAgendaUpdate, authen, ruleAuthorizedDaiAgenda)
 	app.Handle(http.MethodDelete, version, "/agendas/daily/{agenda_id}", hdl.deleteDailyAgenda, authen, tran, ruleAuthorizedDaiAgenda)
 	app.Handle(http.MethodGet, version, "/agendas/daily", hdl.queryDailyAgenda, authen)
 	app.Handle(http.MethodGet, version, "/agendas/daily/{agenda_id}", hdl.queryDailyAgendaByID, authen)
+	app.Handle(http.MethodPost, version, "/businesses/{business_id}/closures", hdl.importClosures, authen, tran)
+	// Closure Handlers
+	app.Handle(http.MethodPost, version, "/agendas/closures", hdl.createClosure, authen, tran)
+	app.Handle(http.MethodPut, version, "/agendas/closures/{closure_id}", hdl.updateClosure, authen, tran, ruleAuthorizedClosure)
+	app.Handle(http.MethodDelete, version, "/agendas/closures/{closure_id}", hdl.deleteClosure, authen, tran, ruleAuthorizedClosure)
+	app.Handle(http.MethodGet, version, "/agendas/closures", hdl.queryClosures, authen)
+	app.Handle(http.MethodGet, version, "/agendas/closures/{closure_id}", hdl.queryClosureByID, authen)
+	// Slot Handlers
+	app.Handle(http.MethodGet, version, "/businesses/{business_id}/slots", hdl.queryAvailableSlots, authen)
 }
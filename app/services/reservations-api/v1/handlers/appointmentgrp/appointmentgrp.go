@@ -3,13 +3,20 @@ package appointmentgrp
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/ameghdadian/service/business/core/agenda"
 	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/waitlist"
 	"github.com/ameghdadian/service/business/data/order"
 	"github.com/ameghdadian/service/business/data/page"
 	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/business/web/v1/auth"
 	"github.com/ameghdadian/service/business/web/v1/mid"
 	"github.com/ameghdadian/service/business/web/v1/response"
 	"github.com/ameghdadian/service/foundation/errs"
@@ -24,12 +31,14 @@ var (
 type handlers struct {
 	aptCore *appointment.Core
 	agdCore *agenda.Core
+	wlCore  *waitlist.Core
 }
 
-func newApp(aptCore *appointment.Core, agdCore *agenda.Core) *handlers {
+func newApp(aptCore *appointment.Core, agdCore *agenda.Core, wlCore *waitlist.Core) *handlers {
 	return &handlers{
 		aptCore: aptCore,
 		agdCore: agdCore,
+		wlCore:  wlCore,
 	}
 }
 
@@ -43,10 +52,15 @@ func (h *handlers) executeUnderTransaction(ctx context.Context) (*handlers, erro
 		if err != nil {
 			return nil, err
 		}
+		wlCore, err := h.wlCore.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
 
 		h = &handlers{
 			aptCore: aptCore,
 			agdCore: agdCore,
+			wlCore:  wlCore,
 		}
 
 		return h, nil
@@ -55,12 +69,25 @@ func (h *handlers) executeUnderTransaction(ctx context.Context) (*handlers, erro
 	return h, nil
 }
 
+// setActor tells the core who makes the changes, to be recorded in the history of
+// the appointment.
+func setActor(ctx context.Context) context.Context {
+	actorID, err := uuid.Parse(auth.GetClaims(ctx).Subject)
+	if err != nil {
+		return ctx
+	}
+
+	return appointment.SetActor(ctx, actorID)
+}
+
 func (h *handlers) create(ctx context.Context, r *http.Request) web.Encoder {
 	h, err := h.executeUnderTransaction(ctx)
 	if err != nil {
 		return errs.New(errs.Internal, err)
 	}
 
+	ctx = setActor(ctx)
+
 	var app AppNewAppointment
 	if err := web.Decode(r, &app); err != nil {
 		return errs.New(errs.InvalidArgument, err)
@@ -71,25 +98,213 @@ func (h *handlers) create(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	// Check whether appointment conforms with daily agenda.
-	if err := h.agdCore.TimeWithinAgendaBoundary(ctx, na.BusinessID, na.ScheduledOn); err != nil {
+	// Check whether appointment conforms with the agendas of the resource, or the business.
+	if err := h.agdCore.TimeWithinAgendaBoundary(ctx, na.BusinessID, na.ResourceID, na.ScheduledOn); err != nil {
 		return errs.NewFieldErrors("scheduled_on", err)
 	}
 
 	apt, err := h.aptCore.Create(ctx, na)
 	if err != nil {
+		switch {
+		case errors.Is(err, appointment.ErrAlreadyReserved):
+			return errs.New(errs.Aborted, err)
+		case errors.Is(err, appointment.ErrSlotFull):
+			return errs.New(errs.ResourceExhausted, err)
+		case errors.Is(err, appointment.ErrServiceMismatch), errors.Is(err, appointment.ErrResourceMismatch),
+			errors.Is(err, appointment.ErrResourceRequired):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, appointment.ErrPastTime), errors.Is(err, appointment.ErrLeadTime),
+			errors.Is(err, appointment.ErrBeyondHorizon):
+			return errs.NewFieldErrors("scheduled_on", err)
+		case errors.Is(err, service.ErrNotFound), errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		}
 		return errs.Newf(errs.Internal, "create: app[%+v]: %s", app, err)
 	}
 
+	// A slot held for a waitlisted customer is theirs to book until the hold ends.
+	// It's checked against the whole of the booked appointment, rolling it back.
+	if err := h.wlCore.CheckHold(ctx, apt); err != nil {
+		if errors.Is(err, waitlist.ErrHeld) {
+			return errs.New(errs.Aborted, err)
+		}
+		return errs.Newf(errs.Internal, "checkhold: appointmentID[%s]: %s", apt.ID, err)
+	}
+
+	if err := h.wlCore.Fulfil(ctx, apt); err != nil {
+		return errs.Newf(errs.Internal, "fulfil: appointmentID[%s]: %s", apt.ID, err)
+	}
+
+	return toAppAppointment(apt)
+}
+
+// hold books the appointment as a hold, keeping its slot from other customers while
+// the customer checks out.
+func (h *handlers) hold(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	ctx = setActor(ctx)
+
+	var app AppNewHold
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	na, err := toCoreNewAppointment(app.AppNewAppointment)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	if err := h.agdCore.TimeWithinAgendaBoundary(ctx, na.BusinessID, na.ResourceID, na.ScheduledOn); err != nil {
+		return errs.NewFieldErrors("scheduled_on", err)
+	}
+
+	apt, err := h.aptCore.Hold(ctx, na, time.Duration(app.Minutes)*time.Minute)
+	if err != nil {
+		switch {
+		case errors.Is(err, appointment.ErrInvalidHold):
+			return errs.NewFieldErrors("minutes", err)
+		case errors.Is(err, appointment.ErrAlreadyReserved):
+			return errs.New(errs.Aborted, err)
+		case errors.Is(err, appointment.ErrSlotFull):
+			return errs.New(errs.ResourceExhausted, err)
+		case errors.Is(err, appointment.ErrServiceMismatch), errors.Is(err, appointment.ErrResourceMismatch),
+			errors.Is(err, appointment.ErrResourceRequired):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, appointment.ErrPastTime), errors.Is(err, appointment.ErrLeadTime),
+			errors.Is(err, appointment.ErrBeyondHorizon):
+			return errs.NewFieldErrors("scheduled_on", err)
+		case errors.Is(err, service.ErrNotFound), errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "hold: app[%+v]: %s", app, err)
+	}
+
+	if err := h.wlCore.CheckHold(ctx, apt); err != nil {
+		if errors.Is(err, waitlist.ErrHeld) {
+			return errs.New(errs.Aborted, err)
+		}
+		return errs.Newf(errs.Internal, "checkhold: appointmentID[%s]: %s", apt.ID, err)
+	}
+
+	if err := h.wlCore.Fulfil(ctx, apt); err != nil {
+		return errs.Newf(errs.Internal, "fulfil: appointmentID[%s]: %s", apt.ID, err)
+	}
+
 	return toAppAppointment(apt)
 }
 
+// confirmHold turns the hold into a regular appointment once the customer checked
+// out.
+func (h *handlers) confirmHold(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	ctx = setActor(ctx)
+
+	apt, err := mid.GetAppointment(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "appointment missing in context: %s", err)
+	}
+
+	capt, err := h.aptCore.ConfirmHold(ctx, apt)
+	if err != nil {
+		switch {
+		case errors.Is(err, appointment.ErrNotHeld), errors.Is(err, appointment.ErrNotOpen),
+			errors.Is(err, appointment.ErrHoldLapsed):
+			return errs.New(errs.FailedPrecondition, err)
+		}
+		return errs.Newf(errs.Internal, "confirmhold: appointmentID[%s]: %s", apt.ID, err)
+	}
+
+	return toAppAppointment(capt)
+}
+
+// createSeries books an appointment for each occurrence of a recurring booking, all
+// or none of them. Every occurrence must conform with the agendas.
+func (h *handlers) createSeries(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	ctx = setActor(ctx)
+
+	var app AppNewSeries
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ns, err := toCoreNewSeries(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	occs, err := h.aptCore.Occurrences(ctx, ns)
+	if err != nil {
+		if errors.Is(err, appointment.ErrTooManyOccurrences) {
+			return errs.NewFieldErrors("rule", err)
+		}
+		return errs.Newf(errs.Internal, "occurrences: app[%+v]: %s", app, err)
+	}
+
+	var fe errs.FieldErrors
+	for _, occ := range occs {
+		if err := h.agdCore.TimeWithinAgendaBoundary(ctx, ns.BusinessID, ns.ResourceID, occ); err != nil {
+			fe.Add("starts_on", fmt.Errorf("occurrence %s: %w", occ.Format(time.RFC3339), err))
+		}
+	}
+	if len(fe) > 0 {
+		return fe.ToError()
+	}
+
+	srs, apts, err := h.aptCore.CreateSeries(ctx, ns)
+	if err != nil {
+		switch {
+		case errors.Is(err, appointment.ErrAlreadyReserved):
+			return errs.New(errs.Aborted, err)
+		case errors.Is(err, appointment.ErrSlotFull):
+			return errs.New(errs.ResourceExhausted, err)
+		case errors.Is(err, appointment.ErrServiceMismatch), errors.Is(err, appointment.ErrResourceMismatch),
+			errors.Is(err, appointment.ErrResourceRequired):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, appointment.ErrPastTime), errors.Is(err, appointment.ErrLeadTime),
+			errors.Is(err, appointment.ErrBeyondHorizon):
+			return errs.NewFieldErrors("starts_on", err)
+		case errors.Is(err, service.ErrNotFound), errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "createseries: app[%+v]: %s", app, err)
+	}
+
+	for _, apt := range apts {
+		if err := h.wlCore.CheckHold(ctx, apt); err != nil {
+			if !errors.Is(err, waitlist.ErrHeld) {
+				return errs.Newf(errs.Internal, "checkhold: appointmentID[%s]: %s", apt.ID, err)
+			}
+			fe.Add("starts_on", fmt.Errorf("occurrence %s: %w", apt.ScheduledOn.Format(time.RFC3339), err))
+		}
+	}
+	if len(fe) > 0 {
+		return fe.ToError()
+	}
+
+	return toAppSeries(srs, apts)
+}
+
 func (h *handlers) update(ctx context.Context, r *http.Request) web.Encoder {
 	h, err := h.executeUnderTransaction(ctx)
 	if err != nil {
 		return errs.New(errs.Internal, err)
 	}
 
+	ctx = setActor(ctx)
+
 	var app AppUpdateAppointment
 	if err := web.Decode(r, &app); err != nil {
 		return errs.New(errs.InvalidArgument, err)
@@ -105,25 +320,154 @@ func (h *handlers) update(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "appointment missing in context: %s", err)
 	}
 
+	party, err := mid.GetParty(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "party missing in context: %s", err)
+	}
+
 	uapt, err := toCoreUpdateAppointment(app)
 	if err != nil {
 		return errs.New(errs.InvalidArgument, err)
 	}
 
-	apt, err = h.aptCore.Update(ctx, apt, uapt)
+	// A rescheduled appointment, or one moved to another resource, must conform with
+	// the agendas of its resource, or the business, like a new one.
+	if uapt.ScheduledOn != nil || uapt.ResourceID != nil {
+		rscID, sch := apt.ResourceID, apt.ScheduledOn
+		if uapt.ResourceID != nil {
+			rscID = *uapt.ResourceID
+		}
+		if uapt.ScheduledOn != nil {
+			sch = *uapt.ScheduledOn
+		}
+
+		if err := h.agdCore.TimeWithinAgendaBoundary(ctx, apt.BusinessID, rscID, sch); err != nil {
+			return errs.NewFieldErrors("scheduled_on", err)
+		}
+	}
+
+	apt, err = h.aptCore.Update(ctx, apt, uapt, party)
 	if err != nil {
+		switch {
+		case errors.Is(err, appointment.ErrAlreadyReserved):
+			return errs.New(errs.Aborted, err)
+		case errors.Is(err, appointment.ErrSlotFull):
+			return errs.New(errs.ResourceExhausted, err)
+		case errors.Is(err, appointment.ErrServiceMismatch), errors.Is(err, appointment.ErrResourceMismatch),
+			errors.Is(err, appointment.ErrResourceRequired):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, appointment.ErrNotOpen), errors.Is(err, appointment.ErrRescheduleWindow):
+			return errs.New(errs.FailedPrecondition, err)
+		case errors.Is(err, appointment.ErrPastTime), errors.Is(err, appointment.ErrLeadTime),
+			errors.Is(err, appointment.ErrBeyondHorizon):
+			return errs.NewFieldErrors("scheduled_on", err)
+		case errors.Is(err, service.ErrNotFound), errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		}
 		return errs.Newf(errs.Internal, "update: appointmentID[%s] uapt[%+v]: %s", aptID, uapt, err)
 	}
 
+	if uapt.ScheduledOn != nil || uapt.ResourceID != nil {
+		if err := h.wlCore.CheckHold(ctx, apt); err != nil {
+			if errors.Is(err, waitlist.ErrHeld) {
+				return errs.New(errs.Aborted, err)
+			}
+			return errs.Newf(errs.Internal, "checkhold: appointmentID[%s]: %s", aptID, err)
+		}
+	}
+
 	return toAppAppointment(apt)
 }
 
+// transition returns a handler giving the appointment the status, on behalf of the
+// party asking for it. The reason for it is optional.
+func (h *handlers) transition(status appointment.Status) web.HandlerFunc {
+	return func(ctx context.Context, r *http.Request) web.Encoder {
+		h, err := h.executeUnderTransaction(ctx)
+		if err != nil {
+			return errs.New(errs.Internal, err)
+		}
+
+		ctx = setActor(ctx)
+
+		var app AppTransition
+		if err := web.Decode(r, &app); err != nil && !errors.Is(err, io.EOF) {
+			return errs.New(errs.InvalidArgument, err)
+		}
+
+		apt, err := mid.GetAppointment(ctx)
+		if err != nil {
+			return errs.Newf(errs.Internal, "appointment missing in context: %s", err)
+		}
+
+		party, err := mid.GetParty(ctx)
+		if err != nil {
+			return errs.Newf(errs.Internal, "party missing in context: %s", err)
+		}
+
+		tapt, err := h.aptCore.Transition(ctx, apt, status, party, app.Reason)
+		if err != nil {
+			switch {
+			case errors.Is(err, appointment.ErrInvalidTransition), errors.Is(err, appointment.ErrCancellationWindow),
+				errors.Is(err, appointment.ErrOnHold):
+				return errs.New(errs.FailedPrecondition, err)
+			case errors.Is(err, appointment.ErrTransitionForbidden):
+				return errs.New(errs.PermissionDenied, err)
+			}
+			return errs.Newf(errs.Internal, "transition: appointmentID[%s] status[%s]: %s", apt.ID, status.Status(), err)
+		}
+
+		return toAppAppointment(tapt)
+	}
+}
+
+// cancelFollowing cancels an occurrence of a series along with the following ones,
+// on behalf of the party asking for it.
+func (h *handlers) cancelFollowing(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	ctx = setActor(ctx)
+
+	var app AppTransition
+	if err := web.Decode(r, &app); err != nil && !errors.Is(err, io.EOF) {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	apt, err := mid.GetAppointment(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "appointment missing in context: %s", err)
+	}
+
+	party, err := mid.GetParty(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "party missing in context: %s", err)
+	}
+
+	apts, err := h.aptCore.CancelFollowing(ctx, apt, party, app.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, appointment.ErrInvalidTransition), errors.Is(err, appointment.ErrCancellationWindow):
+			return errs.New(errs.FailedPrecondition, err)
+		case errors.Is(err, appointment.ErrTransitionForbidden):
+			return errs.New(errs.PermissionDenied, err)
+		}
+		return errs.Newf(errs.Internal, "cancelfollowing: appointmentID[%s]: %s", apt.ID, err)
+	}
+
+	return AppCancelled{Appointments: toAppAppointments(apts)}
+}
+
 func (h *handlers) delete(ctx context.Context, r *http.Request) web.Encoder {
 	h, err := h.executeUnderTransaction(ctx)
 	if err != nil {
 		return errs.New(errs.Internal, err)
 	}
 
+	ctx = setActor(ctx)
+
 	aptID, err := uuid.Parse(web.Param(r, "appointment_id"))
 	if err != nil {
 		return errs.New(errs.InvalidArgument, ErrInvalidID)
@@ -134,13 +478,35 @@ func (h *handlers) delete(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "appointment missing in context: %s", err)
 	}
 
-	if err := h.aptCore.Delete(ctx, apt); err != nil {
+	party, err := mid.GetParty(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "party missing in context: %s", err)
+	}
+
+	if err := h.aptCore.Delete(ctx, apt, party); err != nil {
+		if errors.Is(err, appointment.ErrDeleteForbidden) {
+			return errs.New(errs.PermissionDenied, err)
+		}
 		return errs.Newf(errs.Internal, "delete: appointmentID[%s]: %s", aptID, err)
 	}
 
 	return nil
 }
 
+func (h *handlers) queryHistory(ctx context.Context, r *http.Request) web.Encoder {
+	apt, err := mid.GetAppointment(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "appointment missing in context: %s", err)
+	}
+
+	evts, err := h.aptCore.QueryEvents(ctx, apt.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "queryevents: appointmentID[%s]: %s", apt.ID, err)
+	}
+
+	return toAppHistory(apt.ID, evts)
+}
+
 func (h *handlers) query(ctx context.Context, r *http.Request) web.Encoder {
 	qp, err := parseQueryParams(r)
 	if err != nil {
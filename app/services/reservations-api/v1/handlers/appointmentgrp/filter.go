@@ -18,9 +18,12 @@ func parseQueryParams(r *http.Request) (queryParams, error) {
 		OrderBy:          values.Get("orderBy"),
 		ID:               values.Get("appointment_id"),
 		BusinessID:       values.Get("business_id"),
+		ResourceID:       values.Get("resource_id"),
 		UserID:           values.Get("user_id"),
 		Status:           values.Get("status"),
 		ScheduledOn:      values.Get("scheduled_on"),
+		StartScheduledOn: values.Get("start_scheduled_on"),
+		EndScheduledOn:   values.Get("end_scheduled_on"),
 		StartCreatedDate: values.Get("start_created_date"),
 		EndCreatedDate:   values.Get("end_created_date"),
 	}
@@ -52,6 +55,16 @@ func parseFilter(qp queryParams) (appointment.QueryFilter, error) {
 		}
 	}
 
+	if qp.ResourceID != "" {
+		id, err := uuid.Parse(qp.ResourceID)
+		switch err {
+		case nil:
+			filter.WithResourceID(id)
+		default:
+			fieldErrors.Add("resource_id", err)
+		}
+	}
+
 	if qp.UserID != "" {
 		id, err := uuid.Parse(qp.UserID)
 		switch err {
@@ -83,6 +96,26 @@ func parseFilter(qp queryParams) (appointment.QueryFilter, error) {
 		}
 	}
 
+	if qp.StartScheduledOn != "" {
+		t, err := time.Parse(time.RFC3339, qp.StartScheduledOn)
+		switch err {
+		case nil:
+			filter.WithStartScheduledOn(t)
+		default:
+			fieldErrors.Add("start_scheduled_on", err)
+		}
+	}
+
+	if qp.EndScheduledOn != "" {
+		t, err := time.Parse(time.RFC3339, qp.EndScheduledOn)
+		switch err {
+		case nil:
+			filter.WithEndScheduledOn(t)
+		default:
+			fieldErrors.Add("end_scheduled_on", err)
+		}
+	}
+
 	if qp.StartCreatedDate != "" {
 		t, err := time.Parse(time.RFC3339, qp.StartCreatedDate)
 		switch err {
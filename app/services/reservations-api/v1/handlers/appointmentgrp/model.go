@@ -16,9 +16,12 @@ type queryParams struct {
 	OrderBy          string
 	ID               string
 	BusinessID       string
+	ResourceID       string
 	UserID           string
 	Status           string
 	ScheduledOn      string
+	StartScheduledOn string
+	EndScheduledOn   string
 	StartCreatedDate string
 	EndCreatedDate   string
 }
@@ -27,10 +30,26 @@ type queryParams struct {
 
 type AppAppointment struct {
 	ID          string `json:"id"`
+	SeriesID    string `json:"series_id,omitempty"`
 	BusinessID  string `json:"business_id"`
 	UserID      string `json:"user_id"`
+	ServiceID   string `json:"service_id,omitempty"`
+	ResourceID  string `json:"resource_id,omitempty"`
 	Status      string `json:"string"`
 	ScheduledOn string `json:"scheduled_on"`
+	Duration    int    `json:"duration"`
+	Price       int64  `json:"price"`
+	Currency    string `json:"currency,omitempty"`
+	Capacity    int    `json:"capacity"`
+	Remaining   int    `json:"remaining"`
+	ConfirmedAt string `json:"confirmed_at,omitempty"`
+	CheckedInAt string `json:"checked_in_at,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty"`
+	NoShowAt    string `json:"no_show_at,omitempty"`
+	CancelledAt string `json:"cancelled_at,omitempty"`
+	RejectedAt  string `json:"rejected_at,omitempty"`
+	HeldUntil   string `json:"held_until,omitempty"`
+	FlaggedAt   string `json:"flagged_at,omitempty"`
 	DateCreated string `json:"-"`
 	DateUpdated string `json:"-"`
 }
@@ -41,17 +60,58 @@ func (a AppAppointment) Encode() ([]byte, string, error) {
 }
 
 func toAppAppointment(apt appointment.Appointment) AppAppointment {
+	var svcID string
+	if apt.ServiceID != uuid.Nil {
+		svcID = apt.ServiceID.String()
+	}
+
+	var rscID string
+	if apt.ResourceID != uuid.Nil {
+		rscID = apt.ResourceID.String()
+	}
+
+	var srsID string
+	if apt.SeriesID != uuid.Nil {
+		srsID = apt.SeriesID.String()
+	}
+
 	return AppAppointment{
 		ID:          apt.ID.String(),
+		SeriesID:    srsID,
 		BusinessID:  apt.BusinessID.String(),
 		UserID:      apt.UserID.String(),
+		ServiceID:   svcID,
+		ResourceID:  rscID,
 		Status:      apt.Status.Status(),
 		ScheduledOn: apt.ScheduledOn.Format(time.RFC3339),
+		Duration:    int(apt.Duration / time.Second),
+		Price:       apt.Price,
+		Currency:    apt.Currency.Code(),
+		Capacity:    apt.Capacity,
+		Remaining:   apt.Remaining,
+		ConfirmedAt: optionalTime(apt.ConfirmedAt),
+		CheckedInAt: optionalTime(apt.CheckedInAt),
+		CompletedAt: optionalTime(apt.CompletedAt),
+		NoShowAt:    optionalTime(apt.NoShowAt),
+		CancelledAt: optionalTime(apt.CancelledAt),
+		RejectedAt:  optionalTime(apt.RejectedAt),
+		HeldUntil:   optionalTime(apt.HeldUntil),
+		FlaggedAt:   optionalTime(apt.FlaggedAt),
 		DateCreated: apt.DateCreated.Format(time.RFC3339),
 		DateUpdated: apt.DateUpdated.Format(time.RFC3339),
 	}
 }
 
+// optionalTime formats a time which may be zero, such as the time of a transition
+// not taken place yet, left empty then.
+func optionalTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
 func toAppAppointments(apts []appointment.Appointment) []AppAppointment {
 	apps := make([]AppAppointment, len(apts))
 	for i, apt := range apts {
@@ -66,7 +126,8 @@ func toAppAppointments(apts []appointment.Appointment) []AppAppointment {
 type AppNewAppointment struct {
 	BusinessID  string `json:"business_id" validate:"required,uuid"`
 	UserID      string `json:"user_id" validate:"required,uuid"`
-	Status      string `json:"status" validate:"required"`
+	ServiceID   string `json:"service_id" validate:"required,uuid"`
+	ResourceID  string `json:"resource_id" validate:"omitempty,uuid"`
 	ScheduledOn string `json:"scheduled_on" validate:"required"`
 }
 
@@ -89,9 +150,17 @@ func toCoreNewAppointment(app AppNewAppointment) (appointment.NewAppointment, er
 		return appointment.NewAppointment{}, fmt.Errorf("parsing user id: %w", err)
 	}
 
-	status, err := appointment.ParseStatus(app.Status)
+	svcID, err := uuid.Parse(app.ServiceID)
 	if err != nil {
-		return appointment.NewAppointment{}, fmt.Errorf("parsing status: %w", err)
+		return appointment.NewAppointment{}, fmt.Errorf("parsing service id: %w", err)
+	}
+
+	var rscID uuid.UUID
+	if app.ResourceID != "" {
+		rscID, err = uuid.Parse(app.ResourceID)
+		if err != nil {
+			return appointment.NewAppointment{}, fmt.Errorf("parsing resource id: %w", err)
+		}
 	}
 
 	sch, err := time.Parse(time.RFC3339, app.ScheduledOn)
@@ -102,7 +171,8 @@ func toCoreNewAppointment(app AppNewAppointment) (appointment.NewAppointment, er
 	na := appointment.NewAppointment{
 		BusinessID:  bsnID,
 		UserID:      usrID,
-		Status:      status,
+		ServiceID:   svcID,
+		ResourceID:  rscID,
 		ScheduledOn: sch,
 	}
 
@@ -111,9 +181,28 @@ func toCoreNewAppointment(app AppNewAppointment) (appointment.NewAppointment, er
 
 // -------------------------------------------------------------------------------
 
+// AppNewHold holds a slot for the given number of minutes while the customer checks
+// out.
+type AppNewHold struct {
+	AppNewAppointment
+	Minutes int `json:"minutes" validate:"required,gt=0"`
+}
+
+func (app AppNewHold) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------------------
+
 type AppUpdateAppointment struct {
-	Status      *string `json:"status"`
 	ScheduledOn *string `json:"scheduled_on" validate:"omitempty,datetime"`
+	ServiceID   *string `json:"service_id" validate:"omitempty,uuid"`
+	ResourceID  *string `json:"resource_id" validate:"omitempty,uuid"`
+	Reason      string  `json:"reason" validate:"max=500"`
 }
 
 func (app AppUpdateAppointment) Validate() error {
@@ -125,28 +214,211 @@ func (app AppUpdateAppointment) Validate() error {
 }
 
 func toCoreUpdateAppointment(app AppUpdateAppointment) (appointment.UpdateAppointment, error) {
-	var status appointment.Status
-	if app.Status != nil {
-		var err error
-		status, err = appointment.ParseStatus(*app.Status)
+	var scheduledOn *time.Time
+	if app.ScheduledOn != nil {
+		t, err := time.Parse(time.RFC3339, *app.ScheduledOn)
 		if err != nil {
-			return appointment.UpdateAppointment{}, fmt.Errorf("parsing status: %w", err)
+			return appointment.UpdateAppointment{}, fmt.Errorf("parsing scheduled on: %w", err)
 		}
+		scheduledOn = &t
 	}
 
-	var t time.Time
-	if app.ScheduledOn != nil {
-		var err error
-		t, err = time.Parse(time.RFC3339, *app.ScheduledOn)
+	var svcID *uuid.UUID
+	if app.ServiceID != nil {
+		id, err := uuid.Parse(*app.ServiceID)
 		if err != nil {
-			return appointment.UpdateAppointment{}, fmt.Errorf("parsing scheduled on: %w", err)
+			return appointment.UpdateAppointment{}, fmt.Errorf("parsing service id: %w", err)
 		}
+		svcID = &id
+	}
+
+	var rscID *uuid.UUID
+	if app.ResourceID != nil {
+		id, err := uuid.Parse(*app.ResourceID)
+		if err != nil {
+			return appointment.UpdateAppointment{}, fmt.Errorf("parsing resource id: %w", err)
+		}
+		rscID = &id
 	}
 
 	apt := appointment.UpdateAppointment{
-		Status:      &status,
-		ScheduledOn: &t,
+		ScheduledOn: scheduledOn,
+		ServiceID:   svcID,
+		ResourceID:  rscID,
+		Reason:      app.Reason,
 	}
 
 	return apt, nil
 }
+
+// -------------------------------------------------------------------------------
+
+type AppTransition struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
+func (app AppTransition) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+// AppCancelled lists the appointments cancelled at once, such as an occurrence of a
+// series along with the following ones.
+type AppCancelled struct {
+	Appointments []AppAppointment `json:"appointments"`
+}
+
+func (ac AppCancelled) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(ac)
+	return data, "application/json", err
+}
+
+// -------------------------------------------------------------------------------
+
+// AppSeries is a recurring booking along with the appointment booked for each of
+// its occurrences.
+type AppSeries struct {
+	ID           string           `json:"id"`
+	BusinessID   string           `json:"business_id"`
+	UserID       string           `json:"user_id"`
+	Rule         string           `json:"rule"`
+	StartsOn     string           `json:"starts_on"`
+	Appointments []AppAppointment `json:"appointments"`
+	DateCreated  string           `json:"-"`
+}
+
+func (as AppSeries) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(as)
+	return data, "application/json", err
+}
+
+func toAppSeries(srs appointment.Series, apts []appointment.Appointment) AppSeries {
+	return AppSeries{
+		ID:           srs.ID.String(),
+		BusinessID:   srs.BusinessID.String(),
+		UserID:       srs.UserID.String(),
+		Rule:         srs.Rule.String(),
+		StartsOn:     srs.StartsOn.Format(time.RFC3339),
+		Appointments: toAppAppointments(apts),
+		DateCreated:  srs.DateCreated.Format(time.RFC3339),
+	}
+}
+
+// AppNewSeries books a service from starts_on on, repeating by the rule, such as
+// FREQ=WEEKLY;COUNT=8 for every week at the same time for 8 weeks.
+type AppNewSeries struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	UserID     string `json:"user_id" validate:"required,uuid"`
+	ServiceID  string `json:"service_id" validate:"required,uuid"`
+	ResourceID string `json:"resource_id" validate:"omitempty,uuid"`
+	Rule       string `json:"rule" validate:"required"`
+	StartsOn   string `json:"starts_on" validate:"required"`
+}
+
+func (app AppNewSeries) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toCoreNewSeries(app AppNewSeries) (appointment.NewSeries, error) {
+	bsnID, err := uuid.Parse(app.BusinessID)
+	if err != nil {
+		return appointment.NewSeries{}, fmt.Errorf("parsing business id: %w", err)
+	}
+
+	usrID, err := uuid.Parse(app.UserID)
+	if err != nil {
+		return appointment.NewSeries{}, fmt.Errorf("parsing user id: %w", err)
+	}
+
+	svcID, err := uuid.Parse(app.ServiceID)
+	if err != nil {
+		return appointment.NewSeries{}, fmt.Errorf("parsing service id: %w", err)
+	}
+
+	var rscID uuid.UUID
+	if app.ResourceID != "" {
+		rscID, err = uuid.Parse(app.ResourceID)
+		if err != nil {
+			return appointment.NewSeries{}, fmt.Errorf("parsing resource id: %w", err)
+		}
+	}
+
+	rule, err := appointment.ParseRecurrence(app.Rule)
+	if err != nil {
+		return appointment.NewSeries{}, fmt.Errorf("parsing rule: %w", err)
+	}
+
+	startsOn, err := time.Parse(time.RFC3339, app.StartsOn)
+	if err != nil {
+		return appointment.NewSeries{}, fmt.Errorf("parsing starts on: %w", err)
+	}
+
+	ns := appointment.NewSeries{
+		BusinessID: bsnID,
+		UserID:     usrID,
+		ServiceID:  svcID,
+		ResourceID: rscID,
+		Rule:       rule,
+		StartsOn:   startsOn,
+	}
+
+	return ns, nil
+}
+
+// -------------------------------------------------------------------------------
+
+// AppEvent is an entry in the history of an appointment. Old values are empty for
+// its creation, new values for its deletion.
+type AppEvent struct {
+	ID             string `json:"id"`
+	ActorID        string `json:"actor_id,omitempty"`
+	OldStatus      string `json:"old_status,omitempty"`
+	NewStatus      string `json:"new_status,omitempty"`
+	OldScheduledOn string `json:"old_scheduled_on,omitempty"`
+	NewScheduledOn string `json:"new_scheduled_on,omitempty"`
+	Reason         string `json:"reason,omitempty"`
+	DateCreated    string `json:"date_created"`
+}
+
+type AppHistory struct {
+	AppointmentID string     `json:"appointment_id"`
+	Events        []AppEvent `json:"events"`
+}
+
+func (ah AppHistory) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(ah)
+	return data, "application/json", err
+}
+
+func toAppHistory(aptID uuid.UUID, evts []appointment.Event) AppHistory {
+	items := make([]AppEvent, len(evts))
+	for i, evt := range evts {
+		var actorID string
+		if evt.ActorID != uuid.Nil {
+			actorID = evt.ActorID.String()
+		}
+
+		items[i] = AppEvent{
+			ID:             evt.ID.String(),
+			ActorID:        actorID,
+			OldStatus:      evt.OldStatus.Status(),
+			NewStatus:      evt.NewStatus.Status(),
+			OldScheduledOn: optionalTime(evt.OldScheduledOn),
+			NewScheduledOn: optionalTime(evt.NewScheduledOn),
+			Reason:         evt.Reason,
+			DateCreated:    evt.DateCreated.Format(time.RFC3339),
+		}
+	}
+
+	return AppHistory{
+		AppointmentID: aptID.String(),
+		Events:        items,
+	}
+}
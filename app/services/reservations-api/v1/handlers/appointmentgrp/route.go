@@ -9,8 +9,14 @@ import (
 	"github.com/ameghdadian/service/business/core/appointment/stores/appointmentdb"
 	"github.com/ameghdadian/service/business/core/business"
 	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/resource/stores/resourcedb"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/service/stores/servicedb"
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/business/core/user/stores/userdb"
+	"github.com/ameghdadian/service/business/core/waitlist"
+	"github.com/ameghdadian/service/business/core/waitlist/stores/waitlistdb"
 	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
 	"github.com/ameghdadian/service/business/web/v1/auth"
 	"github.com/ameghdadian/service/business/web/v1/mid"
@@ -33,21 +39,38 @@ func Routes(app *web.App, cfg Config) {
 	const version = "v1"
 
 	aptTask := appointment.NewTask(cfg.TaskClient, cfg.TaskInspector)
+	wlTask := waitlist.NewTask(cfg.TaskClient, cfg.TaskInspector)
 
 	usrCore := user.NewCore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB))
 	bsnCore := business.NewCore(cfg.Log, usrCore, businessdb.NewStore(cfg.Log, cfg.DB))
-	aptCore := appointment.NewCore(cfg.Log, usrCore, bsnCore, appointmentdb.NewStore(cfg.Log, cfg.DB), aptTask)
-	agdCore := agenda.NewCore(cfg.Log, bsnCore, agendadb.NewStore(cfg.Log, cfg.DB))
+	svcCore := service.NewCore(cfg.Log, bsnCore, servicedb.NewStore(cfg.Log, cfg.DB))
+	rscCore := resource.NewCore(cfg.Log, bsnCore, resourcedb.NewStore(cfg.Log, cfg.DB))
+	aptCore := appointment.NewCore(cfg.Log, usrCore, bsnCore, svcCore, rscCore, appointmentdb.NewStore(cfg.Log, cfg.DB), aptTask)
+	agdCore := agenda.NewCore(cfg.Log, bsnCore, rscCore, aptCore, agendadb.NewStore(cfg.Log, cfg.DB))
+	wlCore := waitlist.NewCore(cfg.Log, usrCore, bsnCore, aptCore, waitlistdb.NewStore(cfg.Log, cfg.DB), wlTask)
 
 	authen := mid.Authenticate(cfg.Auth)
 	ruleAdminOnly := mid.Authorize(cfg.Auth, auth.RuleAdminOnly)
 	ruleAuthorizeAppointment := mid.AuthorizeAppointment(cfg.Log, cfg.Auth, aptCore)
+	ruleAuthorizeParty := mid.AuthorizeAppointmentParty(cfg.Log, cfg.Auth, aptCore, bsnCore)
 	tran := mid.ExecuteInTransaction(cfg.Log, db.NewBeginner(cfg.DB))
 
-	hdl := newApp(aptCore, agdCore)
+	hdl := newApp(aptCore, agdCore, wlCore)
 	app.Handle(http.MethodGet, version, "/appointments", hdl.query, authen, ruleAdminOnly)
 	app.Handle(http.MethodGet, version, "/appointments/{appointment_id}", hdl.queryByID, authen, ruleAuthorizeAppointment)
 	app.Handle(http.MethodPost, version, "/appointments", hdl.create, authen, tran)
-	app.Handle(http.MethodPut, version, "/appointments/{appointment_id}", hdl.update, authen, tran, ruleAuthorizeAppointment)
-	app.Handle(http.MethodDelete, version, "/appointments/{appointment_id}", hdl.delete, authen, tran, ruleAuthorizeAppointment)
+	app.Handle(http.MethodPost, version, "/appointment-series", hdl.createSeries, authen, tran)
+	app.Handle(http.MethodPost, version, "/appointment-holds", hdl.hold, authen, tran)
+	app.Handle(http.MethodPut, version, "/appointments/{appointment_id}", hdl.update, authen, tran, ruleAuthorizeParty)
+	app.Handle(http.MethodDelete, version, "/appointments/{appointment_id}", hdl.delete, authen, tran, ruleAuthorizeParty)
+	// Lifecycle Handlers
+	app.Handle(http.MethodPost, version, "/appointments/{appointment_id}/confirm-hold", hdl.confirmHold, authen, tran, ruleAuthorizeAppointment)
+	app.Handle(http.MethodPost, version, "/appointments/{appointment_id}/confirm", hdl.transition(appointment.StatusConfirmed), authen, tran, ruleAuthorizeParty)
+	app.Handle(http.MethodPost, version, "/appointments/{appointment_id}/reject", hdl.transition(appointment.StatusRejected), authen, tran, ruleAuthorizeParty)
+	app.Handle(http.MethodPost, version, "/appointments/{appointment_id}/check-in", hdl.transition(appointment.StatusCheckedIn), authen, tran, ruleAuthorizeParty)
+	app.Handle(http.MethodPost, version, "/appointments/{appointment_id}/complete", hdl.transition(appointment.StatusCompleted), authen, tran, ruleAuthorizeParty)
+	app.Handle(http.MethodPost, version, "/appointments/{appointment_id}/no-show", hdl.transition(appointment.StatusNoShow), authen, tran, ruleAuthorizeParty)
+	app.Handle(http.MethodPost, version, "/appointments/{appointment_id}/cancel", hdl.transition(appointment.StatusCancelled), authen, tran, ruleAuthorizeParty)
+	app.Handle(http.MethodPost, version, "/appointments/{appointment_id}/cancel-following", hdl.cancelFollowing, authen, tran, ruleAuthorizeParty)
+	app.Handle(http.MethodGet, version, "/appointments/{appointment_id}/history", hdl.queryHistory, authen, ruleAuthorizeParty)
 }
@@ -1,24 +1,73 @@
 package appointmentgrp
 
 import (
+	"context"
+	"errors"
+
 	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/appointment/stores/appointmentdb"
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/resource/stores/resourcedb"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/service/stores/servicedb"
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/business/core/user/stores/userdb"
+	"github.com/ameghdadian/service/business/core/waitlist"
+	"github.com/ameghdadian/service/business/core/waitlist/stores/waitlistdb"
+	"github.com/ameghdadian/service/foundation/email"
 	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/sms"
 	"github.com/hibiken/asynq"
 	"github.com/jmoiron/sqlx"
 )
 
 type TaskConfig struct {
-	DB  *sqlx.DB
-	Log *logger.Logger
-	Mux *asynq.ServeMux
+	DB            *sqlx.DB
+	Log           *logger.Logger
+	Mux           *asynq.ServeMux
+	TaskClient    *asynq.Client
+	TaskInspector *asynq.Inspector
+	SMS           sms.Sender
+	Email         email.Sender
+	Unsubscriber  appointment.Unsubscriber
 }
 
 func RegisterTaskHandlers(cfg TaskConfig) {
+	aptTask := appointment.NewTask(cfg.TaskClient, cfg.TaskInspector)
+
 	usrCore := user.NewCore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB))
+	bsnCore := business.NewCore(cfg.Log, usrCore, businessdb.NewStore(cfg.Log, cfg.DB))
+	svcCore := service.NewCore(cfg.Log, bsnCore, servicedb.NewStore(cfg.Log, cfg.DB))
+	rscCore := resource.NewCore(cfg.Log, bsnCore, resourcedb.NewStore(cfg.Log, cfg.DB))
+	aptCore := appointment.NewCore(cfg.Log, usrCore, bsnCore, svcCore, rscCore, appointmentdb.NewStore(cfg.Log, cfg.DB), aptTask)
 
-	th := appointment.NewTaskHandlers(cfg.Log, usrCore)
+	wlTask := waitlist.NewTask(cfg.TaskClient, cfg.TaskInspector)
+	wlCore := waitlist.NewCore(cfg.Log, usrCore, bsnCore, aptCore, waitlistdb.NewStore(cfg.Log, cfg.DB), wlTask)
+
+	th := appointment.NewTaskHandlers(cfg.Log, usrCore, aptCore, cfg.SMS, cfg.Email, cfg.Unsubscriber, waitlistReleaser{wlCore: wlCore})
 
 	cfg.Mux.HandleFunc(appointment.TypeSendSMS, th.HandleSendSMS)
+	cfg.Mux.HandleFunc(appointment.TypeSendConfirmationEmail, th.HandleSendEmail)
+	cfg.Mux.HandleFunc(appointment.TypeSendRescheduleEmail, th.HandleSendEmail)
+	cfg.Mux.HandleFunc(appointment.TypeSendCancellationEmail, th.HandleSendEmail)
+	cfg.Mux.HandleFunc(appointment.TypeSendReminderEmail, th.HandleSendEmail)
+	cfg.Mux.HandleFunc(appointment.TypeSendFlaggedEmail, th.HandleSendEmail)
+	cfg.Mux.HandleFunc(appointment.TypeReleaseHold, th.HandleReleaseHold)
+	cfg.Mux.HandleFunc(appointment.TypeReleaseSlot, th.HandleReleaseSlot)
+}
+
+// waitlistReleaser offers the slots appointments no longer hold to the customers
+// on the waitlist of their business.
+type waitlistReleaser struct {
+	wlCore *waitlist.Core
+}
+
+func (r waitlistReleaser) Release(ctx context.Context, apt appointment.Appointment) error {
+	if _, err := r.wlCore.Release(ctx, apt); err != nil && !errors.Is(err, waitlist.ErrNoneWaiting) {
+		return err
+	}
+
+	return nil
 }
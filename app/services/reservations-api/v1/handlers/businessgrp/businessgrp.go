@@ -74,6 +74,14 @@ func (h *handlers) create(ctx context.Context, r *http.Request) web.Encoder {
 
 	b, err := h.bsnCore.Create(ctx, nb)
 	if err != nil {
+		switch {
+		case errors.Is(err, business.ErrInvalidNotice):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, business.ErrInvalidWindow):
+			return errs.NewFieldErrors("horizon", err)
+		case errors.Is(err, business.ErrInvalidOffset):
+			return errs.NewFieldErrors("reminder_offsets", err)
+		}
 		return errs.Newf(errs.Internal, "create: app[%+v]: %s", app, err)
 	}
 
@@ -96,8 +104,21 @@ func (h *handlers) update(ctx context.Context, r *http.Request) web.Encoder {
 		return errs.Newf(errs.Internal, "business missing in context: %s", err)
 	}
 
-	b, err = h.bsnCore.Update(ctx, b, toCoreUpdateBusiness(app))
+	ub, err := toCoreUpdateBusiness(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	b, err = h.bsnCore.Update(ctx, b, ub)
 	if err != nil {
+		switch {
+		case errors.Is(err, business.ErrInvalidNotice):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, business.ErrInvalidWindow):
+			return errs.NewFieldErrors("horizon", err)
+		case errors.Is(err, business.ErrInvalidOffset):
+			return errs.NewFieldErrors("reminder_offsets", err)
+		}
 		return errs.Newf(errs.Internal, "update: businessID[%s]: app[%+v]: %s", b.ID, app, err)
 	}
 
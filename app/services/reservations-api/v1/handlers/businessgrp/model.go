@@ -23,13 +23,21 @@ type queryParams struct {
 
 // ===================================================================
 
+// AppBusiness is a business. Notices, lead time, horizon and reminder offsets are
+// in seconds.
 type AppBusiness struct {
-	ID          string `json:"id"`
-	OwnerID     string `json:"owner_id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	DateCreated string `json:"-"`
-	DateUpdated string `json:"-"`
+	ID                 string `json:"id"`
+	OwnerID            string `json:"owner_id"`
+	Name               string `json:"name"`
+	Description        string `json:"description"`
+	TimeZone           string `json:"time_zone"`
+	CancellationNotice int    `json:"cancellation_notice"`
+	RescheduleNotice   int    `json:"reschedule_notice"`
+	LeadTime           int    `json:"lead_time"`
+	Horizon            int    `json:"horizon"`
+	ReminderOffsets    []int  `json:"reminder_offsets"`
+	DateCreated        string `json:"-"`
+	DateUpdated        string `json:"-"`
 }
 
 func (ab AppBusiness) Encode() ([]byte, string, error) {
@@ -39,12 +47,18 @@ func (ab AppBusiness) Encode() ([]byte, string, error) {
 
 func toAppBusiness(b business.Business) AppBusiness {
 	return AppBusiness{
-		ID:          b.ID.String(),
-		OwnerID:     b.OwnerID.String(),
-		Name:        b.Name,
-		Description: b.Desc,
-		DateCreated: b.DateCreated.Format(time.RFC3339),
-		DateUpdated: b.DateUpdated.Format(time.RFC3339),
+		ID:                 b.ID.String(),
+		OwnerID:            b.OwnerID.String(),
+		Name:               b.Name,
+		Description:        b.Desc,
+		TimeZone:           b.TimeZone.Name(),
+		CancellationNotice: int(b.CancellationNotice / time.Second),
+		RescheduleNotice:   int(b.RescheduleNotice / time.Second),
+		LeadTime:           int(b.LeadTime / time.Second),
+		Horizon:            int(b.Horizon / time.Second),
+		ReminderOffsets:    toAppSeconds(b.ReminderOffsets),
+		DateCreated:        b.DateCreated.Format(time.RFC3339),
+		DateUpdated:        b.DateUpdated.Format(time.RFC3339),
 	}
 }
 
@@ -60,9 +74,15 @@ func toAppBusinesses(bsns []business.Business) []AppBusiness {
 // ======================================================================
 
 type AppNewBusiness struct {
-	OwnerID     string `json:"owner_id" validate:"required"`
-	Name        string `json:"name" validate:"required"`
-	Description string `json:"description" validate:"required,max=140"`
+	OwnerID            string `json:"owner_id" validate:"required"`
+	Name               string `json:"name" validate:"required"`
+	Description        string `json:"description" validate:"required,max=140"`
+	TimeZone           string `json:"time_zone" validate:"omitempty,timezone"`
+	CancellationNotice int    `json:"cancellation_notice" validate:"gte=0"`
+	RescheduleNotice   int    `json:"reschedule_notice" validate:"gte=0"`
+	LeadTime           int    `json:"lead_time" validate:"gte=0"`
+	Horizon            int    `json:"horizon" validate:"gte=0"`
+	ReminderOffsets    []int  `json:"reminder_offsets" validate:"omitempty,dive,gte=0"`
 }
 
 func (app AppNewBusiness) Validate() error {
@@ -79,10 +99,24 @@ func toCoreNewBusiness(app AppNewBusiness) (business.NewBusiness, error) {
 		return business.NewBusiness{}, fmt.Errorf("parsing ownerID: %w", err)
 	}
 
+	tz := business.TimeZoneUTC
+	if app.TimeZone != "" {
+		tz, err = business.ParseTimeZone(app.TimeZone)
+		if err != nil {
+			return business.NewBusiness{}, fmt.Errorf("parsing time zone: %w", err)
+		}
+	}
+
 	nb := business.NewBusiness{
-		OwnerID: ownerID,
-		Name:    app.Name,
-		Desc:    app.Description,
+		OwnerID:            ownerID,
+		Name:               app.Name,
+		Desc:               app.Description,
+		TimeZone:           tz,
+		CancellationNotice: time.Duration(app.CancellationNotice) * time.Second,
+		RescheduleNotice:   time.Duration(app.RescheduleNotice) * time.Second,
+		LeadTime:           time.Duration(app.LeadTime) * time.Second,
+		Horizon:            time.Duration(app.Horizon) * time.Second,
+		ReminderOffsets:    toCoreSecondsSlice(app.ReminderOffsets),
 	}
 
 	return nb, nil
@@ -91,8 +125,14 @@ func toCoreNewBusiness(app AppNewBusiness) (business.NewBusiness, error) {
 // ======================================================================
 
 type AppUpdateBusiness struct {
-	Name *string `json:"name"`
-	Desc *string `json:"description" validate:"omitempty,max=140"`
+	Name               *string `json:"name"`
+	Desc               *string `json:"description" validate:"omitempty,max=140"`
+	TimeZone           *string `json:"time_zone" validate:"omitempty,timezone"`
+	CancellationNotice *int    `json:"cancellation_notice" validate:"omitempty,gte=0"`
+	RescheduleNotice   *int    `json:"reschedule_notice" validate:"omitempty,gte=0"`
+	LeadTime           *int    `json:"lead_time" validate:"omitempty,gte=0"`
+	Horizon            *int    `json:"horizon" validate:"omitempty,gte=0"`
+	ReminderOffsets    *[]int  `json:"reminder_offsets" validate:"omitempty,dive,gte=0"`
 }
 
 func (app AppUpdateBusiness) Validate() error {
@@ -103,13 +143,66 @@ func (app AppUpdateBusiness) Validate() error {
 	return nil
 }
 
-func toCoreUpdateBusiness(app AppUpdateBusiness) business.UpdateBusiness {
+func toCoreUpdateBusiness(app AppUpdateBusiness) (business.UpdateBusiness, error) {
+	var tz *business.TimeZone
+	if app.TimeZone != nil {
+		t, err := business.ParseTimeZone(*app.TimeZone)
+		if err != nil {
+			return business.UpdateBusiness{}, fmt.Errorf("parsing time zone: %w", err)
+		}
+		tz = &t
+	}
+
 	core := business.UpdateBusiness{
-		Name: app.Name,
-		Desc: app.Desc,
+		Name:               app.Name,
+		Desc:               app.Desc,
+		TimeZone:           tz,
+		CancellationNotice: toCoreSeconds(app.CancellationNotice),
+		RescheduleNotice:   toCoreSeconds(app.RescheduleNotice),
+		LeadTime:           toCoreSeconds(app.LeadTime),
+		Horizon:            toCoreSeconds(app.Horizon),
+	}
+
+	if app.ReminderOffsets != nil {
+		offsets := toCoreSecondsSlice(*app.ReminderOffsets)
+		core.ReminderOffsets = &offsets
+	}
+
+	return core, nil
+}
+
+// toCoreSeconds turns an optional number of seconds into a duration.
+func toCoreSeconds(secs *int) *time.Duration {
+	if secs == nil {
+		return nil
+	}
+
+	d := time.Duration(*secs) * time.Second
+	return &d
+}
+
+// toCoreSecondsSlice turns numbers of seconds into durations, keeping nil apart
+// from empty.
+func toCoreSecondsSlice(secs []int) []time.Duration {
+	if secs == nil {
+		return nil
+	}
+
+	ds := make([]time.Duration, len(secs))
+	for i, s := range secs {
+		ds[i] = time.Duration(s) * time.Second
+	}
+
+	return ds
+}
+
+func toAppSeconds(ds []time.Duration) []int {
+	secs := make([]int, len(ds))
+	for i, d := range ds {
+		secs[i] = int(d / time.Second)
 	}
 
-	return core
+	return secs
 }
 
 // ======================================================================
@@ -0,0 +1,137 @@
+package calendargrp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/foundation/errs"
+	"github.com/ameghdadian/service/foundation/web"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidFeedToken is returned for feeds requested without the feed token of
+// their user, whether the user exists or not.
+var ErrInvalidFeedToken = errors.New("invalid feed token")
+
+type handlers struct {
+	usrCore *user.Core
+	bsnCore *business.Core
+	aptCore *appointment.Core
+}
+
+func newApp(usrCore *user.Core, bsnCore *business.Core, aptCore *appointment.Core) *handlers {
+	return &handlers{
+		usrCore: usrCore,
+		bsnCore: bsnCore,
+		aptCore: aptCore,
+	}
+}
+
+// userFeed renders the appointments of the user, each titled after its business.
+func (h *handlers) userFeed(ctx context.Context, r *http.Request) web.Encoder {
+	userID, err := uuid.Parse(web.Param(r, "user_id"))
+	if err != nil {
+		return errs.NewFieldErrors("user_id", err)
+	}
+
+	usr, err := h.authenticate(ctx, userID, r.URL.Query().Get("token"))
+	if err != nil {
+		return errs.NewError(err)
+	}
+
+	apts, err := h.aptCore.QueryByUserID(ctx, usr.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybyuserid: userID[%s]: %s", usr.ID, err)
+	}
+
+	names := make(map[uuid.UUID]string)
+	for _, apt := range apts {
+		if _, exists := names[apt.BusinessID]; exists {
+			continue
+		}
+
+		bsn, err := h.bsnCore.QueryByID(ctx, apt.BusinessID)
+		if err != nil {
+			return errs.Newf(errs.Internal, "querybyid: businessID[%s]: %s", apt.BusinessID, err)
+		}
+		names[bsn.ID] = bsn.Name
+	}
+
+	return toCalendar(fmt.Sprintf("Appointments of %s", usr.Name), apts, func(apt appointment.Appointment) string {
+		return names[apt.BusinessID]
+	})
+}
+
+// businessFeed renders the appointments booked with the business, each titled after
+// its customer. It takes the feed token of the owner of the business.
+func (h *handlers) businessFeed(ctx context.Context, r *http.Request) web.Encoder {
+	bsnID, err := uuid.Parse(web.Param(r, "business_id"))
+	if err != nil {
+		return errs.NewFieldErrors("business_id", err)
+	}
+
+	bsn, err := h.bsnCore.QueryByID(ctx, bsnID)
+	if err != nil {
+		if errors.Is(err, business.ErrNotFound) {
+			return errs.New(errs.Unauthenticated, ErrInvalidFeedToken)
+		}
+		return errs.Newf(errs.Internal, "querybyid: businessID[%s]: %s", bsnID, err)
+	}
+
+	if _, err := h.authenticate(ctx, bsn.OwnerID, r.URL.Query().Get("token")); err != nil {
+		return errs.NewError(err)
+	}
+
+	apts, err := h.aptCore.QueryByBusinessID(ctx, bsn.ID)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querybybusinessid: businessID[%s]: %s", bsn.ID, err)
+	}
+
+	var userIDs []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, apt := range apts {
+		if !seen[apt.UserID] {
+			seen[apt.UserID] = true
+			userIDs = append(userIDs, apt.UserID)
+		}
+	}
+
+	names := make(map[uuid.UUID]string)
+	if len(userIDs) > 0 {
+		usrs, err := h.usrCore.QueryByIDs(ctx, userIDs)
+		if err != nil {
+			return errs.Newf(errs.Internal, "querybyids: %s", err)
+		}
+
+		for _, usr := range usrs {
+			names[usr.ID] = usr.Name
+		}
+	}
+
+	return toCalendar(bsn.Name, apts, func(apt appointment.Appointment) string {
+		return names[apt.UserID]
+	})
+}
+
+// authenticate returns the user of the feed, checking the token is their feed
+// token. Disabled users have no feeds.
+func (h *handlers) authenticate(ctx context.Context, userID uuid.UUID, token string) (user.User, error) {
+	usr, err := h.usrCore.QueryByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			return user.User{}, errs.New(errs.Unauthenticated, ErrInvalidFeedToken)
+		}
+		return user.User{}, errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+	}
+
+	if !usr.Enabled || !usr.ValidFeedToken(token) {
+		return user.User{}, errs.New(errs.Unauthenticated, ErrInvalidFeedToken)
+	}
+
+	return usr, nil
+}
@@ -0,0 +1,55 @@
+package calendargrp
+
+import (
+	"time"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/foundation/ical"
+)
+
+const prodID = "-//ameghdadian//Reservations//EN"
+
+// toCalendar renders the appointments as events, titled by the given function.
+// Event UIDs derive from the appointment IDs, so calendar apps update appointments
+// in place as they change.
+func toCalendar(name string, apts []appointment.Appointment, title func(appointment.Appointment) string) ical.Calendar {
+	events := make([]ical.Event, len(apts))
+	for i, apt := range apts {
+		events[i] = ical.Event{
+			UID:          apt.ID.String() + "@reservations",
+			Start:        apt.ScheduledOn,
+			End:          toEnd(apt),
+			Summary:      title(apt),
+			Description:  "Status: " + apt.Status.Status(),
+			Status:       toStatus(apt.Status),
+			LastModified: apt.DateUpdated,
+		}
+	}
+
+	return ical.Calendar{
+		ProdID: prodID,
+		Name:   name,
+		Events: events,
+	}
+}
+
+// toEnd returns when the appointment ends. Appointments booked before services
+// had durations take an hour.
+func toEnd(apt appointment.Appointment) time.Time {
+	if apt.Duration <= 0 {
+		return apt.ScheduledOn.Add(time.Hour)
+	}
+
+	return apt.EndsOn()
+}
+
+func toStatus(s appointment.Status) ical.Status {
+	switch {
+	case s.Equal(appointment.StatusPending):
+		return ical.StatusTentative
+	case s.Equal(appointment.StatusCancelled), s.Equal(appointment.StatusRejected):
+		return ical.StatusCancelled
+	}
+
+	return ical.StatusConfirmed
+}
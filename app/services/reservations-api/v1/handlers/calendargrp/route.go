@@ -0,0 +1,47 @@
+package calendargrp
+
+import (
+	"net/http"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/appointment/stores/appointmentdb"
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/resource/stores/resourcedb"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/service/stores/servicedb"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/core/user/stores/userdb"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/web"
+	"github.com/hibiken/asynq"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Build         string
+	Log           *logger.Logger
+	DB            *sqlx.DB
+	TaskClient    *asynq.Client
+	TaskInspector *asynq.Inspector
+}
+
+// Routes adds the calendar feeds. Calendar apps can't send bearer tokens, so the
+// feeds authenticate with the feed token of the user in the token query parameter
+// instead.
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	aptTask := appointment.NewTask(cfg.TaskClient, cfg.TaskInspector)
+
+	usrCore := user.NewCore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB))
+	bsnCore := business.NewCore(cfg.Log, usrCore, businessdb.NewStore(cfg.Log, cfg.DB))
+	svcCore := service.NewCore(cfg.Log, bsnCore, servicedb.NewStore(cfg.Log, cfg.DB))
+	rscCore := resource.NewCore(cfg.Log, bsnCore, resourcedb.NewStore(cfg.Log, cfg.DB))
+	aptCore := appointment.NewCore(cfg.Log, usrCore, bsnCore, svcCore, rscCore, appointmentdb.NewStore(cfg.Log, cfg.DB), aptTask)
+
+	hdl := newApp(usrCore, bsnCore, aptCore)
+	app.Handle(http.MethodGet, version, "/users/{user_id}/calendar.ics", hdl.userFeed)
+	app.Handle(http.MethodGet, version, "/businesses/{business_id}/calendar.ics", hdl.businessFeed)
+}
@@ -0,0 +1,128 @@
+package resourcegrp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/foundation/errs"
+	"github.com/google/uuid"
+)
+
+type queryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	ID               string
+	BusinessID       string
+	Name             string
+	Kind             string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+// ===================================================================
+
+type AppResource struct {
+	ID          string `json:"id"`
+	BusinessID  string `json:"business_id"`
+	Name        string `json:"name"`
+	Kind        string `json:"kind"`
+	DateCreated string `json:"-"`
+	DateUpdated string `json:"-"`
+}
+
+func (ar AppResource) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(ar)
+	return data, "application/json", err
+}
+
+func toAppResource(rsc resource.Resource) AppResource {
+	return AppResource{
+		ID:          rsc.ID.String(),
+		BusinessID:  rsc.BusinessID.String(),
+		Name:        rsc.Name,
+		Kind:        rsc.Kind.Name(),
+		DateCreated: rsc.DateCreated.Format(time.RFC3339),
+		DateUpdated: rsc.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppResources(rscs []resource.Resource) []AppResource {
+	items := make([]AppResource, len(rscs))
+	for i, rsc := range rscs {
+		items[i] = toAppResource(rsc)
+	}
+
+	return items
+}
+
+// ======================================================================
+
+type AppNewResource struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	Name       string `json:"name" validate:"required,max=100"`
+	Kind       string `json:"kind" validate:"required"`
+}
+
+func (app AppNewResource) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toCoreNewResource(app AppNewResource) (resource.NewResource, error) {
+	bsnID, err := uuid.Parse(app.BusinessID)
+	if err != nil {
+		return resource.NewResource{}, fmt.Errorf("parsing business id: %w", err)
+	}
+
+	kind, err := resource.ParseKind(app.Kind)
+	if err != nil {
+		return resource.NewResource{}, fmt.Errorf("parsing kind: %w", err)
+	}
+
+	nr := resource.NewResource{
+		BusinessID: bsnID,
+		Name:       app.Name,
+		Kind:       kind,
+	}
+
+	return nr, nil
+}
+
+// ======================================================================
+
+type AppUpdateResource struct {
+	Name *string `json:"name" validate:"omitempty,max=100"`
+	Kind *string `json:"kind"`
+}
+
+func (app AppUpdateResource) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toCoreUpdateResource(app AppUpdateResource) (resource.UpdateResource, error) {
+	var kind *resource.Kind
+	if app.Kind != nil {
+		k, err := resource.ParseKind(*app.Kind)
+		if err != nil {
+			return resource.UpdateResource{}, fmt.Errorf("parsing kind: %w", err)
+		}
+		kind = &k
+	}
+
+	ur := resource.UpdateResource{
+		Name: app.Name,
+		Kind: kind,
+	}
+
+	return ur, nil
+}
@@ -0,0 +1,12 @@
+package resourcegrp
+
+import (
+	"github.com/ameghdadian/service/business/core/resource"
+)
+
+var orderByFields = map[string]string{
+	"resource_id": resource.OrderByID,
+	"business_id": resource.OrderByBusinessID,
+	"name":        resource.OrderByName,
+	"kind":        resource.OrderByKind,
+}
@@ -0,0 +1,206 @@
+package resourcegrp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/data/order"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/business/web/v1/auth"
+	"github.com/ameghdadian/service/business/web/v1/mid"
+	"github.com/ameghdadian/service/business/web/v1/response"
+	"github.com/ameghdadian/service/foundation/errs"
+	"github.com/ameghdadian/service/foundation/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidID = errors.New("ID is not in its proper format")
+)
+
+type handlers struct {
+	rscCore *resource.Core
+	bsnCore *business.Core
+}
+
+func newApp(rscCore *resource.Core, bsnCore *business.Core) *handlers {
+	return &handlers{
+		rscCore: rscCore,
+		bsnCore: bsnCore,
+	}
+}
+
+func (h *handlers) executeUnderTransaction(ctx context.Context) (*handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		rscCore, err := h.rscCore.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		bsnCore, err := h.bsnCore.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		h = &handlers{
+			rscCore: rscCore,
+			bsnCore: bsnCore,
+		}
+
+		return h, nil
+	}
+
+	return h, nil
+}
+
+func (h *handlers) create(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	var app AppNewResource
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	nr, err := toCoreNewResource(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	bsn, err := h.bsnCore.QueryByID(ctx, nr.BusinessID)
+	if err != nil {
+		switch {
+		case errors.Is(err, business.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "create: app[%+v]: %s", app, err)
+		}
+	}
+
+	usrClaimID := auth.GetClaims(ctx).Subject
+	if usrClaimID != bsn.OwnerID.String() {
+		return errs.Newf(errs.PermissionDenied, "you don't have the persmission for this action: %s", auth.ErrForbidden)
+	}
+
+	rsc, err := h.rscCore.Create(ctx, nr)
+	if err != nil {
+		if errors.Is(err, resource.ErrUniqueName) {
+			return errs.New(errs.Aborted, err)
+		}
+		return errs.Newf(errs.Internal, "create: app[%+v]: %s", app, err)
+	}
+
+	return toAppResource(rsc)
+}
+
+func (h *handlers) update(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	var app AppUpdateResource
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	rsc, err := mid.GetResource(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "resource missing in context: %s", err)
+	}
+
+	ur, err := toCoreUpdateResource(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	rsc, err = h.rscCore.Update(ctx, rsc, ur)
+	if err != nil {
+		if errors.Is(err, resource.ErrUniqueName) {
+			return errs.New(errs.Aborted, err)
+		}
+		return errs.Newf(errs.Internal, "update: resourceID[%s]: app[%+v]: %s", rsc.ID, app, err)
+	}
+
+	return toAppResource(rsc)
+}
+
+func (h *handlers) delete(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	rsc, err := mid.GetResource(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "resource missing in context: %s", err)
+	}
+
+	if err := h.rscCore.Delete(ctx, rsc); err != nil {
+		if errors.Is(err, resource.ErrInUse) {
+			return errs.New(errs.FailedPrecondition, err)
+		}
+		return errs.Newf(errs.Internal, "delete: resourceID[%s]: %s", rsc.ID, err)
+	}
+
+	return nil
+}
+
+func (h *handlers) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, resource.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	rscs, err := h.rscCore.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := h.rscCore.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return response.NewPageDocument(toAppResources(rscs), total, page)
+}
+
+func (h *handlers) queryByID(ctx context.Context, r *http.Request) web.Encoder {
+	rscID, err := uuid.Parse(web.Param(r, "resource_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, ErrInvalidID)
+	}
+
+	rsc, err := h.rscCore.QueryByID(ctx, rscID)
+	if err != nil {
+		switch {
+		case errors.Is(err, resource.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "querybyid: resourceID[%s]: %s", rscID, err)
+		}
+	}
+
+	return toAppResource(rsc)
+}
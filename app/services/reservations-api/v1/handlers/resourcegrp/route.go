@@ -0,0 +1,44 @@
+package resourcegrp
+
+import (
+	"net/http"
+
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/resource/stores/resourcedb"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/core/user/stores/userdb"
+	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
+	"github.com/ameghdadian/service/business/web/v1/auth"
+	"github.com/ameghdadian/service/business/web/v1/mid"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	Auth  *auth.Auth
+	DB    *sqlx.DB
+}
+
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	usrCore := user.NewCore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB))
+	bsnCore := business.NewCore(cfg.Log, usrCore, businessdb.NewStore(cfg.Log, cfg.DB))
+	rscCore := resource.NewCore(cfg.Log, bsnCore, resourcedb.NewStore(cfg.Log, cfg.DB))
+
+	authen := mid.Authenticate(cfg.Auth)
+	ruleAuthorizeResource := mid.AuthorizeResource(cfg.Log, cfg.Auth, rscCore, bsnCore)
+	tran := mid.ExecuteInTransaction(cfg.Log, db.NewBeginner(cfg.DB))
+
+	hdl := newApp(rscCore, bsnCore)
+	app.Handle(http.MethodGet, version, "/resources", hdl.query, authen)
+	app.Handle(http.MethodGet, version, "/resources/{resource_id}", hdl.queryByID, authen)
+	app.Handle(http.MethodPost, version, "/resources", hdl.create, authen, tran)
+	app.Handle(http.MethodPut, version, "/resources/{resource_id}", hdl.update, authen, tran, ruleAuthorizeResource)
+	app.Handle(http.MethodDelete, version, "/resources/{resource_id}", hdl.delete, authen, tran, ruleAuthorizeResource)
+}
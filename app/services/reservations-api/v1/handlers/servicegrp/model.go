@@ -0,0 +1,176 @@
+package servicegrp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/foundation/errs"
+	"github.com/google/uuid"
+)
+
+type queryParams struct {
+	Page             string
+	Rows             string
+	OrderBy          string
+	ID               string
+	BusinessID       string
+	Name             string
+	StartCreatedDate string
+	EndCreatedDate   string
+}
+
+// ===================================================================
+
+// AppService is a service of the catalog. Duration is in seconds and price is in
+// the minor unit of the currency. Capacity is the number of people a slot holds.
+// Lead time and horizon, in seconds, are zero when those of the business apply.
+type AppService struct {
+	ID          string `json:"id"`
+	BusinessID  string `json:"business_id"`
+	Name        string `json:"name"`
+	Duration    int    `json:"duration"`
+	Price       int64  `json:"price"`
+	Currency    string `json:"currency"`
+	Capacity    int    `json:"capacity"`
+	LeadTime    int    `json:"lead_time"`
+	Horizon     int    `json:"horizon"`
+	DateCreated string `json:"-"`
+	DateUpdated string `json:"-"`
+}
+
+func (as AppService) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(as)
+	return data, "application/json", err
+}
+
+func toAppService(svc service.Service) AppService {
+	return AppService{
+		ID:          svc.ID.String(),
+		BusinessID:  svc.BusinessID.String(),
+		Name:        svc.Name,
+		Duration:    int(svc.Duration / time.Second),
+		Price:       svc.Price,
+		Currency:    svc.Currency.Code(),
+		Capacity:    svc.Capacity,
+		LeadTime:    int(svc.LeadTime / time.Second),
+		Horizon:     int(svc.Horizon / time.Second),
+		DateCreated: svc.DateCreated.Format(time.RFC3339),
+		DateUpdated: svc.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+func toAppServices(svcs []service.Service) []AppService {
+	items := make([]AppService, len(svcs))
+	for i, svc := range svcs {
+		items[i] = toAppService(svc)
+	}
+
+	return items
+}
+
+// ======================================================================
+
+type AppNewService struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	Name       string `json:"name" validate:"required,max=100"`
+	Duration   int    `json:"duration" validate:"required,gt=0,lte=86400"`
+	Price      int64  `json:"price" validate:"gte=0"`
+	Currency   string `json:"currency" validate:"required,iso4217"`
+	Capacity   int    `json:"capacity" validate:"omitempty,gt=0"`
+	LeadTime   int    `json:"lead_time" validate:"gte=0"`
+	Horizon    int    `json:"horizon" validate:"gte=0"`
+}
+
+func (app AppNewService) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toCoreNewService(app AppNewService) (service.NewService, error) {
+	bsnID, err := uuid.Parse(app.BusinessID)
+	if err != nil {
+		return service.NewService{}, fmt.Errorf("parsing business id: %w", err)
+	}
+
+	cur, err := service.ParseCurrency(app.Currency)
+	if err != nil {
+		return service.NewService{}, fmt.Errorf("parsing currency: %w", err)
+	}
+
+	// Services hold one person at a time unless told otherwise.
+	capacity := app.Capacity
+	if capacity == 0 {
+		capacity = 1
+	}
+
+	ns := service.NewService{
+		BusinessID: bsnID,
+		Name:       app.Name,
+		Duration:   time.Duration(app.Duration) * time.Second,
+		Price:      app.Price,
+		Currency:   cur,
+		Capacity:   capacity,
+		LeadTime:   time.Duration(app.LeadTime) * time.Second,
+		Horizon:    time.Duration(app.Horizon) * time.Second,
+	}
+
+	return ns, nil
+}
+
+// ======================================================================
+
+type AppUpdateService struct {
+	Name     *string `json:"name" validate:"omitempty,max=100"`
+	Duration *int    `json:"duration" validate:"omitempty,gt=0,lte=86400"`
+	Price    *int64  `json:"price" validate:"omitempty,gte=0"`
+	Currency *string `json:"currency" validate:"omitempty,iso4217"`
+	Capacity *int    `json:"capacity" validate:"omitempty,gt=0"`
+	LeadTime *int    `json:"lead_time" validate:"omitempty,gte=0"`
+	Horizon  *int    `json:"horizon" validate:"omitempty,gte=0"`
+}
+
+func (app AppUpdateService) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func toCoreUpdateService(app AppUpdateService) (service.UpdateService, error) {
+	var cur *service.Currency
+	if app.Currency != nil {
+		c, err := service.ParseCurrency(*app.Currency)
+		if err != nil {
+			return service.UpdateService{}, fmt.Errorf("parsing currency: %w", err)
+		}
+		cur = &c
+	}
+
+	us := service.UpdateService{
+		Name:     app.Name,
+		Duration: toCoreSeconds(app.Duration),
+		Price:    app.Price,
+		Currency: cur,
+		Capacity: app.Capacity,
+		LeadTime: toCoreSeconds(app.LeadTime),
+		Horizon:  toCoreSeconds(app.Horizon),
+	}
+
+	return us, nil
+}
+
+// toCoreSeconds turns an optional number of seconds into a duration.
+func toCoreSeconds(secs *int) *time.Duration {
+	if secs == nil {
+		return nil
+	}
+
+	d := time.Duration(*secs) * time.Second
+	return &d
+}
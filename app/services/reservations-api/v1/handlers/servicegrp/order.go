@@ -0,0 +1,13 @@
+package servicegrp
+
+import (
+	"github.com/ameghdadian/service/business/core/service"
+)
+
+var orderByFields = map[string]string{
+	"service_id":  service.OrderByID,
+	"business_id": service.OrderByBusinessID,
+	"name":        service.OrderByName,
+	"duration":    service.OrderByDuration,
+	"price":       service.OrderByPrice,
+}
@@ -0,0 +1,44 @@
+package servicegrp
+
+import (
+	"net/http"
+
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/service/stores/servicedb"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/core/user/stores/userdb"
+	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
+	"github.com/ameghdadian/service/business/web/v1/auth"
+	"github.com/ameghdadian/service/business/web/v1/mid"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/web"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Build string
+	Log   *logger.Logger
+	Auth  *auth.Auth
+	DB    *sqlx.DB
+}
+
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	usrCore := user.NewCore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB))
+	bsnCore := business.NewCore(cfg.Log, usrCore, businessdb.NewStore(cfg.Log, cfg.DB))
+	svcCore := service.NewCore(cfg.Log, bsnCore, servicedb.NewStore(cfg.Log, cfg.DB))
+
+	authen := mid.Authenticate(cfg.Auth)
+	ruleAuthorizeService := mid.AuthorizeService(cfg.Log, cfg.Auth, svcCore, bsnCore)
+	tran := mid.ExecuteInTransaction(cfg.Log, db.NewBeginner(cfg.DB))
+
+	hdl := newApp(svcCore, bsnCore)
+	app.Handle(http.MethodGet, version, "/services", hdl.query, authen)
+	app.Handle(http.MethodGet, version, "/services/{service_id}", hdl.queryByID, authen)
+	app.Handle(http.MethodPost, version, "/services", hdl.create, authen, tran)
+	app.Handle(http.MethodPut, version, "/services/{service_id}", hdl.update, authen, tran, ruleAuthorizeService)
+	app.Handle(http.MethodDelete, version, "/services/{service_id}", hdl.delete, authen, tran, ruleAuthorizeService)
+}
@@ -0,0 +1,213 @@
+package servicegrp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/data/order"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/business/web/v1/auth"
+	"github.com/ameghdadian/service/business/web/v1/mid"
+	"github.com/ameghdadian/service/business/web/v1/response"
+	"github.com/ameghdadian/service/foundation/errs"
+	"github.com/ameghdadian/service/foundation/web"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidID = errors.New("ID is not in its proper format")
+)
+
+type handlers struct {
+	svcCore *service.Core
+	bsnCore *business.Core
+}
+
+func newApp(svcCore *service.Core, bsnCore *business.Core) *handlers {
+	return &handlers{
+		svcCore: svcCore,
+		bsnCore: bsnCore,
+	}
+}
+
+func (h *handlers) executeUnderTransaction(ctx context.Context) (*handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		svcCore, err := h.svcCore.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		bsnCore, err := h.bsnCore.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		h = &handlers{
+			svcCore: svcCore,
+			bsnCore: bsnCore,
+		}
+
+		return h, nil
+	}
+
+	return h, nil
+}
+
+func (h *handlers) create(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	var app AppNewService
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ns, err := toCoreNewService(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	bsn, err := h.bsnCore.QueryByID(ctx, ns.BusinessID)
+	if err != nil {
+		switch {
+		case errors.Is(err, business.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "create: app[%+v]: %s", app, err)
+		}
+	}
+
+	usrClaimID := auth.GetClaims(ctx).Subject
+	if usrClaimID != bsn.OwnerID.String() {
+		return errs.Newf(errs.PermissionDenied, "you don't have the persmission for this action: %s", auth.ErrForbidden)
+	}
+
+	svc, err := h.svcCore.Create(ctx, ns)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUniqueName):
+			return errs.New(errs.Aborted, err)
+		case errors.Is(err, service.ErrInvalidDuration), errors.Is(err, service.ErrInvalidPrice), errors.Is(err, service.ErrInvalidCapacity):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, service.ErrInvalidWindow):
+			return errs.NewFieldErrors("horizon", err)
+		}
+		return errs.Newf(errs.Internal, "create: app[%+v]: %s", app, err)
+	}
+
+	return toAppService(svc)
+}
+
+func (h *handlers) update(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	var app AppUpdateService
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	svc, err := mid.GetService(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "service missing in context: %s", err)
+	}
+
+	us, err := toCoreUpdateService(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	svc, err = h.svcCore.Update(ctx, svc, us)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrUniqueName):
+			return errs.New(errs.Aborted, err)
+		case errors.Is(err, service.ErrInvalidDuration), errors.Is(err, service.ErrInvalidPrice), errors.Is(err, service.ErrInvalidCapacity):
+			return errs.New(errs.InvalidArgument, err)
+		case errors.Is(err, service.ErrInvalidWindow):
+			return errs.NewFieldErrors("horizon", err)
+		}
+		return errs.Newf(errs.Internal, "update: serviceID[%s]: app[%+v]: %s", svc.ID, app, err)
+	}
+
+	return toAppService(svc)
+}
+
+func (h *handlers) delete(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	svc, err := mid.GetService(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "service missing in context: %s", err)
+	}
+
+	if err := h.svcCore.Delete(ctx, svc); err != nil {
+		return errs.Newf(errs.Internal, "delete: serviceID[%s]: %s", svc.ID, err)
+	}
+
+	return nil
+}
+
+func (h *handlers) query(ctx context.Context, r *http.Request) web.Encoder {
+	qp, err := parseQueryParams(r)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	page, err := page.Parse(qp.Page, qp.Rows)
+	if err != nil {
+		return errs.NewFieldErrors("page", err)
+	}
+
+	filter, err := parseFilter(qp)
+	if err != nil {
+		return err.(*errs.Error)
+	}
+
+	orderBy, err := order.Parse(orderByFields, qp.OrderBy, service.DefaultOrderBy)
+	if err != nil {
+		return errs.NewFieldErrors("order", err)
+	}
+
+	svcs, err := h.svcCore.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return errs.Newf(errs.Internal, "query: %s", err)
+	}
+
+	total, err := h.svcCore.Count(ctx, filter)
+	if err != nil {
+		return errs.Newf(errs.Internal, "count: %s", err)
+	}
+
+	return response.NewPageDocument(toAppServices(svcs), total, page)
+}
+
+func (h *handlers) queryByID(ctx context.Context, r *http.Request) web.Encoder {
+	svcID, err := uuid.Parse(web.Param(r, "service_id"))
+	if err != nil {
+		return errs.New(errs.InvalidArgument, ErrInvalidID)
+	}
+
+	svc, err := h.svcCore.QueryByID(ctx, svcID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "querybyid: serviceID[%s]: %s", svcID, err)
+		}
+	}
+
+	return toAppService(svc)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/foundation/errs"
+	"golang.org/x/text/language"
 )
 
 type queryParams struct {
@@ -171,3 +172,133 @@ func toCoreUpdateUser(app AppUpdateUser) (user.UpdateUser, error) {
 
 	return nu, nil
 }
+
+// =========================================================
+
+// AppQuietHours are the daily quiet hours of a user, as wall clock times in the
+// 15:04 format.
+type AppQuietHours struct {
+	Start string `json:"start" validate:"required"`
+	End   string `json:"end" validate:"required"`
+}
+
+// AppPreferences are how a user likes to be notified. Quiet hours are null when
+// there are none.
+type AppPreferences struct {
+	Channels   []string       `json:"channels"`
+	QuietHours *AppQuietHours `json:"quiet_hours"`
+	Language   string         `json:"language"`
+}
+
+func (app AppPreferences) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+func toAppPreferences(prefs user.Preferences) AppPreferences {
+	channels := make([]string, len(prefs.Channels))
+	for i, ch := range prefs.Channels {
+		channels[i] = ch.Name()
+	}
+
+	var quiet *AppQuietHours
+	if !prefs.QuietHours.IsZero() {
+		quiet = &AppQuietHours{
+			Start: toAppClock(prefs.QuietHours.Start),
+			End:   toAppClock(prefs.QuietHours.End),
+		}
+	}
+
+	return AppPreferences{
+		Channels:   channels,
+		QuietHours: quiet,
+		Language:   prefs.Language.String(),
+	}
+}
+
+// AppFeedToken is the secret token subscribing to the calendar feeds of a user,
+// passed as the token query parameter of the feeds.
+type AppFeedToken struct {
+	Token string `json:"token"`
+}
+
+func (app AppFeedToken) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(app)
+	return data, "application/json", err
+}
+
+// AppUpdatePreferences changes the given preferences. An empty list of channels
+// turns notifications off, and quiet hours starting and ending at once remove them.
+type AppUpdatePreferences struct {
+	Channels   []string       `json:"channels"`
+	QuietHours *AppQuietHours `json:"quiet_hours"`
+	Language   *string        `json:"language"`
+}
+
+func (app AppUpdatePreferences) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toCoreUpdatePreferences(app AppUpdatePreferences) (user.UpdatePreferences, error) {
+	var channels []user.Channel
+	if app.Channels != nil {
+		channels = make([]user.Channel, len(app.Channels))
+		for i, chStr := range app.Channels {
+			ch, err := user.ParseChannel(chStr)
+			if err != nil {
+				return user.UpdatePreferences{}, fmt.Errorf("parsing channel: %w", err)
+			}
+			channels[i] = ch
+		}
+	}
+
+	var quiet *user.QuietHours
+	if app.QuietHours != nil {
+		start, err := toCoreClock(app.QuietHours.Start)
+		if err != nil {
+			return user.UpdatePreferences{}, fmt.Errorf("parsing quiet hours start: %w", err)
+		}
+
+		end, err := toCoreClock(app.QuietHours.End)
+		if err != nil {
+			return user.UpdatePreferences{}, fmt.Errorf("parsing quiet hours end: %w", err)
+		}
+
+		quiet = &user.QuietHours{Start: start, End: end}
+	}
+
+	var lang *language.Tag
+	if app.Language != nil {
+		tag, err := language.Parse(*app.Language)
+		if err != nil {
+			return user.UpdatePreferences{}, fmt.Errorf("parsing language: %w", err)
+		}
+		lang = &tag
+	}
+
+	up := user.UpdatePreferences{
+		Channels:   channels,
+		QuietHours: quiet,
+		Language:   lang,
+	}
+
+	return up, nil
+}
+
+// toCoreClock turns a wall clock time into its offset from midnight.
+func toCoreClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func toAppClock(d time.Duration) string {
+	return time.Time{}.Add(d).Format("15:04")
+}
@@ -36,4 +36,9 @@ func Routes(app *web.App, cfg Config) {
 	app.Handle(http.MethodPost, version, "/users", hdl.create, tran)
 	app.Handle(http.MethodPut, version, "/users/{user_id}", hdl.update, authen, ruleAdminOrSubject, tran)
 	app.Handle(http.MethodDelete, version, "/users/{user_id}", hdl.delete, authen, ruleAdminOrSubject, tran)
+	app.Handle(http.MethodGet, version, "/users/{user_id}/preferences", hdl.queryPreferences, authen, ruleAdminOrSubject)
+	app.Handle(http.MethodPut, version, "/users/{user_id}/preferences", hdl.updatePreferences, authen, ruleAdminOrSubject, tran)
+	app.Handle(http.MethodPost, version, "/users/{user_id}/feed-token", hdl.rotateFeedToken, authen, ruleAdminOrSubject, tran)
+	app.Handle(http.MethodGet, version, "/unsubscribe", hdl.unsubscribe, tran)
+	app.Handle(http.MethodPost, version, "/unsubscribe", hdl.unsubscribe, tran)
 }
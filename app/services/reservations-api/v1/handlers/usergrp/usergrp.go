@@ -193,3 +193,127 @@ func (h *handlers) queryByID(ctx context.Context, r *http.Request) web.Encoder {
 
 	return toAppUser(usr)
 }
+
+func (h *handlers) queryPreferences(ctx context.Context, r *http.Request) web.Encoder {
+	id, err := auth.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "querypreferences: %s", err)
+	}
+
+	usr, err := h.user.QueryByID(ctx, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "querybyid: id[%s]: %s", id, err)
+		}
+	}
+
+	return toAppPreferences(usr.Preferences)
+}
+
+func (h *handlers) updatePreferences(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	var app AppUpdatePreferences
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "updatepreferences: %s", err)
+	}
+
+	usr, err := h.user.QueryByID(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+		}
+	}
+
+	up, err := toCoreUpdatePreferences(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	usr, err = h.user.UpdatePreferences(ctx, usr, up)
+	if err != nil {
+		if errors.Is(err, user.ErrInvalidQuietHours) {
+			return errs.NewFieldErrors("quiet_hours", err)
+		}
+		return errs.Newf(errs.Internal, "updatepreferences: userID[%s] up[%+v]: %s", userID, up, err)
+	}
+
+	return toAppPreferences(usr.Preferences)
+}
+
+// rotateFeedToken issues the user a new token for their calendar feeds, revoking
+// the one before.
+func (h *handlers) rotateFeedToken(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	userID, err := auth.GetUserID(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "rotatefeedtoken: %s", err)
+	}
+
+	usr, err := h.user.QueryByID(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+		}
+	}
+
+	_, token, err := h.user.RotateFeedToken(ctx, usr)
+	if err != nil {
+		return errs.Newf(errs.Internal, "rotatefeedtoken: userID[%s]: %s", userID, err)
+	}
+
+	return AppFeedToken{Token: token}
+}
+
+// unsubscribe stops notifying the user over the channel of the token, signed into
+// the links of emails. Mail clients unsubscribing with one click post to it, while
+// recipients following the link get it.
+func (h *handlers) unsubscribe(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	userID, ch, err := h.auth.ParseUnsubscribeToken(r.URL.Query().Get("token"))
+	if err != nil {
+		return errs.New(errs.Unauthenticated, err)
+	}
+
+	usr, err := h.user.QueryByID(ctx, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, user.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		default:
+			return errs.Newf(errs.Internal, "querybyid: userID[%s]: %s", userID, err)
+		}
+	}
+
+	usr, err = h.user.Unsubscribe(ctx, usr, ch)
+	if err != nil {
+		return errs.Newf(errs.Internal, "unsubscribe: userID[%s] channel[%s]: %s", userID, ch.Name(), err)
+	}
+
+	return toAppPreferences(usr.Preferences)
+}
@@ -0,0 +1,142 @@
+package waitlistgrp
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/waitlist"
+	"github.com/ameghdadian/service/foundation/errs"
+	"github.com/google/uuid"
+)
+
+// AppHeld is the slot held for a waitlisted customer.
+type AppHeld struct {
+	ServiceID  string `json:"service_id,omitempty"`
+	ResourceID string `json:"resource_id,omitempty"`
+	StartsAt   string `json:"starts_at"`
+	EndsAt     string `json:"ends_at"`
+	HeldUntil  string `json:"held_until"`
+}
+
+type AppEntry struct {
+	ID          string   `json:"id"`
+	BusinessID  string   `json:"business_id"`
+	UserID      string   `json:"user_id"`
+	ServiceID   string   `json:"service_id,omitempty"`
+	ResourceID  string   `json:"resource_id,omitempty"`
+	From        string   `json:"from"`
+	To          string   `json:"to"`
+	Status      string   `json:"status"`
+	Held        *AppHeld `json:"held,omitempty"`
+	DateCreated string   `json:"-"`
+	DateUpdated string   `json:"-"`
+}
+
+func (ae AppEntry) Encode() ([]byte, string, error) {
+	data, err := json.Marshal(ae)
+	return data, "application/json", err
+}
+
+func toAppID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+func toAppEntry(e waitlist.Entry) AppEntry {
+	var held *AppHeld
+	if e.Status == waitlist.StatusOffered {
+		held = &AppHeld{
+			ServiceID:  toAppID(e.Held.ServiceID),
+			ResourceID: toAppID(e.Held.ResourceID),
+			StartsAt:   e.Held.StartsAt.Format(time.RFC3339),
+			EndsAt:     e.Held.EndsAt.Format(time.RFC3339),
+			HeldUntil:  e.HeldUntil.Format(time.RFC3339),
+		}
+	}
+
+	return AppEntry{
+		ID:          e.ID.String(),
+		BusinessID:  e.BusinessID.String(),
+		UserID:      e.UserID.String(),
+		ServiceID:   toAppID(e.ServiceID),
+		ResourceID:  toAppID(e.ResourceID),
+		From:        e.From.Format(time.RFC3339),
+		To:          e.To.Format(time.RFC3339),
+		Status:      e.Status.Status(),
+		Held:        held,
+		DateCreated: e.DateCreated.Format(time.RFC3339),
+		DateUpdated: e.DateUpdated.Format(time.RFC3339),
+	}
+}
+
+// ======================================================================
+
+type AppNewEntry struct {
+	BusinessID string `json:"business_id" validate:"required,uuid"`
+	UserID     string `json:"user_id" validate:"required,uuid"`
+	ServiceID  string `json:"service_id" validate:"omitempty,uuid"`
+	ResourceID string `json:"resource_id" validate:"omitempty,uuid"`
+	From       string `json:"from" validate:"required"`
+	To         string `json:"to" validate:"required"`
+}
+
+func (app AppNewEntry) Validate() error {
+	if err := errs.Check(app); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func toCoreNewEntry(app AppNewEntry) (waitlist.NewEntry, error) {
+	bsnID, err := uuid.Parse(app.BusinessID)
+	if err != nil {
+		return waitlist.NewEntry{}, fmt.Errorf("parsing business id: %w", err)
+	}
+
+	usrID, err := uuid.Parse(app.UserID)
+	if err != nil {
+		return waitlist.NewEntry{}, fmt.Errorf("parsing user id: %w", err)
+	}
+
+	var svcID uuid.UUID
+	if app.ServiceID != "" {
+		svcID, err = uuid.Parse(app.ServiceID)
+		if err != nil {
+			return waitlist.NewEntry{}, fmt.Errorf("parsing service id: %w", err)
+		}
+	}
+
+	var rscID uuid.UUID
+	if app.ResourceID != "" {
+		rscID, err = uuid.Parse(app.ResourceID)
+		if err != nil {
+			return waitlist.NewEntry{}, fmt.Errorf("parsing resource id: %w", err)
+		}
+	}
+
+	from, err := time.Parse(time.RFC3339, app.From)
+	if err != nil {
+		return waitlist.NewEntry{}, fmt.Errorf("parsing from: %w", err)
+	}
+
+	to, err := time.Parse(time.RFC3339, app.To)
+	if err != nil {
+		return waitlist.NewEntry{}, fmt.Errorf("parsing to: %w", err)
+	}
+
+	ne := waitlist.NewEntry{
+		BusinessID: bsnID,
+		UserID:     usrID,
+		ServiceID:  svcID,
+		ResourceID: rscID,
+		From:       from,
+		To:         to,
+	}
+
+	return ne, nil
+}
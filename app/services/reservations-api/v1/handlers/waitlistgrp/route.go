@@ -0,0 +1,57 @@
+package waitlistgrp
+
+import (
+	"net/http"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/appointment/stores/appointmentdb"
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/resource/stores/resourcedb"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/service/stores/servicedb"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/core/user/stores/userdb"
+	"github.com/ameghdadian/service/business/core/waitlist"
+	"github.com/ameghdadian/service/business/core/waitlist/stores/waitlistdb"
+	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
+	"github.com/ameghdadian/service/business/web/v1/auth"
+	"github.com/ameghdadian/service/business/web/v1/mid"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/web"
+	"github.com/hibiken/asynq"
+	"github.com/jmoiron/sqlx"
+)
+
+type Config struct {
+	Build         string
+	Log           *logger.Logger
+	DB            *sqlx.DB
+	Auth          *auth.Auth
+	TaskClient    *asynq.Client
+	TaskInspector *asynq.Inspector
+}
+
+func Routes(app *web.App, cfg Config) {
+	const version = "v1"
+
+	aptTask := appointment.NewTask(cfg.TaskClient, cfg.TaskInspector)
+	wlTask := waitlist.NewTask(cfg.TaskClient, cfg.TaskInspector)
+
+	usrCore := user.NewCore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB))
+	bsnCore := business.NewCore(cfg.Log, usrCore, businessdb.NewStore(cfg.Log, cfg.DB))
+	svcCore := service.NewCore(cfg.Log, bsnCore, servicedb.NewStore(cfg.Log, cfg.DB))
+	rscCore := resource.NewCore(cfg.Log, bsnCore, resourcedb.NewStore(cfg.Log, cfg.DB))
+	aptCore := appointment.NewCore(cfg.Log, usrCore, bsnCore, svcCore, rscCore, appointmentdb.NewStore(cfg.Log, cfg.DB), aptTask)
+	wlCore := waitlist.NewCore(cfg.Log, usrCore, bsnCore, aptCore, waitlistdb.NewStore(cfg.Log, cfg.DB), wlTask)
+
+	authen := mid.Authenticate(cfg.Auth)
+	ruleAuthorizeEntry := mid.AuthorizeWaitlistEntry(cfg.Log, cfg.Auth, wlCore)
+	tran := mid.ExecuteInTransaction(cfg.Log, db.NewBeginner(cfg.DB))
+
+	hdl := newApp(wlCore)
+	app.Handle(http.MethodPost, version, "/waitlist", hdl.create, authen, tran)
+	app.Handle(http.MethodGet, version, "/waitlist/{entry_id}", hdl.queryByID, authen, ruleAuthorizeEntry)
+	app.Handle(http.MethodPost, version, "/waitlist/{entry_id}/withdraw", hdl.withdraw, authen, tran, ruleAuthorizeEntry)
+}
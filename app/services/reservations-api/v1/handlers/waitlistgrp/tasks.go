@@ -0,0 +1,50 @@
+package waitlistgrp
+
+import (
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/appointment/stores/appointmentdb"
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/resource/stores/resourcedb"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/service/stores/servicedb"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/core/user/stores/userdb"
+	"github.com/ameghdadian/service/business/core/waitlist"
+	"github.com/ameghdadian/service/business/core/waitlist/stores/waitlistdb"
+	"github.com/ameghdadian/service/foundation/email"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/sms"
+	"github.com/hibiken/asynq"
+	"github.com/jmoiron/sqlx"
+)
+
+type TaskConfig struct {
+	DB            *sqlx.DB
+	Log           *logger.Logger
+	Mux           *asynq.ServeMux
+	TaskClient    *asynq.Client
+	TaskInspector *asynq.Inspector
+	SMS           sms.Sender
+	Email         email.Sender
+	Unsubscriber  appointment.Unsubscriber
+}
+
+func RegisterTaskHandlers(cfg TaskConfig) {
+	aptTask := appointment.NewTask(cfg.TaskClient, cfg.TaskInspector)
+	wlTask := waitlist.NewTask(cfg.TaskClient, cfg.TaskInspector)
+
+	usrCore := user.NewCore(cfg.Log, userdb.NewStore(cfg.Log, cfg.DB))
+	bsnCore := business.NewCore(cfg.Log, usrCore, businessdb.NewStore(cfg.Log, cfg.DB))
+	svcCore := service.NewCore(cfg.Log, bsnCore, servicedb.NewStore(cfg.Log, cfg.DB))
+	rscCore := resource.NewCore(cfg.Log, bsnCore, resourcedb.NewStore(cfg.Log, cfg.DB))
+	aptCore := appointment.NewCore(cfg.Log, usrCore, bsnCore, svcCore, rscCore, appointmentdb.NewStore(cfg.Log, cfg.DB), aptTask)
+	wlCore := waitlist.NewCore(cfg.Log, usrCore, bsnCore, aptCore, waitlistdb.NewStore(cfg.Log, cfg.DB), wlTask)
+
+	th := waitlist.NewTaskHandlers(cfg.Log, usrCore, bsnCore, wlCore, cfg.SMS, cfg.Email, cfg.Unsubscriber)
+
+	cfg.Mux.HandleFunc(waitlist.TypeNotifyOffer, th.HandleNotifyOffer)
+	cfg.Mux.HandleFunc(waitlist.TypeEmailOffer, th.HandleEmailOffer)
+	cfg.Mux.HandleFunc(waitlist.TypeExpireOffer, th.HandleExpireOffer)
+}
@@ -0,0 +1,107 @@
+package waitlistgrp
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/core/waitlist"
+	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/business/web/v1/mid"
+	"github.com/ameghdadian/service/foundation/errs"
+	"github.com/ameghdadian/service/foundation/web"
+)
+
+type handlers struct {
+	wlCore *waitlist.Core
+}
+
+func newApp(wlCore *waitlist.Core) *handlers {
+	return &handlers{
+		wlCore: wlCore,
+	}
+}
+
+func (h *handlers) executeUnderTransaction(ctx context.Context) (*handlers, error) {
+	if tx, ok := transaction.Get(ctx); ok {
+		wlCore, err := h.wlCore.ExecuteUnderTransaction(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		h = &handlers{
+			wlCore: wlCore,
+		}
+
+		return h, nil
+	}
+
+	return h, nil
+}
+
+func (h *handlers) create(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	var app AppNewEntry
+	if err := web.Decode(r, &app); err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	ne, err := toCoreNewEntry(app)
+	if err != nil {
+		return errs.New(errs.InvalidArgument, err)
+	}
+
+	e, err := h.wlCore.Create(ctx, ne)
+	if err != nil {
+		switch {
+		case errors.Is(err, waitlist.ErrInvalidRange), errors.Is(err, waitlist.ErrPastRange):
+			return errs.NewFieldErrors("to", err)
+		case errors.Is(err, waitlist.ErrUserDisabled):
+			return errs.New(errs.FailedPrecondition, err)
+		case errors.Is(err, user.ErrNotFound), errors.Is(err, business.ErrNotFound):
+			return errs.New(errs.NotFound, err)
+		}
+		return errs.Newf(errs.Internal, "create: app[%+v]: %s", app, err)
+	}
+
+	return toAppEntry(e)
+}
+
+// withdraw takes the entry off the waitlist, passing a slot held for it on to
+// whoever is waiting next.
+func (h *handlers) withdraw(ctx context.Context, r *http.Request) web.Encoder {
+	h, err := h.executeUnderTransaction(ctx)
+	if err != nil {
+		return errs.New(errs.Internal, err)
+	}
+
+	e, err := mid.GetWaitlistEntry(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "waitlist entry missing in context: %s", err)
+	}
+
+	we, err := h.wlCore.Withdraw(ctx, e)
+	if err != nil {
+		if errors.Is(err, waitlist.ErrNotActive) {
+			return errs.New(errs.FailedPrecondition, err)
+		}
+		return errs.Newf(errs.Internal, "withdraw: entryID[%s]: %s", e.ID, err)
+	}
+
+	return toAppEntry(we)
+}
+
+func (h *handlers) queryByID(ctx context.Context, r *http.Request) web.Encoder {
+	e, err := mid.GetWaitlistEntry(ctx)
+	if err != nil {
+		return errs.Newf(errs.Internal, "waitlist entry missing in context: %s", err)
+	}
+
+	return toAppEntry(e)
+}
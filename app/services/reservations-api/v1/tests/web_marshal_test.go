@@ -6,17 +6,15 @@ import (
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/agendagrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/appointmentgrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/businessgrp"
+	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/servicegrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/usergrp"
 	"github.com/ameghdadian/service/business/core/agenda"
 	"github.com/ameghdadian/service/business/core/appointment"
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/ameghdadian/service/business/core/user"
 )
 
-const (
-	appTimeFormat = time.TimeOnly + " Z0700"
-)
-
 func toAppUser(usr user.User) usergrp.AppUser {
 	roles := make([]string, len(usr.Roles))
 	for i, role := range usr.Roles {
@@ -58,6 +56,7 @@ func toAppBusiness(b business.Business) businessgrp.AppBusiness {
 		OwnerID:     b.OwnerID.String(),
 		Name:        b.Name,
 		Description: b.Desc,
+		TimeZone:    b.TimeZone.Name(),
 		DateCreated: "",
 		DateUpdated: "",
 	}
@@ -79,13 +78,49 @@ func toAppBusinessPtr(b business.Business) *businessgrp.AppBusiness {
 
 // ----------------------------------------------------------
 
+func toAppService(svc service.Service) servicegrp.AppService {
+	return servicegrp.AppService{
+		ID:          svc.ID.String(),
+		BusinessID:  svc.BusinessID.String(),
+		Name:        svc.Name,
+		Duration:    int(svc.Duration / time.Second),
+		Price:       svc.Price,
+		Currency:    svc.Currency.Code(),
+		Capacity:    svc.Capacity,
+		DateCreated: "",
+		DateUpdated: "",
+	}
+}
+
+func toAppServices(svcs []service.Service) []servicegrp.AppService {
+	items := make([]servicegrp.AppService, len(svcs))
+	for i, svc := range svcs {
+		items[i] = toAppService(svc)
+	}
+
+	return items
+}
+
+func toAppServicePtr(svc service.Service) *servicegrp.AppService {
+	appSvc := toAppService(svc)
+	return &appSvc
+}
+
+// ----------------------------------------------------------
+
 func toAppAppointment(apt appointment.Appointment) appointmentgrp.AppAppointment {
 	return appointmentgrp.AppAppointment{
 		ID:          apt.ID.String(),
 		BusinessID:  apt.BusinessID.String(),
 		UserID:      apt.UserID.String(),
+		ServiceID:   apt.ServiceID.String(),
 		Status:      apt.Status.Status(),
 		ScheduledOn: apt.ScheduledOn.Format(time.RFC3339),
+		Duration:    int(apt.Duration / time.Second),
+		Price:       apt.Price,
+		Currency:    apt.Currency.Code(),
+		Capacity:    apt.Capacity,
+		Remaining:   apt.Remaining,
 		DateCreated: "",
 		DateUpdated: "",
 	}
@@ -108,18 +143,20 @@ func toAppAppointmentPtr(a appointment.Appointment) *appointmentgrp.AppAppointme
 // ----------------------------------------------------------
 
 func toAppGeneralAgenda(agd agenda.GeneralAgenda) agendagrp.AppGeneralAgenda {
-	days := make([]int, len(agd.WorkingDays))
-	for i, d := range agd.WorkingDays {
-		days[i] = int(d.DayOfWeedk())
+	hours := make([]agendagrp.AppOpeningHours, len(agd.Hours))
+	for i, h := range agd.Hours {
+		hours[i] = agendagrp.AppOpeningHours{
+			Day:      int(h.Day.DayOfWeedk()),
+			OpensAt:  h.OpensAt.String(),
+			ClosedAt: h.ClosedAt.String(),
+			Interval: h.Interval,
+		}
 	}
 
 	return agendagrp.AppGeneralAgenda{
 		ID:          agd.ID.String(),
 		BusinessID:  agd.BusinessID.String(),
-		OpensAt:     agd.OpensAt.Format(appTimeFormat),
-		ClosedAt:    agd.ClosedAt.Format(appTimeFormat),
-		Interval:    int(agd.Interval),
-		WorkingDays: days,
+		Hours:       hours,
 		DateCreated: "",
 		DateUpdated: "",
 	}
@@ -140,13 +177,21 @@ func toAppGeneralAgendaPtr(app agenda.GeneralAgenda) *agendagrp.AppGeneralAgenda
 }
 
 func toAppDailyAgenda(agd agenda.DailyAgenda) agendagrp.AppDailyAgenda {
+	wins := make([]agendagrp.AppWindow, len(agd.Windows))
+	for i, w := range agd.Windows {
+		wins[i] = agendagrp.AppWindow{
+			OpensAt:  w.OpensAt.String(),
+			ClosedAt: w.ClosedAt.String(),
+			Interval: w.Interval,
+		}
+	}
+
 	return agendagrp.AppDailyAgenda{
 		ID:           agd.ID.String(),
 		BusinessID:   agd.BusinessID.String(),
-		OpensAt:      agd.OpensAt.Format(time.RFC3339),
-		ClosedAt:     agd.ClosedAt.Format(time.RFC3339),
-		Interval:     int(time.Duration(agd.Interval)),
+		Date:         agd.Date.Format(time.DateOnly),
 		Availability: agd.Availability,
+		Windows:      wins,
 		DateCreated:  "",
 		DateUpdated:  "",
 	}
@@ -15,10 +15,13 @@ import (
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/agendagrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/appointmentgrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/businessgrp"
+	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/resourcegrp"
+	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/servicegrp"
 	"github.com/ameghdadian/service/app/services/reservations-api/v1/handlers/usergrp"
 	"github.com/ameghdadian/service/business/core/agenda"
 	"github.com/ameghdadian/service/business/core/appointment"
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/business/data/dbtest"
 	"github.com/ameghdadian/service/business/data/order"
@@ -31,6 +34,7 @@ import (
 type seedData struct {
 	users          []user.User
 	businesses     []business.Business
+	services       []service.Service
 	appointments   []appointment.Appointment
 	generalAgendas []agenda.GeneralAgenda
 	dailyAgendas   []agenda.DailyAgenda
@@ -100,12 +104,26 @@ func Test_Web(t *testing.T) {
 		bsns = append(bsns, bsns2...)
 		bsns = append(bsns, bsns3...)
 
-		apts1, err := appointment.TestGenerateSeedAppointments(1, api.Appointment, usrs[0].ID, bsns1[0].ID)
+		svcs1, err := service.TestGenerateSeedServices(1, api.Service, bsns1[0].ID)
+		if err != nil {
+			return seedData{}, fmt.Errorf("seeding services: %w", err)
+		}
+
+		svcs2, err := service.TestGenerateSeedServices(1, api.Service, bsns2[0].ID)
+		if err != nil {
+			return seedData{}, fmt.Errorf("seeding services: %w", err)
+		}
+
+		var svcs []service.Service
+		svcs = append(svcs, svcs1...)
+		svcs = append(svcs, svcs2...)
+
+		apts1, err := appointment.TestGenerateSeedAppointments(1, api.Appointment, usrs[0].ID, bsns1[0].ID, svcs1[0].ID)
 		if err != nil {
 			return seedData{}, fmt.Errorf("seeding appointments: %w", err)
 		}
 
-		apts2, err := appointment.TestGenerateSeedAppointments(1, api.Appointment, usrs[1].ID, bsns2[0].ID)
+		apts2, err := appointment.TestGenerateSeedAppointments(1, api.Appointment, usrs[1].ID, bsns2[0].ID, svcs2[0].ID)
 		if err != nil {
 			return seedData{}, fmt.Errorf("seeding appointments: %w", err)
 		}
@@ -126,6 +144,7 @@ func Test_Web(t *testing.T) {
 		sd := seedData{
 			users:          usrs,
 			businesses:     bsns,
+			services:       svcs,
 			appointments:   apts,
 			generalAgendas: gagd,
 			dailyAgendas:   dagd,
@@ -147,7 +166,10 @@ func Test_Web(t *testing.T) {
 	t.Run("queryByID200", tests.queryByID200(sd))
 	t.Run("createUser200", tests.createUser200(sd))
 	t.Run("createBusiness200", tests.createBusiness200(sd))
+	t.Run("createService200", tests.createService200(sd))
+	t.Run("createResource200", tests.createResource200(sd))
 	t.Run("createAppointment200", tests.createAppointment200(sd))
+	t.Run("createAppointmentSeries200", tests.createAppointmentSeries200(sd))
 	t.Run("createGeneralAgenda200", tests.createGeneralAgenda200(sd))
 	t.Run("createDailyAgenda200", tests.createDailyAgenda200(sd))
 }
@@ -182,6 +204,17 @@ func (wt *WebTests) query200(sd seedData) func(t *testing.T) {
 					Items:       toAppBusinesses(sd.businesses),
 				},
 			},
+			{
+				name: "service",
+				url:  fmt.Sprintf("/v1/services?page=1&rows=10&business_id=%s", sd.businesses[0].ID),
+				resp: &response.PageDocument[servicegrp.AppService]{},
+				expResp: &response.PageDocument[servicegrp.AppService]{
+					Page:        1,
+					RowsPerPage: 10,
+					Total:       1,
+					Items:       toAppServices(sd.services[:1]),
+				},
+			},
 			{
 				name: "appointment",
 				url:  "/v1/appointments?page=1&rows=2&orderBy=user_id,DESC",
@@ -266,6 +299,12 @@ func (wt *WebTests) queryByID200(sd seedData) func(t *testing.T) {
 				resp:    &businessgrp.AppBusiness{},
 				expResp: toAppBusinessPtr(sd.businesses[2]),
 			},
+			{
+				name:    "service",
+				url:     fmt.Sprintf("/v1/services/%s", sd.services[1].ID),
+				resp:    &servicegrp.AppService{},
+				expResp: toAppServicePtr(sd.services[1]),
+			},
 			{
 				name:    "appointment",
 				url:     fmt.Sprintf("/v1/appointments/%s", sd.appointments[1].ID),
@@ -406,12 +445,14 @@ func (wt *WebTests) createBusiness200(sd seedData) func(t *testing.T) {
 					Name:        "New Business",
 					OwnerID:     sd.users[0].ID.String(),
 					Description: "New Businesss Description",
+					TimeZone:    "Europe/Berlin",
 				},
 				resp: &businessgrp.AppBusiness{},
 				expResp: &businessgrp.AppBusiness{
 					Name:        "New Business",
 					OwnerID:     sd.users[0].ID.String(),
 					Description: "New Businesss Description",
+					TimeZone:    "Europe/Berlin",
 				},
 			},
 		}
@@ -460,8 +501,156 @@ func (wt *WebTests) createBusiness200(sd seedData) func(t *testing.T) {
 	}
 }
 
+func (wt *WebTests) createService200(sd seedData) func(t *testing.T) {
+	return func(t *testing.T) {
+		table := []struct {
+			name    string
+			url     string
+			input   any
+			resp    any
+			expResp any
+		}{
+			{
+				name: "service",
+				url:  "/v1/services",
+				input: &servicegrp.AppNewService{
+					BusinessID: sd.businesses[0].ID.String(),
+					Name:       "Coloring",
+					Duration:   90 * 60,
+					Price:      6000,
+					Currency:   "EUR",
+				},
+				resp: &servicegrp.AppService{},
+				expResp: &servicegrp.AppService{
+					BusinessID: sd.businesses[0].ID.String(),
+					Name:       "Coloring",
+					Duration:   90 * 60,
+					Price:      6000,
+					Currency:   "EUR",
+					Capacity:   1,
+				},
+			},
+		}
+
+		for _, tt := range table {
+			d, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("error occurred")
+			}
+
+			r := httptest.NewRequest(http.MethodPost, tt.url, bytes.NewBuffer(d))
+			w := httptest.NewRecorder()
+
+			// The admin user owns the first seeded business.
+			r.Header.Set("Authorization", "Bearer "+wt.adminToken)
+			wt.app.ServeHTTP(w, r)
+
+			if w.Code != http.StatusCreated {
+				t.Errorf("%s: Should receive a status code of 201 for the response: %d", tt.name, w.Code)
+				continue
+			}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &tt.resp); err != nil {
+				t.Errorf("Should be able to unmarshal the respones: %s", err)
+			}
+
+			gotResp, exists := tt.resp.(*servicegrp.AppService)
+			if !exists {
+				t.Fatalf("error occurred")
+			}
+
+			expResp := tt.expResp.(*servicegrp.AppService)
+			expResp.ID = gotResp.ID
+			expResp.DateCreated = gotResp.DateCreated
+			expResp.DateUpdated = gotResp.DateUpdated
+
+			diff := cmp.Diff(gotResp, expResp)
+			if diff != "" {
+				t.Error("Should get the expected response")
+				t.Log("GOT")
+				t.Logf("%#v", gotResp)
+				t.Log("EXP")
+				t.Logf("%#v", expResp)
+				continue
+			}
+		}
+	}
+}
+
+func (wt *WebTests) createResource200(sd seedData) func(t *testing.T) {
+	return func(t *testing.T) {
+		table := []struct {
+			name    string
+			url     string
+			input   any
+			resp    any
+			expResp any
+		}{
+			{
+				name: "resource",
+				url:  "/v1/resources",
+				input: &resourcegrp.AppNewResource{
+					BusinessID: sd.businesses[0].ID.String(),
+					Name:       "Chair 1",
+					Kind:       "ROOM",
+				},
+				resp: &resourcegrp.AppResource{},
+				expResp: &resourcegrp.AppResource{
+					BusinessID: sd.businesses[0].ID.String(),
+					Name:       "Chair 1",
+					Kind:       "ROOM",
+				},
+			},
+		}
+
+		for _, tt := range table {
+			d, err := json.Marshal(tt.input)
+			if err != nil {
+				t.Fatalf("error occurred")
+			}
+
+			r := httptest.NewRequest(http.MethodPost, tt.url, bytes.NewBuffer(d))
+			w := httptest.NewRecorder()
+
+			// The admin user owns the first seeded business.
+			r.Header.Set("Authorization", "Bearer "+wt.adminToken)
+			wt.app.ServeHTTP(w, r)
+
+			if w.Code != http.StatusCreated {
+				t.Errorf("%s: Should receive a status code of 201 for the response: %d", tt.name, w.Code)
+				continue
+			}
+
+			if err := json.Unmarshal(w.Body.Bytes(), &tt.resp); err != nil {
+				t.Errorf("Should be able to unmarshal the respones: %s", err)
+			}
+
+			gotResp, exists := tt.resp.(*resourcegrp.AppResource)
+			if !exists {
+				t.Fatalf("error occurred")
+			}
+
+			expResp := tt.expResp.(*resourcegrp.AppResource)
+			expResp.ID = gotResp.ID
+			expResp.DateCreated = gotResp.DateCreated
+			expResp.DateUpdated = gotResp.DateUpdated
+
+			diff := cmp.Diff(gotResp, expResp)
+			if diff != "" {
+				t.Error("Should get the expected response")
+				t.Log("GOT")
+				t.Logf("%#v", gotResp)
+				t.Log("EXP")
+				t.Logf("%#v", expResp)
+				continue
+			}
+		}
+	}
+}
+
 func (wt *WebTests) createAppointment200(sd seedData) func(t *testing.T) {
-	sch := sd.generalAgendas[0].OpensAt.Add(time.Hour)
+	// Seeded businesses operate in UTC and are open every day of the week.
+	sch := sd.generalAgendas[0].Hours[0].OpensAt.On(time.Now().UTC()).Add(time.Hour)
 
 	return func(t *testing.T) {
 		table := []struct {
@@ -477,15 +666,20 @@ func (wt *WebTests) createAppointment200(sd seedData) func(t *testing.T) {
 				input: &appointmentgrp.AppNewAppointment{
 					BusinessID:  sd.businesses[0].ID.String(),
 					UserID:      sd.users[0].ID.String(),
-					Status:      appointment.StatusScheduled.Status(),
+					ServiceID:   sd.services[0].ID.String(),
 					ScheduledOn: sch.Format(time.RFC3339),
 				},
 				resp: &appointmentgrp.AppAppointment{},
 				expResp: &appointmentgrp.AppAppointment{
 					BusinessID:  sd.businesses[0].ID.String(),
 					UserID:      sd.users[0].ID.String(),
-					Status:      appointment.StatusScheduled.Status(),
+					ServiceID:   sd.services[0].ID.String(),
+					Status:      appointment.StatusPending.Status(),
 					ScheduledOn: sch.In(time.Local).Format(time.RFC3339),
+					Duration:    int(sd.services[0].Duration / time.Second),
+					Price:       sd.services[0].Price,
+					Currency:    sd.services[0].Currency.Code(),
+					Capacity:    sd.services[0].Capacity,
 				},
 			},
 		}
@@ -534,9 +728,61 @@ func (wt *WebTests) createAppointment200(sd seedData) func(t *testing.T) {
 	}
 }
 
+func (wt *WebTests) createAppointmentSeries200(sd seedData) func(t *testing.T) {
+	// Starting tomorrow, clear of the appointment booked today.
+	sch := sd.generalAgendas[0].Hours[0].OpensAt.On(time.Now().UTC()).Add(time.Hour).AddDate(0, 0, 1)
+
+	return func(t *testing.T) {
+		input := appointmentgrp.AppNewSeries{
+			BusinessID: sd.businesses[0].ID.String(),
+			UserID:     sd.users[0].ID.String(),
+			ServiceID:  sd.services[0].ID.String(),
+			Rule:       "FREQ=WEEKLY;COUNT=3",
+			StartsOn:   sch.Format(time.RFC3339),
+		}
+
+		d, err := json.Marshal(input)
+		if err != nil {
+			t.Fatalf("error occurred")
+		}
+
+		r := httptest.NewRequest(http.MethodPost, "/v1/appointment-series", bytes.NewBuffer(d))
+		w := httptest.NewRecorder()
+
+		r.Header.Set("Authorization", "Bearer "+wt.adminToken)
+		wt.app.ServeHTTP(w, r)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("Should receive a status code of 201 for the response: %d", w.Code)
+		}
+
+		var got appointmentgrp.AppSeries
+		if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+			t.Fatalf("Should be able to unmarshal the respones: %s", err)
+		}
+
+		if got.Rule != input.Rule || len(got.Appointments) != 3 {
+			t.Errorf("Should book an appointment for each occurrence: rule[%s] appointments[%d]", got.Rule, len(got.Appointments))
+		}
+
+		for i, apt := range got.Appointments {
+			exp := sch.AddDate(0, 0, 7*i).In(time.Local).Format(time.RFC3339)
+			if apt.SeriesID != got.ID || apt.ScheduledOn != exp {
+				t.Error("Should book each occurrence a week apart as part of the series")
+				t.Errorf("GOT: %s %s\n", apt.SeriesID, apt.ScheduledOn)
+				t.Errorf("EXP: %s %s\n", got.ID, exp)
+			}
+		}
+	}
+}
+
 func (wt *WebTests) createGeneralAgenda200(sd seedData) func(t *testing.T) {
-	loc, _ := time.LoadLocation("America/New_York")
-	now := time.Now()
+	hours := []agendagrp.AppOpeningHours{
+		{Day: 1, OpensAt: "10:00", ClosedAt: "12:00", Interval: 30 * 60},
+		{Day: 1, OpensAt: "13:00", ClosedAt: "20:00", Interval: 30 * 60},
+		{Day: 5, OpensAt: "10:12", ClosedAt: "14:00", Interval: 2 * 60 * 60}, // Every 2 hours
+	}
+
 	return func(t *testing.T) {
 		table := []struct {
 			name    string
@@ -549,19 +795,13 @@ func (wt *WebTests) createGeneralAgenda200(sd seedData) func(t *testing.T) {
 				name: "general_agenda",
 				url:  "/v1/agendas/general",
 				input: &agendagrp.AppNewGeneralAgenda{
-					BusinessID:  sd.businesses[1].ID.String(),
-					OpensAt:     time.Date(now.Year(), now.Month(), now.Day(), 10, 12, 0, 0, loc).Format(time.RFC3339),
-					ClosedAt:    time.Date(now.Year(), now.Month(), now.Day(), 20, 0, 0, 0, loc).Format(time.RFC3339),
-					Interval:    2 * 60 * 60, // Every 2 hours
-					WorkingDays: []int{1, 5},
+					BusinessID: sd.businesses[1].ID.String(),
+					Hours:      hours,
 				},
 				resp: &agendagrp.AppGeneralAgenda{},
 				expResp: &agendagrp.AppGeneralAgenda{
-					BusinessID:  sd.businesses[1].ID.String(),
-					OpensAt:     time.Date(now.Year(), now.Month(), now.Day(), 10, 12, 0, 0, loc).Format(appTimeFormat),
-					ClosedAt:    time.Date(now.Year(), now.Month(), now.Day(), 20, 0, 0, 0, loc).Format(appTimeFormat),
-					Interval:    2 * 60 * 60, // Every 2 hours
-					WorkingDays: []int{1, 5},
+					BusinessID: sd.businesses[1].ID.String(),
+					Hours:      hours,
 				},
 			},
 		}
@@ -611,8 +851,8 @@ func (wt *WebTests) createGeneralAgenda200(sd seedData) func(t *testing.T) {
 }
 
 func (wt *WebTests) createDailyAgenda200(sd seedData) func(t *testing.T) {
-	loc, _ := time.LoadLocation("America/New_York")
-	now := time.Now()
+	date := time.Now().UTC().AddDate(0, 0, 7).Format(time.DateOnly)
+	available := true
 
 	return func(t *testing.T) {
 		table := []struct {
@@ -627,18 +867,22 @@ func (wt *WebTests) createDailyAgenda200(sd seedData) func(t *testing.T) {
 				url:  "/v1/agendas/daily",
 				input: &agendagrp.AppNewDailyAgenda{
 					BusinessID:   sd.businesses[2].ID.String(),
-					OpensAt:      time.Date(now.Year(), now.Month(), now.Day(), 10, 12, 0, 0, loc).Format(time.RFC3339),
-					ClosedAt:     time.Date(now.Year(), now.Month(), now.Day(), 20, 0, 0, 0, loc).Format(time.RFC3339),
-					Interval:     2 * 60 * 60, // Every 2 hours
-					Availability: true,
+					Date:         date,
+					Availability: &available,
+					Windows: []agendagrp.AppWindow{
+						{OpensAt: "09:00", ClosedAt: "12:00", Interval: 30 * 60},
+						{OpensAt: "15:00", ClosedAt: "19:00", Interval: 60 * 60},
+					},
 				},
 				resp: &agendagrp.AppDailyAgenda{},
 				expResp: &agendagrp.AppDailyAgenda{
 					BusinessID:   sd.businesses[2].ID.String(),
-					OpensAt:      time.Date(now.Year(), now.Month(), now.Day(), 10, 12, 0, 0, loc).Format(time.RFC3339),
-					ClosedAt:     time.Date(now.Year(), now.Month(), now.Day(), 20, 0, 0, 0, loc).Format(time.RFC3339),
-					Interval:     2 * 60 * 60, // Every 2 hours
+					Date:         date,
 					Availability: true,
+					Windows: []agendagrp.AppWindow{
+						{OpensAt: "09:00", ClosedAt: "12:00", Interval: 30 * 60},
+						{OpensAt: "15:00", ClosedAt: "19:00", Interval: 60 * 60},
+					},
 				},
 			},
 		}
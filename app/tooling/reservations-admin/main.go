@@ -15,11 +15,26 @@ import (
 	"log"
 	"os"
 	"time"
-
+	_ "time/tzdata"
+
+	"github.com/ameghdadian/service/business/core/agenda"
+	"github.com/ameghdadian/service/business/core/agenda/stores/agendadb"
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/appointment/stores/appointmentdb"
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/resource/stores/resourcedb"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/service/stores/servicedb"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/core/user/stores/userdb"
 	"github.com/ameghdadian/service/business/data/dbmigrate"
 	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
+	"github.com/ameghdadian/service/foundation/logger"
 	"github.com/ardanlabs/conf/v3"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/open-policy-agent/opa/rego"
 )
 
@@ -28,10 +43,16 @@ var (
 	opaAuthentication string
 )
 
-var command string
+var (
+	command    string
+	businessID string
+	file       string
+)
 
 func init() {
-	flag.StringVar(&command, "command", "", "Valid commands: (migrateseed, gentoken, genkey)")
+	flag.StringVar(&command, "command", "", "Valid commands: (migrateseed, gentoken, genkey, importclosures)")
+	flag.StringVar(&businessID, "business", "", "ID of the business to import closures for (importclosures)")
+	flag.StringVar(&file, "file", "", "Path of the iCalendar file to import closures from (importclosures)")
 }
 
 func main() {
@@ -45,6 +66,8 @@ func main() {
 		err = gentoken()
 	case "genkey":
 		_, err = genkey()
+	case "importclosures":
+		err = importClosures()
 	default:
 		log.Fatalln("unrecognized command")
 	}
@@ -113,6 +136,110 @@ func migrateSeed() error {
 	return nil
 }
 
+// importClosures closes the business on the dates of the events of the iCalendar
+// file, printing the appointments now falling on closed dates.
+func importClosures() error {
+	bsnID, err := uuid.Parse(businessID)
+	if err != nil {
+		return fmt.Errorf("parsing business id: %w", err)
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("opening calendar file: %w", err)
+	}
+	defer f.Close()
+
+	var cfg struct {
+		DB struct {
+			User         string `conf:"default:postgres"`
+			Password     string `conf:"default:postgres,mask"`
+			Host         string `conf:"default:database-service.reservations-system.svc.cluster.local"`
+			Name         string `conf:"default:postgres"`
+			MaxIdleConns int    `conf:"default:2"`
+			MaxOpenConns int    `conf:"default:0"`
+			DisableTLS   bool   `conf:"default:true"`
+		}
+	}
+
+	const prefix = "RESERVATIONS"
+	help, err := conf.Parse(prefix, &cfg)
+	if err != nil {
+		if errors.Is(err, conf.ErrHelpWanted) {
+			fmt.Println(help)
+			return nil
+		}
+
+		return fmt.Errorf("parsing config: %w", err)
+	}
+
+	dbConfig := db.Config{
+		User:         cfg.DB.User,
+		Password:     cfg.DB.Password,
+		Host:         cfg.DB.Host,
+		Name:         cfg.DB.Name,
+		MaxIdleConns: cfg.DB.MaxIdleConns,
+		MaxOpenConns: cfg.DB.MaxOpenConns,
+		DisableTLS:   cfg.DB.DisableTLS,
+	}
+
+	sqlxDB, err := db.Open(dbConfig)
+	if err != nil {
+		return fmt.Errorf("connect database: %w", err)
+	}
+	defer sqlxDB.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	log := logger.New(io.Discard, logger.LevelInfo, "ADMIN", func(context.Context) string { return "" })
+
+	usrCore := user.NewCore(log, userdb.NewStore(log, sqlxDB))
+	bsnCore := business.NewCore(log, usrCore, businessdb.NewStore(log, sqlxDB))
+	svcCore := service.NewCore(log, bsnCore, servicedb.NewStore(log, sqlxDB))
+	rscCore := resource.NewCore(log, bsnCore, resourcedb.NewStore(log, sqlxDB))
+	aptCore := appointment.NewCore(log, usrCore, bsnCore, svcCore, rscCore, appointmentdb.NewStore(log, sqlxDB), appointment.NewTask(nil, nil))
+	agdCore := agenda.NewCore(log, bsnCore, rscCore, aptCore, agendadb.NewStore(log, sqlxDB))
+
+	tx, err := db.NewBeginner(sqlxDB).Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	agdCore, err = agdCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return fmt.Errorf("execute under transaction: %w", err)
+	}
+
+	res, err := agdCore.ImportClosures(ctx, bsnID, f)
+	if err != nil {
+		return fmt.Errorf("import closures: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	fmt.Printf("closures imported: %d dates closed, %d reopened, %d left as set\n", len(res.Created), len(res.Removed), len(res.Skipped))
+
+	for _, agd := range res.Skipped {
+		fmt.Printf("  left as set: %s (availability %t)\n", agd.Date.Format(time.DateOnly), agd.Availability)
+	}
+
+	if len(res.Affected) == 0 {
+		fmt.Println("no appointments fall on closed dates")
+		return nil
+	}
+
+	fmt.Println("appointments falling on closed dates:")
+	for _, apt := range res.Affected {
+		fmt.Printf("  %s  %s  user[%s]  %s\n", apt.ScheduledOn.Format(time.RFC3339), apt.ID, apt.UserID, apt.Status.Status())
+	}
+
+	return nil
+}
+
 func gentoken() error {
 
 	file, err := os.Open("zarf/keys/963df661-d92e-4991-b519-77d838a21705.pem")
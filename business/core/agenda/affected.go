@@ -0,0 +1,373 @@
+package agenda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/data/order"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/foundation/errs"
+	"github.com/ameghdadian/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+// ReasonAgendaChanged is recorded on appointments handled for no longer fitting the
+// agendas, unless the owner gives a reason of their own.
+const ReasonAgendaChanged = "The opening hours have changed"
+
+// Action is what becomes of the appointments an agenda change leaves outside the
+// agendas: kept as they are, cancelled, or flagged for the customer to pick another
+// time. Customers are notified of cancelled and flagged appointments.
+type Action struct {
+	name string
+}
+
+var (
+	ActionKeep   = Action{"keep"}
+	ActionCancel = Action{"cancel"}
+	ActionFlag   = Action{"flag"}
+)
+
+var actions = map[string]Action{
+	ActionKeep.name:   ActionKeep,
+	ActionCancel.name: ActionCancel,
+	ActionFlag.name:   ActionFlag,
+}
+
+// ParseAction returns the action of the given name, where an empty name keeps the
+// appointments as they are.
+func ParseAction(value string) (Action, error) {
+	if value == "" {
+		return ActionKeep, nil
+	}
+
+	action, exists := actions[value]
+	if !exists {
+		return Action{}, fmt.Errorf("invalid action: %q", value)
+	}
+
+	return action, nil
+}
+
+func (a Action) Name() string {
+	return a.name
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// AffectedByGeneralAgendaUpdate returns the open appointments still to come which fit
+// the agendas now but wouldn't once the general agenda is updated, ordered by their
+// start. The general agenda is left as it is.
+func (c *Core) AffectedByGeneralAgendaUpdate(ctx context.Context, agd GeneralAgenda, uAgd UpdateGeneralAgenda) ([]appointment.Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.generalagenda.affected")
+	defer span.End()
+
+	updated, err := updatedGeneralAgenda(agd, uAgd)
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := c.businessLocation(ctx, agd.BusinessID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Appointments past the last date either version is in effect on aren't affected.
+	var to time.Time
+	if !agd.EffectiveTo.IsZero() && !updated.EffectiveTo.IsZero() {
+		last := agd.EffectiveTo
+		if updated.EffectiveTo.After(last) {
+			last = updated.EffectiveTo
+		}
+		to = time.Date(last.Year(), last.Month(), last.Day()+1, 0, 0, 0, 0, loc)
+	}
+
+	pc := c.preview(previewStorer{Storer: c.storer, gAgd: &updated})
+
+	return c.affected(ctx, pc, agd.BusinessID, agd.ResourceID, time.Now(), to)
+}
+
+// AffectedByDailyAgenda returns the open appointments still to come which fit the
+// agendas now but wouldn't once the daily agenda is created, ordered by their start.
+// The daily agenda isn't created.
+func (c *Core) AffectedByDailyAgenda(ctx context.Context, na NewDailyAgenda) ([]appointment.Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.dailyagenda.affected")
+	defer span.End()
+
+	agd, err := c.newDailyAgenda(ctx, na)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := c.preview(previewStorer{Storer: c.storer, dAgd: &agd})
+
+	return c.affectedOn(ctx, pc, agd.BusinessID, agd.ResourceID, agd.Date)
+}
+
+// AffectedByDailyAgendaUpdate returns the open appointments still to come which fit
+// the agendas now but wouldn't once the daily agenda is updated, on either its former
+// or its new date, ordered by their start. The daily agenda is left as it is.
+func (c *Core) AffectedByDailyAgendaUpdate(ctx context.Context, agd DailyAgenda, uAgd UpdateDailyAgenda) ([]appointment.Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.dailyagenda.affected")
+	defer span.End()
+
+	updated, err := updatedDailyAgenda(agd, uAgd)
+	if err != nil {
+		return nil, err
+	}
+
+	pc := c.preview(previewStorer{Storer: c.storer, dAgd: &updated})
+
+	dates := []time.Time{updated.Date}
+	if !agd.Date.Equal(updated.Date) {
+		dates = append(dates, agd.Date)
+	}
+
+	return c.affectedOn(ctx, pc, agd.BusinessID, agd.ResourceID, dates...)
+}
+
+// HandleAffected takes the action on the appointments, on behalf of the owner of the
+// business, and returns them as they are left. Holds are left to lapse.
+func (c *Core) HandleAffected(ctx context.Context, apts []appointment.Appointment, action Action, reason string) ([]appointment.Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.agenda.handleaffected")
+	defer span.End()
+
+	if reason == "" {
+		reason = ReasonAgendaChanged
+	}
+
+	handled := make([]appointment.Appointment, 0, len(apts))
+	for _, apt := range apts {
+		hapt := apt
+
+		var err error
+		switch {
+		case apt.Held():
+		case action == ActionCancel:
+			hapt, err = c.aptCore.Transition(ctx, apt, appointment.StatusCancelled, appointment.PartyOwner, reason)
+		case action == ActionFlag:
+			hapt, err = c.aptCore.Flag(ctx, apt, reason)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: appointmentID[%s]: %w", action.name, apt.ID, err)
+		}
+
+		handled = append(handled, hapt)
+	}
+
+	return handled, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// affectedOn returns the appointments affected by the previewed change on the dates,
+// taken in the business time zone.
+func (c *Core) affectedOn(ctx context.Context, pc *Core, bsnID uuid.UUID, rscID uuid.UUID, dates ...time.Time) ([]appointment.Appointment, error) {
+	loc, err := c.businessLocation(ctx, bsnID)
+	if err != nil {
+		return nil, err
+	}
+
+	var affected []appointment.Appointment
+	for _, d := range dates {
+		from := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+		if now := time.Now(); from.Before(now) {
+			from = now
+		}
+
+		to := time.Date(d.Year(), d.Month(), d.Day()+1, 0, 0, 0, 0, loc)
+		if !to.After(from) {
+			continue
+		}
+
+		apts, err := c.affected(ctx, pc, bsnID, rscID, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		affected = append(affected, apts...)
+	}
+
+	sort.Slice(affected, func(i, j int) bool {
+		return affected[i].ScheduledOn.Before(affected[j].ScheduledOn)
+	})
+
+	return affected, nil
+}
+
+// affected returns the open appointments starting within [from, to), or from on when
+// to is zero, which fit the agendas as they are but not as the preview core pc sees
+// them. Given a resource, only its appointments are looked at; otherwise every
+// appointment of the business is, since resources fall back to its agendas.
+func (c *Core) affected(ctx context.Context, pc *Core, bsnID uuid.UUID, rscID uuid.UUID, from time.Time, to time.Time) ([]appointment.Appointment, error) {
+	var filter appointment.QueryFilter
+	filter.WithBusinessID(bsnID)
+	filter.WithStartScheduledOn(from)
+	if !to.IsZero() {
+		filter.WithEndScheduledOn(to.Add(-time.Nanosecond))
+	}
+	if rscID != uuid.Nil {
+		filter.WithResourceID(rscID)
+	}
+
+	var affected []appointment.Appointment
+
+	const rows = 100
+	for pn := 1; ; pn++ {
+		pagination, err := page.Parse(strconv.Itoa(pn), strconv.Itoa(rows))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse page parameters: %w", err)
+		}
+
+		apts, err := c.aptCore.Query(ctx, filter, appointment.DefaultOrderBy, pagination)
+		if err != nil {
+			return nil, fmt.Errorf("query appointments: %w", err)
+		}
+
+		for _, apt := range apts {
+			if !apt.Status.Open() {
+				continue
+			}
+
+			before, err := c.fits(ctx, apt)
+			if err != nil {
+				return nil, err
+			}
+
+			after, err := pc.fits(ctx, apt)
+			if err != nil {
+				return nil, err
+			}
+
+			if before && !after {
+				affected = append(affected, apt)
+			}
+		}
+
+		if len(apts) < rows {
+			break
+		}
+	}
+
+	sort.Slice(affected, func(i, j int) bool {
+		return affected[i].ScheduledOn.Before(affected[j].ScheduledOn)
+	})
+
+	return affected, nil
+}
+
+// fits reports whether the appointment starts within the agendas of its resource.
+func (c *Core) fits(ctx context.Context, apt appointment.Appointment) (bool, error) {
+	err := c.TimeWithinAgendaBoundary(ctx, apt.BusinessID, apt.ResourceID, apt.ScheduledOn)
+	if err == nil {
+		return true, nil
+	}
+
+	var e *errs.Error
+	if errors.As(err, &e) && e.Code == errs.InvalidArgument {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("appointmentID[%s]: %w", apt.ID, err)
+}
+
+// preview returns a copy of the core reading its agendas from the storer.
+func (c *Core) preview(storer previewStorer) *Core {
+	return &Core{
+		storer:  storer,
+		bsnCore: c.bsnCore,
+		rscCore: c.rscCore,
+		aptCore: c.aptCore,
+		log:     c.log,
+	}
+}
+
+// previewStorer reads agendas as if a general or a daily agenda, not kept yet, were
+// created or replaced the one of the same ID. Everything else is read from Storer.
+type previewStorer struct {
+	Storer
+	gAgd *GeneralAgenda
+	dAgd *DailyAgenda
+}
+
+func (ps previewStorer) QueryGeneralAgendaByBusinessID(ctx context.Context, bsnID uuid.UUID, date time.Time) (GeneralAgenda, error) {
+	if ps.gAgd == nil || ps.gAgd.BusinessID != bsnID || ps.gAgd.ResourceID != uuid.Nil {
+		return ps.Storer.QueryGeneralAgendaByBusinessID(ctx, bsnID, date)
+	}
+
+	return ps.generalAgendaOn(date, func() (GeneralAgenda, error) {
+		return ps.Storer.QueryGeneralAgendaByBusinessID(ctx, bsnID, date)
+	})
+}
+
+func (ps previewStorer) QueryGeneralAgendaByResourceID(ctx context.Context, rscID uuid.UUID, date time.Time) (GeneralAgenda, error) {
+	if ps.gAgd == nil || ps.gAgd.ResourceID != rscID {
+		return ps.Storer.QueryGeneralAgendaByResourceID(ctx, rscID, date)
+	}
+
+	return ps.generalAgendaOn(date, func() (GeneralAgenda, error) {
+		return ps.Storer.QueryGeneralAgendaByResourceID(ctx, rscID, date)
+	})
+}
+
+// generalAgendaOn returns the previewed general agenda when it's in effect on the date,
+// or else the one query finds, unless that's the previewed agenda before the change.
+func (ps previewStorer) generalAgendaOn(date time.Time, query func() (GeneralAgenda, error)) (GeneralAgenda, error) {
+	if ps.gAgd.EffectiveOn(date) {
+		return *ps.gAgd, nil
+	}
+
+	agd, err := query()
+	if err != nil {
+		return GeneralAgenda{}, err
+	}
+
+	if agd.ID == ps.gAgd.ID {
+		return GeneralAgenda{}, ErrNotFound
+	}
+
+	return agd, nil
+}
+
+func (ps previewStorer) QueryDailyAgenda(ctx context.Context, filter DAQueryFilter, orderBy order.By, page page.Page) ([]DailyAgenda, error) {
+	agds, err := ps.Storer.QueryDailyAgenda(ctx, filter, orderBy, page)
+	if err != nil || ps.dAgd == nil {
+		return agds, err
+	}
+
+	filtered := make([]DailyAgenda, 0, len(agds))
+	for _, agd := range agds {
+		if agd.ID != ps.dAgd.ID {
+			filtered = append(filtered, agd)
+		}
+	}
+
+	if ps.matches(filter) {
+		filtered = append([]DailyAgenda{*ps.dAgd}, filtered...)
+	}
+
+	return filtered, nil
+}
+
+// matches reports whether the previewed daily agenda is one the filter looks for.
+func (ps previewStorer) matches(filter DAQueryFilter) bool {
+	agd := ps.dAgd
+
+	switch {
+	case filter.ID != nil && *filter.ID != agd.ID:
+		return false
+	case filter.BusinessID != nil && *filter.BusinessID != agd.BusinessID:
+		return false
+	case filter.ResourceID != nil && *filter.ResourceID != agd.ResourceID:
+		return false
+	case filter.Date != nil && !calendarDate(*filter.Date).Equal(agd.Date):
+		return false
+	}
+
+	return true
+}
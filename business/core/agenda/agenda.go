@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
+	"sort"
+	"strconv"
 	"time"
 
+	"github.com/ameghdadian/service/business/core/appointment"
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/ameghdadian/service/business/data/order"
 	"github.com/ameghdadian/service/business/data/page"
 	"github.com/ameghdadian/service/business/data/transaction"
@@ -18,11 +22,18 @@ import (
 )
 
 var (
-	ErrNotFound       = errors.New("agenda is not found")
-	ErrOutOfRange     = errors.New("selected time is not within business working hours")
-	ErrIntervalAbused = errors.New("interval is not respected")
-	ErrNoDailyAgenda  = errors.New("no daily agenda found")
-	ErrBusinessOff    = errors.New("business has no activity at given date")
+	ErrNotFound            = errors.New("agenda is not found")
+	ErrOutOfRange          = errors.New("selected time is not within business working hours")
+	ErrIntervalAbused      = errors.New("interval is not respected")
+	ErrNoDailyAgenda       = errors.New("no daily agenda found")
+	ErrBusinessOff         = errors.New("business has no activity at given date")
+	ErrInvalidRange        = errors.New("end of range should be after its start")
+	ErrNotWorkingDay       = errors.New("selected day is not a working day of business")
+	ErrInvalidHours        = errors.New("opening hours are not valid")
+	ErrInvalidWindows      = errors.New("daily agenda windows are not valid")
+	ErrDailyAgendaExists   = errors.New("business already has a daily agenda on this date")
+	ErrGeneralAgendaExists = errors.New("business or resource already has a general agenda in effect on these dates")
+	ErrResourceMismatch    = errors.New("resource does not belong to the business")
 )
 
 type Storer interface {
@@ -31,7 +42,8 @@ type Storer interface {
 	UpdateGeneralAgenda(ctx context.Context, agd GeneralAgenda) error
 	DeleteGeneralAgenda(ctx context.Context, agd GeneralAgenda) error
 	QueryGeneralAgenda(ctx context.Context, filter GAQueryFilter, orderBy order.By, page page.Page) ([]GeneralAgenda, error)
-	QueryGeneralAgendaByBusinessID(ctx context.Context, bsnID uuid.UUID) (GeneralAgenda, error)
+	QueryGeneralAgendaByBusinessID(ctx context.Context, bsnID uuid.UUID, date time.Time) (GeneralAgenda, error)
+	QueryGeneralAgendaByResourceID(ctx context.Context, rscID uuid.UUID, date time.Time) (GeneralAgenda, error)
 	QueryGeneralAgendaByID(ctx context.Context, agdID uuid.UUID) (GeneralAgenda, error)
 	CountGeneralAgenda(ctx context.Context, filter GAQueryFilter) (int, error)
 
@@ -41,18 +53,29 @@ type Storer interface {
 	QueryDailyAgenda(ctx context.Context, filter DAQueryFilter, orderBy order.By, page page.Page) ([]DailyAgenda, error)
 	CountDailyAgenda(ctx context.Context, filter DAQueryFilter) (int, error)
 	QueryDailyAgendaByID(ctx context.Context, agdID uuid.UUID) (DailyAgenda, error)
+
+	CreateClosure(ctx context.Context, cl Closure) error
+	UpdateClosure(ctx context.Context, cl Closure) error
+	DeleteClosure(ctx context.Context, cl Closure) error
+	QueryClosures(ctx context.Context, filter ClosureQueryFilter, orderBy order.By, page page.Page) ([]Closure, error)
+	CountClosures(ctx context.Context, filter ClosureQueryFilter) (int, error)
+	QueryClosureByID(ctx context.Context, clID uuid.UUID) (Closure, error)
 }
 
 type Core struct {
 	storer  Storer
 	bsnCore *business.Core
+	rscCore *resource.Core
+	aptCore *appointment.Core
 	log     *logger.Logger
 }
 
-func NewCore(log *logger.Logger, bsnCore *business.Core, storer Storer) *Core {
+func NewCore(log *logger.Logger, bsnCore *business.Core, rscCore *resource.Core, aptCore *appointment.Core, storer Storer) *Core {
 	return &Core{
 		storer:  storer,
 		bsnCore: bsnCore,
+		rscCore: rscCore,
+		aptCore: aptCore,
 		log:     log,
 	}
 }
@@ -63,9 +86,27 @@ func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error
 		return nil, err
 	}
 
+	bsnCore, err := c.bsnCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rscCore, err := c.rscCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	aptCore, err := c.aptCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
 	c = &Core{
-		storer: storer,
-		log:    c.log,
+		storer:  storer,
+		bsnCore: bsnCore,
+		rscCore: rscCore,
+		aptCore: aptCore,
+		log:     c.log,
 	}
 
 	return c, nil
@@ -75,17 +116,28 @@ func (c *Core) CreateGeneralAgenda(ctx context.Context, na NewGeneralAgenda) (Ge
 	ctx, span := otel.AddSpan(ctx, "business.generalagenda.create")
 	defer span.End()
 
+	if err := validateHours(na.Hours); err != nil {
+		return GeneralAgenda{}, err
+	}
+
+	if err := c.checkResource(ctx, na.BusinessID, na.ResourceID); err != nil {
+		return GeneralAgenda{}, err
+	}
+
 	now := time.Now()
 
-	agd := GeneralAgenda{
-		ID:          uuid.New(),
-		BusinessID:  na.BusinessID,
-		OpensAt:     na.OpensAt,
-		ClosedAt:    na.ClosedAt,
-		Interval:    na.Interval,
-		WorkingDays: na.WorkingDays,
-		DateCreated: now,
-		DateUpdated: now,
+	agd, err := prepareGeneralAgenda(GeneralAgenda{
+		ID:            uuid.New(),
+		BusinessID:    na.BusinessID,
+		ResourceID:    na.ResourceID,
+		Hours:         na.Hours,
+		EffectiveFrom: na.EffectiveFrom,
+		EffectiveTo:   na.EffectiveTo,
+		DateCreated:   now,
+		DateUpdated:   now,
+	})
+	if err != nil {
+		return GeneralAgenda{}, err
 	}
 
 	if err := c.storer.CreateGeneralAgenda(ctx, agd); err != nil {
@@ -99,20 +151,9 @@ func (c *Core) UpdateGenralAgenda(ctx context.Context, agd GeneralAgenda, uAgd U
 	ctx, span := otel.AddSpan(ctx, "business.generalagenda.update")
 	defer span.End()
 
-	if uAgd.OpensAt != nil {
-		agd.OpensAt = *uAgd.OpensAt
-	}
-
-	if uAgd.ClosedAt != nil {
-		agd.ClosedAt = *uAgd.ClosedAt
-	}
-
-	if uAgd.Interval != nil {
-		agd.Interval = *uAgd.Interval
-	}
-
-	if uAgd.WorkingDays != nil {
-		agd.WorkingDays = uAgd.WorkingDays
+	agd, err := updatedGeneralAgenda(agd, uAgd)
+	if err != nil {
+		return GeneralAgenda{}, err
 	}
 
 	agd.DateUpdated = time.Now()
@@ -146,11 +187,13 @@ func (c *Core) QueryGeneralAgenda(ctx context.Context, filter GAQueryFilter, ord
 	return agds, nil
 }
 
-func (c *Core) QueryGeneralAgendaByBusinessID(ctx context.Context, bsnID uuid.UUID) (GeneralAgenda, error) {
+// QueryGeneralAgendaByBusinessID returns the general agenda of the business as a whole
+// in effect on the calendar date of date, taken as it reads in the location of the date.
+func (c *Core) QueryGeneralAgendaByBusinessID(ctx context.Context, bsnID uuid.UUID, date time.Time) (GeneralAgenda, error) {
 	ctx, span := otel.AddSpan(ctx, "business.generalagenda.querybybusinessid")
 	defer span.End()
 
-	agd, err := c.storer.QueryGeneralAgendaByBusinessID(ctx, bsnID)
+	agd, err := c.storer.QueryGeneralAgendaByBusinessID(ctx, bsnID, date)
 	if err != nil {
 		return GeneralAgenda{}, fmt.Errorf("query: bsnID[%s]: %w", bsnID, err)
 	}
@@ -158,6 +201,20 @@ func (c *Core) QueryGeneralAgendaByBusinessID(ctx context.Context, bsnID uuid.UU
 	return agd, nil
 }
 
+// QueryGeneralAgendaByResourceID returns the general agenda of a resource in effect on
+// the calendar date of date, taken as it reads in the location of the date.
+func (c *Core) QueryGeneralAgendaByResourceID(ctx context.Context, rscID uuid.UUID, date time.Time) (GeneralAgenda, error) {
+	ctx, span := otel.AddSpan(ctx, "business.generalagenda.querybyresourceid")
+	defer span.End()
+
+	agd, err := c.storer.QueryGeneralAgendaByResourceID(ctx, rscID, date)
+	if err != nil {
+		return GeneralAgenda{}, fmt.Errorf("query: rscID[%s]: %w", rscID, err)
+	}
+
+	return agd, nil
+}
+
 func (c *Core) QueryGeneralAgendaByID(ctx context.Context, agdID uuid.UUID) (GeneralAgenda, error) {
 	ctx, span := otel.AddSpan(ctx, "business.generalagenda.querybyid")
 	defer span.End()
@@ -177,53 +234,234 @@ func (c *Core) CountGeneralAgenda(ctx context.Context, filter GAQueryFilter) (in
 	return c.storer.CountGeneralAgenda(ctx, filter)
 }
 
-// conformsGeneralAgendaBoundary checks two things:
-// 1. Whether check time is placed inside inclusive opening and closing business agenda,
-// 2. And if check time conforms with interval requirement.
-func (c *Core) conformGeneralAgendaBoundary(ctx context.Context, bsnID uuid.UUID, checkTime time.Time) error {
+// conformsGeneralAgendaBoundary treats general agenda as a recurring weekly template, expressed in the wall clock
+// time of the business time zone, and checks three things:
+// 1. Whether check time falls on one of the working days,
+// 2. Whether its time of day is placed inside inclusive opening and exclusive closing hour of any of the day's hours,
+// 3. And if check time conforms with interval requirement of those hours.
+func (c *Core) conformGeneralAgendaBoundary(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, loc *time.Location, checkTime time.Time) error {
 	ctx, span := otel.AddSpan(ctx, "business.generalagenda.conformboundary")
 	defer span.End()
 
-	agd, err := c.storer.QueryGeneralAgendaByBusinessID(ctx, bsnID)
+	check := checkTime.In(loc)
+
+	agd, err := c.generalAgendaOf(ctx, bsnID, rscID, check)
 	if err != nil {
-		return fmt.Errorf("query: bsnID[%s]: %w", bsnID, err)
+		return err
+	}
+
+	hours := agd.HoursOf(check.Weekday())
+	if len(hours) == 0 {
+		return ErrNotWorkingDay
+	}
+
+	wins := make([]Window, len(hours))
+	for i, h := range hours {
+		wins[i] = h.Window()
+	}
+
+	return conformWindows(clockSeconds(check), wins)
+}
+
+// updatedGeneralAgenda returns the general agenda as the update leaves it.
+func updatedGeneralAgenda(agd GeneralAgenda, uAgd UpdateGeneralAgenda) (GeneralAgenda, error) {
+	if uAgd.Hours != nil {
+		if err := validateHours(uAgd.Hours); err != nil {
+			return GeneralAgenda{}, err
+		}
+		agd.Hours = uAgd.Hours
+	}
+
+	if uAgd.EffectiveFrom != nil {
+		agd.EffectiveFrom = *uAgd.EffectiveFrom
+	}
+
+	if uAgd.EffectiveTo != nil {
+		agd.EffectiveTo = *uAgd.EffectiveTo
+	}
+
+	return prepareGeneralAgenda(agd)
+}
+
+// generalAgendaOf returns the general agenda of a resource in effect on the date of day,
+// falling back to the one of the business when the resource has none of its own in
+// effect or rscID is uuid.Nil.
+func (c *Core) generalAgendaOf(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, day time.Time) (GeneralAgenda, error) {
+	if rscID != uuid.Nil {
+		agd, err := c.storer.QueryGeneralAgendaByResourceID(ctx, rscID, day)
+		switch {
+		case err == nil:
+			return agd, nil
+		case !errors.Is(err, ErrNotFound):
+			return GeneralAgenda{}, fmt.Errorf("query: rscID[%s]: %w", rscID, err)
+		}
+	}
+
+	agd, err := c.storer.QueryGeneralAgendaByBusinessID(ctx, bsnID, day)
+	if err != nil {
+		return GeneralAgenda{}, fmt.Errorf("query: bsnID[%s]: %w", bsnID, err)
+	}
+
+	return agd, nil
+}
+
+// prepareGeneralAgenda normalizes the effective dates of a general agenda to calendar
+// dates and validates them.
+func prepareGeneralAgenda(agd GeneralAgenda) (GeneralAgenda, error) {
+	if !agd.EffectiveFrom.IsZero() {
+		agd.EffectiveFrom = calendarDate(agd.EffectiveFrom)
 	}
 
-	opens := agd.OpensAt.UTC()
-	closed := agd.ClosedAt.UTC()
-	check := checkTime.UTC()
+	if !agd.EffectiveTo.IsZero() {
+		agd.EffectiveTo = calendarDate(agd.EffectiveTo)
+	}
 
-	if check.Before(opens) || check.Equal(closed) || check.After(closed) {
-		return ErrOutOfRange
+	if !agd.EffectiveFrom.IsZero() && !agd.EffectiveTo.IsZero() && agd.EffectiveTo.Before(agd.EffectiveFrom) {
+		return GeneralAgenda{}, ErrInvalidRange
 	}
 
-	interval := agd.Interval
-	checkpoint := check.Sub(opens).Seconds()
+	return agd, nil
+}
+
+// checkResource makes sure the resource of the given id, unless uuid.Nil, belongs to the business.
+func (c *Core) checkResource(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID) error {
+	if rscID == uuid.Nil {
+		return nil
+	}
+
+	rsc, err := c.rscCore.QueryByID(ctx, rscID)
+	if err != nil {
+		return fmt.Errorf("resource.querybyid: %s: %w", rscID, err)
+	}
 
-	if int(math.Ceil(checkpoint))%interval != 0 {
-		return ErrIntervalAbused
+	if rsc.BusinessID != bsnID {
+		return ErrResourceMismatch
 	}
 
 	return nil
 }
 
+// conformWindows checks whether a wall clock, given in seconds since midnight, is placed inside
+// one of the windows and conforms with its interval requirement.
+func conformWindows(checkpoint int, wins []Window) error {
+	for _, w := range wins {
+		opens := w.OpensAt.Seconds()
+		if checkpoint < opens || checkpoint >= w.ClosedAt.Seconds() {
+			continue
+		}
+
+		if (checkpoint-opens)%w.Interval != 0 {
+			return ErrIntervalAbused
+		}
+
+		return nil
+	}
+
+	return ErrOutOfRange
+}
+
+// validateHours checks every opening hours opens before it closes with a sane interval,
+// and that opening hours of the same weekday don't overlap.
+func validateHours(hours []OpeningHours) error {
+	agd := GeneralAgenda{Hours: hours}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		dh := agd.HoursOf(wd)
+
+		wins := make([]Window, len(dh))
+		for i, h := range dh {
+			wins[i] = h.Window()
+		}
+
+		if err := validateWindows(wins); err != nil {
+			return fmt.Errorf("%w: day[%d]: %s", ErrInvalidHours, wd, err)
+		}
+	}
+
+	return nil
+}
+
+// validateWindows checks every window, already ordered by opening, opens before it closes
+// with a sane interval and doesn't overlap the window before it.
+func validateWindows(wins []Window) error {
+	for i, w := range wins {
+		if !w.OpensAt.Before(w.ClosedAt) {
+			return fmt.Errorf("%s should be before %s", w.OpensAt, w.ClosedAt)
+		}
+
+		if w.Interval <= 0 || w.Interval > secondsPerDay {
+			return fmt.Errorf("interval should be between 1 and %d seconds", secondsPerDay)
+		}
+
+		if i > 0 && w.OpensAt.Before(wins[i-1].ClosedAt) {
+			return fmt.Errorf("%s-%s overlaps %s-%s", wins[i-1].OpensAt, wins[i-1].ClosedAt, w.OpensAt, w.ClosedAt)
+		}
+	}
+
+	return nil
+}
+
+// prepareDailyAgenda normalizes the date of a daily agenda to its calendar date, orders its
+// windows by opening and validates them against its availability.
+func prepareDailyAgenda(agd DailyAgenda) (DailyAgenda, error) {
+	agd.Date = calendarDate(agd.Date)
+
+	wins := make([]Window, len(agd.Windows))
+	copy(wins, agd.Windows)
+	sort.Slice(wins, func(i, j int) bool {
+		return wins[i].OpensAt.Before(wins[j].OpensAt)
+	})
+	agd.Windows = wins
+
+	switch {
+	case agd.Availability && len(wins) == 0:
+		return DailyAgenda{}, fmt.Errorf("%w: an available day needs at least one window", ErrInvalidWindows)
+	case !agd.Availability && len(wins) != 0:
+		return DailyAgenda{}, fmt.Errorf("%w: an unavailable day can't have windows", ErrInvalidWindows)
+	}
+
+	if err := validateWindows(wins); err != nil {
+		return DailyAgenda{}, fmt.Errorf("%w: %s", ErrInvalidWindows, err)
+	}
+
+	return agd, nil
+}
+
+// calendarDate returns the date of t, as it reads in its own location, at midnight UTC.
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// clockSeconds returns the seconds elapsed since midnight on the wall clock of t.
+// Comparing wall clocks instead of instants keeps the checks correct on days
+// with daylight saving transitions.
+func clockSeconds(t time.Time) int {
+	return t.Hour()*3600 + t.Minute()*60 + t.Second()
+}
+
+// atClockSeconds returns the instant on the date of day whose wall clock is sec seconds past midnight.
+func atClockSeconds(day time.Time, sec int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, sec, 0, day.Location())
+}
+
+// businessLocation returns the location of the time zone business operates in.
+func (c *Core) businessLocation(ctx context.Context, bsnID uuid.UUID) (*time.Location, error) {
+	bsn, err := c.bsnCore.QueryByID(ctx, bsnID)
+	if err != nil {
+		return nil, fmt.Errorf("business.querybyid: %s: %w", bsnID, err)
+	}
+
+	return bsn.TimeZone.Location(), nil
+}
+
 // -------------------------------------------------------------------------------------------------------
 
 func (c *Core) CreateDailyAgenda(ctx context.Context, na NewDailyAgenda) (DailyAgenda, error) {
 	ctx, span := otel.AddSpan(ctx, "business.dailyagenda.create")
 	defer span.End()
 
-	now := time.Now()
-
-	agd := DailyAgenda{
-		ID:           uuid.New(),
-		BusinessID:   na.BusinessID,
-		OpensAt:      na.OpensAt,
-		ClosedAt:     na.ClosedAt,
-		Interval:     na.Interval,
-		Availability: na.Availability,
-		DateCreated:  now,
-		DateUpdated:  now,
+	agd, err := c.newDailyAgenda(ctx, na)
+	if err != nil {
+		return DailyAgenda{}, err
 	}
 
 	if err := c.storer.CreateDailyAgenda(ctx, agd); err != nil {
@@ -233,33 +471,64 @@ func (c *Core) CreateDailyAgenda(ctx context.Context, na NewDailyAgenda) (DailyA
 	return agd, nil
 }
 
+// newDailyAgenda returns the daily agenda the new daily agenda is made into.
+func (c *Core) newDailyAgenda(ctx context.Context, na NewDailyAgenda) (DailyAgenda, error) {
+	if err := c.checkResource(ctx, na.BusinessID, na.ResourceID); err != nil {
+		return DailyAgenda{}, err
+	}
+
+	now := time.Now()
+
+	return prepareDailyAgenda(DailyAgenda{
+		ID:           uuid.New(),
+		BusinessID:   na.BusinessID,
+		ResourceID:   na.ResourceID,
+		Date:         na.Date,
+		Availability: na.Availability,
+		Windows:      na.Windows,
+		DateCreated:  now,
+		DateUpdated:  now,
+	})
+}
+
 func (c *Core) UpdateDailyAgenda(ctx context.Context, agd DailyAgenda, uAgd UpdateDailyAgenda) (DailyAgenda, error) {
 	ctx, span := otel.AddSpan(ctx, "business.dailyagenda.update")
 	defer span.End()
 
-	if uAgd.OpensAt != nil {
-		agd.OpensAt = *uAgd.OpensAt
+	agd, err := updatedDailyAgenda(agd, uAgd)
+	if err != nil {
+		return DailyAgenda{}, err
 	}
 
-	if uAgd.ClosedAt != nil {
-		agd.ClosedAt = *uAgd.ClosedAt
+	agd.DateUpdated = time.Now()
+
+	if err := c.storer.UpdateDailyAgenda(ctx, agd); err != nil {
+		return DailyAgenda{}, fmt.Errorf("update: %w", err)
 	}
 
-	if uAgd.Interval != nil {
-		agd.Interval = *uAgd.Interval
+	return agd, nil
+}
+
+// updatedDailyAgenda returns the daily agenda as the update leaves it.
+func updatedDailyAgenda(agd DailyAgenda, uAgd UpdateDailyAgenda) (DailyAgenda, error) {
+	if uAgd.Date != nil {
+		agd.Date = *uAgd.Date
 	}
 
 	if uAgd.Availability != nil {
 		agd.Availability = *uAgd.Availability
-	}
 
-	agd.DateUpdated = time.Now()
+		// Closing the day drops its windows unless new ones are given, which is then rejected.
+		if !agd.Availability {
+			agd.Windows = nil
+		}
+	}
 
-	if err := c.storer.UpdateDailyAgenda(ctx, agd); err != nil {
-		return DailyAgenda{}, fmt.Errorf("update: %w", err)
+	if uAgd.Windows != nil {
+		agd.Windows = uAgd.Windows
 	}
 
-	return agd, nil
+	return prepareDailyAgenda(agd)
 }
 
 func (c *Core) DeleteDailyAgenda(ctx context.Context, agd DailyAgenda) error {
@@ -304,73 +573,363 @@ func (c *Core) QueryDailyAgendaByID(ctx context.Context, agdID uuid.UUID) (Daily
 	return agd, nil
 }
 
-// conformsDailyAgendaBoundary checks two things:
-// 1. Whether check time is placed inside inclusive opening and closing business agenda,
-// 2. And if check time conforms with interval requirement.
-func (c *Core) conformDailyAgendaBoundary(ctx context.Context, bsnID uuid.UUID, checkTime time.Time) error {
+// conformsDailyAgendaBoundary checks three things:
+// 1. Whether business, or the resource, is available on the date of check time, resolved in the business time zone,
+// 2. Whether its time of day is placed inside inclusive opening and exclusive closing hour of any of the windows,
+// 3. And if check time conforms with interval requirement of that window.
+func (c *Core) conformDailyAgendaBoundary(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, loc *time.Location, checkTime time.Time) error {
 	ctx, span := otel.AddSpan(ctx, "business.dailyagenda.conformboundary")
 	defer span.End()
 
+	check := checkTime.In(loc)
+
+	agd, found, err := c.dailyAgendaOn(ctx, bsnID, rscID, check)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return ErrNoDailyAgenda
+	}
+
+	if !agd.Availability {
+		return ErrBusinessOff
+	}
+
+	return conformWindows(clockSeconds(check), agd.Windows)
+}
+
+// dailyAgendaOn returns the daily agenda of a resource on the date of day, if there is any,
+// falling back to the one of the business. Given uuid.Nil, only the business is looked at.
+func (c *Core) dailyAgendaOn(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, day time.Time) (DailyAgenda, bool, error) {
+	if rscID != uuid.Nil {
+		agd, found, err := c.queryDailyAgendaOn(ctx, bsnID, rscID, day)
+		if err != nil || found {
+			return agd, found, err
+		}
+	}
+
+	return c.queryDailyAgendaOn(ctx, bsnID, uuid.Nil, day)
+}
+
+// queryDailyAgendaOn returns the daily agenda of exactly the given resource, or of the business
+// as a whole when given uuid.Nil, on the date of day.
+func (c *Core) queryDailyAgendaOn(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, day time.Time) (DailyAgenda, bool, error) {
 	var filter DAQueryFilter
 	filter.WithBusinessID(bsnID)
-	filter.WithDate(checkTime.UTC())
+	filter.WithResourceID(rscID)
+	filter.WithDate(day)
 
-	pagination, err := page.Parse("1", "10")
+	pagination, err := page.Parse("1", "1")
 	if err != nil {
-		return fmt.Errorf("couldn't parse page parameters: %w", err)
+		return DailyAgenda{}, false, fmt.Errorf("couldn't parse page parameters: %w", err)
 	}
+
 	agds, err := c.storer.QueryDailyAgenda(ctx, filter, DefaultOrderBy, pagination)
 	if err != nil {
-		return err
+		return DailyAgenda{}, false, fmt.Errorf("query daily agenda: %w", err)
 	}
 
 	if len(agds) == 0 {
-		return ErrNoDailyAgenda
+		return DailyAgenda{}, false, nil
+	}
+
+	return agds[0], true, nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// TimeWithinAgendaBoundary checks the given time against the agendas of a resource, or of
+// the business as a whole when given uuid.Nil. Closures come first, then daily agendas and
+// then general ones, and at each agenda level the agenda of the resource comes before the
+// one of the business.
+func (c *Core) TimeWithinAgendaBoundary(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, checkTime time.Time) error {
+	loc, err := c.businessLocation(ctx, bsnID)
+	if err != nil {
+		return err
+	}
+
+	closed, err := c.closedOn(ctx, bsnID, rscID, checkTime.In(loc))
+	if err != nil {
+		return err
+	}
+
+	if closed {
+		return errs.New(errs.InvalidArgument, ErrClosed)
+	}
+
+	err = c.conformDailyAgendaBoundary(ctx, bsnID, rscID, loc, checkTime)
+	if err != nil {
+		if !errors.Is(err, ErrNoDailyAgenda) {
+			return errs.New(errs.InvalidArgument, err)
+		}
+
+		// If doesn't conform with daily agenda, check with the general agenda to see any match.
+		if err = c.conformGeneralAgendaBoundary(ctx, bsnID, rscID, loc, checkTime); err != nil {
+			return errs.New(errs.InvalidArgument, err)
+		}
 	}
 
-	err = ErrBusinessOff
-	for i := range agds {
-		if agds[i].Availability {
+	return nil
+}
+
+// -------------------------------------------------------------------------------------------------------
+
+// period is a continuous stretch of a specific day in which a business accepts
+// appointments every interval seconds. Bounds are in the business time zone.
+type period struct {
+	opens    time.Time
+	closed   time.Time
+	interval int
+}
+
+// AvailableSlots returns the free slots of a resource starting within [from, to). Given
+// uuid.Nil, the slots of every resource of the business are returned, ordered by their
+// start, or the slots of the business as a whole when it has no resources.
+// Each day, in the business time zone, is expanded from its daily agendas when
+// there is any, otherwise from the general agenda. Slots in the past, outside the
+// booking window of the service or already booked are left out.
+//
+// Slots last one interval and hold one person, unless a service is given; then slots
+// last as long as the service and must end by the period closing. A slot of a service
+// holding more than one person stays available, alongside its bookings, until full.
+func (c *Core) AvailableSlots(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, from time.Time, to time.Time, svc service.Service) ([]Slot, error) {
+	ctx, span := otel.AddSpan(ctx, "business.agenda.availableslots")
+	defer span.End()
+
+	if !to.After(from) {
+		return nil, ErrInvalidRange
+	}
 
-			opens := agds[i].OpensAt.UTC()
-			closed := agds[i].ClosedAt.UTC()
-			check := checkTime.UTC()
+	bsn, err := c.bsnCore.QueryByID(ctx, bsnID)
+	if err != nil {
+		return nil, fmt.Errorf("business.querybyid: %s: %w", bsnID, err)
+	}
+	loc := bsn.TimeZone.Location()
 
-			if (check.Equal(opens) || check.After(opens)) && check.Before(closed) {
+	// Without a service, the booking window of the business applies.
+	now := time.Now()
+	lead, horizon := svc.BookingWindow(bsn)
+	if earliest := now.Add(lead); from.Before(earliest) {
+		from = earliest
+	}
+	if latest := now.Add(horizon); horizon > 0 && to.After(latest) {
+		to = latest
+	}
+	if !to.After(from) {
+		return nil, nil
+	}
 
-				interval := agds[i].Interval
-				checkpoint := check.Sub(opens).Seconds()
+	rscIDs := []uuid.UUID{rscID}
+	switch rscID {
+	case uuid.Nil:
+		rscs, err := c.rscCore.QueryByBusinessID(ctx, bsnID)
+		if err != nil {
+			return nil, fmt.Errorf("resource.querybybusinessid: %s: %w", bsnID, err)
+		}
 
-				if int(math.Ceil(checkpoint))%interval != 0 {
-					err = ErrIntervalAbused
+		if len(rscs) > 0 {
+			rscIDs = make([]uuid.UUID, len(rscs))
+			for i, rsc := range rscs {
+				rscIDs[i] = rsc.ID
+			}
+		}
+	default:
+		if err := c.checkResource(ctx, bsnID, rscID); err != nil {
+			return nil, err
+		}
+	}
+
+	var slots []Slot
+	for _, id := range rscIDs {
+		rscSlots, err := c.resourceSlots(ctx, bsnID, id, from.In(loc), to.In(loc), svc)
+		if err != nil {
+			return nil, err
+		}
+
+		slots = append(slots, rscSlots...)
+	}
+
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].StartsAt.Before(slots[j].StartsAt)
+	})
+
+	return slots, nil
+}
+
+// resourceSlots returns the free slots of a single resource, or of the business as a
+// whole when given uuid.Nil, starting within [from, to) given in the business time zone.
+func (c *Core) resourceSlots(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, from time.Time, to time.Time, svc service.Service) ([]Slot, error) {
+	booked, err := c.bookedPeriods(ctx, bsnID, rscID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	capacity := max(svc.Capacity, 1)
+
+	var slots []Slot
+	for day := atClockSeconds(from, 0); day.Before(to); day = day.AddDate(0, 0, 1) {
+		pers, err := c.dayPeriods(ctx, bsnID, rscID, day)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, w := range pers {
+			if w.interval <= 0 {
+				continue
+			}
+
+			// Slots are laid on the wall clock so that they keep their local
+			// time of day across daylight saving transitions.
+			opens := clockSeconds(w.opens)
+			for sec := opens; ; sec += w.interval {
+				start := atClockSeconds(w.opens, sec)
+				if !start.Before(w.closed) {
 					break
 				}
 
-				return nil
+				if start.Before(from) || !start.Before(to) || start.Before(now) {
+					continue
+				}
+
+				end := atClockSeconds(w.opens, sec+w.interval)
+				if svc.Duration > 0 {
+					end = start.Add(svc.Duration)
+					if end.After(w.closed) {
+						break
+					}
+				}
+
+				shared, ok := slotBookings(booked, svc, start, end)
+				if !ok || shared >= capacity {
+					continue
+				}
+
+				slots = append(slots, Slot{
+					ResourceID: rscID,
+					StartsAt:   start,
+					EndsAt:     end,
+					Capacity:   capacity,
+					Remaining:  capacity - shared,
+				})
 			}
+		}
+	}
+
+	return slots, nil
+}
+
+// dayPeriods resolves the periods of the given day. A daily agenda overrides the
+// general agenda in effect on the day; a closure or an unavailable daily agenda
+// leaves no period at all.
+func (c *Core) dayPeriods(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, day time.Time) ([]period, error) {
+	closed, err := c.closedOn(ctx, bsnID, rscID, day)
+	if err != nil {
+		return nil, err
+	}
 
-			err = ErrOutOfRange
+	if closed {
+		return nil, nil
+	}
+
+	dAgd, found, err := c.dailyAgendaOn(ctx, bsnID, rscID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	var wins []Window
+	switch {
+	case found:
+		wins = dAgd.Windows
+	default:
+		gAgd, err := c.generalAgendaOf(ctx, bsnID, rscID, day)
+		if err != nil {
+			if !errors.Is(err, ErrNotFound) {
+				return nil, err
+			}
+			break
+		}
+
+		for _, h := range gAgd.HoursOf(day.Weekday()) {
+			wins = append(wins, h.Window())
 		}
+	}
 
+	pers := make([]period, len(wins))
+	for i, w := range wins {
+		pers[i] = period{
+			opens:    w.OpensAt.On(day),
+			closed:   w.ClosedAt.On(day),
+			interval: w.Interval,
+		}
 	}
 
-	return err
+	return pers, nil
 }
 
-// -------------------------------------------------------------------------------------------------------
+// bookedPeriods returns the appointments of a resource, or of the business as a
+// whole when given uuid.Nil, holding their slot, which overlap with [from, to).
+// Appointments last no longer than a day, so the ones starting a day before from are
+// looked at too.
+func (c *Core) bookedPeriods(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, from time.Time, to time.Time) ([]appointment.Appointment, error) {
+	var filter appointment.QueryFilter
+	filter.WithBusinessID(bsnID)
+	filter.WithStartScheduledOn(from.Add(-24 * time.Hour))
+	filter.WithEndScheduledOn(to)
 
-func (c *Core) TimeWithinAgendaBoundary(ctx context.Context, bsnID uuid.UUID, checkTime time.Time) error {
-	err := c.conformDailyAgendaBoundary(ctx, bsnID, checkTime)
-	if err != nil {
-		if !errors.Is(err, ErrNoDailyAgenda) {
-			return errs.New(errs.InvalidArgument, err)
+	const rows = 100
+
+	var booked []appointment.Appointment
+	for pn := 1; ; pn++ {
+		pagination, err := page.Parse(strconv.Itoa(pn), strconv.Itoa(rows))
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse page parameters: %w", err)
 		}
 
-		// If doesn't conform with daily agenda, check with the general agenda to see any match.
-		if err = c.conformGeneralAgendaBoundary(ctx, bsnID, checkTime); err != nil {
-			return errs.New(errs.InvalidArgument, err)
+		apts, err := c.aptCore.Query(ctx, filter, appointment.DefaultOrderBy, pagination)
+		if err != nil {
+			return nil, fmt.Errorf("query appointments: %w", err)
+		}
+
+		for _, apt := range apts {
+			if apt.ResourceID != rscID || !apt.Status.Holds() || !apt.EndsOn().After(from) {
+				continue
+			}
+			booked = append(booked, apt)
+		}
+
+		if len(apts) < rows {
+			break
 		}
 	}
 
-	return nil
+	return booked, nil
+}
+
+// slotBookings returns the number of appointments sharing the slot [start, end) of
+// the given service. It reports false when any other appointment overlaps with it.
+func slotBookings(booked []appointment.Appointment, svc service.Service, start time.Time, end time.Time) (int, bool) {
+	slot := appointment.Appointment{
+		ServiceID:   svc.ID,
+		ScheduledOn: start,
+		Duration:    end.Sub(start),
+		Capacity:    svc.Capacity,
+	}
+
+	var shared int
+	for _, apt := range booked {
+		if !apt.ScheduledOn.Before(slot.EndsOn()) || !apt.EndsOn().After(start) {
+			continue
+		}
+
+		if !slot.SharesSlot(apt) {
+			return 0, false
+		}
+
+		shared++
+	}
+
+	return shared, true
 }
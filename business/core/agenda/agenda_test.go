@@ -5,11 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ameghdadian/service/business/core/agenda"
+	"github.com/ameghdadian/service/business/core/appointment"
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/business/data/dbtest"
 	"github.com/ameghdadian/service/business/data/order"
@@ -53,6 +57,13 @@ func TestMain(m *testing.M) {
 
 func Test_Agenda(t *testing.T) {
 	t.Run("crud", crud)
+	t.Run("slots", slots)
+	t.Run("resources", resources)
+	t.Run("capacity", capacity)
+	t.Run("imports", imports)
+	t.Run("closures", closures)
+	t.Run("seasons", seasons)
+	t.Run("affected", affected)
 }
 
 func crud(t *testing.T) {
@@ -198,19 +209,28 @@ func crud(t *testing.T) {
 	// ----------------------------------------------------------------------------------------------------------------
 	// Create
 
-	loc, _ := time.LoadLocation("America/New_York")
-	now := time.Now()
-	wd, err := agenda.GetWorkingDays(0, 2, 3)
-	if err != nil {
-		t.Fatal("Should be able to call GetWorkingDays: s", err)
+	nga := agenda.NewGeneralAgenda{
+		BusinessID: sd.bsns[1].ID,
+		Hours: []agenda.OpeningHours{
+			{Day: agenda.DaySunday, OpensAt: agenda.MustParseClock("14:10"), ClosedAt: agenda.MustParseClock("20:00"), Interval: 60 * 20},
+			{Day: agenda.DayTuesday, OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 30},
+			{Day: agenda.DayTuesday, OpensAt: agenda.MustParseClock("13:00"), ClosedAt: agenda.MustParseClock("17:30"), Interval: 60 * 30},
+			{Day: agenda.DayWednesday, OpensAt: agenda.MustParseClock("14:10"), ClosedAt: agenda.MustParseClock("20:00"), Interval: 60 * 20},
+		},
 	}
 
-	nga := agenda.NewGeneralAgenda{
-		BusinessID:  sd.bsns[1].ID,
-		OpensAt:     time.Date(now.Year(), now.Month(), now.Day(), 14, 10, 0, 0, loc),
-		ClosedAt:    time.Date(now.Year(), now.Month(), now.Day(), 20, 0, 0, 0, loc),
-		Interval:    60 * 20,
-		WorkingDays: wd,
+	overlapping := agenda.NewGeneralAgenda{
+		BusinessID: sd.bsns[1].ID,
+		Hours: []agenda.OpeningHours{
+			{Day: agenda.DayMonday, OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 30},
+			{Day: agenda.DayMonday, OpensAt: agenda.MustParseClock("11:30"), ClosedAt: agenda.MustParseClock("17:30"), Interval: 60 * 30},
+		},
+	}
+
+	if _, err := api.Agenda.CreateGeneralAgenda(ctx, overlapping); !errors.Is(err, agenda.ErrInvalidHours) {
+		t.Error("Should reject overlapping opening hours")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrInvalidHours)
 	}
 
 	gagd, err := api.Agenda.CreateGeneralAgenda(ctx, nga)
@@ -246,8 +266,12 @@ func crud(t *testing.T) {
 	// ----------------------------------------------------------------------------------------------------------------
 	// Update
 
-	wd, _ = agenda.GetWorkingDays(0, 1, 3)
-	uga := agenda.UpdateGeneralAgenda{WorkingDays: wd}
+	uga := agenda.UpdateGeneralAgenda{
+		Hours: []agenda.OpeningHours{
+			{Day: agenda.DayMonday, OpensAt: agenda.MustParseClock("08:00"), ClosedAt: agenda.MustParseClock("13:00"), Interval: 60 * 15},
+			{Day: agenda.DaySaturday, OpensAt: agenda.MustParseClock("10:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 15},
+		},
+	}
 	agd, err := api.Agenda.UpdateGenralAgenda(ctx, sd.gAgds[0], uga)
 	if err != nil {
 		t.Fatalf("Should be able to update general agenda: %s", err)
@@ -351,15 +375,30 @@ func crud(t *testing.T) {
 	// ----------------------------------------------------------------------------------------------------------------
 	// Create
 
-	loc, _ = time.LoadLocation("America/New_York")
-	now = time.Now()
-
 	nda := agenda.NewDailyAgenda{
 		BusinessID:   sd.bsns[1].ID,
-		OpensAt:      time.Date(now.Year(), now.Month(), now.Day(), 14, 10, 0, 0, loc),
-		ClosedAt:     time.Date(now.Year(), now.Month(), now.Day(), 20, 0, 0, 0, loc),
-		Interval:     60 * 20,
+		Date:         time.Now().UTC().AddDate(0, 0, 5),
+		Availability: true,
+		Windows: []agenda.Window{
+			{OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 30},
+			{OpensAt: agenda.MustParseClock("14:10"), ClosedAt: agenda.MustParseClock("20:00"), Interval: 60 * 20},
+		},
+	}
+
+	overlappingDA := agenda.NewDailyAgenda{
+		BusinessID:   sd.bsns[1].ID,
+		Date:         nda.Date,
 		Availability: true,
+		Windows: []agenda.Window{
+			{OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 30},
+			{OpensAt: agenda.MustParseClock("11:30"), ClosedAt: agenda.MustParseClock("17:30"), Interval: 60 * 30},
+		},
+	}
+
+	if _, err := api.Agenda.CreateDailyAgenda(ctx, overlappingDA); !errors.Is(err, agenda.ErrInvalidWindows) {
+		t.Error("Should reject overlapping windows")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrInvalidWindows)
 	}
 
 	dagd, err := api.Agenda.CreateDailyAgenda(ctx, nda)
@@ -367,6 +406,12 @@ func crud(t *testing.T) {
 		t.Fatalf("Should be able to create a daily agenda: %s", err)
 	}
 
+	if _, err := api.Agenda.CreateDailyAgenda(ctx, nda); !errors.Is(err, agenda.ErrDailyAgendaExists) {
+		t.Error("Should reject a second daily agenda on the same date")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrDailyAgendaExists)
+	}
+
 	daSaved, err = api.Agenda.QueryDailyAgendaByID(ctx, dagd.ID)
 	if err != nil {
 		t.Fatalf("Should be able to query daily agenda by id: %s", err)
@@ -395,13 +440,22 @@ func crud(t *testing.T) {
 	// ----------------------------------------------------------------------------------------------------------------
 	// Update
 
-	dura := 60 * 60
-	uda := agenda.UpdateDailyAgenda{Interval: &dura}
+	uda := agenda.UpdateDailyAgenda{
+		Windows: []agenda.Window{
+			{OpensAt: agenda.MustParseClock("15:00"), ClosedAt: agenda.MustParseClock("19:00"), Interval: 60 * 60},
+			{OpensAt: agenda.MustParseClock("08:00"), ClosedAt: agenda.MustParseClock("11:00"), Interval: 60 * 60},
+		},
+	}
 	dagd, err = api.Agenda.UpdateDailyAgenda(ctx, sd.dAgds[0], uda)
 	if err != nil {
 		t.Fatalf("Should be able to update daily agenda: %s", err)
 	}
 
+	if len(dagd.Windows) != 2 || !dagd.Windows[0].OpensAt.Equal(agenda.MustParseClock("08:00")) {
+		t.Error("Should keep the windows ordered by their opening")
+		t.Errorf("GOT: %v\n", dagd.Windows)
+	}
+
 	daSaved, err = api.Agenda.QueryDailyAgendaByID(ctx, sd.dAgds[0].ID)
 	if err != nil {
 		t.Fatalf("Should be able to query daily agenda by id: %s", err)
@@ -437,3 +491,1145 @@ func crud(t *testing.T) {
 		}
 	}
 }
+
+func slots(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var filter user.QueryFilter
+	filter.WithName("User Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Should be able to query users: %s", err)
+	}
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+
+	wd, err := agenda.GetWorkingDays(0, 1, 2, 3, 4, 5, 6)
+	if err != nil {
+		t.Fatalf("Should be able to call GetWorkingDays: %s", err)
+	}
+
+	hours := make([]agenda.OpeningHours, len(wd))
+	for i, d := range wd {
+		hours[i] = agenda.OpeningHours{
+			Day:      d,
+			OpensAt:  agenda.MustParseClock("09:00"),
+			ClosedAt: agenda.MustParseClock("12:00"),
+			Interval: 60 * 60,
+		}
+	}
+
+	nga := agenda.NewGeneralAgenda{
+		BusinessID: bsns[0].ID,
+		Hours:      hours,
+	}
+
+	gAgd, err := api.Agenda.CreateGeneralAgenda(ctx, nga)
+	if err != nil {
+		t.Fatalf("Should be able to create a general agenda: %s", err)
+	}
+
+	got, err := api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day, day.AddDate(0, 0, 1), service.Service{})
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	if len(got) != 3 {
+		t.Error("Should have the correct number of available slots")
+		t.Errorf("GOT: %d\n", len(got))
+		t.Errorf("EXP: %d\n", 3)
+	}
+
+	ns := service.NewService{
+		BusinessID: bsns[0].ID,
+		Name:       "Coloring",
+		Duration:   90 * time.Minute,
+		Price:      6000,
+		Currency:   service.MustParseCurrency("EUR"),
+		Capacity:   1,
+	}
+
+	svc, err := api.Service.Create(ctx, ns)
+	if err != nil {
+		t.Fatalf("Should be able to create a service: %s", err)
+	}
+
+	// Slots of the service last 90 minutes, so only 09:00 and 10:00 end by noon.
+	got, err = api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day, day.AddDate(0, 0, 1), svc)
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	if len(got) != 2 {
+		t.Error("Should leave out slots of the service ending after closing")
+		t.Errorf("GOT: %d\n", len(got))
+		t.Errorf("EXP: %d\n", 2)
+	}
+
+	// Tomorrow is at most 36 hours away, too soon to book a service needing two days ahead.
+	ns.Name = "Perm"
+	ns.LeadTime = 48 * time.Hour
+	perm, err := api.Service.Create(ctx, ns)
+	if err != nil {
+		t.Fatalf("Should be able to create a service: %s", err)
+	}
+
+	got, err = api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day, day.AddDate(0, 0, 1), perm)
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	if len(got) != 0 {
+		t.Error("Should leave out slots within the lead time of the service")
+		t.Errorf("GOT: %d\n", len(got))
+		t.Errorf("EXP: %d\n", 0)
+	}
+
+	na := appointment.NewAppointment{
+		BusinessID:  bsns[0].ID,
+		UserID:      usrs[0].ID,
+		ServiceID:   svc.ID,
+		ScheduledOn: day.Add(10 * time.Hour),
+	}
+
+	apt, err := api.Appointment.Create(ctx, na)
+	if err != nil {
+		t.Fatalf("Should be able to create an appointment: %s", err)
+	}
+
+	got, err = api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day, day.AddDate(0, 0, 1), service.Service{})
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	// The appointment lasts from 10:00 to 11:30, taking both the 10:00 and 11:00 slots.
+	if len(got) != 1 {
+		t.Error("Should leave booked slots out")
+		t.Errorf("GOT: %d\n", len(got))
+		t.Errorf("EXP: %d\n", 1)
+	}
+
+	for _, s := range got {
+		if s.StartsAt.Before(apt.EndsOn()) && s.EndsAt.After(apt.ScheduledOn) {
+			t.Errorf("Should not return a slot overlapping the booking: %s", s.StartsAt)
+		}
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Boundary
+
+	// General agenda is a weekly template, so the same hours hold a week later.
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.AddDate(0, 0, 7).Add(11*time.Hour)); err != nil {
+		t.Errorf("Should accept the same time of day on another working day: %s", err)
+	}
+
+	// A lunch break splits the day into two opening hours.
+	today, _ := agenda.ParseDay(uint(day.Weekday()))
+	uga := agenda.UpdateGeneralAgenda{
+		Hours: []agenda.OpeningHours{
+			{Day: today, OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("10:00"), Interval: 60 * 30},
+			{Day: today, OpensAt: agenda.MustParseClock("11:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 60},
+		},
+	}
+
+	gAgd, err = api.Agenda.UpdateGenralAgenda(ctx, gAgd, uga)
+	if err != nil {
+		t.Fatalf("Should be able to update general agenda: %s", err)
+	}
+
+	got, err = api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day, day.AddDate(0, 0, 1), service.Service{})
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	// 09:00 and 09:30 from the morning; 11:00 after the break is still booked.
+	if len(got) != 2 {
+		t.Error("Should have a slot for each free interval of each opening hours")
+		t.Errorf("GOT: %d\n", len(got))
+		t.Errorf("EXP: %d\n", 2)
+	}
+
+	err = api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.Add(10*time.Hour+30*time.Minute))
+	if err == nil || err.Error() != agenda.ErrOutOfRange.Error() {
+		t.Error("Should reject a time during the break")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrOutOfRange)
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.Add(9*time.Hour+30*time.Minute)); err != nil {
+		t.Errorf("Should accept a time respecting the interval of its opening hours: %s", err)
+	}
+
+	tomorrowDay, _ := agenda.ParseDay((uint(day.Weekday()) + 1) % 7)
+	uga = agenda.UpdateGeneralAgenda{
+		Hours: []agenda.OpeningHours{
+			{Day: tomorrowDay, OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 60},
+		},
+	}
+
+	if _, err := api.Agenda.UpdateGenralAgenda(ctx, gAgd, uga); err != nil {
+		t.Fatalf("Should be able to update general agenda: %s", err)
+	}
+
+	err = api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.Add(11*time.Hour))
+	if err == nil || err.Error() != agenda.ErrNotWorkingDay.Error() {
+		t.Error("Should reject a time on a non-working day")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrNotWorkingDay)
+	}
+}
+
+func resources(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var filter user.QueryFilter
+	filter.WithName("User Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Should be able to query users: %s", err)
+	}
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	rscs, err := resource.TestGenerateSeedResources(2, api.Resource, bsns[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed resources: %s", err)
+	}
+
+	svcs, err := service.TestGenerateSeedServices(1, api.Service, bsns[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed services: %s", err)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+	today, _ := agenda.ParseDay(uint(day.Weekday()))
+
+	// The business opens 09:00 to 12:00, while the second resource starts at 10:00.
+	nga := agenda.NewGeneralAgenda{
+		BusinessID: bsns[0].ID,
+		Hours: []agenda.OpeningHours{
+			{Day: today, OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 60},
+		},
+	}
+
+	if _, err := api.Agenda.CreateGeneralAgenda(ctx, nga); err != nil {
+		t.Fatalf("Should be able to create a general agenda: %s", err)
+	}
+
+	nga = agenda.NewGeneralAgenda{
+		BusinessID: bsns[0].ID,
+		ResourceID: rscs[1].ID,
+		Hours: []agenda.OpeningHours{
+			{Day: today, OpensAt: agenda.MustParseClock("10:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 60},
+		},
+	}
+
+	if _, err := api.Agenda.CreateGeneralAgenda(ctx, nga); err != nil {
+		t.Fatalf("Should be able to create a general agenda of a resource: %s", err)
+	}
+
+	if _, err := api.Agenda.CreateGeneralAgenda(ctx, nga); !errors.Is(err, agenda.ErrGeneralAgendaExists) {
+		t.Error("Should reject a second general agenda of the same resource")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrGeneralAgendaExists)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Boundary
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, rscs[0].ID, day.Add(9*time.Hour)); err != nil {
+		t.Errorf("Should follow the business agenda for a resource without one: %s", err)
+	}
+
+	err = api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, rscs[1].ID, day.Add(9*time.Hour))
+	if err == nil || err.Error() != agenda.ErrOutOfRange.Error() {
+		t.Error("Should follow the agenda of the resource")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrOutOfRange)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Appointments
+
+	na := appointment.NewAppointment{
+		BusinessID:  bsns[0].ID,
+		UserID:      usrs[0].ID,
+		ServiceID:   svcs[0].ID,
+		ScheduledOn: day.Add(10 * time.Hour),
+	}
+
+	if _, err := api.Appointment.Create(ctx, na); !errors.Is(err, appointment.ErrResourceRequired) {
+		t.Error("Should require a resource from a business with resources")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrResourceRequired)
+	}
+
+	// Both resources take an appointment at the same time.
+	for _, rsc := range rscs {
+		na.ResourceID = rsc.ID
+		if _, err := api.Appointment.Create(ctx, na); err != nil {
+			t.Fatalf("Should be able to book each resource at the same time: %s", err)
+		}
+	}
+
+	if _, err := api.Appointment.Create(ctx, na); !errors.Is(err, appointment.ErrAlreadyReserved) {
+		t.Error("Should reject an overlapping appointment of the same resource")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrAlreadyReserved)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Slots
+
+	got, err := api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day, day.AddDate(0, 0, 1), service.Service{})
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	// The first resource is free at 09:00 and 11:00, the second one only at 11:00.
+	exp := []agenda.Slot{
+		{ResourceID: rscs[0].ID, StartsAt: day.Add(9 * time.Hour), EndsAt: day.Add(10 * time.Hour), Capacity: 1, Remaining: 1},
+		{ResourceID: rscs[0].ID, StartsAt: day.Add(11 * time.Hour), EndsAt: day.Add(12 * time.Hour), Capacity: 1, Remaining: 1},
+		{ResourceID: rscs[1].ID, StartsAt: day.Add(11 * time.Hour), EndsAt: day.Add(12 * time.Hour), Capacity: 1, Remaining: 1},
+	}
+
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Errorf("Should get the free slots of every resource, diff:\n%s", diff)
+	}
+
+	got, err = api.Agenda.AvailableSlots(ctx, bsns[0].ID, rscs[1].ID, day, day.AddDate(0, 0, 1), service.Service{})
+	if err != nil {
+		t.Fatalf("Should be able to query available slots of a resource: %s", err)
+	}
+
+	if len(got) != 1 || got[0].ResourceID != rscs[1].ID {
+		t.Error("Should get the free slots of the given resource only")
+		t.Errorf("GOT: %v\n", got)
+	}
+}
+
+func capacity(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var filter user.QueryFilter
+	filter.WithName("User Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Should be able to query users: %s", err)
+	}
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	svcs, err := service.TestGenerateSeedServices(1, api.Service, bsns[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed services: %s", err)
+	}
+
+	ns := service.NewService{
+		BusinessID: bsns[0].ID,
+		Name:       "Yoga",
+		Duration:   time.Hour,
+		Price:      1500,
+		Currency:   service.MustParseCurrency("EUR"),
+		Capacity:   2,
+	}
+
+	class, err := api.Service.Create(ctx, ns)
+	if err != nil {
+		t.Fatalf("Should be able to create a service: %s", err)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+	today, _ := agenda.ParseDay(uint(day.Weekday()))
+
+	nga := agenda.NewGeneralAgenda{
+		BusinessID: bsns[0].ID,
+		Hours: []agenda.OpeningHours{
+			{Day: today, OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 60},
+		},
+	}
+
+	if _, err := api.Agenda.CreateGeneralAgenda(ctx, nga); err != nil {
+		t.Fatalf("Should be able to create a general agenda: %s", err)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Appointments
+
+	na := appointment.NewAppointment{
+		BusinessID:  bsns[0].ID,
+		UserID:      usrs[0].ID,
+		ServiceID:   class.ID,
+		ScheduledOn: day.Add(9 * time.Hour),
+	}
+
+	apt, err := api.Appointment.Create(ctx, na)
+	if err != nil {
+		t.Fatalf("Should be able to book a class: %s", err)
+	}
+
+	if apt.Capacity != 2 || apt.Remaining != 1 {
+		t.Error("Should leave room for one more in the class")
+		t.Errorf("GOT: %d/%d\n", apt.Remaining, apt.Capacity)
+		t.Errorf("EXP: %d/%d\n", 1, 2)
+	}
+
+	got, err := api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day, day.AddDate(0, 0, 1), class)
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	exp := []agenda.Slot{
+		{StartsAt: day.Add(9 * time.Hour), EndsAt: day.Add(10 * time.Hour), Capacity: 2, Remaining: 1},
+		{StartsAt: day.Add(10 * time.Hour), EndsAt: day.Add(11 * time.Hour), Capacity: 2, Remaining: 2},
+		{StartsAt: day.Add(11 * time.Hour), EndsAt: day.Add(12 * time.Hour), Capacity: 2, Remaining: 2},
+	}
+
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Errorf("Should keep the class slot available until full, diff:\n%s", diff)
+	}
+
+	na.ServiceID = svcs[0].ID
+	if _, err := api.Appointment.Create(ctx, na); !errors.Is(err, appointment.ErrAlreadyReserved) {
+		t.Error("Should reject another service overlapping the class")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrAlreadyReserved)
+	}
+
+	na.ServiceID = class.ID
+	if _, err := api.Appointment.Create(ctx, na); err != nil {
+		t.Fatalf("Should be able to join the class: %s", err)
+	}
+
+	if _, err := api.Appointment.Create(ctx, na); !errors.Is(err, appointment.ErrSlotFull) {
+		t.Error("Should reject joining a full class")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrSlotFull)
+	}
+
+	saved, err := api.Appointment.QueryByID(ctx, apt.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve appointment by ID: %s", err)
+	}
+
+	if saved.Remaining != 0 {
+		t.Error("Should see the class as full")
+		t.Errorf("GOT: %d\n", saved.Remaining)
+		t.Errorf("EXP: %d\n", 0)
+	}
+
+	got, err = api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day, day.AddDate(0, 0, 1), class)
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	if len(got) != 2 || !got[0].StartsAt.Equal(day.Add(10*time.Hour)) {
+		t.Error("Should leave the full class slot out")
+		t.Errorf("GOT: %v\n", got)
+	}
+}
+
+func imports(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var filter user.QueryFilter
+	filter.WithName("User Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Should be able to query users: %s", err)
+	}
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+
+	hours := make([]agenda.OpeningHours, 0, 7)
+	for wd := range 7 {
+		d, _ := agenda.ParseDay(uint(wd))
+		hours = append(hours, agenda.OpeningHours{Day: d, OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 60})
+	}
+
+	if _, err := api.Agenda.CreateGeneralAgenda(ctx, agenda.NewGeneralAgenda{BusinessID: bsns[0].ID, Hours: hours}); err != nil {
+		t.Fatalf("Should be able to create a general agenda: %s", err)
+	}
+
+	na := appointment.NewAppointment{
+		BusinessID:  bsns[0].ID,
+		UserID:      usrs[0].ID,
+		ScheduledOn: day.AddDate(0, 0, 1).Add(9 * time.Hour),
+	}
+
+	apt, err := api.Appointment.Create(ctx, na)
+	if err != nil {
+		t.Fatalf("Should be able to create an appointment: %s", err)
+	}
+
+	calendar := func(start, end string) string {
+		return strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"BEGIN:VEVENT",
+			"UID:vacation@example.com",
+			"SUMMARY:Vacation",
+			"DTSTART;VALUE=DATE:" + start,
+			"DTEND;VALUE=DATE:" + end,
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\r\n")
+	}
+
+	// The vacation closes the day after tomorrow and the one after it.
+	from := day.AddDate(0, 0, 1)
+	ics := calendar(from.Format("20060102"), from.AddDate(0, 0, 2).Format("20060102"))
+
+	res, err := api.Agenda.ImportClosures(ctx, bsns[0].ID, strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("Should be able to import closures: %s", err)
+	}
+
+	if len(res.Created) != 2 || len(res.Removed) != 0 {
+		t.Error("Should close both dates of the vacation")
+		t.Errorf("GOT: %d created, %d removed\n", len(res.Created), len(res.Removed))
+	}
+
+	if len(res.Affected) != 1 || res.Affected[0].ID != apt.ID {
+		t.Error("Should report the appointment falling on the vacation")
+		t.Errorf("GOT: %v\n", res.Affected)
+	}
+
+	err = api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, from.Add(10*time.Hour))
+	if err == nil || err.Error() != agenda.ErrBusinessOff.Error() {
+		t.Error("Should be closed during the vacation")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrBusinessOff)
+	}
+
+	res, err = api.Agenda.ImportClosures(ctx, bsns[0].ID, strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("Should be able to import closures again: %s", err)
+	}
+
+	if len(res.Created) != 0 || len(res.Removed) != 0 {
+		t.Error("Should change nothing importing the same calendar again")
+		t.Errorf("GOT: %d created, %d removed\n", len(res.Created), len(res.Removed))
+	}
+
+	// The vacation is cut short to its first date.
+	ics = calendar(from.Format("20060102"), from.AddDate(0, 0, 1).Format("20060102"))
+
+	res, err = api.Agenda.ImportClosures(ctx, bsns[0].ID, strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("Should be able to import the moved event: %s", err)
+	}
+
+	if len(res.Created) != 0 || len(res.Removed) != 1 || !res.Removed[0].Date.Equal(from.AddDate(0, 0, 1)) {
+		t.Error("Should reopen the date dropped from the vacation")
+		t.Errorf("GOT: %d created, %v removed\n", len(res.Created), res.Removed)
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, from.AddDate(0, 0, 1).Add(10*time.Hour)); err != nil {
+		t.Errorf("Should be open again after the vacation: %s", err)
+	}
+
+	if _, err := api.Agenda.ImportClosures(ctx, bsns[0].ID, strings.NewReader("BEGIN:VEVENT")); !errors.Is(err, agenda.ErrInvalidCalendar) {
+		t.Error("Should reject a malformed calendar")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrInvalidCalendar)
+	}
+
+	recurring := strings.Replace(ics, "SUMMARY:Vacation", "SUMMARY:Vacation\r\nRRULE:FREQ=YEARLY", 1)
+
+	if _, err := api.Agenda.ImportClosures(ctx, bsns[0].ID, strings.NewReader(recurring)); !errors.Is(err, agenda.ErrRecurringEvent) {
+		t.Error("Should reject a recurring event rather than close its first occurrence only")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrRecurringEvent)
+	}
+}
+
+func closures(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var filter user.QueryFilter
+	filter.WithName("User Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Should be able to query users: %s", err)
+	}
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	rscs, err := resource.TestGenerateSeedResources(1, api.Resource, bsns[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed resources: %s", err)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+
+	hours := make([]agenda.OpeningHours, 0, 7)
+	for wd := range 7 {
+		d, _ := agenda.ParseDay(uint(wd))
+		hours = append(hours, agenda.OpeningHours{Day: d, OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 60})
+	}
+
+	if _, err := api.Agenda.CreateGeneralAgenda(ctx, agenda.NewGeneralAgenda{BusinessID: bsns[0].ID, Hours: hours}); err != nil {
+		t.Fatalf("Should be able to create a general agenda: %s", err)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Ranges
+
+	nc := agenda.NewClosure{
+		BusinessID: bsns[0].ID,
+		From:       day.AddDate(0, 0, 3),
+		To:         day.AddDate(0, 0, 1),
+	}
+
+	if _, err := api.Agenda.CreateClosure(ctx, nc); !errors.Is(err, agenda.ErrInvalidRange) {
+		t.Error("Should reject a closure ending before it starts")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrInvalidRange)
+	}
+
+	nc.From, nc.To = day.AddDate(0, 0, 1), day.AddDate(0, 0, 14)
+	nc.Reason = "Vacation"
+
+	cl, err := api.Agenda.CreateClosure(ctx, nc)
+	if err != nil {
+		t.Fatalf("Should be able to create a closure: %s", err)
+	}
+
+	for _, d := range []time.Time{nc.From, nc.From.AddDate(0, 0, 6), nc.To} {
+		err = api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, d.Add(9*time.Hour))
+		if err == nil || err.Error() != agenda.ErrClosed.Error() {
+			t.Errorf("Should be closed on %s", d.Format(time.DateOnly))
+			t.Errorf("GOT: %v\n", err)
+			t.Errorf("EXP: %s\n", agenda.ErrClosed)
+		}
+	}
+
+	err = api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, rscs[0].ID, nc.From.Add(9*time.Hour))
+	if err == nil || err.Error() != agenda.ErrClosed.Error() {
+		t.Error("Should close every resource with the business")
+		t.Errorf("GOT: %v\n", err)
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.Add(9*time.Hour)); err != nil {
+		t.Errorf("Should be open before the closure: %s", err)
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, nc.To.AddDate(0, 0, 1).Add(9*time.Hour)); err != nil {
+		t.Errorf("Should be open after the closure: %s", err)
+	}
+
+	slots, err := api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day, day.AddDate(0, 0, 3), service.Service{})
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	for _, s := range slots {
+		if !s.StartsAt.Before(nc.From) {
+			t.Errorf("Should leave closed dates out of the slots: %s", s.StartsAt)
+		}
+	}
+
+	short := nc.From
+	cl, err = api.Agenda.UpdateClosure(ctx, cl, agenda.UpdateClosure{To: &short})
+	if err != nil {
+		t.Fatalf("Should be able to update the closure: %s", err)
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, nc.To.Add(9*time.Hour)); err != nil {
+		t.Errorf("Should reopen the dates dropped from the closure: %s", err)
+	}
+
+	if err := api.Agenda.DeleteClosure(ctx, cl); err != nil {
+		t.Fatalf("Should be able to delete the closure: %s", err)
+	}
+
+	if _, err := api.Agenda.QueryClosureByID(ctx, cl.ID); !errors.Is(err, agenda.ErrClosureNotFound) {
+		t.Error("Should not find the deleted closure")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrClosureNotFound)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Resources
+
+	nc = agenda.NewClosure{
+		BusinessID: bsns[0].ID,
+		ResourceID: rscs[0].ID,
+		From:       day,
+		To:         day,
+	}
+
+	if _, err := api.Agenda.CreateClosure(ctx, nc); err != nil {
+		t.Fatalf("Should be able to close a resource: %s", err)
+	}
+
+	err = api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, rscs[0].ID, day.Add(9*time.Hour))
+	if err == nil || err.Error() != agenda.ErrClosed.Error() {
+		t.Error("Should close the resource")
+		t.Errorf("GOT: %v\n", err)
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.Add(9*time.Hour)); err != nil {
+		t.Errorf("Should keep the business open while a resource is closed: %s", err)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Yearly
+
+	// A yearly closure set a year ago, spanning tomorrow and the day after it.
+	yc := agenda.NewClosure{
+		BusinessID: bsns[0].ID,
+		From:       day.AddDate(-1, 0, 1),
+		To:         day.AddDate(-1, 0, 2),
+		Yearly:     true,
+		Reason:     "Anniversary",
+	}
+
+	if _, err := api.Agenda.CreateClosure(ctx, agenda.NewClosure{BusinessID: bsns[0].ID, From: yc.From, To: yc.From.AddDate(1, 0, 0), Yearly: true}); !errors.Is(err, agenda.ErrInvalidRange) {
+		t.Error("Should reject a yearly closure lasting more than a year")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrInvalidRange)
+	}
+
+	if _, err := api.Agenda.CreateClosure(ctx, yc); err != nil {
+		t.Fatalf("Should be able to create a yearly closure: %s", err)
+	}
+
+	for _, d := range []time.Time{day.AddDate(0, 0, 1), day.AddDate(0, 0, 2), day.AddDate(1, 0, 1)} {
+		err = api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, d.Add(9*time.Hour))
+		if err == nil || err.Error() != agenda.ErrClosed.Error() {
+			t.Errorf("Should be closed every year on %s", d.Format(time.DateOnly))
+			t.Errorf("GOT: %v\n", err)
+		}
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.AddDate(0, 0, 3).Add(9*time.Hour)); err != nil {
+		t.Errorf("Should be open after the yearly closure: %s", err)
+	}
+
+	var cf agenda.ClosureQueryFilter
+	cf.WithBusinessID(bsns[0].ID)
+	cf.WithDate(day.AddDate(0, 0, 1))
+
+	cls, err := api.Agenda.QueryClosures(ctx, cf, agenda.DefaultOrderBy, page.MustParse("1", "10"))
+	if err != nil {
+		t.Fatalf("Should be able to query closures: %s", err)
+	}
+
+	if len(cls) != 1 || cls[0].Reason != yc.Reason {
+		t.Error("Should find the yearly closure by date")
+		t.Errorf("GOT: %v\n", cls)
+	}
+}
+
+func seasons(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var filter user.QueryFilter
+	filter.WithName("User Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Should be able to query users: %s", err)
+	}
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+
+	hoursOf := func(opens string, closed string) []agenda.OpeningHours {
+		hours := make([]agenda.OpeningHours, 0, 7)
+		for wd := range 7 {
+			d, _ := agenda.ParseDay(uint(wd))
+			hours = append(hours, agenda.OpeningHours{Day: d, OpensAt: agenda.MustParseClock(opens), ClosedAt: agenda.MustParseClock(closed), Interval: 60 * 60})
+		}
+		return hours
+	}
+
+	// The business opens in the morning for a week, then in the afternoon from then on.
+	morning, err := api.Agenda.CreateGeneralAgenda(ctx, agenda.NewGeneralAgenda{
+		BusinessID:  bsns[0].ID,
+		Hours:       hoursOf("09:00", "12:00"),
+		EffectiveTo: day.AddDate(0, 0, 6),
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create a general agenda: %s", err)
+	}
+
+	afternoon, err := api.Agenda.CreateGeneralAgenda(ctx, agenda.NewGeneralAgenda{
+		BusinessID:    bsns[0].ID,
+		Hours:         hoursOf("14:00", "18:00"),
+		EffectiveFrom: day.AddDate(0, 0, 7),
+	})
+	if err != nil {
+		t.Fatalf("Should be able to create a following general agenda: %s", err)
+	}
+
+	nga := agenda.NewGeneralAgenda{
+		BusinessID:    bsns[0].ID,
+		Hours:         hoursOf("09:00", "18:00"),
+		EffectiveFrom: day.AddDate(0, 0, 5),
+		EffectiveTo:   day.AddDate(0, 0, 10),
+	}
+
+	if _, err := api.Agenda.CreateGeneralAgenda(ctx, nga); !errors.Is(err, agenda.ErrGeneralAgendaExists) {
+		t.Error("Should reject a general agenda overlapping with others")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrGeneralAgendaExists)
+	}
+
+	nga.EffectiveFrom, nga.EffectiveTo = nga.EffectiveTo, nga.EffectiveFrom
+	if _, err := api.Agenda.CreateGeneralAgenda(ctx, nga); !errors.Is(err, agenda.ErrInvalidRange) {
+		t.Error("Should reject a general agenda ending before it starts")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrInvalidRange)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Resolution
+
+	for _, tt := range []struct {
+		date time.Time
+		exp  agenda.GeneralAgenda
+	}{
+		{day, morning},
+		{day.AddDate(0, 0, 6), morning},
+		{day.AddDate(0, 0, 7), afternoon},
+		{day.AddDate(1, 0, 0), afternoon},
+	} {
+		agd, err := api.Agenda.QueryGeneralAgendaByBusinessID(ctx, bsns[0].ID, tt.date)
+		if err != nil {
+			t.Fatalf("Should be able to query the general agenda on %s: %s", tt.date.Format(time.DateOnly), err)
+		}
+
+		if agd.ID != tt.exp.ID {
+			t.Errorf("Should resolve the general agenda in effect on %s", tt.date.Format(time.DateOnly))
+			t.Errorf("GOT: %s\n", agd.ID)
+			t.Errorf("EXP: %s\n", tt.exp.ID)
+		}
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.AddDate(0, 0, 6).Add(9*time.Hour)); err != nil {
+		t.Errorf("Should follow the morning agenda until it ends: %s", err)
+	}
+
+	err = api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.AddDate(0, 0, 7).Add(9*time.Hour))
+	if err == nil || err.Error() != agenda.ErrOutOfRange.Error() {
+		t.Error("Should follow the afternoon agenda once it starts")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrOutOfRange)
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.AddDate(0, 0, 7).Add(14*time.Hour)); err != nil {
+		t.Errorf("Should follow the afternoon agenda once it starts: %s", err)
+	}
+
+	slots, err := api.Agenda.AvailableSlots(ctx, bsns[0].ID, uuid.Nil, day.AddDate(0, 0, 6), day.AddDate(0, 0, 8), service.Service{})
+	if err != nil {
+		t.Fatalf("Should be able to query available slots: %s", err)
+	}
+
+	if len(slots) != 7 {
+		t.Errorf("Should lay the slots of each day from its own agenda: got %d slots, exp 7", len(slots))
+	}
+
+	season := day.AddDate(0, 0, 7)
+	for _, s := range slots {
+		if s.StartsAt.Before(season) != (s.StartsAt.Hour() < 12) {
+			t.Errorf("Should lay the slot from the agenda in effect on its day: %s", s.StartsAt)
+		}
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Update
+
+	from := day.AddDate(0, 0, 6)
+	if _, err := api.Agenda.UpdateGenralAgenda(ctx, afternoon, agenda.UpdateGeneralAgenda{EffectiveFrom: &from}); !errors.Is(err, agenda.ErrGeneralAgendaExists) {
+		t.Error("Should reject moving an agenda over another one")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrGeneralAgendaExists)
+	}
+
+	to := day.AddDate(0, 0, 3)
+	if _, err := api.Agenda.UpdateGenralAgenda(ctx, morning, agenda.UpdateGeneralAgenda{EffectiveTo: &to}); err != nil {
+		t.Fatalf("Should be able to shorten an agenda: %s", err)
+	}
+
+	if _, err := api.Agenda.QueryGeneralAgendaByBusinessID(ctx, bsns[0].ID, day.AddDate(0, 0, 4)); !errors.Is(err, agenda.ErrNotFound) {
+		t.Error("Should find no general agenda between seasons")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", agenda.ErrNotFound)
+	}
+
+	if err := api.Agenda.TimeWithinAgendaBoundary(ctx, bsns[0].ID, uuid.Nil, day.AddDate(0, 0, 4).Add(9*time.Hour)); err == nil {
+		t.Error("Should not accept appointments between seasons")
+	}
+}
+
+func affected(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var filter user.QueryFilter
+	filter.WithName("User Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Should be able to query users: %s", err)
+	}
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	svcs, err := service.TestGenerateSeedServices(1, api.Service, bsns[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed services: %s", err)
+	}
+
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1)
+	day := time.Date(tomorrow.Year(), tomorrow.Month(), tomorrow.Day(), 0, 0, 0, 0, time.UTC)
+
+	hours := make([]agenda.OpeningHours, 0, 7)
+	for wd := range 7 {
+		d, _ := agenda.ParseDay(uint(wd))
+		hours = append(hours, agenda.OpeningHours{Day: d, OpensAt: agenda.MustParseClock("09:00"), ClosedAt: agenda.MustParseClock("12:00"), Interval: 60 * 60})
+	}
+
+	gAgd, err := api.Agenda.CreateGeneralAgenda(ctx, agenda.NewGeneralAgenda{BusinessID: bsns[0].ID, Hours: hours})
+	if err != nil {
+		t.Fatalf("Should be able to create a general agenda: %s", err)
+	}
+
+	na := appointment.NewAppointment{
+		BusinessID:  bsns[0].ID,
+		UserID:      usrs[0].ID,
+		ServiceID:   svcs[0].ID,
+		ScheduledOn: day.Add(10 * time.Hour),
+	}
+
+	early, err := api.Appointment.Create(ctx, na)
+	if err != nil {
+		t.Fatalf("Should be able to create an appointment: %s", err)
+	}
+
+	na.ScheduledOn = day.AddDate(0, 0, 1).Add(11 * time.Hour)
+	late, err := api.Appointment.Create(ctx, na)
+	if err != nil {
+		t.Fatalf("Should be able to create an appointment: %s", err)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Preview
+
+	// Closing at 11:00 leaves out the appointments at 11:00 only.
+	shorter := make([]agenda.OpeningHours, len(hours))
+	for i, h := range hours {
+		h.ClosedAt = agenda.MustParseClock("11:00")
+		shorter[i] = h
+	}
+
+	apts, err := api.Agenda.AffectedByGeneralAgendaUpdate(ctx, gAgd, agenda.UpdateGeneralAgenda{Hours: shorter})
+	if err != nil {
+		t.Fatalf("Should be able to preview the update of the general agenda: %s", err)
+	}
+
+	if len(apts) != 1 || apts[0].ID != late.ID {
+		t.Error("Should find the appointment falling outside the updated agenda")
+		t.Errorf("GOT: %v\n", apts)
+		t.Errorf("EXP: %s\n", late.ID)
+	}
+
+	saved, err := api.Agenda.QueryGeneralAgendaByID(ctx, gAgd.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve the general agenda: %s", err)
+	}
+
+	if diff := cmp.Diff(gAgd.Hours, saved.Hours); diff != "" {
+		t.Errorf("Should leave the general agenda as it is on preview, diff:\n%s", diff)
+	}
+
+	nda := agenda.NewDailyAgenda{
+		BusinessID:   bsns[0].ID,
+		Date:         day,
+		Availability: false,
+	}
+
+	apts, err = api.Agenda.AffectedByDailyAgenda(ctx, nda)
+	if err != nil {
+		t.Fatalf("Should be able to preview a daily agenda: %s", err)
+	}
+
+	if len(apts) != 1 || apts[0].ID != early.ID {
+		t.Error("Should find the appointment on the day off")
+		t.Errorf("GOT: %v\n", apts)
+		t.Errorf("EXP: %s\n", early.ID)
+	}
+
+	// ----------------------------------------------------------------------------------------------------------------
+	// Handling
+
+	if _, err := api.Agenda.CreateDailyAgenda(ctx, nda); err != nil {
+		t.Fatalf("Should be able to create a daily agenda: %s", err)
+	}
+
+	handled, err := api.Agenda.HandleAffected(ctx, apts, agenda.ActionCancel, "")
+	if err != nil {
+		t.Fatalf("Should be able to cancel the affected appointments: %s", err)
+	}
+
+	if len(handled) != 1 || handled[0].Status != appointment.StatusCancelled {
+		t.Error("Should cancel the affected appointment")
+		t.Errorf("GOT: %v\n", handled)
+	}
+
+	apts, err = api.Agenda.AffectedByGeneralAgendaUpdate(ctx, gAgd, agenda.UpdateGeneralAgenda{Hours: shorter})
+	if err != nil {
+		t.Fatalf("Should be able to preview the update of the general agenda: %s", err)
+	}
+
+	if _, err := api.Agenda.UpdateGenralAgenda(ctx, gAgd, agenda.UpdateGeneralAgenda{Hours: shorter}); err != nil {
+		t.Fatalf("Should be able to update the general agenda: %s", err)
+	}
+
+	if _, err := api.Agenda.HandleAffected(ctx, apts, agenda.ActionFlag, "Closing earlier"); err != nil {
+		t.Fatalf("Should be able to flag the affected appointments: %s", err)
+	}
+
+	apt, err := api.Appointment.QueryByID(ctx, late.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve the appointment: %s", err)
+	}
+
+	if apt.FlaggedAt.IsZero() || apt.Status != late.Status {
+		t.Error("Should flag the appointment for rescheduling, keeping its status")
+		t.Errorf("GOT: %v\n", apt)
+	}
+
+	when := day.AddDate(0, 0, 1).Add(10 * time.Hour)
+	apt, err = api.Appointment.Update(ctx, apt, appointment.UpdateAppointment{ScheduledOn: &when}, appointment.PartyCustomer)
+	if err != nil {
+		t.Fatalf("Should be able to reschedule the appointment: %s", err)
+	}
+
+	if !apt.FlaggedAt.IsZero() {
+		t.Error("Should clear the flag on rescheduling")
+	}
+}
@@ -0,0 +1,105 @@
+package agenda
+
+import (
+	"fmt"
+	"time"
+)
+
+const secondsPerDay = 24 * 60 * 60
+
+// Clock is a wall clock time of day, from 00:00 up to and including 24:00, kept
+// with a precision of seconds. It carries no time zone; it's read in the time
+// zone of the business it belongs to.
+type Clock struct {
+	sec int
+}
+
+// NewClock constructs a Clock from its components.
+func NewClock(hour int, min int, sec int) (Clock, error) {
+	if hour < 0 || min < 0 || min > 59 || sec < 0 || sec > 59 {
+		return Clock{}, fmt.Errorf("invalid clock: %02d:%02d:%02d", hour, min, sec)
+	}
+
+	total := hour*3600 + min*60 + sec
+	if total > secondsPerDay {
+		return Clock{}, fmt.Errorf("invalid clock: %02d:%02d:%02d", hour, min, sec)
+	}
+
+	return Clock{sec: total}, nil
+}
+
+// ClockOf returns the wall clock of t in its own location.
+func ClockOf(t time.Time) Clock {
+	return Clock{sec: clockSeconds(t)}
+}
+
+// ParseClock parses a clock in either "15:04" or "15:04:05" format.
+func ParseClock(value string) (Clock, error) {
+	var hour, min, sec int
+
+	switch len(value) {
+	case len("15:04"):
+		if _, err := fmt.Sscanf(value, "%02d:%02d", &hour, &min); err != nil {
+			return Clock{}, fmt.Errorf("invalid clock: %q", value)
+		}
+	case len("15:04:05"):
+		if _, err := fmt.Sscanf(value, "%02d:%02d:%02d", &hour, &min, &sec); err != nil {
+			return Clock{}, fmt.Errorf("invalid clock: %q", value)
+		}
+	default:
+		return Clock{}, fmt.Errorf("invalid clock: %q", value)
+	}
+
+	return NewClock(hour, min, sec)
+}
+
+// MustParseClock parses a clock and panics if it's not valid.
+func MustParseClock(value string) Clock {
+	c, err := ParseClock(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// Seconds returns the seconds elapsed since midnight.
+func (c Clock) Seconds() int {
+	return c.sec
+}
+
+// On returns the instant on the date of day, in its location, showing this clock.
+func (c Clock) On(day time.Time) time.Time {
+	return atClockSeconds(day, c.sec)
+}
+
+func (c Clock) Before(c2 Clock) bool {
+	return c.sec < c2.sec
+}
+
+func (c *Clock) UnmarshalText(data []byte) error {
+	clk, err := ParseClock(string(data))
+	if err != nil {
+		return err
+	}
+
+	c.sec = clk.sec
+	return nil
+}
+
+func (c Clock) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+func (c Clock) String() string {
+	hour, min, sec := c.sec/3600, c.sec%3600/60, c.sec%60
+	if sec != 0 {
+		return fmt.Sprintf("%02d:%02d:%02d", hour, min, sec)
+	}
+
+	return fmt.Sprintf("%02d:%02d", hour, min)
+}
+
+func (c Clock) Equal(c2 Clock) bool {
+	return c.sec == c2.sec
+}
@@ -0,0 +1,176 @@
+package agenda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/data/order"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrClosed          = errors.New("business is closed on the selected date")
+	ErrClosureNotFound = errors.New("closure is not found")
+)
+
+// prepareClosure normalizes the dates of a closure to calendar dates and validates
+// them. Yearly closures last no more than a year.
+func prepareClosure(cl Closure) (Closure, error) {
+	cl.From = calendarDate(cl.From)
+	cl.To = calendarDate(cl.To)
+
+	if cl.To.Before(cl.From) {
+		return Closure{}, ErrInvalidRange
+	}
+
+	if cl.Yearly && !cl.To.Before(cl.From.AddDate(1, 0, 0)) {
+		return Closure{}, fmt.Errorf("%w: a yearly closure can't last more than a year", ErrInvalidRange)
+	}
+
+	return cl, nil
+}
+
+func (c *Core) CreateClosure(ctx context.Context, nc NewClosure) (Closure, error) {
+	ctx, span := otel.AddSpan(ctx, "business.closure.create")
+	defer span.End()
+
+	if err := c.checkResource(ctx, nc.BusinessID, nc.ResourceID); err != nil {
+		return Closure{}, err
+	}
+
+	now := time.Now()
+
+	cl, err := prepareClosure(Closure{
+		ID:          uuid.New(),
+		BusinessID:  nc.BusinessID,
+		ResourceID:  nc.ResourceID,
+		From:        nc.From,
+		To:          nc.To,
+		Yearly:      nc.Yearly,
+		Reason:      nc.Reason,
+		DateCreated: now,
+		DateUpdated: now,
+	})
+	if err != nil {
+		return Closure{}, err
+	}
+
+	if err := c.storer.CreateClosure(ctx, cl); err != nil {
+		return Closure{}, fmt.Errorf("create closure: %w", err)
+	}
+
+	return cl, nil
+}
+
+func (c *Core) UpdateClosure(ctx context.Context, cl Closure, uc UpdateClosure) (Closure, error) {
+	ctx, span := otel.AddSpan(ctx, "business.closure.update")
+	defer span.End()
+
+	if uc.From != nil {
+		cl.From = *uc.From
+	}
+
+	if uc.To != nil {
+		cl.To = *uc.To
+	}
+
+	if uc.Yearly != nil {
+		cl.Yearly = *uc.Yearly
+	}
+
+	if uc.Reason != nil {
+		cl.Reason = *uc.Reason
+	}
+
+	cl, err := prepareClosure(cl)
+	if err != nil {
+		return Closure{}, err
+	}
+
+	cl.DateUpdated = time.Now()
+
+	if err := c.storer.UpdateClosure(ctx, cl); err != nil {
+		return Closure{}, fmt.Errorf("update: %w", err)
+	}
+
+	return cl, nil
+}
+
+func (c *Core) DeleteClosure(ctx context.Context, cl Closure) error {
+	ctx, span := otel.AddSpan(ctx, "business.closure.delete")
+	defer span.End()
+
+	if err := c.storer.DeleteClosure(ctx, cl); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Core) QueryClosures(ctx context.Context, filter ClosureQueryFilter, orderBy order.By, page page.Page) ([]Closure, error) {
+	ctx, span := otel.AddSpan(ctx, "business.closure.query")
+	defer span.End()
+
+	cls, err := c.storer.QueryClosures(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return cls, nil
+}
+
+func (c *Core) CountClosures(ctx context.Context, filter ClosureQueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.closure.count")
+	defer span.End()
+
+	return c.storer.CountClosures(ctx, filter)
+}
+
+func (c *Core) QueryClosureByID(ctx context.Context, clID uuid.UUID) (Closure, error) {
+	ctx, span := otel.AddSpan(ctx, "business.closure.querybyid")
+	defer span.End()
+
+	cl, err := c.storer.QueryClosureByID(ctx, clID)
+	if err != nil {
+		return Closure{}, fmt.Errorf("query: closureID[%s]: %w", clID, err)
+	}
+
+	return cl, nil
+}
+
+// closedOn reports whether a resource is closed on the date of day, by a closure of
+// its own or of the business as a whole. Given uuid.Nil, only the closures of the
+// business are looked at.
+func (c *Core) closedOn(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, day time.Time) (bool, error) {
+	rscIDs := []uuid.UUID{uuid.Nil}
+	if rscID != uuid.Nil {
+		rscIDs = append(rscIDs, rscID)
+	}
+
+	pagination, err := page.Parse("1", "1")
+	if err != nil {
+		return false, fmt.Errorf("couldn't parse page parameters: %w", err)
+	}
+
+	for _, id := range rscIDs {
+		var filter ClosureQueryFilter
+		filter.WithBusinessID(bsnID)
+		filter.WithResourceID(id)
+		filter.WithDate(day)
+
+		cls, err := c.storer.QueryClosures(ctx, filter, DefaultOrderBy, pagination)
+		if err != nil {
+			return false, fmt.Errorf("query closures: %w", err)
+		}
+
+		if len(cls) > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
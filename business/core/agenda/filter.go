@@ -11,6 +11,8 @@ import (
 type GAQueryFilter struct {
 	ID          *uuid.UUID `validate:"omitempty,uuid"`
 	BusinesesID *uuid.UUID `validate:"omitempty,uuid"`
+	ResourceID  *uuid.UUID `validate:"omitempty"`
+	Date        *time.Time `validate:"omitempty"`
 }
 
 func (qf *GAQueryFilter) Validate() error {
@@ -29,11 +31,25 @@ func (qf *GAQueryFilter) WithBusinessID(bsnID uuid.UUID) {
 	qf.BusinesesID = &bsnID
 }
 
+// WithResourceID narrows down to the agendas of a resource, or to the agendas of
+// the business as a whole when given uuid.Nil.
+func (qf *GAQueryFilter) WithResourceID(rscID uuid.UUID) {
+	qf.ResourceID = &rscID
+}
+
+// WithDate narrows down to the agendas in effect on the calendar date, taken as it
+// reads in the location of the date.
+func (qf *GAQueryFilter) WithDate(date time.Time) {
+	qf.Date = &date
+}
+
 // --------------------------------------------------------------------
 
 type DAQueryFilter struct {
 	ID         *uuid.UUID `validate:"omitempty,uuid"`
 	BusinessID *uuid.UUID `validate:"omitempty,uuid"`
+	ResourceID *uuid.UUID `validate:"omitempty"`
+	SourceUID  *string    `validate:"omitempty"`
 	Date       *time.Time `validadte:"omitempty,excluded_with=From To Days"`
 	From       *string    `validate:"omitempty,required_with=To"`
 	To         *string    `validate:"omitempty,required_with=From"`
@@ -56,6 +72,18 @@ func (qf *DAQueryFilter) WithBusinessID(id uuid.UUID) {
 	qf.BusinessID = &id
 }
 
+// WithResourceID narrows down to the agendas of a resource, or to the agendas of
+// the business as a whole when given uuid.Nil.
+func (qf *DAQueryFilter) WithResourceID(rscID uuid.UUID) {
+	qf.ResourceID = &rscID
+}
+
+// WithSourceUID narrows down to the closures imported from the calendar event of
+// the UID, past ones included.
+func (qf *DAQueryFilter) WithSourceUID(uid string) {
+	qf.SourceUID = &uid
+}
+
 func (qf *DAQueryFilter) WithDate(date time.Time) {
 	qf.Date = &date
 }
@@ -71,3 +99,40 @@ func (qf *DAQueryFilter) WithTo(to string) {
 func (qf *DAQueryFilter) WithDays(days int) {
 	qf.Days = &days
 }
+
+// --------------------------------------------------------------------
+
+type ClosureQueryFilter struct {
+	ID         *uuid.UUID `validate:"omitempty"`
+	BusinessID *uuid.UUID `validate:"omitempty"`
+	ResourceID *uuid.UUID `validate:"omitempty"`
+	Date       *time.Time `validate:"omitempty"`
+}
+
+func (qf *ClosureQueryFilter) Validate() error {
+	if err := errs.Check(qf); err != nil {
+		return fmt.Errorf("validate: %w", err)
+	}
+
+	return nil
+}
+
+func (qf *ClosureQueryFilter) WithClosureID(id uuid.UUID) {
+	qf.ID = &id
+}
+
+func (qf *ClosureQueryFilter) WithBusinessID(bsnID uuid.UUID) {
+	qf.BusinessID = &bsnID
+}
+
+// WithResourceID narrows down to the closures of a resource, or to the closures of
+// the business as a whole when given uuid.Nil.
+func (qf *ClosureQueryFilter) WithResourceID(rscID uuid.UUID) {
+	qf.ResourceID = &rscID
+}
+
+// WithDate narrows down to the closures closing the calendar date, taken as it
+// reads in the location of the date.
+func (qf *ClosureQueryFilter) WithDate(date time.Time) {
+	qf.Date = &date
+}
@@ -0,0 +1,308 @@
+package agenda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/foundation/ical"
+	"github.com/ameghdadian/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+// MaxClosureDays limits how many dates a single imported event may close.
+const MaxClosureDays = 366
+
+var (
+	ErrInvalidCalendar = errors.New("calendar is not valid")
+	ErrClosureTooLong  = fmt.Errorf("an event can't close more than %d days", MaxClosureDays)
+	ErrRecurringEvent  = errors.New("recurring events can't be imported, add them as closures instead")
+)
+
+// ImportedClosure is a stretch of dates a business is closed on, such as a public
+// holiday or a vacation, read from an event of an external calendar. From and To are the
+// first and last closed dates, at midnight UTC, and are zero for cancelled events,
+// which close no date.
+type ImportedClosure struct {
+	UID     string
+	Summary string
+	From    time.Time
+	To      time.Time
+}
+
+// Dates returns the dates the closure closes, in order.
+func (cl ImportedClosure) Dates() []time.Time {
+	if cl.From.IsZero() {
+		return nil
+	}
+
+	var dates []time.Time
+	for d := cl.From; !d.After(cl.To); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+	}
+
+	return dates
+}
+
+// ImportResult reports what importing closures changed. Skipped are the daily
+// agendas already set on dates the closures fall on, which are left untouched.
+// Affected are the appointments holding their slot on the dates now closed.
+type ImportResult struct {
+	Closures []ImportedClosure
+	Created  []DailyAgenda
+	Removed  []DailyAgenda
+	Skipped  []DailyAgenda
+	Affected []appointment.Appointment
+}
+
+// toImportedClosures turns the events of the calendar into closures. All-day events
+// close their dates; timed ones close every date they touch in loc. Recurring events
+// are turned down rather than closing their first occurrence only.
+func toImportedClosures(cal ical.Calendar, loc *time.Location) ([]ImportedClosure, error) {
+	closures := make([]ImportedClosure, 0, len(cal.Events))
+	for _, e := range cal.Events {
+		if e.RRule != "" {
+			return nil, fmt.Errorf("%w: event %q", ErrRecurringEvent, e.UID)
+		}
+
+		cl := ImportedClosure{
+			UID:     e.UID,
+			Summary: e.Summary,
+		}
+
+		switch {
+		case e.Status == ical.StatusCancelled:
+			closures = append(closures, cl)
+			continue
+
+		case e.AllDay:
+			cl.From = calendarDate(e.Start)
+			cl.To = calendarDate(e.End).AddDate(0, 0, -1)
+			if cl.To.Before(cl.From) {
+				cl.To = cl.From
+			}
+
+		default:
+			start, end := e.Start.In(loc), e.End.In(loc)
+			cl.From = calendarDate(start)
+			cl.To = cl.From
+			if end.After(start) {
+				cl.To = calendarDate(end.Add(-time.Nanosecond))
+			}
+		}
+
+		if cl.To.Sub(cl.From) >= MaxClosureDays*24*time.Hour {
+			return nil, fmt.Errorf("%w: event %q", ErrClosureTooLong, e.UID)
+		}
+
+		closures = append(closures, cl)
+	}
+
+	return closures, nil
+}
+
+// ImportClosures reads the iCalendar data and closes the business as a whole on
+// the dates of its events, with unavailable daily agendas. Importing the same
+// calendar again is safe: closures are kept by the UID of their event, so moving
+// an event moves its closure and cancelling it reopens its dates. Dates with a
+// daily agenda of their own are left alone. Closures of events since removed from
+// the calendar are kept.
+func (c *Core) ImportClosures(ctx context.Context, bsnID uuid.UUID, r io.Reader) (ImportResult, error) {
+	ctx, span := otel.AddSpan(ctx, "business.dailyagenda.importclosures")
+	defer span.End()
+
+	loc, err := c.businessLocation(ctx, bsnID)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	cal, err := ical.Parse(r, loc)
+	if err != nil {
+		return ImportResult{}, fmt.Errorf("%w: %s", ErrInvalidCalendar, err)
+	}
+
+	closures, err := toImportedClosures(cal, loc)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	res := ImportResult{
+		Closures: closures,
+	}
+
+	closed := make(map[time.Time]bool)
+	for _, cl := range closures {
+		if err := c.importClosure(ctx, bsnID, cl, &res, closed); err != nil {
+			return ImportResult{}, fmt.Errorf("import closure: uid[%s]: %w", cl.UID, err)
+		}
+	}
+
+	res.Affected, err = c.closedAppointments(ctx, bsnID, loc, closed)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	return res, nil
+}
+
+// importClosure brings the daily agendas of the closure in line with its dates,
+// recording the dates it keeps closed.
+func (c *Core) importClosure(ctx context.Context, bsnID uuid.UUID, cl ImportedClosure, res *ImportResult, closed map[time.Time]bool) error {
+	var filter DAQueryFilter
+	filter.WithBusinessID(bsnID)
+	filter.WithResourceID(uuid.Nil)
+	filter.WithSourceUID(cl.UID)
+
+	// The agendas are all read before any is deleted, so deleting doesn't shift
+	// the pages still to be read.
+	var existing []DailyAgenda
+	const rows = 100
+	for pn := 1; ; pn++ {
+		pagination, err := page.Parse(strconv.Itoa(pn), strconv.Itoa(rows))
+		if err != nil {
+			return fmt.Errorf("couldn't parse page parameters: %w", err)
+		}
+
+		agds, err := c.storer.QueryDailyAgenda(ctx, filter, DefaultOrderBy, pagination)
+		if err != nil {
+			return fmt.Errorf("query daily agenda: %w", err)
+		}
+		existing = append(existing, agds...)
+
+		if len(agds) < rows {
+			break
+		}
+	}
+
+	dates := make(map[time.Time]bool)
+	for _, d := range cl.Dates() {
+		dates[d] = true
+	}
+
+	imported := make(map[time.Time]bool)
+	for _, agd := range existing {
+		if !dates[agd.Date] {
+			if err := c.storer.DeleteDailyAgenda(ctx, agd); err != nil {
+				return fmt.Errorf("delete: %w", err)
+			}
+			res.Removed = append(res.Removed, agd)
+			continue
+		}
+
+		imported[agd.Date] = true
+		closed[agd.Date] = true
+	}
+
+	for _, d := range cl.Dates() {
+		if imported[d] {
+			continue
+		}
+
+		agd, found, err := c.queryDailyAgendaOn(ctx, bsnID, uuid.Nil, d)
+		if err != nil {
+			return err
+		}
+
+		if found {
+			res.Skipped = append(res.Skipped, agd)
+			if !agd.Availability {
+				closed[d] = true
+			}
+			continue
+		}
+
+		now := time.Now()
+		agd = DailyAgenda{
+			ID:           uuid.New(),
+			BusinessID:   bsnID,
+			Date:         d,
+			Availability: false,
+			SourceUID:    cl.UID,
+			DateCreated:  now,
+			DateUpdated:  now,
+		}
+
+		if err := c.storer.CreateDailyAgenda(ctx, agd); err != nil {
+			return fmt.Errorf("create daily agenda: %w", err)
+		}
+		res.Created = append(res.Created, agd)
+		closed[d] = true
+	}
+
+	return nil
+}
+
+// closedAppointments returns the appointments holding their slot on the closed
+// dates, ordered by when they're scheduled. Appointments of resources with a daily
+// agenda of their own on the date stay open and are left out.
+func (c *Core) closedAppointments(ctx context.Context, bsnID uuid.UUID, loc *time.Location, closed map[time.Time]bool) ([]appointment.Appointment, error) {
+	if len(closed) == 0 {
+		return nil, nil
+	}
+
+	dates := make([]time.Time, 0, len(closed))
+	for d := range closed {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
+
+	var affected []appointment.Appointment
+	for _, d := range dates {
+		from := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+		to := from.AddDate(0, 0, 1)
+
+		var filter appointment.QueryFilter
+		filter.WithBusinessID(bsnID)
+		filter.WithStartScheduledOn(from)
+		filter.WithEndScheduledOn(to.Add(-time.Nanosecond))
+
+		const rows = 100
+		for pn := 1; ; pn++ {
+			pagination, err := page.Parse(strconv.Itoa(pn), strconv.Itoa(rows))
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse page parameters: %w", err)
+			}
+
+			apts, err := c.aptCore.Query(ctx, filter, appointment.DefaultOrderBy, pagination)
+			if err != nil {
+				return nil, fmt.Errorf("query appointments: %w", err)
+			}
+
+			for _, apt := range apts {
+				if !apt.Status.Holds() || !apt.ScheduledOn.Before(to) {
+					continue
+				}
+
+				if apt.ResourceID != uuid.Nil {
+					_, found, err := c.queryDailyAgendaOn(ctx, bsnID, apt.ResourceID, d)
+					if err != nil {
+						return nil, err
+					}
+					if found {
+						continue
+					}
+				}
+
+				affected = append(affected, apt)
+			}
+
+			if len(apts) < rows {
+				break
+			}
+		}
+	}
+
+	sort.Slice(affected, func(i, j int) bool {
+		return affected[i].ScheduledOn.Before(affected[j].ScheduledOn)
+	})
+
+	return affected, nil
+}
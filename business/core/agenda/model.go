@@ -1,64 +1,182 @@
 package agenda
 
 import (
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// OpeningHours is a period of a weekday, in wall clock time of the business, in which
+// appointments are accepted every Interval seconds. A weekday might have several of them.
+type OpeningHours struct {
+	Day      Day
+	OpensAt  Clock
+	ClosedAt Clock
+	Interval int
+}
+
+// Window returns the period of the day covered by opening hours.
+func (oh OpeningHours) Window() Window {
+	return Window{
+		OpensAt:  oh.OpensAt,
+		ClosedAt: oh.ClosedAt,
+		Interval: oh.Interval,
+	}
+}
+
 // GeneralAgenda is the general detailed availability of a business during a week
-// REMINDER: All fields are mandatory
+// REMINDER: All fields are mandatory, except ResourceID which is uuid.Nil for the
+// agenda of the business as a whole, and the effective dates which are zero when
+// the agenda is in effect indefinitely on that side.
 type GeneralAgenda struct {
-	ID          uuid.UUID
-	BusinessID  uuid.UUID
-	OpensAt     time.Time
-	ClosedAt    time.Time
-	Interval    int
-	WorkingDays []Day
-	DateCreated time.Time
-	DateUpdated time.Time
+	ID            uuid.UUID
+	BusinessID    uuid.UUID
+	ResourceID    uuid.UUID
+	Hours         []OpeningHours
+	EffectiveFrom time.Time
+	EffectiveTo   time.Time
+	DateCreated   time.Time
+	DateUpdated   time.Time
+}
+
+// EffectiveOn reports whether the agenda is in effect on the calendar date of date,
+// taken as it reads in the location of the date.
+func (ga GeneralAgenda) EffectiveOn(date time.Time) bool {
+	day := calendarDate(date)
+
+	if !ga.EffectiveFrom.IsZero() && day.Before(ga.EffectiveFrom) {
+		return false
+	}
+
+	if !ga.EffectiveTo.IsZero() && day.After(ga.EffectiveTo) {
+		return false
+	}
+
+	return true
+}
+
+// IsWorkingDay reports whether the business works on the given weekday.
+func (ga GeneralAgenda) IsWorkingDay(wd time.Weekday) bool {
+	return len(ga.HoursOf(wd)) > 0
+}
+
+// HoursOf returns the opening hours of the given weekday, ordered by their opening.
+func (ga GeneralAgenda) HoursOf(wd time.Weekday) []OpeningHours {
+	var hours []OpeningHours
+	for _, h := range ga.Hours {
+		if h.Day.DayOfWeedk() == uint(wd) {
+			hours = append(hours, h)
+		}
+	}
+
+	sort.Slice(hours, func(i, j int) bool {
+		return hours[i].OpensAt.Before(hours[j].OpensAt)
+	})
+
+	return hours
 }
 
 type NewGeneralAgenda struct {
-	BusinessID  uuid.UUID
-	OpensAt     time.Time
-	ClosedAt    time.Time
-	Interval    int
-	WorkingDays []Day
+	BusinessID    uuid.UUID
+	ResourceID    uuid.UUID
+	Hours         []OpeningHours
+	EffectiveFrom time.Time
+	EffectiveTo   time.Time
 }
 
+// UpdateGeneralAgenda leaves the fields which are nil untouched. Setting an effective
+// date to the zero time removes that bound.
 type UpdateGeneralAgenda struct {
-	OpensAt     *time.Time
-	ClosedAt    *time.Time
-	Interval    *int
-	WorkingDays []Day
+	Hours         []OpeningHours
+	EffectiveFrom *time.Time
+	EffectiveTo   *time.Time
 }
 
 // ------------------------------------------------------
 
-// DailyAgenda represents daily modifications to general agenda which each business might need
+// Window is a period of a day, in wall clock time of the business, in which
+// appointments are accepted every Interval seconds.
+type Window struct {
+	OpensAt  Clock
+	ClosedAt Clock
+	Interval int
+}
+
+// DailyAgenda overrides the general agenda of a business on a single date. An
+// available day is open only within its windows; an unavailable one is closed.
+// ResourceID is uuid.Nil for the agenda of the business as a whole. SourceUID is the
+// UID of the calendar event a closure was imported from, empty when set by hand.
 type DailyAgenda struct {
 	ID           uuid.UUID
 	BusinessID   uuid.UUID
-	OpensAt      time.Time // OPTIONAL
-	ClosedAt     time.Time // OPTIONAL
-	Interval     int       // OPTIONAL
-	Availability bool      // MANDATORY
+	ResourceID   uuid.UUID
+	Date         time.Time // Calendar date in the business time zone; time of day is ignored.
+	Availability bool
+	Windows      []Window // Ordered by opening and non-overlapping. Empty when not available.
+	SourceUID    string
 	DateCreated  time.Time
 	DateUpdated  time.Time
 }
 
 type NewDailyAgenda struct {
 	BusinessID   uuid.UUID
-	OpensAt      time.Time
-	ClosedAt     time.Time
-	Interval     int
+	ResourceID   uuid.UUID
+	Date         time.Time
 	Availability bool
+	Windows      []Window
 }
 
 type UpdateDailyAgenda struct {
-	OpensAt      *time.Time
-	ClosedAt     *time.Time
-	Interval     *int
+	Date         *time.Time
 	Availability *bool
+	Windows      []Window
+}
+
+// ------------------------------------------------------
+
+// Closure closes a business on every date from From to To, inclusive, such as for a
+// vacation. Yearly closures recur on the same dates every year from the year of From
+// on, such as Christmas, and may span the new year. From and To are calendar dates
+// in the business time zone. ResourceID is uuid.Nil for closures of the business as
+// a whole; closures of a resource close it alone.
+type Closure struct {
+	ID          uuid.UUID
+	BusinessID  uuid.UUID
+	ResourceID  uuid.UUID
+	From        time.Time
+	To          time.Time
+	Yearly      bool
+	Reason      string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type NewClosure struct {
+	BusinessID uuid.UUID
+	ResourceID uuid.UUID
+	From       time.Time
+	To         time.Time
+	Yearly     bool
+	Reason     string
+}
+
+type UpdateClosure struct {
+	From   *time.Time
+	To     *time.Time
+	Yearly *bool
+	Reason *string
+}
+
+// ------------------------------------------------------
+
+// Slot is a bookable period of time, computed from general and daily agendas.
+// ResourceID is uuid.Nil for slots of a business without resources. Remaining is the
+// number of people out of Capacity the slot still holds.
+type Slot struct {
+	ResourceID uuid.UUID
+	StartsAt   time.Time
+	EndsAt     time.Time
+	Capacity   int
+	Remaining  int
 }
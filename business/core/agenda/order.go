@@ -7,4 +7,6 @@ var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
 const (
 	OrderByID         = "id"
 	OrderByBusinessID = "business_id"
+	OrderByDate       = "date"
+	OrderByFrom       = "from"
 )
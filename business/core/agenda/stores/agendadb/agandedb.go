@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ameghdadian/service/business/core/agenda"
 	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
@@ -44,12 +45,20 @@ func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (agenda.Stor
 func (s *Store) CreateGeneralAgenda(ctx context.Context, agd agenda.GeneralAgenda) error {
 	const q = `
 	INSERT INTO general_agenda
-		(id, business_id, opens_at, closed_at, interval, working_days, date_created, date_updated)
+		(id, business_id, resource_id, hours, effective_from, effective_to, date_created, date_updated)
 	VALUES
-		(:id, :business_id, :opens_at, :closed_at, :interval, :working_days, :date_created, :date_updated)
+		(:id, :business_id, :resource_id, :hours, :effective_from, :effective_to, :date_created, :date_updated)
 	`
 
-	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBGeneralAgenda(agd)); err != nil {
+	dbAgd, err := toDBGeneralAgenda(agd)
+	if err != nil {
+		return err
+	}
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, dbAgd); err != nil {
+		if errors.Is(err, db.ErrDBExclusionViolation) {
+			return fmt.Errorf("namedexeccontext: %w", agenda.ErrGeneralAgendaExists)
+		}
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -61,16 +70,23 @@ func (s *Store) UpdateGeneralAgenda(ctx context.Context, agd agenda.GeneralAgend
 	UPDATE
 		general_agenda
 	SET
-		"opens_at" = :opens_at,
-		"closed_at" = :closed_at,
-		"interval" = :interval,
-		"working_days" = :working_days,
+		"hours" = :hours,
+		"effective_from" = :effective_from,
+		"effective_to" = :effective_to,
 		"date_updated" = :date_updated
 	WHERE
 		"id" = :id
 	`
 
-	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBGeneralAgenda(agd)); err != nil {
+	dbAgd, err := toDBGeneralAgenda(agd)
+	if err != nil {
+		return err
+	}
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, dbAgd); err != nil {
+		if errors.Is(err, db.ErrDBExclusionViolation) {
+			return fmt.Errorf("namedexeccontext: %w", agenda.ErrGeneralAgendaExists)
+		}
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -106,7 +122,7 @@ func (s *Store) QueryGeneralAgenda(ctx context.Context, filter agenda.GAQueryFil
 
 	const q = `
 	SELECT
-		id, business_id, opens_at, closed_at, interval, working_days, date_created, date_updated
+		id, business_id, resource_id, hours, effective_from, effective_to, date_created, date_updated
 	FROM
 		general_agenda
 	`
@@ -138,20 +154,61 @@ func (s *Store) QueryGeneralAgenda(ctx context.Context, filter agenda.GAQueryFil
 	return agds, nil
 }
 
-func (s *Store) QueryGeneralAgendaByBusinessID(ctx context.Context, bsnID uuid.UUID) (agenda.GeneralAgenda, error) {
+func (s *Store) QueryGeneralAgendaByBusinessID(ctx context.Context, bsnID uuid.UUID, date time.Time) (agenda.GeneralAgenda, error) {
 	data := struct {
-		BusinessID string `db:"business_id"`
+		BusinessID string    `db:"business_id"`
+		Date       time.Time `db:"date"`
 	}{
 		BusinessID: bsnID.String(),
+		Date:       time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
 	}
 
 	const q = `
 	SELECT 	
-		id, business_id, opens_at, closed_at, interval, working_days, date_created, date_updated
+		id, business_id, resource_id, hours, effective_from, effective_to, date_created, date_updated
+	FROM
+		general_agenda
+	WHERE
+		business_id = :business_id AND
+		resource_id IS NULL AND
+		(effective_from IS NULL OR effective_from <= :date) AND
+		(effective_to IS NULL OR effective_to >= :date)
+	`
+
+	var dbgAgd dbGeneralAgenda
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbgAgd); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return agenda.GeneralAgenda{}, fmt.Errorf("namedquerystruct: %w", agenda.ErrNotFound)
+		}
+		return agenda.GeneralAgenda{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	agd, err := toCoreGeneralAgenda(dbgAgd)
+	if err != nil {
+		return agenda.GeneralAgenda{}, err
+	}
+
+	return agd, nil
+}
+
+func (s *Store) QueryGeneralAgendaByResourceID(ctx context.Context, rscID uuid.UUID, date time.Time) (agenda.GeneralAgenda, error) {
+	data := struct {
+		ResourceID string    `db:"resource_id"`
+		Date       time.Time `db:"date"`
+	}{
+		ResourceID: rscID.String(),
+		Date:       time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+	}
+
+	const q = `
+	SELECT
+		id, business_id, resource_id, hours, effective_from, effective_to, date_created, date_updated
 	FROM
 		general_agenda
 	WHERE
-		business_id = :business_id
+		resource_id = :resource_id AND
+		(effective_from IS NULL OR effective_from <= :date) AND
+		(effective_to IS NULL OR effective_to >= :date)
 	`
 
 	var dbgAgd dbGeneralAgenda
@@ -179,7 +236,7 @@ func (s *Store) QueryGeneralAgendaByID(ctx context.Context, agdID uuid.UUID) (ag
 
 	const q = `
 	SELECT 	
-		id, business_id, opens_at, closed_at, interval, working_days, date_created, date_updated
+		id, business_id, resource_id, hours, effective_from, effective_to, date_created, date_updated
 	FROM
 		general_agenda
 	WHERE
@@ -228,12 +285,20 @@ func (s *Store) CountGeneralAgenda(ctx context.Context, filter agenda.GAQueryFil
 func (s *Store) CreateDailyAgenda(ctx context.Context, agd agenda.DailyAgenda) error {
 	const q = `
 	INSERT INTO daily_agenda
-		(id, business_id, opens_at, closed_at, interval, availability, date_created, date_updated)
+		(id, business_id, resource_id, date, availability, windows, source_uid, date_created, date_updated)
 	VALUES
-		(:id, :business_id, :opens_at, :closed_at, :interval, :availability, :date_created, :date_updated)
+		(:id, :business_id, :resource_id, :date, :availability, :windows, :source_uid, :date_created, :date_updated)
 	`
 
-	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBDailyAgenda(agd)); err != nil {
+	dbAgd, err := toDBDailyAgenda(agd)
+	if err != nil {
+		return err
+	}
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, dbAgd); err != nil {
+		if errors.Is(err, db.ErrDBDuplicateEntry) {
+			return fmt.Errorf("namedexeccontext: %w", agenda.ErrDailyAgendaExists)
+		}
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -245,15 +310,23 @@ func (s *Store) UpdateDailyAgenda(ctx context.Context, agd agenda.DailyAgenda) e
 	UPDATE
 		daily_agenda
 	SET
-		"opens_at" = :opens_at,
-		"closed_at" = :closed_at,
-		"interval" = :interval,
+		"date" = :date,
+		"availability" = :availability,
+		"windows" = :windows,
 		"date_updated" = :date_updated
 	WHERE
 		"id" = :id
 	`
 
-	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBDailyAgenda(agd)); err != nil {
+	dbAgd, err := toDBDailyAgenda(agd)
+	if err != nil {
+		return err
+	}
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, dbAgd); err != nil {
+		if errors.Is(err, db.ErrDBDuplicateEntry) {
+			return fmt.Errorf("namedexeccontext: %w", agenda.ErrDailyAgendaExists)
+		}
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -289,7 +362,7 @@ func (s *Store) QueryDailyAgenda(ctx context.Context, filter agenda.DAQueryFilte
 
 	const q = `
 	SELECT 	
-		id, business_id, opens_at, closed_at, interval, availability, date_created, date_updated
+		id, business_id, resource_id, date, availability, windows, source_uid, date_created, date_updated
 	FROM
 		daily_agenda
 	`
@@ -351,7 +424,7 @@ func (s *Store) QueryDailyAgendaByID(ctx context.Context, agdID uuid.UUID) (agen
 
 	const q = `
 	SELECT 	
-		id, business_id, opens_at, closed_at, interval, availability, date_created, date_updated
+		id, business_id, resource_id, date, availability, windows, source_uid, date_created, date_updated
 	FROM 
 		daily_agenda
 	WHERE
@@ -373,3 +446,144 @@ func (s *Store) QueryDailyAgendaByID(ctx context.Context, agdID uuid.UUID) (agen
 
 	return cAgd, nil
 }
+
+// ---------------------------------------------------------------------------------
+
+func (s *Store) CreateClosure(ctx context.Context, cl agenda.Closure) error {
+	const q = `
+	INSERT INTO closures
+		(closure_id, business_id, resource_id, start_date, end_date, yearly, reason, date_created, date_updated)
+	VALUES
+		(:closure_id, :business_id, :resource_id, :start_date, :end_date, :yearly, :reason, :date_created, :date_updated)
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBClosure(cl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) UpdateClosure(ctx context.Context, cl agenda.Closure) error {
+	const q = `
+	UPDATE
+		closures
+	SET
+		"start_date" = :start_date,
+		"end_date" = :end_date,
+		"yearly" = :yearly,
+		"reason" = :reason,
+		"date_updated" = :date_updated
+	WHERE
+		"closure_id" = :closure_id
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBClosure(cl)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) DeleteClosure(ctx context.Context, cl agenda.Closure) error {
+	data := struct {
+		ID string `db:"closure_id"`
+	}{
+		ID: cl.ID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		closures
+	WHERE
+		"closure_id" = :closure_id
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) QueryClosures(ctx context.Context, filter agenda.ClosureQueryFilter, orderBy order.By, page page.Page) ([]agenda.Closure, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		closure_id, business_id, resource_id, start_date, end_date, yearly, reason, date_created, date_updated
+	FROM
+		closures
+	`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilterClosure(filter, data, buf)
+
+	orderByClause, err := closureOrderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbCls []dbClosure
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbCls); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreClosureSlice(dbCls), nil
+}
+
+func (s *Store) CountClosures(ctx context.Context, filter agenda.ClosureQueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		COUNT(1)
+	FROM
+		closures
+	`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilterClosure(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+func (s *Store) QueryClosureByID(ctx context.Context, clID uuid.UUID) (agenda.Closure, error) {
+	data := struct {
+		ID string `db:"closure_id"`
+	}{
+		ID: clID.String(),
+	}
+
+	const q = `
+	SELECT
+		closure_id, business_id, resource_id, start_date, end_date, yearly, reason, date_created, date_updated
+	FROM
+		closures
+	WHERE
+		closure_id = :closure_id
+	`
+
+	var dbCl dbClosure
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbCl); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return agenda.Closure{}, fmt.Errorf("namedquerystruct: %w", agenda.ErrClosureNotFound)
+		}
+		return agenda.Closure{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreClosure(dbCl), nil
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ameghdadian/service/business/core/agenda"
+	"github.com/google/uuid"
 )
 
 func (s *Store) applyFilterGeneralAgenda(filter agenda.GAQueryFilter, data map[string]any, buf *bytes.Buffer) {
@@ -19,6 +20,14 @@ func (s *Store) applyFilterGeneralAgenda(filter agenda.GAQueryFilter, data map[s
 		data["business_id"] = *filter.BusinesesID
 		wc = append(wc, "business_id = :business_id")
 	}
+	if filter.ResourceID != nil {
+		wc = append(wc, resourceClause(*filter.ResourceID, data))
+	}
+	if filter.Date != nil {
+		d := *filter.Date
+		data["date"] = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		wc = append(wc, "(effective_from IS NULL OR effective_from <= :date)", "(effective_to IS NULL OR effective_to >= :date)")
+	}
 
 	if len(wc) > 0 {
 		buf.WriteString(" WHERE ")
@@ -39,36 +48,95 @@ func (s *Store) applyFilterDailyAgenda(filter agenda.DAQueryFilter, data map[str
 		wc = append(wc, "business_id = :bsnID")
 	}
 
+	if filter.ResourceID != nil {
+		wc = append(wc, resourceClause(*filter.ResourceID, data))
+	}
+
+	if filter.SourceUID != nil {
+		data["source_uid"] = *filter.SourceUID
+		wc = append(wc, "source_uid = :source_uid")
+	}
+
 	if filter.Date != nil {
+		// The calendar date is taken as it reads in the location of the date.
 		d := *filter.Date
-		data["date_start"] = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, d.Location())
-		data["date_end"] = time.Date(d.Year(), d.Month(), d.Day(), 23, 59, 59, 0, d.Location())
-		wc = append(wc, "opens_at >= :date_start AND opens_at <= :date_end")
+		data["date"] = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		wc = append(wc, "date = :date")
 	}
 
 	if filter.From != nil {
 		data["from"] = *filter.From
-		wc = append(wc, "opens_at >= :from")
+		wc = append(wc, "date >= :from")
 	}
 
 	if filter.To != nil {
 		data["to"] = *filter.To
-		wc = append(wc, "opens_at <= :to")
+		wc = append(wc, "date <= :to")
 	}
 
 	if filter.Days != nil {
-		data["now"] = time.Now().UTC()
-		data["then"] = time.Now().UTC().AddDate(0, 0, *filter.Days)
-		wc = append(wc, "opens_at >= :now AND opens_at < :then")
+		data["now"] = time.Now().UTC().Format(time.DateOnly)
+		data["then"] = time.Now().UTC().AddDate(0, 0, *filter.Days).Format(time.DateOnly)
+		wc = append(wc, "date >= :now AND date < :then")
 	}
 
 	if filter.ID == nil &&
+		filter.SourceUID == nil &&
 		filter.Date == nil &&
 		filter.From == nil &&
 		filter.To == nil &&
 		filter.Days == nil {
-		data["now"] = time.Now().UTC()
-		wc = append(wc, "opens_at >= :now")
+		data["now"] = time.Now().UTC().Format(time.DateOnly)
+		wc = append(wc, "date >= :now")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.Write([]byte(strings.Join(wc, " AND ")))
+	}
+}
+
+// resourceClause narrows down to the agendas of a resource, or to the agendas of the
+// business as a whole when given uuid.Nil.
+func resourceClause(rscID uuid.UUID, data map[string]any) string {
+	if rscID == uuid.Nil {
+		return "resource_id IS NULL"
+	}
+
+	data["resource_id"] = rscID
+	return "resource_id = :resource_id"
+}
+
+func (s *Store) applyFilterClosure(filter agenda.ClosureQueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if filter.ID != nil {
+		data["closure_id"] = *filter.ID
+		wc = append(wc, "closure_id = :closure_id")
+	}
+
+	if filter.BusinessID != nil {
+		data["business_id"] = *filter.BusinessID
+		wc = append(wc, "business_id = :business_id")
+	}
+
+	if filter.ResourceID != nil {
+		wc = append(wc, resourceClause(*filter.ResourceID, data))
+	}
+
+	if filter.Date != nil {
+		// Yearly closures match on month and day, from their first year on. Those
+		// spanning the new year end on an earlier month and day than they start.
+		d := *filter.Date
+		data["on"] = time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		data["on_day"] = d.Format("01-02")
+		wc = append(wc, `start_date <= :on AND (
+			(NOT yearly AND end_date >= :on) OR
+			(yearly AND to_char(start_date, 'MM-DD') <= to_char(end_date, 'MM-DD') AND
+				:on_day BETWEEN to_char(start_date, 'MM-DD') AND to_char(end_date, 'MM-DD')) OR
+			(yearly AND to_char(start_date, 'MM-DD') > to_char(end_date, 'MM-DD') AND
+				(:on_day >= to_char(start_date, 'MM-DD') OR :on_day <= to_char(end_date, 'MM-DD')))
+		)`)
 	}
 
 	if len(wc) > 0 {
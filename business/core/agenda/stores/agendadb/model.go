@@ -2,65 +2,114 @@ package agendadb
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/ameghdadian/service/business/core/agenda"
-	"github.com/ameghdadian/service/business/data/dbsql/pgx/dbarray"
 	"github.com/google/uuid"
 )
 
 type dbGeneralAgenda struct {
-	ID          uuid.UUID     `db:"id"`
-	BusinessID  uuid.UUID     `db:"business_id"`
-	OpensAt     time.Time     `db:"opens_at"`
-	ClosedAt    time.Time     `db:"closed_at"`
-	Interval    int           `db:"interval"`
-	WorkingDays dbarray.Int32 `db:"working_days"`
-	DateCreated time.Time     `db:"date_created"`
-	DateUpdated time.Time     `db:"date_updated"`
+	ID            uuid.UUID     `db:"id"`
+	BusinessID    uuid.UUID     `db:"business_id"`
+	ResourceID    uuid.NullUUID `db:"resource_id"`
+	Hours         []byte        `db:"hours"`
+	EffectiveFrom sql.NullTime  `db:"effective_from"`
+	EffectiveTo   sql.NullTime  `db:"effective_to"`
+	DateCreated   time.Time     `db:"date_created"`
+	DateUpdated   time.Time     `db:"date_updated"`
+}
+
+// dbOpeningHours is how a single opening hours is kept inside the hours JSONB column.
+type dbOpeningHours struct {
+	Day      uint   `json:"day"`
+	OpensAt  string `json:"opens_at"`
+	ClosedAt string `json:"closed_at"`
+	Interval int    `json:"interval"`
 }
 
-func toDBGeneralAgenda(gAgd agenda.GeneralAgenda) dbGeneralAgenda {
-	days := make([]int32, len(gAgd.WorkingDays))
-	for i, d := range gAgd.WorkingDays {
-		days[i] = int32(d.DayOfWeedk())
+func toDBGeneralAgenda(gAgd agenda.GeneralAgenda) (dbGeneralAgenda, error) {
+	hours := make([]dbOpeningHours, len(gAgd.Hours))
+	for i, h := range gAgd.Hours {
+		hours[i] = dbOpeningHours{
+			Day:      h.Day.DayOfWeedk(),
+			OpensAt:  h.OpensAt.String(),
+			ClosedAt: h.ClosedAt.String(),
+			Interval: h.Interval,
+		}
 	}
 
-	return dbGeneralAgenda{
-		ID:          gAgd.ID,
-		BusinessID:  gAgd.BusinessID,
-		OpensAt:     gAgd.OpensAt.UTC(),
-		ClosedAt:    gAgd.ClosedAt.UTC(),
-		Interval:    gAgd.Interval,
-		WorkingDays: days,
-		DateCreated: gAgd.DateCreated.UTC(),
-		DateUpdated: gAgd.DateUpdated.UTC(),
+	data, err := json.Marshal(hours)
+	if err != nil {
+		return dbGeneralAgenda{}, fmt.Errorf("marshal hours: %w", err)
 	}
+
+	return dbGeneralAgenda{
+		ID:            gAgd.ID,
+		BusinessID:    gAgd.BusinessID,
+		ResourceID:    uuid.NullUUID{UUID: gAgd.ResourceID, Valid: gAgd.ResourceID != uuid.Nil},
+		Hours:         data,
+		EffectiveFrom: sql.NullTime{Time: gAgd.EffectiveFrom, Valid: !gAgd.EffectiveFrom.IsZero()},
+		EffectiveTo:   sql.NullTime{Time: gAgd.EffectiveTo, Valid: !gAgd.EffectiveTo.IsZero()},
+		DateCreated:   gAgd.DateCreated.UTC(),
+		DateUpdated:   gAgd.DateUpdated.UTC(),
+	}, nil
 }
 
 func toCoreGeneralAgenda(dbAgd dbGeneralAgenda) (agenda.GeneralAgenda, error) {
-	days := make([]agenda.Day, len(dbAgd.WorkingDays))
-	for i, dd := range dbAgd.WorkingDays {
-		var err error
-		days[i], err = agenda.ParseDay(uint(dd))
+	var dbHours []dbOpeningHours
+	if err := json.Unmarshal(dbAgd.Hours, &dbHours); err != nil {
+		return agenda.GeneralAgenda{}, fmt.Errorf("unmarshal hours: %w", err)
+	}
+
+	hours := make([]agenda.OpeningHours, len(dbHours))
+	for i, dh := range dbHours {
+		day, err := agenda.ParseDay(dh.Day)
 		if err != nil {
 			return agenda.GeneralAgenda{}, fmt.Errorf("parse day: %w", err)
 		}
+
+		opn, err := agenda.ParseClock(dh.OpensAt)
+		if err != nil {
+			return agenda.GeneralAgenda{}, fmt.Errorf("parse opens at: %w", err)
+		}
+
+		cld, err := agenda.ParseClock(dh.ClosedAt)
+		if err != nil {
+			return agenda.GeneralAgenda{}, fmt.Errorf("parse closed at: %w", err)
+		}
+
+		hours[i] = agenda.OpeningHours{
+			Day:      day,
+			OpensAt:  opn,
+			ClosedAt: cld,
+			Interval: dh.Interval,
+		}
 	}
 
 	return agenda.GeneralAgenda{
-		ID:          dbAgd.ID,
-		BusinessID:  dbAgd.BusinessID,
-		OpensAt:     dbAgd.OpensAt.In(time.Local),
-		ClosedAt:    dbAgd.ClosedAt.In(time.Local),
-		Interval:    dbAgd.Interval,
-		WorkingDays: days,
-		DateCreated: dbAgd.DateCreated.In(time.Local),
-		DateUpdated: dbAgd.DateUpdated.In(time.Local),
+		ID:            dbAgd.ID,
+		BusinessID:    dbAgd.BusinessID,
+		ResourceID:    dbAgd.ResourceID.UUID,
+		Hours:         hours,
+		EffectiveFrom: toCoreDate(dbAgd.EffectiveFrom),
+		EffectiveTo:   toCoreDate(dbAgd.EffectiveTo),
+		DateCreated:   dbAgd.DateCreated.In(time.Local),
+		DateUpdated:   dbAgd.DateUpdated.In(time.Local),
 	}, nil
 }
 
+// toCoreDate returns the calendar date kept in a nullable DATE column at midnight UTC,
+// or the zero time when there's none.
+func toCoreDate(nt sql.NullTime) time.Time {
+	if !nt.Valid {
+		return time.Time{}
+	}
+
+	return time.Date(nt.Time.Year(), nt.Time.Month(), nt.Time.Day(), 0, 0, 0, 0, time.UTC)
+}
+
 func toCoreGeneralAgendaSlice(dbgAgds []dbGeneralAgenda) ([]agenda.GeneralAgenda, error) {
 	agds := make([]agenda.GeneralAgenda, len(dbgAgds))
 
@@ -78,55 +127,94 @@ func toCoreGeneralAgendaSlice(dbgAgds []dbGeneralAgenda) ([]agenda.GeneralAgenda
 // ---------------------------------------------------------------------------------
 
 type dbDailyAgenda struct {
-	ID           uuid.UUID     `db:"id"`
-	BusinessID   uuid.UUID     `db:"business_id"`
-	OpensAt      sql.NullTime  `db:"opens_at"`
-	ClosedAt     sql.NullTime  `db:"closed_at"`
-	Interval     sql.NullInt64 `db:"interval"`
-	Availability bool          `db:"availability"`
-	DateCreated  time.Time     `db:"date_created"`
-	DateUpdated  time.Time     `db:"date_updated"`
+	ID           uuid.UUID      `db:"id"`
+	BusinessID   uuid.UUID      `db:"business_id"`
+	ResourceID   uuid.NullUUID  `db:"resource_id"`
+	Date         time.Time      `db:"date"`
+	Availability bool           `db:"availability"`
+	Windows      []byte         `db:"windows"`
+	SourceUID    sql.NullString `db:"source_uid"`
+	DateCreated  time.Time      `db:"date_created"`
+	DateUpdated  time.Time      `db:"date_updated"`
 }
 
-func toDBDailyAgenda(gAgd agenda.DailyAgenda) dbDailyAgenda {
-	return dbDailyAgenda{
-		ID:         gAgd.ID,
-		BusinessID: gAgd.BusinessID,
-		OpensAt: sql.NullTime{
-			Time:  gAgd.OpensAt.UTC(),
-			Valid: !gAgd.OpensAt.UTC().IsZero(),
-		},
-		ClosedAt: sql.NullTime{
-			Time:  gAgd.ClosedAt.UTC(),
-			Valid: !gAgd.ClosedAt.UTC().IsZero(),
-		},
-		Interval: sql.NullInt64{
-			Int64: int64(gAgd.Interval),
-			Valid: gAgd.Interval > 0,
-		},
-		Availability: true,
-		DateCreated:  gAgd.DateCreated.UTC(),
-		DateUpdated:  gAgd.DateUpdated.UTC(),
+// dbWindow is how a single window is kept inside the windows JSONB column.
+type dbWindow struct {
+	OpensAt  string `json:"opens_at"`
+	ClosedAt string `json:"closed_at"`
+	Interval int    `json:"interval"`
+}
+
+func toDBDailyAgenda(dAgd agenda.DailyAgenda) (dbDailyAgenda, error) {
+	wins := make([]dbWindow, len(dAgd.Windows))
+	for i, w := range dAgd.Windows {
+		wins[i] = dbWindow{
+			OpensAt:  w.OpensAt.String(),
+			ClosedAt: w.ClosedAt.String(),
+			Interval: w.Interval,
+		}
+	}
+
+	data, err := json.Marshal(wins)
+	if err != nil {
+		return dbDailyAgenda{}, fmt.Errorf("marshal windows: %w", err)
 	}
+
+	return dbDailyAgenda{
+		ID:           dAgd.ID,
+		BusinessID:   dAgd.BusinessID,
+		ResourceID:   uuid.NullUUID{UUID: dAgd.ResourceID, Valid: dAgd.ResourceID != uuid.Nil},
+		Date:         dAgd.Date,
+		Availability: dAgd.Availability,
+		Windows:      data,
+		SourceUID:    sql.NullString{String: dAgd.SourceUID, Valid: dAgd.SourceUID != ""},
+		DateCreated:  dAgd.DateCreated.UTC(),
+		DateUpdated:  dAgd.DateUpdated.UTC(),
+	}, nil
 }
 
 func toCoreDailyAgenda(dbAgd dbDailyAgenda) (agenda.DailyAgenda, error) {
+	var dbWins []dbWindow
+	if err := json.Unmarshal(dbAgd.Windows, &dbWins); err != nil {
+		return agenda.DailyAgenda{}, fmt.Errorf("unmarshal windows: %w", err)
+	}
+
+	wins := make([]agenda.Window, len(dbWins))
+	for i, dw := range dbWins {
+		opn, err := agenda.ParseClock(dw.OpensAt)
+		if err != nil {
+			return agenda.DailyAgenda{}, fmt.Errorf("parse opens at: %w", err)
+		}
+
+		cld, err := agenda.ParseClock(dw.ClosedAt)
+		if err != nil {
+			return agenda.DailyAgenda{}, fmt.Errorf("parse closed at: %w", err)
+		}
+
+		wins[i] = agenda.Window{
+			OpensAt:  opn,
+			ClosedAt: cld,
+			Interval: dw.Interval,
+		}
+	}
+
 	return agenda.DailyAgenda{
 		ID:           dbAgd.ID,
 		BusinessID:   dbAgd.BusinessID,
-		OpensAt:      dbAgd.OpensAt.Time.In(time.Local),
-		ClosedAt:     dbAgd.ClosedAt.Time.In(time.Local),
-		Interval:     int(dbAgd.Interval.Int64),
+		ResourceID:   dbAgd.ResourceID.UUID,
+		Date:         time.Date(dbAgd.Date.Year(), dbAgd.Date.Month(), dbAgd.Date.Day(), 0, 0, 0, 0, time.UTC),
 		Availability: dbAgd.Availability,
+		Windows:      wins,
+		SourceUID:    dbAgd.SourceUID.String,
 		DateCreated:  dbAgd.DateCreated.In(time.Local),
 		DateUpdated:  dbAgd.DateUpdated.In(time.Local),
 	}, nil
 }
 
-func toCoreDailyAgendaSlice(dbgAgds []dbDailyAgenda) ([]agenda.DailyAgenda, error) {
-	agds := make([]agenda.DailyAgenda, len(dbgAgds))
+func toCoreDailyAgendaSlice(dbAgds []dbDailyAgenda) ([]agenda.DailyAgenda, error) {
+	agds := make([]agenda.DailyAgenda, len(dbAgds))
 
-	for i, agd := range dbgAgds {
+	for i, agd := range dbAgds {
 		var err error
 		agds[i], err = toCoreDailyAgenda(agd)
 		if err != nil {
@@ -136,3 +224,54 @@ func toCoreDailyAgendaSlice(dbgAgds []dbDailyAgenda) ([]agenda.DailyAgenda, erro
 
 	return agds, nil
 }
+
+// ---------------------------------------------------------------------------------
+
+type dbClosure struct {
+	ID          uuid.UUID     `db:"closure_id"`
+	BusinessID  uuid.UUID     `db:"business_id"`
+	ResourceID  uuid.NullUUID `db:"resource_id"`
+	StartDate   time.Time     `db:"start_date"`
+	EndDate     time.Time     `db:"end_date"`
+	Yearly      bool          `db:"yearly"`
+	Reason      string        `db:"reason"`
+	DateCreated time.Time     `db:"date_created"`
+	DateUpdated time.Time     `db:"date_updated"`
+}
+
+func toDBClosure(cl agenda.Closure) dbClosure {
+	return dbClosure{
+		ID:          cl.ID,
+		BusinessID:  cl.BusinessID,
+		ResourceID:  uuid.NullUUID{UUID: cl.ResourceID, Valid: cl.ResourceID != uuid.Nil},
+		StartDate:   cl.From,
+		EndDate:     cl.To,
+		Yearly:      cl.Yearly,
+		Reason:      cl.Reason,
+		DateCreated: cl.DateCreated.UTC(),
+		DateUpdated: cl.DateUpdated.UTC(),
+	}
+}
+
+func toCoreClosure(dbCl dbClosure) agenda.Closure {
+	return agenda.Closure{
+		ID:          dbCl.ID,
+		BusinessID:  dbCl.BusinessID,
+		ResourceID:  dbCl.ResourceID.UUID,
+		From:        time.Date(dbCl.StartDate.Year(), dbCl.StartDate.Month(), dbCl.StartDate.Day(), 0, 0, 0, 0, time.UTC),
+		To:          time.Date(dbCl.EndDate.Year(), dbCl.EndDate.Month(), dbCl.EndDate.Day(), 0, 0, 0, 0, time.UTC),
+		Yearly:      dbCl.Yearly,
+		Reason:      dbCl.Reason,
+		DateCreated: dbCl.DateCreated.In(time.Local),
+		DateUpdated: dbCl.DateUpdated.In(time.Local),
+	}
+}
+
+func toCoreClosureSlice(dbCls []dbClosure) []agenda.Closure {
+	cls := make([]agenda.Closure, len(dbCls))
+	for i, cl := range dbCls {
+		cls[i] = toCoreClosure(cl)
+	}
+
+	return cls
+}
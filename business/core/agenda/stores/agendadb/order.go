@@ -10,6 +10,7 @@ import (
 var orderByFields = map[string]string{
 	agenda.OrderByID:         "id",
 	agenda.OrderByBusinessID: "business_id",
+	agenda.OrderByDate:       "date",
 }
 
 func orderByClause(orderBy order.By) (string, error) {
@@ -20,3 +21,18 @@ func orderByClause(orderBy order.By) (string, error) {
 
 	return " ORDER BY " + by + " " + orderBy.Direction, nil
 }
+
+var closureOrderByFields = map[string]string{
+	agenda.OrderByID:         "closure_id",
+	agenda.OrderByBusinessID: "business_id",
+	agenda.OrderByFrom:       "start_date",
+}
+
+func closureOrderByClause(orderBy order.By) (string, error) {
+	by, exists := closureOrderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("filed %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -12,16 +12,24 @@ import (
 func TestGenerateNewGeneralAgendas(n int, bsnID uuid.UUID, userID uuid.UUID) ([]NewGeneralAgenda, error) {
 	newGAgds := make([]NewGeneralAgenda, n)
 
-	now := time.Now()
+	// Seeded businesses operate in UTC.
+	now := time.Now().UTC()
 
 	diff := int(math.Min(float64(24-now.Hour()), 2))
 	for i := range n {
+		hours := make([]OpeningHours, 0, len(days))
+		for _, d := range []Day{DaySunday, DayMonday, DayTuesday, DayWednesday, DayThursday, DayFriday, DaySaturday} {
+			hours = append(hours, OpeningHours{
+				Day:      d,
+				OpensAt:  Clock{sec: now.Hour() * 3600},
+				ClosedAt: Clock{sec: (now.Hour() + diff) * 3600},
+				Interval: 60 * 15, // 15 minutes
+			})
+		}
+
 		newGAgds[i] = NewGeneralAgenda{
-			BusinessID:  bsnID,
-			OpensAt:     time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, time.Local),
-			ClosedAt:    time.Date(now.Year(), now.Month(), now.Day(), now.Add(time.Duration(diff)*time.Hour).Hour(), 0, 0, 0, time.Local),
-			Interval:    60 * 15, // 15 minutes
-			WorkingDays: []Day{{2}, {4}, {6}},
+			BusinessID: bsnID,
+			Hours:      hours,
 		}
 	}
 
@@ -51,16 +59,22 @@ func TestGenerateSeedGeneralAgendas(n int, agdCore *Core, bsnID uuid.UUID, userI
 func TestGenerateNewDailyAgendas(n int, bsnID uuid.UUID, userID uuid.UUID) ([]NewDailyAgenda, error) {
 	newDAgds := make([]NewDailyAgenda, n)
 
-	then := time.Now().AddDate(0, 0, 2)
+	// Seeded businesses operate in UTC.
+	then := time.Now().UTC().AddDate(0, 0, 2)
 
 	diff := int(math.Min(float64(24-then.Hour()), 2))
 	for i := range n {
 		newDAgds[i] = NewDailyAgenda{
 			BusinessID:   bsnID,
-			OpensAt:      time.Date(then.Year(), then.Month(), then.Day(), then.Hour(), 0, 0, 0, time.Local),
-			ClosedAt:     time.Date(then.Year(), then.Month(), then.Day(), then.Add(time.Duration(diff)*time.Hour).Hour(), 0, 0, 0, time.Local),
-			Interval:     60 * 10, // 10 minutes
+			Date:         then.AddDate(0, 0, i),
 			Availability: true,
+			Windows: []Window{
+				{
+					OpensAt:  Clock{sec: then.Hour() * 3600},
+					ClosedAt: Clock{sec: (then.Hour() + diff) * 3600},
+					Interval: 60 * 10, // 10 minutes
+				},
+			},
 		}
 	}
 
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/business/data/order"
 	"github.com/ameghdadian/service/business/data/page"
@@ -14,16 +16,37 @@ import (
 	"github.com/ameghdadian/service/foundation/logger"
 	"github.com/ameghdadian/service/foundation/otel"
 	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
 )
 
 var (
-	ErrNotFound         = errors.New("appointment not found")
-	ErrUserDisabled     = errors.New("user disabled")
-	ErrPastTime         = errors.New("time past now")
-	ErrAlreadyCancelled = errors.New("appointment already cancelled")
-	ErrAlreadyReserved  = errors.New("given time is already reserverd")
+	ErrNotFound            = errors.New("appointment not found")
+	ErrUserDisabled        = errors.New("user disabled")
+	ErrPastTime            = errors.New("time past now")
+	ErrAlreadyCancelled    = errors.New("appointment already cancelled")
+	ErrAlreadyReserved     = errors.New("given time is already reserverd")
+	ErrServiceMismatch     = errors.New("service is not offered by the business")
+	ErrResourceMismatch    = errors.New("resource does not belong to the business")
+	ErrResourceRequired    = errors.New("business books appointments against its resources")
+	ErrSlotFull            = errors.New("slot is fully booked")
+	ErrNotOpen             = errors.New("appointment is no longer open")
+	ErrInvalidTransition   = errors.New("appointment can't take the given status")
+	ErrTransitionForbidden = errors.New("party may not give the appointment the given status")
+	ErrCancellationWindow  = errors.New("appointment is too close to cancel")
+	ErrRescheduleWindow    = errors.New("appointment is too close to reschedule")
+	ErrLeadTime            = errors.New("appointment must be booked further ahead")
+	ErrBeyondHorizon       = errors.New("appointment is booked too far ahead")
+	ErrTooManyOccurrences  = errors.New("series has too many occurrences")
+	ErrInvalidHold         = fmt.Errorf("hold must last between a minute and %s", MaxHold)
+	ErrNotHeld             = errors.New("appointment is not held")
+	ErrHoldLapsed          = errors.New("hold has lapsed")
+	ErrOnHold              = errors.New("appointment is held while the customer checks out")
+	ErrDeleteForbidden     = errors.New("party may not delete the appointment, only cancel it")
 )
 
+// MaxHold is the longest a slot is held while a customer checks out.
+const MaxHold = 30 * time.Minute
+
 type Storer interface {
 	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
 	Create(ctx context.Context, apt Appointment) error
@@ -32,8 +55,13 @@ type Storer interface {
 	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Appointment, error)
 	Count(ctx context.Context, filter QueryFilter) (int, error)
 	QueryByID(ctx context.Context, aptID uuid.UUID) (Appointment, error)
+	QueryOverlapping(ctx context.Context, apt Appointment) ([]Appointment, error)
 	QueryByUserID(ctx context.Context, usrID uuid.UUID) ([]Appointment, error)
 	QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]Appointment, error)
+	CreateEvent(ctx context.Context, evt Event) error
+	QueryEvents(ctx context.Context, aptID uuid.UUID) ([]Event, error)
+	CreateSeries(ctx context.Context, srs Series) error
+	QueryBySeriesID(ctx context.Context, srsID uuid.UUID) ([]Appointment, error)
 }
 
 type Core struct {
@@ -41,15 +69,19 @@ type Core struct {
 	log     *logger.Logger
 	usrCore *user.Core
 	bsnCore *business.Core
+	svcCore *service.Core
+	rscCore *resource.Core
 	task    *Task
 }
 
-func NewCore(log *logger.Logger, usrCore *user.Core, bsnCore *business.Core, storer Storer, task *Task) *Core {
+func NewCore(log *logger.Logger, usrCore *user.Core, bsnCore *business.Core, svcCore *service.Core, rscCore *resource.Core, storer Storer, task *Task) *Core {
 	return &Core{
 		storer:  storer,
 		log:     log,
 		usrCore: usrCore,
 		bsnCore: bsnCore,
+		svcCore: svcCore,
+		rscCore: rscCore,
 		task:    task,
 	}
 }
@@ -70,11 +102,23 @@ func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error
 		return nil, err
 	}
 
+	svcCore, err := c.svcCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	rscCore, err := c.rscCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
 	c = &Core{
 		storer:  storer,
 		log:     c.log,
 		usrCore: usrCore,
 		bsnCore: bsnCore,
+		svcCore: svcCore,
+		rscCore: rscCore,
 		task:    c.task,
 	}
 
@@ -85,75 +129,346 @@ func (c *Core) Create(ctx context.Context, na NewAppointment) (Appointment, erro
 	ctx, span := otel.AddSpan(ctx, "business.appointment.create")
 	defer span.End()
 
-	usr, err := c.usrCore.QueryByID(ctx, na.UserID)
+	bsn, err := c.queryBooker(ctx, na.UserID, na.BusinessID)
+	if err != nil {
+		return Appointment{}, err
+	}
+
+	apt, err := c.create(ctx, na, bsn, uuid.Nil, time.Time{})
 	if err != nil {
-		return Appointment{}, fmt.Errorf("user.querybyid: %s: %w", na.UserID, err)
+		return Appointment{}, err
+	}
+
+	if err := c.remind(apt, bsn); err != nil {
+		return Appointment{}, err
+	}
+
+	if err := c.notify(emailConfirmation, apt, bsn.TimeZone.Location(), time.Time{}, ""); err != nil {
+		return Appointment{}, err
+	}
+
+	return apt, nil
+}
+
+// CreateSeries books an appointment for each occurrence of the series, all or none
+// of them. Each appointment is reminded of on its own.
+func (c *Core) CreateSeries(ctx context.Context, ns NewSeries) (Series, []Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.createseries")
+	defer span.End()
+
+	bsn, err := c.queryBooker(ctx, ns.UserID, ns.BusinessID)
+	if err != nil {
+		return Series{}, nil, err
+	}
+
+	occs, err := ns.Rule.Occurrences(ns.StartsOn.In(bsn.TimeZone.Location()))
+	if err != nil {
+		return Series{}, nil, err
+	}
+
+	srs := Series{
+		ID:          uuid.New(),
+		BusinessID:  bsn.ID,
+		UserID:      ns.UserID,
+		Rule:        ns.Rule,
+		StartsOn:    ns.StartsOn,
+		DateCreated: time.Now(),
+	}
+
+	if err := c.storer.CreateSeries(ctx, srs); err != nil {
+		return Series{}, nil, fmt.Errorf("createseries: %w", err)
+	}
+
+	apts := make([]Appointment, len(occs))
+	for i, occ := range occs {
+		na := NewAppointment{
+			BusinessID:  ns.BusinessID,
+			UserID:      ns.UserID,
+			ServiceID:   ns.ServiceID,
+			ResourceID:  ns.ResourceID,
+			ScheduledOn: occ,
+		}
+
+		apts[i], err = c.create(ctx, na, bsn, srs.ID, time.Time{})
+		if err != nil {
+			return Series{}, nil, fmt.Errorf("occurrence %s: %w", occ.Format(time.RFC3339), err)
+		}
+	}
+
+	// Reminders and confirmations are only sent once every occurrence is booked.
+	for _, apt := range apts {
+		if err := c.remind(apt, bsn); err != nil {
+			return Series{}, nil, err
+		}
+
+		if err := c.notify(emailConfirmation, apt, bsn.TimeZone.Location(), time.Time{}, ""); err != nil {
+			return Series{}, nil, err
+		}
+	}
+
+	return srs, apts, nil
+}
+
+// Occurrences returns the times the series would book appointments at, in the time
+// zone of its business.
+func (c *Core) Occurrences(ctx context.Context, ns NewSeries) ([]time.Time, error) {
+	bsn, err := c.bsnCore.QueryByID(ctx, ns.BusinessID)
+	if err != nil {
+		return nil, fmt.Errorf("business.querybyid: %s: %w", ns.BusinessID, err)
+	}
+
+	return ns.Rule.Occurrences(ns.StartsOn.In(bsn.TimeZone.Location()))
+}
+
+// remind schedules the reminders of the appointment, by SMS and email, at the
+// reminder offsets of its business.
+func (c *Core) remind(apt Appointment, bsn business.Business) error {
+	if err := c.task.NewSendSMSTask(apt, bsn.TimeZone.Location(), bsn.ReminderOffsets); err != nil {
+		return fmt.Errorf("newsendsmstask: %w", err)
+	}
+
+	if err := c.task.newReminderEmailTask(apt, bsn.TimeZone.Location(), bsn.ReminderOffsets); err != nil {
+		return fmt.Errorf("newreminderemailtask: %w", err)
+	}
+
+	return nil
+}
+
+// forget cancels the reminders of the appointment. Reminders scheduled at offsets
+// the business no longer has are left to find the appointment changed once due.
+func (c *Core) forget(apt Appointment, bsn business.Business) error {
+	if err := c.task.cancelSendSMSTask(apt.ID.String(), bsn.ReminderOffsets); err != nil {
+		return fmt.Errorf("cancelsendsmstask: %w", err)
+	}
+
+	if err := c.task.cancelReminderEmailTask(apt.ID.String(), bsn.ReminderOffsets); err != nil {
+		return fmt.Errorf("cancelreminderemailtask: %w", err)
+	}
+
+	return nil
+}
+
+// notify emails the customer about the appointment once the change is committed,
+// telling the time it was scheduled on before where it's moved.
+func (c *Core) notify(kind emailKind, apt Appointment, loc *time.Location, oldScheduledOn time.Time, reason string) error {
+	err := c.task.newSendEmailTask(
+		kind,
+		newEmailPayload(apt, loc, oldScheduledOn, reason),
+		asynq.TaskID(notifyTaskID(kind, apt.ID.String(), apt.ScheduledOn)),
+		asynq.ProcessIn(commitDelay),
+	)
+
+	// The same change told of again is told of once.
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return fmt.Errorf("newsendemailtask[%s]: %w", kind.name, err)
+	}
+
+	return nil
+}
+
+// queryBooker returns the business, making sure the user booking with it is enabled.
+func (c *Core) queryBooker(ctx context.Context, usrID uuid.UUID, bsnID uuid.UUID) (business.Business, error) {
+	usr, err := c.usrCore.QueryByID(ctx, usrID)
+	if err != nil {
+		return business.Business{}, fmt.Errorf("user.querybyid: %s: %w", usrID, err)
 	}
 
 	if !usr.Enabled {
-		return Appointment{}, ErrUserDisabled
+		return business.Business{}, ErrUserDisabled
 	}
 
-	bsn, err := c.bsnCore.QueryByID(ctx, na.BusinessID)
+	bsn, err := c.bsnCore.QueryByID(ctx, bsnID)
 	if err != nil {
-		return Appointment{}, fmt.Errorf("business.querybyid: %s: %w", na.BusinessID, err)
+		return business.Business{}, fmt.Errorf("business.querybyid: %s: %w", bsnID, err)
 	}
 
-	if na.ScheduledOn.UTC().Before(time.Now().UTC()) {
-		return Appointment{}, ErrPastTime
+	return bsn, nil
+}
+
+// Hold books the appointment as a hold, keeping its slot from other customers for
+// the given duration while the customer checks out. A hold not confirmed in time is
+// cancelled, and no longer occupies its slot even before then. It's reminded of once
+// confirmed.
+func (c *Core) Hold(ctx context.Context, na NewAppointment, d time.Duration) (Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.hold")
+	defer span.End()
+
+	if d < time.Minute || d > MaxHold {
+		return Appointment{}, ErrInvalidHold
+	}
+
+	bsn, err := c.queryBooker(ctx, na.UserID, na.BusinessID)
+	if err != nil {
+		return Appointment{}, err
+	}
+
+	apt, err := c.create(ctx, na, bsn, uuid.Nil, time.Now().Add(d))
+	if err != nil {
+		return Appointment{}, err
+	}
+
+	if err := c.task.newReleaseHoldTask(apt.ID, apt.HeldUntil); err != nil {
+		return Appointment{}, fmt.Errorf("newreleaseholdtask: %w", err)
+	}
+
+	return apt, nil
+}
+
+// ConfirmHold turns the hold into a regular appointment, pending like any other,
+// once the customer has checked out. It returns ErrHoldLapsed if the hold wasn't
+// confirmed in time.
+func (c *Core) ConfirmHold(ctx context.Context, apt Appointment) (Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.confirmhold")
+	defer span.End()
+
+	if !apt.Held() {
+		return Appointment{}, ErrNotHeld
+	}
+
+	if !apt.Status.Holds() {
+		return Appointment{}, ErrNotOpen
 	}
 
-	var filter QueryFilter
-	filter.WithScheduledOn(na.ScheduledOn.UTC())
-	filter.WithBusinessID(na.BusinessID)
+	now := time.Now()
+
+	if apt.Lapsed(now) {
+		return Appointment{}, ErrHoldLapsed
+	}
 
-	page, err := page.Parse("1", "1")
+	bsn, err := c.bsnCore.QueryByID(ctx, apt.BusinessID)
 	if err != nil {
-		return Appointment{}, fmt.Errorf("couldn't parse page parameters: %w", err)
+		return Appointment{}, fmt.Errorf("business.querybyid: %s: %w", apt.BusinessID, err)
 	}
 
-	agds, err := c.storer.Query(ctx, filter, DefaultOrderBy, page)
+	old := apt
+
+	apt.HeldUntil = time.Time{}
+	apt.DateUpdated = now
+
+	if err := c.storer.Update(ctx, apt); err != nil {
+		return Appointment{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := c.storer.CreateEvent(ctx, newEvent(ctx, old, apt, "hold confirmed")); err != nil {
+		return Appointment{}, fmt.Errorf("createevent: %w", err)
+	}
+
+	if err := c.task.cancelReleaseHoldTask(apt.ID.String()); err != nil {
+		return Appointment{}, fmt.Errorf("cancelreleaseholdtask: %w", err)
+	}
+
+	if err := c.remind(apt, bsn); err != nil {
+		return Appointment{}, err
+	}
+
+	if err := c.notify(emailConfirmation, apt, bsn.TimeZone.Location(), time.Time{}, ""); err != nil {
+		return Appointment{}, err
+	}
+
+	return apt, nil
+}
+
+// ReleaseHold cancels the hold of the given id if it lapsed. Holds confirmed,
+// cancelled or deleted meanwhile are left as they are.
+func (c *Core) ReleaseHold(ctx context.Context, aptID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.releasehold")
+	defer span.End()
+
+	apt, err := c.storer.QueryByID(ctx, aptID)
 	if err != nil {
-		return Appointment{}, fmt.Errorf("query: %w", err)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("query: appointmentID[%s]: %w", aptID, err)
 	}
-	if len(agds) == 1 {
-		return Appointment{}, ErrAlreadyReserved
+
+	if !apt.Status.Holds() || !apt.Lapsed(time.Now()) {
+		return nil
 	}
-	if len(agds) > 1 {
-		return Appointment{}, fmt.Errorf("found unexpected number of entries(gt. 1)")
+
+	if _, err := c.lapse(ctx, apt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// lapse cancels the hold not confirmed in time, on behalf of no one in particular.
+func (c *Core) lapse(ctx context.Context, apt Appointment) (Appointment, error) {
+	lapsed, err := c.Transition(SetActor(ctx, uuid.Nil), apt, StatusCancelled, PartyAdmin, "hold lapsed")
+	if err != nil {
+		return Appointment{}, fmt.Errorf("lapse: appointmentID[%s]: %w", apt.ID, err)
+	}
+
+	return lapsed, nil
+}
+
+// create books the appointment with the business, as part of the series unless
+// given uuid.Nil, and as a hold lapsing at heldUntil unless it's zero. Scheduling
+// its reminder is left to the caller.
+func (c *Core) create(ctx context.Context, na NewAppointment, bsn business.Business, seriesID uuid.UUID, heldUntil time.Time) (Appointment, error) {
+	if na.ScheduledOn.UTC().Before(time.Now().UTC()) {
+		return Appointment{}, ErrPastTime
+	}
+
+	svc, err := c.queryService(ctx, bsn.ID, na.ServiceID)
+	if err != nil {
+		return Appointment{}, err
+	}
+
+	if err := c.checkResource(ctx, bsn.ID, na.ResourceID); err != nil {
+		return Appointment{}, err
 	}
 
 	now := time.Now()
 
+	if err := checkBookingWindow(na.ScheduledOn, bsn, svc, now); err != nil {
+		return Appointment{}, err
+	}
+
 	apt := Appointment{
 		ID:          uuid.New(),
+		SeriesID:    seriesID,
 		BusinessID:  bsn.ID,
-		UserID:      usr.ID,
-		Status:      na.Status,
+		UserID:      na.UserID,
+		ServiceID:   svc.ID,
+		ResourceID:  na.ResourceID,
+		Status:      StatusPending,
 		ScheduledOn: na.ScheduledOn,
+		Duration:    svc.Duration,
+		Price:       svc.Price,
+		Currency:    svc.Currency,
+		Capacity:    svc.Capacity,
+		HeldUntil:   heldUntil,
 		DateCreated: now,
 		DateUpdated: now,
 	}
 
+	booked, err := c.checkOverlap(ctx, apt)
+	if err != nil {
+		return Appointment{}, err
+	}
+	apt.Remaining = remaining(apt, booked)
+
 	if err := c.storer.Create(ctx, apt); err != nil {
 		return Appointment{}, fmt.Errorf("create: %w", err)
 	}
 
-	_, err = c.task.NewSendSMSTask(usr.ID, na.ScheduledOn, apt.ID.String())
-	if err != nil {
-		return Appointment{}, fmt.Errorf("newsendsmstask: %w", err)
+	if err := c.storer.CreateEvent(ctx, newEvent(ctx, Appointment{}, apt, "")); err != nil {
+		return Appointment{}, fmt.Errorf("createevent: %w", err)
 	}
 
 	return apt, nil
 }
 
-func (c *Core) Update(ctx context.Context, apt Appointment, uapt UpdateAppointment) (Appointment, error) {
-	// TODO: Check given scheduled time is not already booked for this business.
-	// It's already done once in Create Method above. See if you can Copy/Paste it.
-
+// Update reschedules the appointment on behalf of the party. Customers can't do so
+// within the reschedule notice of the business.
+func (c *Core) Update(ctx context.Context, apt Appointment, uapt UpdateAppointment, party Party) (Appointment, error) {
 	ctx, span := otel.AddSpan(ctx, "business.appointment.update")
 	defer span.End()
 
+	old := apt
+
 	// Query appointment to check if the scheduled time is not passed
 	// or appointment is not already cancelled
 	if apt.ScheduledOn.UTC().Before(time.Now().UTC()) {
@@ -164,37 +479,426 @@ func (c *Core) Update(ctx context.Context, apt Appointment, uapt UpdateAppointme
 		return Appointment{}, ErrAlreadyCancelled
 	}
 
-	if uapt.Status != nil {
-		apt.Status = *uapt.Status
+	if !apt.Status.Open() {
+		return Appointment{}, ErrNotOpen
+	}
+
+	bsn, err := c.bsnCore.QueryByID(ctx, apt.BusinessID)
+	if err != nil {
+		return Appointment{}, fmt.Errorf("business.querybyid: %s: %w", apt.BusinessID, err)
+	}
+
+	if party == PartyCustomer && withinNotice(apt, bsn.RescheduleNotice, time.Now()) {
+		return Appointment{}, ErrRescheduleWindow
+	}
+
+	// The booking window of the service applies to the time it's booked at, whether
+	// the service or the time changes.
+	var svc service.Service
+	switch {
+	case uapt.ServiceID != nil:
+		svc, err = c.queryService(ctx, apt.BusinessID, *uapt.ServiceID)
+	case uapt.ScheduledOn != nil && apt.ServiceID != uuid.Nil:
+		svc, err = c.queryService(ctx, apt.BusinessID, apt.ServiceID)
+	}
+	if err != nil {
+		return Appointment{}, err
+	}
+
+	if uapt.ServiceID != nil {
+		apt.ServiceID = svc.ID
+		apt.Duration = svc.Duration
+		apt.Price = svc.Price
+		apt.Currency = svc.Currency
+		apt.Capacity = svc.Capacity
+	}
+
+	if uapt.ResourceID != nil {
+		if err := c.checkResource(ctx, apt.BusinessID, *uapt.ResourceID); err != nil {
+			return Appointment{}, err
+		}
+
+		apt.ResourceID = *uapt.ResourceID
 	}
 
 	if uapt.ScheduledOn != nil {
 		apt.ScheduledOn = *uapt.ScheduledOn
+		apt.FlaggedAt = time.Time{}
+	}
 
-		_, err := c.task.updateSendSMSTask(apt.UserID, *uapt.ScheduledOn, apt.ID.String())
-		if err != nil {
-			return Appointment{}, fmt.Errorf("updatesendsmstask: %w", err)
+	if uapt.ScheduledOn != nil || uapt.ServiceID != nil {
+		if err := checkBookingWindow(apt.ScheduledOn, bsn, svc, time.Now()); err != nil {
+			return Appointment{}, err
 		}
 	}
 
+	booked, err := c.checkOverlap(ctx, apt)
+	if err != nil {
+		return Appointment{}, err
+	}
+	apt.Remaining = remaining(apt, booked)
+
 	apt.DateUpdated = time.Now()
 	if err := c.storer.Update(ctx, apt); err != nil {
 		return Appointment{}, fmt.Errorf("update: %w", err)
 	}
 
+	if err := c.storer.CreateEvent(ctx, newEvent(ctx, old, apt, uapt.Reason)); err != nil {
+		return Appointment{}, fmt.Errorf("createevent: %w", err)
+	}
+
+	// Holds are reminded of once confirmed, not before.
+	if uapt.ScheduledOn != nil && !apt.Held() {
+		if err := c.forget(old, bsn); err != nil {
+			return Appointment{}, err
+		}
+
+		if err := c.remind(apt, bsn); err != nil {
+			return Appointment{}, err
+		}
+	}
+
+	if !apt.ScheduledOn.Equal(old.ScheduledOn) {
+		if err := c.notify(emailReschedule, apt, bsn.TimeZone.Location(), old.ScheduledOn, uapt.Reason); err != nil {
+			return Appointment{}, err
+		}
+	}
+
+	return apt, nil
+}
+
+// Transition gives the appointment the status, on behalf of the party. The time of the
+// transition is recorded on the appointment, and the reason in its history. An
+// appointment cancelled or rejected releases its slot, offered to whoever waits for
+// it, and is no longer reminded of.
+// Customers can't cancel within the cancellation notice of the business.
+func (c *Core) Transition(ctx context.Context, apt Appointment, status Status, party Party, reason string) (Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.transition")
+	defer span.End()
+
+	if err := checkTransition(apt.Status, status, party); err != nil {
+		return Appointment{}, err
+	}
+
+	// A hold goes nowhere but cancelled until the customer confirms it.
+	if apt.Held() && status != StatusCancelled {
+		return Appointment{}, ErrOnHold
+	}
+
+	now := time.Now()
+
+	bsn, err := c.bsnCore.QueryByID(ctx, apt.BusinessID)
+	if err != nil {
+		return Appointment{}, fmt.Errorf("business.querybyid: %s: %w", apt.BusinessID, err)
+	}
+
+	if status == StatusCancelled && party == PartyCustomer {
+		if withinNotice(apt, bsn.CancellationNotice, now) {
+			return Appointment{}, ErrCancellationWindow
+		}
+	}
+
+	old := apt
+
+	apt.Status = status
+	apt.stamp(status, now)
+	apt.DateUpdated = now
+
+	if err := c.storer.Update(ctx, apt); err != nil {
+		return Appointment{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := c.storer.CreateEvent(ctx, newEvent(ctx, old, apt, reason)); err != nil {
+		return Appointment{}, fmt.Errorf("createevent: %w", err)
+	}
+
+	if !status.Holds() {
+		if err := c.forget(apt, bsn); err != nil {
+			return Appointment{}, err
+		}
+		if apt.Held() {
+			if err := c.task.cancelReleaseHoldTask(apt.ID.String()); err != nil {
+				return Appointment{}, fmt.Errorf("cancelreleaseholdtask: %w", err)
+			}
+		}
+		if old.Status.Holds() {
+			if err := c.task.newReleaseSlotTask(apt); err != nil {
+				return Appointment{}, fmt.Errorf("newreleaseslottask: %w", err)
+			}
+		}
+		apt.Remaining++
+	}
+
+	// Customers never told of a hold aren't told of it lapsing either.
+	if status == StatusCancelled && !apt.Held() {
+		if err := c.notify(emailCancellation, apt, bsn.TimeZone.Location(), time.Time{}, reason); err != nil {
+			return Appointment{}, err
+		}
+	}
+
 	return apt, nil
 }
 
-func (c *Core) Delete(ctx context.Context, apt Appointment) error {
+// CancelFollowing cancels the appointment, on behalf of the party, along with the open
+// appointments of its series scheduled after it. It returns every appointment it
+// cancelled, starting with the given one.
+func (c *Core) CancelFollowing(ctx context.Context, apt Appointment, party Party, reason string) ([]Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.cancelfollowing")
+	defer span.End()
+
+	capt, err := c.Transition(ctx, apt, StatusCancelled, party, reason)
+	if err != nil {
+		return nil, err
+	}
+	cancelled := []Appointment{capt}
+
+	if apt.SeriesID == uuid.Nil {
+		return cancelled, nil
+	}
+
+	apts, err := c.storer.QueryBySeriesID(ctx, apt.SeriesID)
+	if err != nil {
+		return nil, fmt.Errorf("querybyseriesid: %s: %w", apt.SeriesID, err)
+	}
+
+	for _, a := range apts {
+		if a.ID == apt.ID || a.ScheduledOn.Before(apt.ScheduledOn) || !a.Status.Open() {
+			continue
+		}
+
+		capt, err := c.Transition(ctx, a, StatusCancelled, party, reason)
+		if err != nil {
+			return nil, fmt.Errorf("occurrence %s: %w", a.ID, err)
+		}
+		cancelled = append(cancelled, capt)
+	}
+
+	return cancelled, nil
+}
+
+// Flag marks the open appointment for the customer to pick another time, such as when
+// it no longer fits the agendas of its business, and emails the customer about it.
+// The appointment keeps its slot until rescheduled or cancelled.
+func (c *Core) Flag(ctx context.Context, apt Appointment, reason string) (Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.flag")
+	defer span.End()
+
+	if !apt.Status.Open() {
+		return Appointment{}, ErrNotOpen
+	}
+
+	if apt.Held() {
+		return Appointment{}, ErrOnHold
+	}
+
+	bsn, err := c.bsnCore.QueryByID(ctx, apt.BusinessID)
+	if err != nil {
+		return Appointment{}, fmt.Errorf("business.querybyid: %s: %w", apt.BusinessID, err)
+	}
+
+	old := apt
+
+	now := time.Now()
+	apt.FlaggedAt = now
+	apt.DateUpdated = now
+
+	if err := c.storer.Update(ctx, apt); err != nil {
+		return Appointment{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := c.storer.CreateEvent(ctx, newEvent(ctx, old, apt, reason)); err != nil {
+		return Appointment{}, fmt.Errorf("createevent: %w", err)
+	}
+
+	if err := c.notify(emailFlagged, apt, bsn.TimeZone.Location(), time.Time{}, reason); err != nil {
+		return Appointment{}, err
+	}
+
+	return apt, nil
+}
+
+// queryService returns the service of the given id, making sure it's offered by
+// the business.
+func (c *Core) queryService(ctx context.Context, bsnID uuid.UUID, svcID uuid.UUID) (service.Service, error) {
+	svc, err := c.svcCore.QueryByID(ctx, svcID)
+	if err != nil {
+		return service.Service{}, fmt.Errorf("service.querybyid: %s: %w", svcID, err)
+	}
+
+	if svc.BusinessID != bsnID {
+		return service.Service{}, ErrServiceMismatch
+	}
+
+	return svc, nil
+}
+
+// checkResource makes sure the resource of the given id belongs to the business. A
+// business with resources books every appointment against one of them, while one
+// without books them against the business as a whole, given as uuid.Nil.
+func (c *Core) checkResource(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID) error {
+	if rscID == uuid.Nil {
+		var filter resource.QueryFilter
+		filter.WithBusinessID(bsnID)
+
+		n, err := c.rscCore.Count(ctx, filter)
+		if err != nil {
+			return fmt.Errorf("resource.count: %w", err)
+		}
+
+		if n > 0 {
+			return ErrResourceRequired
+		}
+
+		return nil
+	}
+
+	rsc, err := c.rscCore.QueryByID(ctx, rscID)
+	if err != nil {
+		return fmt.Errorf("resource.querybyid: %s: %w", rscID, err)
+	}
+
+	if rsc.BusinessID != bsnID {
+		return ErrResourceMismatch
+	}
+
+	return nil
+}
+
+// checkOverlap returns the number of other appointments holding the slot of apt. It
+// returns ErrAlreadyReserved if the resource apt is booked against, or the business
+// when there is none, has any other appointment whose time overlaps with apt without
+// sharing its slot, and ErrSlotFull when the slot holds no more people. An apt that
+// doesn't hold its slot conflicts with nothing. Holds occupy their slot until they
+// lapse; lapsed ones not released yet are released on the spot.
+func (c *Core) checkOverlap(ctx context.Context, apt Appointment) (int, error) {
+	apts, err := c.storer.QueryOverlapping(ctx, apt)
+	if err != nil {
+		return 0, fmt.Errorf("queryoverlapping: %w", err)
+	}
+
+	now := time.Now()
+
+	var booked int
+	for _, o := range apts {
+		if o.Lapsed(now) {
+			if _, err := c.lapse(ctx, o); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		switch {
+		case apt.SharesSlot(o):
+			booked++
+		case apt.Status.Holds():
+			return 0, ErrAlreadyReserved
+		}
+	}
+
+	if apt.Status.Holds() && booked >= apt.Capacity {
+		return 0, ErrSlotFull
+	}
+
+	return booked, nil
+}
+
+// SlotFree reports whether a customer could still book the slot of apt, no other
+// appointment taking it up and the slot holding more people. Only the business,
+// service, resource and times of apt are looked at.
+func (c *Core) SlotFree(ctx context.Context, apt Appointment) (bool, error) {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.slotfree")
+	defer span.End()
+
+	probe := Appointment{
+		BusinessID:  apt.BusinessID,
+		ServiceID:   apt.ServiceID,
+		ResourceID:  apt.ResourceID,
+		Status:      StatusPending,
+		ScheduledOn: apt.ScheduledOn,
+		Duration:    apt.Duration,
+		Capacity:    1,
+	}
+
+	// Services removed since keep no more than a person per slot.
+	if apt.ServiceID != uuid.Nil {
+		svc, err := c.svcCore.QueryByID(ctx, apt.ServiceID)
+		switch {
+		case err == nil:
+			probe.Capacity = svc.Capacity
+		case !errors.Is(err, service.ErrNotFound):
+			return false, fmt.Errorf("service.querybyid: %s: %w", apt.ServiceID, err)
+		}
+	}
+
+	if _, err := c.checkOverlap(ctx, probe); err != nil {
+		if errors.Is(err, ErrAlreadyReserved) || errors.Is(err, ErrSlotFull) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// checkBookingWindow returns ErrLeadTime if an appointment of the service at the given
+// time is booked with less lead time than allowed, and ErrBeyondHorizon if it's booked
+// further ahead than the horizon.
+func checkBookingWindow(scheduledOn time.Time, bsn business.Business, svc service.Service, now time.Time) error {
+	lead, horizon := svc.BookingWindow(bsn)
+
+	if scheduledOn.Before(now.Add(lead)) {
+		return ErrLeadTime
+	}
+
+	if horizon > 0 && scheduledOn.After(now.Add(horizon)) {
+		return ErrBeyondHorizon
+	}
+
+	return nil
+}
+
+// remaining returns the number of people the slot of apt still holds, given the
+// number of other appointments booked in it.
+func remaining(apt Appointment, booked int) int {
+	if apt.Status.Holds() {
+		booked++
+	}
+
+	return max(apt.Capacity-booked, 0)
+}
+
+// Delete removes the appointment on behalf of the party. Only owners and admins
+// delete appointments; customers cancel theirs, keeping to the cancellation notice
+// of the business. One holding its slot releases it, offered to whoever waits for it.
+func (c *Core) Delete(ctx context.Context, apt Appointment, party Party) error {
 	ctx, span := otel.AddSpan(ctx, "business.appointment.delete")
 	defer span.End()
 
+	if party == PartyCustomer {
+		return ErrDeleteForbidden
+	}
+
 	if err := c.storer.Delete(ctx, apt); err != nil {
 		return fmt.Errorf("delete: %w", err)
 	}
 
-	if err := c.task.cancelSendSMSTask(apt.ID.String()); err != nil {
-		return fmt.Errorf("cancelsendsmstask: %w", err)
+	if err := c.storer.CreateEvent(ctx, newEvent(ctx, apt, Appointment{}, "")); err != nil {
+		return fmt.Errorf("createevent: %w", err)
+	}
+
+	bsn, err := c.bsnCore.QueryByID(ctx, apt.BusinessID)
+	if err != nil {
+		return fmt.Errorf("business.querybyid: %s: %w", apt.BusinessID, err)
+	}
+
+	if err := c.forget(apt, bsn); err != nil {
+		return err
+	}
+
+	if apt.Status.Holds() {
+		if err := c.task.newReleaseSlotTask(apt); err != nil {
+			return fmt.Errorf("newreleaseslottask: %w", err)
+		}
 	}
 
 	return nil
@@ -231,6 +935,34 @@ func (c *Core) QueryByID(ctx context.Context, aptID uuid.UUID) (Appointment, err
 	return apt, nil
 }
 
+// QueryEvents returns the history of the appointment, oldest first. The history
+// outlives the appointment itself.
+func (c *Core) QueryEvents(ctx context.Context, aptID uuid.UUID) ([]Event, error) {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.queryevents")
+	defer span.End()
+
+	evts, err := c.storer.QueryEvents(ctx, aptID)
+	if err != nil {
+		return nil, fmt.Errorf("query: appointmentID[%s]: %w", aptID, err)
+	}
+
+	return evts, nil
+}
+
+// QueryBySeriesID returns the appointments of the series, in the order they're
+// scheduled.
+func (c *Core) QueryBySeriesID(ctx context.Context, srsID uuid.UUID) ([]Appointment, error) {
+	ctx, span := otel.AddSpan(ctx, "business.appointment.querybyseriesid")
+	defer span.End()
+
+	apts, err := c.storer.QueryBySeriesID(ctx, srsID)
+	if err != nil {
+		return nil, fmt.Errorf("query: seriesID[%s]: %w", srsID, err)
+	}
+
+	return apts, nil
+}
+
 func (c *Core) QueryByUserID(ctx context.Context, usrID uuid.UUID) ([]Appointment, error) {
 	ctx, span := otel.AddSpan(ctx, "business.appointment.querybyuserid")
 	defer span.End()
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ameghdadian/service/business/core/appointment"
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/business/data/dbtest"
 	"github.com/ameghdadian/service/business/data/page"
@@ -23,6 +24,7 @@ type seedData struct {
 	apts []appointment.Appointment
 	usrs []user.User
 	bsns []business.Business
+	svcs []service.Service
 }
 
 var c *docker.Container
@@ -50,10 +52,12 @@ func TestMain(m *testing.M) {
 
 func Test_Appointment(t *testing.T) {
 	t.Run("crud", crud)
+	t.Run("series", series)
+	t.Run("holds", holds)
 }
 
 func crud(t *testing.T) {
-	seed := func(ctx context.Context, aptCore *appointment.Core, usrCore *user.Core, bsnCore *business.Core) (seedData, error) {
+	seed := func(ctx context.Context, aptCore *appointment.Core, usrCore *user.Core, bsnCore *business.Core, svcCore *service.Core) (seedData, error) {
 		var filter user.QueryFilter
 		filter.WithName("Admin Gopher")
 
@@ -68,7 +72,12 @@ func crud(t *testing.T) {
 			return seedData{}, fmt.Errorf("seeding bsns: %w", err)
 		}
 
-		apts, err := appointment.TestGenerateSeedAppointments(1, aptCore, usrs[0].ID, bsns[0].ID)
+		svcs, err := service.TestGenerateSeedServices(2, svcCore, bsns[0].ID)
+		if err != nil {
+			return seedData{}, fmt.Errorf("seeding svcs: %w", err)
+		}
+
+		apts, err := appointment.TestGenerateSeedAppointments(1, aptCore, usrs[0].ID, bsns[0].ID, svcs[0].ID)
 		if err != nil {
 			return seedData{}, fmt.Errorf("seeding apts: %w", err)
 		}
@@ -77,6 +86,7 @@ func crud(t *testing.T) {
 			apts: apts,
 			usrs: usrs,
 			bsns: bsns,
+			svcs: svcs,
 		}, nil
 	}
 
@@ -98,7 +108,7 @@ func crud(t *testing.T) {
 
 	t.Log("Go seeding ...")
 
-	sd, err := seed(ctx, api.Appointment, api.User, api.Business)
+	sd, err := seed(ctx, api.Appointment, api.User, api.Business, api.Service)
 	if err != nil {
 		t.Fatalf("Seeding error: %s", err)
 	}
@@ -163,9 +173,18 @@ func crud(t *testing.T) {
 	na := appointment.NewAppointment{
 		BusinessID:  sd.bsns[0].ID,
 		UserID:      sd.usrs[0].ID,
-		Status:      appointment.StatusScheduled,
-		ScheduledOn: time.Now().Add(2 * time.Hour),
+		ServiceID:   sd.svcs[1].ID,
+		ScheduledOn: time.Now().Add(4 * time.Hour),
 	}
+
+	overlapping := na
+	overlapping.ScheduledOn = time.Now().Add(2*time.Hour + 15*time.Minute)
+	if _, err := api.Appointment.Create(ctx, overlapping); !errors.Is(err, appointment.ErrAlreadyReserved) {
+		t.Error("Should reject an appointment overlapping another one")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrAlreadyReserved)
+	}
+
 	a, err := api.Appointment.Create(ctx, na)
 	if err != nil {
 		t.Fatalf("Should be able to create a appointment: %s", err)
@@ -186,10 +205,15 @@ func crud(t *testing.T) {
 		t.Errorf("GOT: %v\n", a.UserID)
 		t.Errorf("EXP: %v\n", na.UserID)
 	}
-	if a.Status != na.Status {
-		t.Error("Should have the correct status.")
+	if a.Status != appointment.StatusPending {
+		t.Error("Should be pending.")
 		t.Errorf("GOT: %v\n", a.Status)
-		t.Errorf("EXP: %v\n", na.Status)
+		t.Errorf("EXP: %v\n", appointment.StatusPending)
+	}
+	if a.Duration != sd.svcs[1].Duration || a.Price != sd.svcs[1].Price || !a.Currency.Equal(sd.svcs[1].Currency) {
+		t.Error("Should take the duration and price from the service.")
+		t.Errorf("GOT: %s %d %s\n", a.Duration, a.Price, a.Currency.Code())
+		t.Errorf("EXP: %s %d %s\n", sd.svcs[1].Duration, sd.svcs[1].Price, sd.svcs[1].Currency.Code())
 	}
 	if a.ScheduledOn != na.ScheduledOn {
 		t.Error("Should have the correct scheduled on datetime.")
@@ -223,28 +247,167 @@ func crud(t *testing.T) {
 	// -------------------------------------------------------------------
 	// Update
 
+	moved := time.Now().Add(2 * time.Hour)
+	if _, err := api.Appointment.Update(ctx, a, appointment.UpdateAppointment{ScheduledOn: &moved}, appointment.PartyCustomer); !errors.Is(err, appointment.ErrAlreadyReserved) {
+		t.Error("Should reject moving an appointment over another one")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrAlreadyReserved)
+	}
+
+	// -------------------------------------------------------------------
+	// Transition
+
+	if _, err := api.Appointment.Transition(ctx, a, appointment.StatusConfirmed, appointment.PartyCustomer, ""); !errors.Is(err, appointment.ErrTransitionForbidden) {
+		t.Error("Should not let the customer confirm the appointment")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrTransitionForbidden)
+	}
+
+	a, err = api.Appointment.Transition(ctx, a, appointment.StatusConfirmed, appointment.PartyOwner, "")
+	if err != nil {
+		t.Fatalf("Should let the owner confirm the appointment: %s", err)
+	}
+
+	if a.Status != appointment.StatusConfirmed || a.ConfirmedAt.IsZero() {
+		t.Error("Should be confirmed, recording when.")
+		t.Errorf("GOT: %v %s\n", a.Status, a.ConfirmedAt)
+		t.Errorf("EXP: %v\n", appointment.StatusConfirmed)
+	}
+
+	if _, err := api.Appointment.Transition(ctx, a, appointment.StatusCompleted, appointment.PartyOwner, ""); !errors.Is(err, appointment.ErrInvalidTransition) {
+		t.Error("Should not complete an appointment not checked in")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrInvalidTransition)
+	}
+
 	// Restore back scheduled on time after resetting it in QueryByID test suite
 	sd.apts[0].ScheduledOn = time.Now().Add(2 * time.Hour)
-	ua := appointment.UpdateAppointment{Status: &appointment.StatusCancelled}
-	apt1, err := api.Appointment.Update(ctx, sd.apts[0], ua)
+	const reason = "Can't make it"
+	actx := appointment.SetActor(ctx, sd.apts[0].UserID)
+	apt1, err := api.Appointment.Transition(actx, sd.apts[0], appointment.StatusCancelled, appointment.PartyCustomer, reason)
+	if err != nil {
+		t.Fatalf("Should let the customer cancel the appointment: %s", err)
+	}
+
+	if apt1.Status != appointment.StatusCancelled || apt1.CancelledAt.IsZero() {
+		t.Error("Should be cancelled, recording when.")
+		t.Errorf("GOT: %v %s\n", apt1.Status, apt1.CancelledAt)
+		t.Errorf("EXP: %v\n", appointment.StatusCancelled)
+	}
+
+	if _, err := api.Appointment.Update(ctx, apt1, appointment.UpdateAppointment{ScheduledOn: &moved}, appointment.PartyCustomer); !errors.Is(err, appointment.ErrAlreadyCancelled) {
+		t.Error("Should not reschedule a cancelled appointment")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrAlreadyCancelled)
+	}
+
+	// -------------------------------------------------------------------
+	// History
+
+	evts, err := api.Appointment.QueryEvents(ctx, sd.apts[0].ID)
 	if err != nil {
-		t.Fatalf("Should be able to update appointment: %s", err)
+		t.Fatalf("Should be able to query the history of the appointment: %s", err)
+	}
+
+	if len(evts) != 2 {
+		t.Fatalf("Should have recorded the creation and cancellation: got %d events", len(evts))
+	}
+
+	exp := appointment.Event{
+		AppointmentID:  sd.apts[0].ID,
+		ActorID:        sd.apts[0].UserID,
+		OldStatus:      appointment.StatusPending,
+		NewStatus:      appointment.StatusCancelled,
+		OldScheduledOn: evts[1].OldScheduledOn,
+		NewScheduledOn: evts[1].NewScheduledOn,
+		Reason:         reason,
+	}
+
+	got := evts[1]
+	got.ID = uuid.UUID{}
+	got.DateCreated = time.Time{}
+	if diff := cmp.Diff(exp, got); diff != "" {
+		t.Errorf("Should record who cancelled the appointment and why, diff:\n%s", diff)
+	}
+
+	// -------------------------------------------------------------------
+	// Notice
+
+	notice := 24 * time.Hour
+	ub := business.UpdateBusiness{CancellationNotice: &notice, RescheduleNotice: &notice}
+	if _, err := api.Business.Update(ctx, sd.bsns[0], ub); err != nil {
+		t.Fatalf("Should be able to update the notice of the business: %s", err)
+	}
+
+	later := a.ScheduledOn.Add(2 * time.Hour)
+	if _, err := api.Appointment.Update(ctx, a, appointment.UpdateAppointment{ScheduledOn: &later}, appointment.PartyCustomer); !errors.Is(err, appointment.ErrRescheduleWindow) {
+		t.Error("Should not let the customer reschedule within the notice")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrRescheduleWindow)
+	}
+
+	a, err = api.Appointment.Update(ctx, a, appointment.UpdateAppointment{ScheduledOn: &later}, appointment.PartyOwner)
+	if err != nil {
+		t.Fatalf("Should let the owner reschedule within the notice: %s", err)
+	}
+
+	if _, err := api.Appointment.Transition(ctx, a, appointment.StatusCancelled, appointment.PartyCustomer, ""); !errors.Is(err, appointment.ErrCancellationWindow) {
+		t.Error("Should not let the customer cancel within the notice")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrCancellationWindow)
 	}
 
-	if apt1.Status != *ua.Status {
-		t.Error("Should have the new status")
-		t.Errorf("EXP: %v\n", *ua.Status)
-		t.Errorf("GOT: %v\n", apt1.Status)
+	if _, err := api.Appointment.Transition(ctx, a, appointment.StatusCancelled, appointment.PartyAdmin, ""); err != nil {
+		t.Fatalf("Should let an admin cancel within the notice: %s", err)
+	}
+
+	// -------------------------------------------------------------------
+	// Booking window
+
+	lead, horizon := 6*time.Hour, 30*24*time.Hour
+	ub = business.UpdateBusiness{LeadTime: &lead, Horizon: &horizon}
+	if _, err := api.Business.Update(ctx, sd.bsns[0], ub); err != nil {
+		t.Fatalf("Should be able to update the booking window of the business: %s", err)
+	}
+
+	na.ScheduledOn = time.Now().Add(time.Hour)
+	if _, err := api.Appointment.Create(ctx, na); !errors.Is(err, appointment.ErrLeadTime) {
+		t.Error("Should reject an appointment booked within the lead time")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrLeadTime)
+	}
+
+	na.ScheduledOn = time.Now().AddDate(0, 0, 60)
+	if _, err := api.Appointment.Create(ctx, na); !errors.Is(err, appointment.ErrBeyondHorizon) {
+		t.Error("Should reject an appointment booked beyond the horizon")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrBeyondHorizon)
 	}
 
 	// -------------------------------------------------------------------
 	// Delete
 
-	err = api.Appointment.Delete(ctx, sd.apts[0])
+	if err := api.Appointment.Delete(ctx, sd.apts[0], appointment.PartyCustomer); !errors.Is(err, appointment.ErrDeleteForbidden) {
+		t.Error("Should not let customers delete their appointment")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrDeleteForbidden)
+	}
+
+	err = api.Appointment.Delete(ctx, sd.apts[0], appointment.PartyAdmin)
 	if err != nil {
 		t.Fatalf("Should be able to delete appointment")
 	}
 
+	evts, err = api.Appointment.QueryEvents(ctx, sd.apts[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to query the history of the appointment: %s", err)
+	}
+
+	if len(evts) != 3 || evts[2].NewStatus != (appointment.Status{}) {
+		t.Error("Should keep the history of a deleted appointment, recording its deletion")
+		t.Errorf("GOT: %v\n", evts)
+	}
+
 	a, err = api.Appointment.QueryByID(ctx, sd.apts[0].ID)
 	if err != nil {
 		if !errors.Is(err, appointment.ErrNotFound) {
@@ -252,3 +415,221 @@ func crud(t *testing.T) {
 		}
 	}
 }
+
+func series(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var filter user.QueryFilter
+	filter.WithName("User Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Should be able to query users: %s", err)
+	}
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	svcs, err := service.TestGenerateSeedServices(1, api.Service, bsns[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed services: %s", err)
+	}
+
+	// -------------------------------------------------------------------
+	// Create
+
+	ns := appointment.NewSeries{
+		BusinessID: bsns[0].ID,
+		UserID:     usrs[0].ID,
+		ServiceID:  svcs[0].ID,
+		Rule:       appointment.MustParseRecurrence("FREQ=WEEKLY;INTERVAL=2;COUNT=4"),
+		StartsOn:   time.Now().Add(24 * time.Hour).Truncate(time.Minute),
+	}
+
+	srs, apts, err := api.Appointment.CreateSeries(ctx, ns)
+	if err != nil {
+		t.Fatalf("Should be able to create a series: %s", err)
+	}
+
+	if len(apts) != 4 {
+		t.Fatalf("Should book an appointment for each occurrence: got %d", len(apts))
+	}
+
+	for i, apt := range apts {
+		if apt.SeriesID != srs.ID {
+			t.Errorf("Should book occurrence %d as part of the series", i)
+		}
+
+		if exp := ns.StartsOn.AddDate(0, 0, 14*i); !apt.ScheduledOn.Equal(exp) {
+			t.Error("Should book every other week at the same time")
+			t.Errorf("GOT: %s\n", apt.ScheduledOn)
+			t.Errorf("EXP: %s\n", exp)
+		}
+	}
+
+	ns.Rule = appointment.MustParseRecurrence("FREQ=DAILY;UNTIL=20991231")
+	if _, _, err := api.Appointment.CreateSeries(ctx, ns); !errors.Is(err, appointment.ErrTooManyOccurrences) {
+		t.Error("Should reject a series with too many occurrences")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrTooManyOccurrences)
+	}
+
+	// -------------------------------------------------------------------
+	// Cancel following
+
+	cancelled, err := api.Appointment.CancelFollowing(ctx, apts[1], appointment.PartyCustomer, "Moving away")
+	if err != nil {
+		t.Fatalf("Should be able to cancel an occurrence and the following ones: %s", err)
+	}
+
+	if len(cancelled) != 3 || cancelled[0].ID != apts[1].ID {
+		t.Errorf("Should cancel the occurrence and the 2 following ones: got %d", len(cancelled))
+	}
+
+	saved, err := api.Appointment.QueryBySeriesID(ctx, srs.ID)
+	if err != nil {
+		t.Fatalf("Should be able to query the series: %s", err)
+	}
+
+	exp := []appointment.Status{
+		appointment.StatusPending,
+		appointment.StatusCancelled,
+		appointment.StatusCancelled,
+		appointment.StatusCancelled,
+	}
+
+	got := make([]appointment.Status, len(saved))
+	for i, apt := range saved {
+		got[i] = apt.Status
+	}
+
+	if diff := cmp.Diff(exp, got, cmp.Comparer(func(a, b appointment.Status) bool { return a == b })); diff != "" {
+		t.Errorf("Should leave the occurrences before the cancelled one, diff:\n%s", diff)
+	}
+}
+
+func holds(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var filter user.QueryFilter
+	filter.WithName("User Gopher")
+
+	usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Should be able to query users: %s", err)
+	}
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, usrs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	svcs, err := service.TestGenerateSeedServices(1, api.Service, bsns[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed services: %s", err)
+	}
+
+	na := appointment.NewAppointment{
+		BusinessID:  bsns[0].ID,
+		UserID:      usrs[0].ID,
+		ServiceID:   svcs[0].ID,
+		ScheduledOn: time.Now().Add(24 * time.Hour).Truncate(time.Minute),
+	}
+
+	// -------------------------------------------------------------------
+	// Hold
+
+	if _, err := api.Appointment.Hold(ctx, na, appointment.MaxHold+time.Minute); !errors.Is(err, appointment.ErrInvalidHold) {
+		t.Error("Should reject a hold longer than allowed")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrInvalidHold)
+	}
+
+	held, err := api.Appointment.Hold(ctx, na, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("Should be able to hold a slot: %s", err)
+	}
+
+	if !held.Held() || held.Status != appointment.StatusPending {
+		t.Errorf("Should hold the slot as a pending appointment: held until %s, status %s", held.HeldUntil, held.Status.Status())
+	}
+
+	if _, err := api.Appointment.Create(ctx, na); !errors.Is(err, appointment.ErrAlreadyReserved) {
+		t.Error("Should treat a hold as occupying its slot")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrAlreadyReserved)
+	}
+
+	if _, err := api.Appointment.Transition(ctx, held, appointment.StatusConfirmed, appointment.PartyOwner, ""); !errors.Is(err, appointment.ErrOnHold) {
+		t.Error("Should not confirm a hold the customer didn't check out")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrOnHold)
+	}
+
+	// -------------------------------------------------------------------
+	// Confirm
+
+	confirmed, err := api.Appointment.ConfirmHold(ctx, held)
+	if err != nil {
+		t.Fatalf("Should be able to confirm the hold: %s", err)
+	}
+
+	saved, err := api.Appointment.QueryByID(ctx, confirmed.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve appointment by ID: %s", err)
+	}
+
+	if saved.Held() {
+		t.Errorf("Should no longer hold the confirmed appointment: held until %s", saved.HeldUntil)
+	}
+
+	if _, err := api.Appointment.ConfirmHold(ctx, saved); !errors.Is(err, appointment.ErrNotHeld) {
+		t.Error("Should not confirm a hold twice")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", appointment.ErrNotHeld)
+	}
+
+	// -------------------------------------------------------------------
+	// Lapse
+
+	if err := api.Appointment.ReleaseHold(ctx, saved.ID); err != nil {
+		t.Fatalf("Should leave a confirmed hold as it is: %s", err)
+	}
+
+	saved, err = api.Appointment.QueryByID(ctx, saved.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve appointment by ID: %s", err)
+	}
+
+	if saved.Status != appointment.StatusPending {
+		t.Error("Should not release a confirmed hold")
+		t.Errorf("GOT: %s\n", saved.Status.Status())
+		t.Errorf("EXP: %s\n", appointment.StatusPending.Status())
+	}
+}
@@ -0,0 +1,157 @@
+package appointment
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Each email has a plain text and an HTML template named after it, and text
+// messages an SMS one. Templates in other languages than English add the base
+// language to the name, such as reminder.de.txt, falling back to English.
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+	smsTemplates  = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.sms"))
+)
+
+// templateName returns the name of the template in the language, if there's one,
+// or else in English.
+func templateName(lookup func(string) bool, name string, lang language.Tag, ext string) string {
+	base, _ := lang.Base()
+	if localized := name + "." + base.String() + ext; base.String() != "en" && lookup(localized) {
+		return localized
+	}
+
+	return name + ext
+}
+
+// emailKind is an email sent to customers about their appointments, each sent by a
+// task of its own type.
+type emailKind struct {
+	taskType string
+	name     string
+	subject  string
+}
+
+var (
+	emailConfirmation = emailKind{TypeSendConfirmationEmail, "confirmation", "Your appointment is booked"}
+	emailReschedule   = emailKind{TypeSendRescheduleEmail, "reschedule", "Your appointment is moved"}
+	emailCancellation = emailKind{TypeSendCancellationEmail, "cancellation", "Your appointment is cancelled"}
+	emailReminder     = emailKind{TypeSendReminderEmail, "reminder", "Your appointment is coming up"}
+	emailFlagged      = emailKind{TypeSendFlaggedEmail, "flagged", "Your appointment needs a new time"}
+)
+
+var emailKinds = map[string]emailKind{
+	emailConfirmation.taskType: emailConfirmation,
+	emailReschedule.taskType:   emailReschedule,
+	emailCancellation.taskType: emailCancellation,
+	emailReminder.taskType:     emailReminder,
+	emailFlagged.taskType:      emailFlagged,
+}
+
+// emailPayload carries what an email tells about the appointment. Times are in the
+// time zone of the business. OldScheduledOn is only set for reschedule notices.
+type emailPayload struct {
+	UserID         uuid.UUID
+	AppointmentID  uuid.UUID
+	ScheduledOn    time.Time
+	OldScheduledOn time.Time
+	TimeZone       string
+	Reason         string
+}
+
+func newEmailPayload(apt Appointment, loc *time.Location, oldScheduledOn time.Time, reason string) emailPayload {
+	p := emailPayload{
+		UserID:        apt.UserID,
+		AppointmentID: apt.ID,
+		ScheduledOn:   apt.ScheduledOn.In(loc),
+		TimeZone:      loc.String(),
+		Reason:        reason,
+	}
+
+	if !oldScheduledOn.IsZero() {
+		p.OldScheduledOn = oldScheduledOn.In(loc)
+	}
+
+	return p
+}
+
+type emailData struct {
+	Name           string
+	ScheduledOn    string
+	OldScheduledOn string
+	Reason         string
+	Unsubscribe    string
+}
+
+// renderEmail renders the plain text and HTML bodies of the email to the customer
+// of the given name, in their language, linking to where they unsubscribe if set.
+func renderEmail(kind emailKind, lang language.Tag, name string, unsubscribe string, p emailPayload) (string, string, error) {
+	data := emailData{
+		Name:        name,
+		ScheduledOn: formatWhen(inZone(p.ScheduledOn, p.TimeZone)),
+		Reason:      p.Reason,
+		Unsubscribe: unsubscribe,
+	}
+
+	if !p.OldScheduledOn.IsZero() {
+		data.OldScheduledOn = formatWhen(inZone(p.OldScheduledOn, p.TimeZone))
+	}
+
+	textName := templateName(func(n string) bool { return textTemplates.Lookup(n) != nil }, kind.name, lang, ".txt")
+	htmlName := templateName(func(n string) bool { return htmlTemplates.Lookup(n) != nil }, kind.name, lang, ".html")
+
+	var text bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, textName, data); err != nil {
+		return "", "", fmt.Errorf("rendering %s text: %w", kind.name, err)
+	}
+
+	var html bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&html, htmlName, data); err != nil {
+		return "", "", fmt.Errorf("rendering %s html: %w", kind.name, err)
+	}
+
+	return text.String(), html.String(), nil
+}
+
+func formatWhen(t time.Time) string {
+	return t.Format("Mon, Jan 2 2006 at 15:04 MST")
+}
+
+type smsData struct {
+	Name string
+	Day  string
+	Time string
+	Zone string
+}
+
+// reminderBody renders the reminder of an appointment, at the wall clock time of
+// its business, in the language of the customer.
+func reminderBody(lang language.Tag, name string, scheduledOn time.Time) (string, error) {
+	data := smsData{
+		Name: name,
+		Day:  scheduledOn.Format("Mon, Jan 2"),
+		Time: scheduledOn.Format("15:04"),
+		Zone: scheduledOn.Format("MST"),
+	}
+
+	tmpl := templateName(func(n string) bool { return smsTemplates.Lookup(n) != nil }, "reminder", lang, ".sms")
+
+	var body bytes.Buffer
+	if err := smsTemplates.ExecuteTemplate(&body, tmpl, data); err != nil {
+		return "", fmt.Errorf("rendering reminder sms: %w", err)
+	}
+
+	return strings.TrimSpace(body.String()), nil
+}
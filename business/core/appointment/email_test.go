@@ -0,0 +1,99 @@
+package appointment
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/text/language"
+)
+
+func Test_RenderEmail(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Should be able to load location: %s", err)
+	}
+
+	apt := Appointment{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		ScheduledOn: time.Date(2024, time.July, 15, 7, 30, 0, 0, time.UTC),
+	}
+	old := time.Date(2024, time.July, 14, 8, 0, 0, 0, time.UTC)
+
+	const unsubscribe = "https://example.com/v1/unsubscribe?token=abc"
+
+	for _, kind := range emailKinds {
+		for _, lang := range []language.Tag{language.English, language.German} {
+			var oldScheduledOn time.Time
+			if kind == emailReschedule {
+				oldScheduledOn = old
+			}
+			p := newEmailPayload(apt, loc, oldScheduledOn, "Closed for maintenance")
+
+			text, html, err := renderEmail(kind, lang, "Ann", unsubscribe, p)
+			if err != nil {
+				t.Fatalf("%s/%s: Should be able to render the email: %s", kind.name, lang, err)
+			}
+
+			// Times are told in the time zone of the business.
+			for _, exp := range []string{"Hi Ann", "Mon, Jul 15 2024 at 09:30 CEST", unsubscribe} {
+				if !strings.Contains(text, exp) {
+					t.Errorf("%s/%s: Should have %q in the text: got %q", kind.name, lang, exp, text)
+				}
+				if !strings.Contains(html, exp) {
+					t.Errorf("%s/%s: Should have %q in the html: got %q", kind.name, lang, exp, html)
+				}
+			}
+
+			switch kind {
+			case emailReschedule:
+				if !strings.Contains(text, "Sun, Jul 14 2024 at 10:00 CEST") {
+					t.Errorf("%s/%s: Should tell the time the appointment was moved from: got %q", kind.name, lang, text)
+				}
+			case emailCancellation, emailFlagged:
+				if !strings.Contains(text, "Closed for maintenance") {
+					t.Errorf("%s/%s: Should tell the reason: got %q", kind.name, lang, text)
+				}
+			}
+		}
+	}
+
+	text, html, err := renderEmail(emailConfirmation, language.English, "<Ann>", "", newEmailPayload(apt, loc, time.Time{}, ""))
+	if err != nil {
+		t.Fatalf("Should be able to render the email: %s", err)
+	}
+
+	if strings.Contains(text, "unsubscribe") || strings.Contains(html, "unsubscribe") {
+		t.Errorf("Should not link to unsubscribe without a link")
+	}
+
+	if !strings.Contains(html, "&lt;Ann&gt;") {
+		t.Errorf("Should escape the name in the html: got %q", html)
+	}
+}
+
+func Test_TemplateName(t *testing.T) {
+	localized := map[string]bool{
+		"reminder.txt":    true,
+		"reminder.de.txt": true,
+	}
+	lookup := func(n string) bool { return localized[n] }
+
+	tests := []struct {
+		lang language.Tag
+		exp  string
+	}{
+		{language.English, "reminder.txt"},
+		{language.German, "reminder.de.txt"},
+		{language.MustParse("de-AT"), "reminder.de.txt"},
+		{language.French, "reminder.txt"},
+	}
+
+	for _, tt := range tests {
+		if got := templateName(lookup, "reminder", tt.lang, ".txt"); got != tt.exp {
+			t.Errorf("%s: Should get the template name: got %s, exp %s", tt.lang, got, tt.exp)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package appointment
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is an entry in the history of an appointment, recorded on every change made
+// to it. ActorID is who made the change, uuid.Nil when unknown. Old values are zero
+// for the creation of the appointment and new values are zero for its deletion.
+type Event struct {
+	ID             uuid.UUID
+	AppointmentID  uuid.UUID
+	ActorID        uuid.UUID
+	OldStatus      Status
+	NewStatus      Status
+	OldScheduledOn time.Time
+	NewScheduledOn time.Time
+	Reason         string
+	DateCreated    time.Time
+}
+
+// newEvent returns the event of an appointment changing from before to after, either
+// of which is zero when the appointment is created or deleted.
+func newEvent(ctx context.Context, before Appointment, after Appointment, reason string) Event {
+	aptID := after.ID
+	if aptID == uuid.Nil {
+		aptID = before.ID
+	}
+
+	return Event{
+		ID:             uuid.New(),
+		AppointmentID:  aptID,
+		ActorID:        getActor(ctx),
+		OldStatus:      before.Status,
+		NewStatus:      after.Status,
+		OldScheduledOn: before.ScheduledOn,
+		NewScheduledOn: after.ScheduledOn,
+		Reason:         reason,
+		DateCreated:    time.Now(),
+	}
+}
+
+// ========================================================
+
+type ctxKey int
+
+const actorKey ctxKey = 1
+
+// SetActor returns a context telling who makes the changes to appointments, to be
+// recorded in their history.
+func SetActor(ctx context.Context, actorID uuid.UUID) context.Context {
+	return context.WithValue(ctx, actorKey, actorID)
+}
+
+func getActor(ctx context.Context) uuid.UUID {
+	v, ok := ctx.Value(actorKey).(uuid.UUID)
+	if !ok {
+		return uuid.Nil
+	}
+
+	return v
+}
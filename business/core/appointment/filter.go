@@ -11,9 +11,12 @@ import (
 type QueryFilter struct {
 	ID               *uuid.UUID `validate:"omitempty"`
 	BusinessID       *uuid.UUID `validate:"omitempty"`
+	ResourceID       *uuid.UUID `validate:"omitempty"`
 	UserID           *uuid.UUID `validate:"omitempty"`
 	Status           *Status    `validate:"omitempty"`
 	ScheduledOn      *time.Time `validate:"omitempty"`
+	StartScheduledOn *time.Time `validate:"omitempty"`
+	EndScheduledOn   *time.Time `validate:"omitempty"`
 	StartCreatedDate *time.Time `validate:"omitempty"`
 	EndCreatedDate   *time.Time `validate:"omitempty"`
 }
@@ -34,6 +37,10 @@ func (qf *QueryFilter) WithBusinessID(bsnID uuid.UUID) {
 	qf.BusinessID = &bsnID
 }
 
+func (qf *QueryFilter) WithResourceID(rscID uuid.UUID) {
+	qf.ResourceID = &rscID
+}
+
 func (qf *QueryFilter) WithUserID(usrID uuid.UUID) {
 	qf.UserID = &usrID
 }
@@ -47,6 +54,16 @@ func (qf *QueryFilter) WithScheduledOn(on time.Time) {
 	qf.ScheduledOn = &d
 }
 
+func (qf *QueryFilter) WithStartScheduledOn(startDate time.Time) {
+	d := startDate.UTC()
+	qf.StartScheduledOn = &d
+}
+
+func (qf *QueryFilter) WithEndScheduledOn(endDate time.Time) {
+	d := endDate.UTC()
+	qf.EndScheduledOn = &d
+}
+
 func (qf *QueryFilter) WithStartCreatedDate(startDate time.Time) {
 	d := startDate.UTC()
 	qf.StartCreatedDate = &d
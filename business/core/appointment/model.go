@@ -3,27 +3,105 @@ package appointment
 import (
 	"time"
 
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/google/uuid"
 )
 
+// Appointment is a booking of a service. Price, currency and capacity are copied
+// from the service at the time of booking. ServiceID is uuid.Nil for appointments
+// booked before the catalog existed. ResourceID is uuid.Nil for appointments booked
+// against the business as a whole. Remaining is the number of people the slot of the
+// appointment still holds. Each transition of its status is stamped with the time it
+// took place, left zero until then. SeriesID is uuid.Nil for appointments booked on
+// their own. HeldUntil is when a hold, a pending appointment taken while the customer
+// checks out, lapses unless confirmed; it's zero for any other appointment. FlaggedAt
+// is when the appointment was flagged for the customer to pick another time, and is
+// zero unless it's still to be rescheduled.
 type Appointment struct {
 	ID          uuid.UUID
+	SeriesID    uuid.UUID
 	BusinessID  uuid.UUID
 	UserID      uuid.UUID
+	ServiceID   uuid.UUID
+	ResourceID  uuid.UUID
 	Status      Status
 	ScheduledOn time.Time
+	Duration    time.Duration
+	Price       int64
+	Currency    service.Currency
+	Capacity    int
+	Remaining   int
+	ConfirmedAt time.Time
+	CheckedInAt time.Time
+	CompletedAt time.Time
+	NoShowAt    time.Time
+	CancelledAt time.Time
+	RejectedAt  time.Time
+	HeldUntil   time.Time
+	FlaggedAt   time.Time
 	DateCreated time.Time
 	DateUpdated time.Time
 }
 
+// EndsOn returns the time the appointment is over.
+func (a Appointment) EndsOn() time.Time {
+	return a.ScheduledOn.Add(a.Duration)
+}
+
+// Held reports whether the appointment is a hold not confirmed yet.
+func (a Appointment) Held() bool {
+	return !a.HeldUntil.IsZero()
+}
+
+// Lapsed reports whether the appointment is a hold not confirmed in time, which no
+// longer occupies its slot.
+func (a Appointment) Lapsed(now time.Time) bool {
+	return a.Held() && !now.Before(a.HeldUntil)
+}
+
+// SharesSlot reports whether both appointments are bookings of the same slot of a
+// service holding more than one person, which then don't conflict with each other.
+func (a Appointment) SharesSlot(b Appointment) bool {
+	return a.ServiceID != uuid.Nil &&
+		a.ServiceID == b.ServiceID &&
+		a.Capacity > 1 &&
+		b.Capacity > 1 &&
+		a.ScheduledOn.Equal(b.ScheduledOn)
+}
+
 type NewAppointment struct {
 	BusinessID  uuid.UUID
 	UserID      uuid.UUID
-	Status      Status
+	ServiceID   uuid.UUID
+	ResourceID  uuid.UUID
 	ScheduledOn time.Time
 }
 
+// UpdateAppointment reschedules an appointment. Its status changes through
+// transitions only. Reason is recorded in the history of the appointment.
 type UpdateAppointment struct {
-	Status      *Status
 	ScheduledOn *time.Time
+	ServiceID   *uuid.UUID
+	ResourceID  *uuid.UUID
+	Reason      string
+}
+
+// Series is a recurring booking, such as every Tuesday at 10:00 for 8 weeks. It's
+// expanded into an appointment for each occurrence of its rule from StartsOn.
+type Series struct {
+	ID          uuid.UUID
+	BusinessID  uuid.UUID
+	UserID      uuid.UUID
+	Rule        Recurrence
+	StartsOn    time.Time
+	DateCreated time.Time
+}
+
+type NewSeries struct {
+	BusinessID uuid.UUID
+	UserID     uuid.UUID
+	ServiceID  uuid.UUID
+	ResourceID uuid.UUID
+	Rule       Recurrence
+	StartsOn   time.Time
 }
@@ -0,0 +1,203 @@
+package appointment
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxOccurrences is the most appointments a single series expands into.
+const MaxOccurrences = 52
+
+const (
+	untilDate     = "20060102"
+	untilDateTime = "20060102T150405Z"
+)
+
+// Frequency is the unit a series of appointments repeats by.
+type Frequency struct {
+	name string
+	days int
+}
+
+var (
+	FrequencyDaily  = Frequency{"DAILY", 1}
+	FrequencyWeekly = Frequency{"WEEKLY", 7}
+)
+
+var frequencies = map[string]Frequency{
+	FrequencyDaily.name:  FrequencyDaily,
+	FrequencyWeekly.name: FrequencyWeekly,
+}
+
+func (f Frequency) Name() string {
+	return f.name
+}
+
+// Recurrence is how a series of appointments repeats: a subset of the RRULE of
+// RFC 5545 made of FREQ, being DAILY or WEEKLY, an optional INTERVAL, and either
+// COUNT or UNTIL. An UNTIL date without a time includes that whole day.
+//
+//	FREQ=WEEKLY;COUNT=8
+//	FREQ=WEEKLY;INTERVAL=2;UNTIL=20250630
+type Recurrence struct {
+	freq     Frequency
+	interval int
+	count    int
+	until    time.Time
+	dateOnly bool
+}
+
+func ParseRecurrence(value string) (Recurrence, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return Recurrence{}, errors.New("recurrence rule must not be empty")
+	}
+
+	r := Recurrence{interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return Recurrence{}, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+
+		name = strings.ToUpper(name)
+		if seen[name] {
+			return Recurrence{}, fmt.Errorf("recurrence rule part %s given more than once", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			freq, exists := frequencies[strings.ToUpper(val)]
+			if !exists {
+				return Recurrence{}, fmt.Errorf("unsupported frequency %q", val)
+			}
+			r.freq = freq
+
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 {
+				return Recurrence{}, fmt.Errorf("interval must be a positive number: %q", val)
+			}
+			r.interval = n
+
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 || n > MaxOccurrences {
+				return Recurrence{}, fmt.Errorf("count must be between 1 and %d: %q", MaxOccurrences, val)
+			}
+			r.count = n
+
+		case "UNTIL":
+			if t, err := time.Parse(untilDateTime, val); err == nil {
+				r.until = t
+				break
+			}
+
+			t, err := time.Parse(untilDate, val)
+			if err != nil {
+				return Recurrence{}, fmt.Errorf("until must be a date or a UTC date-time: %q", val)
+			}
+			r.until = t
+			r.dateOnly = true
+
+		default:
+			return Recurrence{}, fmt.Errorf("unsupported recurrence rule part %s", name)
+		}
+	}
+
+	if r.freq == (Frequency{}) {
+		return Recurrence{}, errors.New("recurrence rule must have a frequency")
+	}
+
+	if (r.count == 0) == r.until.IsZero() {
+		return Recurrence{}, errors.New("recurrence rule must have either a count or an until")
+	}
+
+	return r, nil
+}
+
+func MustParseRecurrence(value string) Recurrence {
+	r, err := ParseRecurrence(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Occurrences returns the times of the series starting at start. Occurrences keep
+// the wall clock time of start in its location, so a series should start in the
+// time zone of its business. It returns ErrTooManyOccurrences if the series expands
+// beyond MaxOccurrences.
+func (r Recurrence) Occurrences(start time.Time) ([]time.Time, error) {
+	within := func(t time.Time) bool {
+		return !t.After(r.until)
+	}
+
+	if r.dateOnly {
+		end := time.Date(r.until.Year(), r.until.Month(), r.until.Day()+1, 0, 0, 0, 0, start.Location())
+		within = func(t time.Time) bool {
+			return t.Before(end)
+		}
+	}
+
+	var occs []time.Time
+	for i := 0; ; i++ {
+		occ := start.AddDate(0, 0, i*r.interval*r.freq.days)
+
+		switch {
+		case r.count > 0 && i == r.count:
+			return occs, nil
+		case r.count == 0 && !within(occ):
+			return occs, nil
+		case i == MaxOccurrences:
+			return nil, ErrTooManyOccurrences
+		}
+
+		occs = append(occs, occ)
+	}
+}
+
+// String returns the rule in its canonical RRULE form.
+func (r Recurrence) String() string {
+	parts := []string{"FREQ=" + r.freq.name}
+
+	if r.interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.interval))
+	}
+
+	switch {
+	case r.count > 0:
+		parts = append(parts, "COUNT="+strconv.Itoa(r.count))
+	case r.dateOnly:
+		parts = append(parts, "UNTIL="+r.until.Format(untilDate))
+	case !r.until.IsZero():
+		parts = append(parts, "UNTIL="+r.until.UTC().Format(untilDateTime))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+func (r *Recurrence) UnmarshalText(data []byte) error {
+	rec, err := ParseRecurrence(string(data))
+	if err != nil {
+		return err
+	}
+
+	*r = rec
+	return nil
+}
+
+func (r Recurrence) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r Recurrence) Equal(r2 Recurrence) bool {
+	return r.String() == r2.String()
+}
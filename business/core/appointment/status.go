@@ -3,13 +3,23 @@ package appointment
 import "fmt"
 
 var (
-	StatusScheduled = Status{"Scheduled"}
+	StatusPending   = Status{"Pending"}
+	StatusConfirmed = Status{"Confirmed"}
+	StatusCheckedIn = Status{"CheckedIn"}
+	StatusCompleted = Status{"Completed"}
+	StatusNoShow    = Status{"NoShow"}
 	StatusCancelled = Status{"Cancelled"}
+	StatusRejected  = Status{"Rejected"}
 )
 
 var statuses = map[string]Status{
+	StatusPending.status:   StatusPending,
+	StatusConfirmed.status: StatusConfirmed,
+	StatusCheckedIn.status: StatusCheckedIn,
+	StatusCompleted.status: StatusCompleted,
+	StatusNoShow.status:    StatusNoShow,
 	StatusCancelled.status: StatusCancelled,
-	StatusScheduled.status: StatusScheduled,
+	StatusRejected.status:  StatusRejected,
 }
 
 type Status struct {
@@ -29,6 +39,18 @@ func (as Status) Status() string {
 	return as.status
 }
 
+// Holds reports whether an appointment of the status holds its slot, keeping it
+// from being booked by anybody else.
+func (as Status) Holds() bool {
+	return as != StatusCancelled && as != StatusRejected
+}
+
+// Open reports whether an appointment of the status is still to take place, so it
+// can be rescheduled.
+func (as Status) Open() bool {
+	return as == StatusPending || as == StatusConfirmed
+}
+
 func (as *Status) UnmarshalText(data []byte) error {
 	status, err := ParseStatus(string(data))
 	if err != nil {
@@ -16,6 +16,24 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// remaining is the number of people the slot of an appointment still holds, given
+// the appointments holding it, of the same service starting at the same time.
+// Cancelled and rejected appointments don't hold their slot.
+const remaining = `
+		capacity - (
+			SELECT
+				COUNT(1)
+			FROM
+				appointments AS booked
+			WHERE
+				booked.business_id = appointments.business_id AND
+				booked.resource_id IS NOT DISTINCT FROM appointments.resource_id AND
+				booked.service_id = appointments.service_id AND
+				booked.scheduled_on = appointments.scheduled_on AND
+				booked.status NOT IN (0, 6)
+		) AS remaining
+`
+
 type Store struct {
 	log *logger.Logger
 	db  sqlx.ExtContext
@@ -45,12 +63,17 @@ func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (appointment
 func (s *Store) Create(ctx context.Context, apt appointment.Appointment) error {
 	const q = `
 	INSERT INTO appointments
-		(appointment_id, business_id, user_id, status, scheduled_on, date_created, date_updated)
+		(appointment_id, series_id, business_id, user_id, service_id, resource_id, status, scheduled_on, ends_on, price, currency, capacity,
+		confirmed_at, checked_in_at, completed_at, no_show_at, cancelled_at, rejected_at, held_until, flagged_at, date_created, date_updated)
 	VALUES
-		(:appointment_id, :business_id, :user_id, :status, :scheduled_on, :date_created, :date_updated)
+		(:appointment_id, :series_id, :business_id, :user_id, :service_id, :resource_id, :status, :scheduled_on, :ends_on, :price, :currency, :capacity,
+		:confirmed_at, :checked_in_at, :completed_at, :no_show_at, :cancelled_at, :rejected_at, :held_until, :flagged_at, :date_created, :date_updated)
 	`
 
 	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBAppointment(apt)); err != nil {
+		if errors.Is(err, db.ErrDBExclusionViolation) {
+			return fmt.Errorf("namedexeccontext: %w", appointment.ErrAlreadyReserved)
+		}
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -64,11 +87,28 @@ func (s *Store) Update(ctx context.Context, apt appointment.Appointment) error {
 	SET
 		"status" = :status,
 		"scheduled_on" = :scheduled_on,
+		"ends_on" = :ends_on,
+		"service_id" = :service_id,
+		"resource_id" = :resource_id,
+		"price" = :price,
+		"currency" = :currency,
+		"capacity" = :capacity,
+		"confirmed_at" = :confirmed_at,
+		"checked_in_at" = :checked_in_at,
+		"completed_at" = :completed_at,
+		"no_show_at" = :no_show_at,
+		"cancelled_at" = :cancelled_at,
+		"rejected_at" = :rejected_at,
+		"held_until" = :held_until,
+		"flagged_at" = :flagged_at,
 		"date_updated" = :date_updated
 	WHERE
 		appointment_id = :appointment_id
 	`
 	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBAppointment(apt)); err != nil {
+		if errors.Is(err, db.ErrDBExclusionViolation) {
+			return fmt.Errorf("namedexeccontext: %w", appointment.ErrAlreadyReserved)
+		}
 		return fmt.Errorf("namedexeccontext: %w", err)
 	}
 
@@ -104,7 +144,8 @@ func (s *Store) Query(ctx context.Context, filter appointment.QueryFilter, order
 
 	const q = `
 	SELECT	
-		appointment_id, business_id, user_id, status, scheduled_on, date_created, date_updated
+		appointment_id, series_id, business_id, user_id, service_id, resource_id, status, scheduled_on, ends_on, price, currency, capacity,
+		confirmed_at, checked_in_at, completed_at, no_show_at, cancelled_at, rejected_at, held_until, flagged_at, date_created, date_updated,` + remaining + `
 	FROM
 		appointments
 	`
@@ -125,7 +166,10 @@ func (s *Store) Query(ctx context.Context, filter appointment.QueryFilter, order
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
-	apts := toCoreAppointmentSlice(dbApts)
+	apts, err := toCoreAppointmentSlice(dbApts)
+	if err != nil {
+		return nil, err
+	}
 
 	return apts, nil
 }
@@ -162,7 +206,8 @@ func (s *Store) QueryByID(ctx context.Context, aptID uuid.UUID) (appointment.App
 	}
 	const q = `
 	SELECT	
-		appointment_id, business_id, user_id, status, scheduled_on, date_created, date_updated
+		appointment_id, series_id, business_id, user_id, service_id, resource_id, status, scheduled_on, ends_on, price, currency, capacity,
+		confirmed_at, checked_in_at, completed_at, no_show_at, cancelled_at, rejected_at, held_until, flagged_at, date_created, date_updated,` + remaining + `
 	FROM
 		appointments
 	WHERE
@@ -177,7 +222,71 @@ func (s *Store) QueryByID(ctx context.Context, aptID uuid.UUID) (appointment.App
 		return appointment.Appointment{}, fmt.Errorf("namedquerystruct: %w", err)
 	}
 
-	return toCoreAppointment(dbApt), nil
+	apt, err := toCoreAppointment(dbApt)
+	if err != nil {
+		return appointment.Appointment{}, err
+	}
+
+	return apt, nil
+}
+
+// QueryOverlapping retrieves the appointments overlapping with apt, on its resource
+// or its business when there is none. Under a transaction, it first takes a lock on
+// the resource held until the transaction ends, for concurrent bookings to count
+// the slot one after the other rather than both seeing room left in it.
+func (s *Store) QueryOverlapping(ctx context.Context, apt appointment.Appointment) ([]appointment.Appointment, error) {
+	dbApt := toDBAppointment(apt)
+
+	lock := map[string]any{
+		"business_id": dbApt.BusinessID,
+		"resource_id": dbApt.ResourceID,
+	}
+
+	const ql = `
+	SELECT
+		pg_advisory_xact_lock(hashtextextended(CAST(:business_id AS TEXT) || '/' || COALESCE(CAST(:resource_id AS TEXT), ''), 0))
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, ql, lock); err != nil {
+		return nil, fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	data := map[string]any{
+		"appointment_id": dbApt.ID,
+		"business_id":    dbApt.BusinessID,
+		"resource_id":    dbApt.ResourceID,
+		"scheduled_on":   dbApt.ScheduledOn,
+		"ends_on":        dbApt.EndsOn,
+		"cancelled":      toDBStatus(appointment.StatusCancelled),
+		"rejected":       toDBStatus(appointment.StatusRejected),
+	}
+
+	const q = `
+	SELECT
+		appointment_id, series_id, business_id, user_id, service_id, resource_id, status, scheduled_on, ends_on, price, currency, capacity,
+		confirmed_at, checked_in_at, completed_at, no_show_at, cancelled_at, rejected_at, held_until, flagged_at, date_created, date_updated,` + remaining + `
+	FROM
+		appointments
+	WHERE
+		business_id = :business_id AND
+		resource_id IS NOT DISTINCT FROM :resource_id AND
+		appointment_id <> :appointment_id AND
+		status NOT IN (:cancelled, :rejected) AND
+		scheduled_on < :ends_on AND
+		ends_on > :scheduled_on
+	`
+
+	var dbApts []dbAppointment
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbApts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	apts, err := toCoreAppointmentSlice(dbApts)
+	if err != nil {
+		return nil, err
+	}
+
+	return apts, nil
 }
 
 func (s *Store) QueryByUserID(ctx context.Context, usrID uuid.UUID) ([]appointment.Appointment, error) {
@@ -189,7 +298,8 @@ func (s *Store) QueryByUserID(ctx context.Context, usrID uuid.UUID) ([]appointme
 
 	const q = `
 	SELECT
-		appointment_id, business_id, user_id, status, scheduled_on, date_created, date_updated
+		appointment_id, series_id, business_id, user_id, service_id, resource_id, status, scheduled_on, ends_on, price, currency, capacity,
+		confirmed_at, checked_in_at, completed_at, no_show_at, cancelled_at, rejected_at, held_until, flagged_at, date_created, date_updated,` + remaining + `
 	FROM
 		appointments
 	WHERE
@@ -201,7 +311,12 @@ func (s *Store) QueryByUserID(ctx context.Context, usrID uuid.UUID) ([]appointme
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
-	return toCoreAppointmentSlice(dbApts), nil
+	apts, err := toCoreAppointmentSlice(dbApts)
+	if err != nil {
+		return nil, err
+	}
+
+	return apts, nil
 }
 
 func (s *Store) QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]appointment.Appointment, error) {
@@ -213,7 +328,8 @@ func (s *Store) QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]appoi
 
 	const q = `
 	SELECT
-		appointment_id, business_id, user_id, status, scheduled_on, date_created, date_updated
+		appointment_id, series_id, business_id, user_id, service_id, resource_id, status, scheduled_on, ends_on, price, currency, capacity,
+		confirmed_at, checked_in_at, completed_at, no_show_at, cancelled_at, rejected_at, held_until, flagged_at, date_created, date_updated,` + remaining + `
 	FROM
 		appointments
 	WHERE
@@ -225,5 +341,98 @@ func (s *Store) QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]appoi
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
-	return toCoreAppointmentSlice(dbApts), nil
+	apts, err := toCoreAppointmentSlice(dbApts)
+	if err != nil {
+		return nil, err
+	}
+
+	return apts, nil
+}
+
+func (s *Store) QueryBySeriesID(ctx context.Context, srsID uuid.UUID) ([]appointment.Appointment, error) {
+	data := struct {
+		SeriesID string `db:"series_id"`
+	}{
+		SeriesID: srsID.String(),
+	}
+
+	const q = `
+	SELECT
+		appointment_id, series_id, business_id, user_id, service_id, resource_id, status, scheduled_on, ends_on, price, currency, capacity,
+		confirmed_at, checked_in_at, completed_at, no_show_at, cancelled_at, rejected_at, held_until, flagged_at, date_created, date_updated,` + remaining + `
+	FROM
+		appointments
+	WHERE
+		series_id = :series_id
+	ORDER BY
+		scheduled_on
+	`
+
+	var dbApts []dbAppointment
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbApts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	apts, err := toCoreAppointmentSlice(dbApts)
+	if err != nil {
+		return nil, err
+	}
+
+	return apts, nil
+}
+
+func (s *Store) CreateSeries(ctx context.Context, srs appointment.Series) error {
+	const q = `
+	INSERT INTO appointment_series
+		(series_id, business_id, user_id, rule, starts_on, date_created)
+	VALUES
+		(:series_id, :business_id, :user_id, :rule, :starts_on, :date_created)
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBSeries(srs)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) CreateEvent(ctx context.Context, evt appointment.Event) error {
+	const q = `
+	INSERT INTO appointment_events
+		(event_id, appointment_id, actor_id, old_status, new_status, old_scheduled_on, new_scheduled_on, reason, date_created)
+	VALUES
+		(:event_id, :appointment_id, :actor_id, :old_status, :new_status, :old_scheduled_on, :new_scheduled_on, :reason, :date_created)
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBEvent(evt)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) QueryEvents(ctx context.Context, aptID uuid.UUID) ([]appointment.Event, error) {
+	data := struct {
+		AppointmentID string `db:"appointment_id"`
+	}{
+		AppointmentID: aptID.String(),
+	}
+
+	const q = `
+	SELECT
+		event_id, appointment_id, actor_id, old_status, new_status, old_scheduled_on, new_scheduled_on, reason, date_created
+	FROM
+		appointment_events
+	WHERE
+		appointment_id = :appointment_id
+	ORDER BY
+		date_created
+	`
+
+	var dbEvts []dbEvent
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEvts); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreEventSlice(dbEvts), nil
 }
@@ -20,6 +20,11 @@ func (s *Store) applyFilter(filter appointment.QueryFilter, data map[string]any,
 		wc = append(wc, "business_id = :business_id")
 	}
 
+	if filter.ResourceID != nil {
+		data["resource_id"] = *filter.ResourceID
+		wc = append(wc, "resource_id = :resource_id")
+	}
+
 	if filter.UserID != nil {
 		data["user_id"] = *filter.UserID
 		wc = append(wc, "user_id = :user_id")
@@ -35,6 +40,16 @@ func (s *Store) applyFilter(filter appointment.QueryFilter, data map[string]any,
 		wc = append(wc, "scheduled_on = :scheduled_on")
 	}
 
+	if filter.StartScheduledOn != nil {
+		data["start_scheduled_on"] = *filter.StartScheduledOn
+		wc = append(wc, "scheduled_on >= :start_scheduled_on")
+	}
+
+	if filter.EndScheduledOn != nil {
+		data["end_scheduled_on"] = *filter.EndScheduledOn
+		wc = append(wc, "scheduled_on <= :end_scheduled_on")
+	}
+
 	if filter.StartCreatedDate != nil {
 		data["start_date_created"] = *filter.StartCreatedDate
 		wc = append(wc, "date_created >= :start_date_created")
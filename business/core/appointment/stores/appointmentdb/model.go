@@ -1,20 +1,34 @@
 package appointmentdb
 
 import (
+	"database/sql"
+	"fmt"
 	"time"
 
 	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/google/uuid"
 )
 
+// Appointments scheduled before their lifecycle existed are taken as confirmed.
 var toDBStatuses = map[appointment.Status]int16{
 	appointment.StatusCancelled: 0,
-	appointment.StatusScheduled: 1,
+	appointment.StatusConfirmed: 1,
+	appointment.StatusPending:   2,
+	appointment.StatusCheckedIn: 3,
+	appointment.StatusCompleted: 4,
+	appointment.StatusNoShow:    5,
+	appointment.StatusRejected:  6,
 }
 
 var toCoreStatuses = map[int16]appointment.Status{
 	0: appointment.StatusCancelled,
-	1: appointment.StatusScheduled,
+	1: appointment.StatusConfirmed,
+	2: appointment.StatusPending,
+	3: appointment.StatusCheckedIn,
+	4: appointment.StatusCompleted,
+	5: appointment.StatusNoShow,
+	6: appointment.StatusRejected,
 }
 
 // toDBStatus converts status string value to db smallint
@@ -27,45 +41,218 @@ func toCoreStatus(val int16) appointment.Status {
 	return toCoreStatuses[val]
 }
 
+// toDBTime converts a time which may be zero, such as the time of a transition not
+// taken place yet, to a nullable column.
+func toDBTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func toCoreTime(t sql.NullTime) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+
+	return t.Time.In(time.Local)
+}
+
 type dbAppointment struct {
-	ID          uuid.UUID `db:"appointment_id"`
-	BusinessID  uuid.UUID `db:"business_id"`
-	UserID      uuid.UUID `db:"user_id"`
-	Status      int16     `db:"status"`
-	ScheduledOn time.Time `db:"scheduled_on"`
-	DateCreated time.Time `db:"date_created"`
-	DateUpdated time.Time `db:"date_updated"`
+	ID          uuid.UUID     `db:"appointment_id"`
+	SeriesID    uuid.NullUUID `db:"series_id"`
+	BusinessID  uuid.UUID     `db:"business_id"`
+	UserID      uuid.UUID     `db:"user_id"`
+	ServiceID   uuid.NullUUID `db:"service_id"`
+	ResourceID  uuid.NullUUID `db:"resource_id"`
+	Status      int16         `db:"status"`
+	ScheduledOn time.Time     `db:"scheduled_on"`
+	EndsOn      time.Time     `db:"ends_on"`
+	Price       int64         `db:"price"`
+	Currency    string        `db:"currency"`
+	Capacity    int           `db:"capacity"`
+	Remaining   int           `db:"remaining"`
+	ConfirmedAt sql.NullTime  `db:"confirmed_at"`
+	CheckedInAt sql.NullTime  `db:"checked_in_at"`
+	CompletedAt sql.NullTime  `db:"completed_at"`
+	NoShowAt    sql.NullTime  `db:"no_show_at"`
+	CancelledAt sql.NullTime  `db:"cancelled_at"`
+	RejectedAt  sql.NullTime  `db:"rejected_at"`
+	HeldUntil   sql.NullTime  `db:"held_until"`
+	FlaggedAt   sql.NullTime  `db:"flagged_at"`
+	DateCreated time.Time     `db:"date_created"`
+	DateUpdated time.Time     `db:"date_updated"`
 }
 
 func toDBAppointment(apt appointment.Appointment) dbAppointment {
 	return dbAppointment{
 		ID:          apt.ID,
+		SeriesID:    uuid.NullUUID{UUID: apt.SeriesID, Valid: apt.SeriesID != uuid.Nil},
 		BusinessID:  apt.BusinessID,
 		UserID:      apt.UserID,
+		ServiceID:   uuid.NullUUID{UUID: apt.ServiceID, Valid: apt.ServiceID != uuid.Nil},
+		ResourceID:  uuid.NullUUID{UUID: apt.ResourceID, Valid: apt.ResourceID != uuid.Nil},
 		Status:      toDBStatus(apt.Status),
 		ScheduledOn: apt.ScheduledOn.UTC(),
+		EndsOn:      apt.EndsOn().UTC(),
+		Price:       apt.Price,
+		Currency:    apt.Currency.Code(),
+		Capacity:    apt.Capacity,
+		Remaining:   apt.Remaining,
+		ConfirmedAt: toDBTime(apt.ConfirmedAt),
+		CheckedInAt: toDBTime(apt.CheckedInAt),
+		CompletedAt: toDBTime(apt.CompletedAt),
+		NoShowAt:    toDBTime(apt.NoShowAt),
+		CancelledAt: toDBTime(apt.CancelledAt),
+		RejectedAt:  toDBTime(apt.RejectedAt),
+		HeldUntil:   toDBTime(apt.HeldUntil),
+		FlaggedAt:   toDBTime(apt.FlaggedAt),
 		DateCreated: apt.DateCreated.UTC(),
 		DateUpdated: apt.DateUpdated.UTC(),
 	}
 }
 
-func toCoreAppointment(dbApt dbAppointment) appointment.Appointment {
-	return appointment.Appointment{
+func toCoreAppointment(dbApt dbAppointment) (appointment.Appointment, error) {
+	// Appointments booked before the catalog existed have no currency.
+	var cur service.Currency
+	if dbApt.Currency != "" {
+		var err error
+		cur, err = service.ParseCurrency(dbApt.Currency)
+		if err != nil {
+			return appointment.Appointment{}, fmt.Errorf("parse currency: %w", err)
+		}
+	}
+
+	apt := appointment.Appointment{
 		ID:          dbApt.ID,
+		SeriesID:    dbApt.SeriesID.UUID,
 		BusinessID:  dbApt.BusinessID,
 		UserID:      dbApt.UserID,
+		ServiceID:   dbApt.ServiceID.UUID,
+		ResourceID:  dbApt.ResourceID.UUID,
 		Status:      toCoreStatus(dbApt.Status),
 		ScheduledOn: dbApt.ScheduledOn.In(time.Local),
+		Duration:    dbApt.EndsOn.Sub(dbApt.ScheduledOn),
+		Price:       dbApt.Price,
+		Currency:    cur,
+		Capacity:    dbApt.Capacity,
+		Remaining:   max(dbApt.Remaining, 0),
+		ConfirmedAt: toCoreTime(dbApt.ConfirmedAt),
+		CheckedInAt: toCoreTime(dbApt.CheckedInAt),
+		CompletedAt: toCoreTime(dbApt.CompletedAt),
+		NoShowAt:    toCoreTime(dbApt.NoShowAt),
+		CancelledAt: toCoreTime(dbApt.CancelledAt),
+		RejectedAt:  toCoreTime(dbApt.RejectedAt),
+		HeldUntil:   toCoreTime(dbApt.HeldUntil),
+		FlaggedAt:   toCoreTime(dbApt.FlaggedAt),
 		DateCreated: dbApt.DateCreated.In(time.Local),
 		DateUpdated: dbApt.DateUpdated.In(time.Local),
 	}
+
+	return apt, nil
 }
 
-func toCoreAppointmentSlice(dbApts []dbAppointment) []appointment.Appointment {
+func toCoreAppointmentSlice(dbApts []dbAppointment) ([]appointment.Appointment, error) {
 	apts := make([]appointment.Appointment, len(dbApts))
 	for i, dbApt := range dbApts {
-		apts[i] = toCoreAppointment(dbApt)
+		var err error
+		apts[i], err = toCoreAppointment(dbApt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return apts, nil
+}
+
+// =============================================================================
+
+type dbEvent struct {
+	ID             uuid.UUID     `db:"event_id"`
+	AppointmentID  uuid.UUID     `db:"appointment_id"`
+	ActorID        uuid.NullUUID `db:"actor_id"`
+	OldStatus      sql.NullInt16 `db:"old_status"`
+	NewStatus      sql.NullInt16 `db:"new_status"`
+	OldScheduledOn sql.NullTime  `db:"old_scheduled_on"`
+	NewScheduledOn sql.NullTime  `db:"new_scheduled_on"`
+	Reason         string        `db:"reason"`
+	DateCreated    time.Time     `db:"date_created"`
+}
+
+// toDBEventStatus converts the status of an event, zero where the appointment is
+// created or deleted, to a nullable column.
+func toDBEventStatus(st appointment.Status) sql.NullInt16 {
+	if st == (appointment.Status{}) {
+		return sql.NullInt16{}
+	}
+
+	return sql.NullInt16{Int16: toDBStatus(st), Valid: true}
+}
+
+func toCoreEventStatus(val sql.NullInt16) appointment.Status {
+	if !val.Valid {
+		return appointment.Status{}
+	}
+
+	return toCoreStatus(val.Int16)
+}
+
+func toDBEvent(evt appointment.Event) dbEvent {
+	return dbEvent{
+		ID:             evt.ID,
+		AppointmentID:  evt.AppointmentID,
+		ActorID:        uuid.NullUUID{UUID: evt.ActorID, Valid: evt.ActorID != uuid.Nil},
+		OldStatus:      toDBEventStatus(evt.OldStatus),
+		NewStatus:      toDBEventStatus(evt.NewStatus),
+		OldScheduledOn: toDBTime(evt.OldScheduledOn),
+		NewScheduledOn: toDBTime(evt.NewScheduledOn),
+		Reason:         evt.Reason,
+		DateCreated:    evt.DateCreated.UTC(),
+	}
+}
+
+func toCoreEvent(dbEvt dbEvent) appointment.Event {
+	return appointment.Event{
+		ID:             dbEvt.ID,
+		AppointmentID:  dbEvt.AppointmentID,
+		ActorID:        dbEvt.ActorID.UUID,
+		OldStatus:      toCoreEventStatus(dbEvt.OldStatus),
+		NewStatus:      toCoreEventStatus(dbEvt.NewStatus),
+		OldScheduledOn: toCoreTime(dbEvt.OldScheduledOn),
+		NewScheduledOn: toCoreTime(dbEvt.NewScheduledOn),
+		Reason:         dbEvt.Reason,
+		DateCreated:    dbEvt.DateCreated.In(time.Local),
+	}
+}
+
+func toCoreEventSlice(dbEvts []dbEvent) []appointment.Event {
+	evts := make([]appointment.Event, len(dbEvts))
+	for i, dbEvt := range dbEvts {
+		evts[i] = toCoreEvent(dbEvt)
 	}
 
-	return apts
+	return evts
+}
+
+// =============================================================================
+
+type dbSeries struct {
+	ID          uuid.UUID `db:"series_id"`
+	BusinessID  uuid.UUID `db:"business_id"`
+	UserID      uuid.UUID `db:"user_id"`
+	Rule        string    `db:"rule"`
+	StartsOn    time.Time `db:"starts_on"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+func toDBSeries(srs appointment.Series) dbSeries {
+	return dbSeries{
+		ID:          srs.ID,
+		BusinessID:  srs.BusinessID,
+		UserID:      srs.UserID,
+		Rule:        srs.Rule.String(),
+		StartsOn:    srs.StartsOn.UTC(),
+		DateCreated: srs.DateCreated.UTC(),
+	}
 }
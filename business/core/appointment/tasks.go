@@ -3,17 +3,27 @@ package appointment
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/foundation/email"
 	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/sms"
 	"github.com/google/uuid"
 	"github.com/hibiken/asynq"
 )
 
 const (
-	TypeSendSMS = "sms:send"
+	TypeSendSMS               = "sms:send"
+	TypeSendConfirmationEmail = "email:confirmation"
+	TypeSendRescheduleEmail   = "email:reschedule"
+	TypeSendCancellationEmail = "email:cancellation"
+	TypeSendReminderEmail     = "email:reminder"
+	TypeSendFlaggedEmail      = "email:flagged"
+	TypeReleaseHold           = "hold:release"
+	TypeReleaseSlot           = "slot:release"
 )
 
 type Task struct {
@@ -29,65 +39,310 @@ func NewTask(client *asynq.Client, inspector *asynq.Inspector) *Task {
 }
 
 type sendSMSPayload struct {
-	UserID uuid.UUID
+	UserID        uuid.UUID
+	AppointmentID uuid.UUID
+	ScheduledOn   time.Time
+	TimeZone      string
 }
 
-func (t *Task) NewSendSMSTask(userID uuid.UUID, fireAt time.Time, taskID string) (*asynq.TaskInfo, error) {
-	fireAt = fireAt.UTC()
-	data := sendSMSPayload{UserID: userID}
+// reminderAt returns when the reminder of an appointment is due, offset before it
+// starts. A zero offset reminds at ScheduledOn itself; any earlier reminder comes
+// from the offsets of the business. Whole days are subtracted in the business
+// location so a daylight saving transition in between doesn't shift the wall
+// clock time of the reminder.
+func reminderAt(scheduledOn time.Time, offset time.Duration, loc *time.Location) time.Time {
+	const day = 24 * time.Hour
+	return scheduledOn.In(loc).AddDate(0, 0, -int(offset/day)).Add(-(offset % day))
+}
+
+// reminderTaskID derives the ID of the reminder task of the type, so the reminders
+// of an appointment can be found again knowing the offsets they were scheduled at.
+func reminderTaskID(typ string, aptID string, offset time.Duration) string {
+	return fmt.Sprintf("%s:%s:%d", typ, aptID, int64(offset/time.Second))
+}
+
+// NewSendSMSTask schedules a reminder for an appointment at each of the offsets
+// before it starts, for a business operating in loc. Reminders already due by now
+// are left out, customers having just booked.
+func (t *Task) NewSendSMSTask(apt Appointment, loc *time.Location, offsets []time.Duration) error {
+	data := sendSMSPayload{
+		UserID:        apt.UserID,
+		AppointmentID: apt.ID,
+		ScheduledOn:   apt.ScheduledOn.In(loc),
+		TimeZone:      loc.String(),
+	}
 	payload, err := json.Marshal(data)
 	if err != nil {
-		return nil, fmt.Errorf("creating a new send sms task: %w", err)
+		return fmt.Errorf("creating a new send sms task: %w", err)
+	}
+
+	now := time.Now()
+	for _, offset := range offsets {
+		fireAt := reminderAt(apt.ScheduledOn, offset, loc)
+		if !fireAt.After(now) {
+			continue
+		}
+
+		task := asynq.NewTask(
+			TypeSendSMS,
+			payload,
+			asynq.TaskID(reminderTaskID(TypeSendSMS, apt.ID.String(), offset)),
+			asynq.ProcessAt(afterCommit(fireAt, now).UTC()),
+			asynq.Timeout(time.Minute*1),
+		)
+
+		if _, err := t.client.Enqueue(task); err != nil {
+			return fmt.Errorf("enqueue task[%s]: offset[%s]: %w", TypeSendSMS, offset, err)
+		}
+	}
+
+	return nil
+}
+
+// cancelSendSMSTask cancels the reminders of an appointment scheduled at each of
+// the offsets.
+func (t *Task) cancelSendSMSTask(aptID string, offsets []time.Duration) error {
+	for _, offset := range offsets {
+		if err := t.deleteTask(reminderTaskID(TypeSendSMS, aptID, offset)); err != nil {
+			return fmt.Errorf("delete scheduled sms task: offset[%s]: %w", offset, err)
+		}
+	}
+
+	return nil
+}
+
+// notifyTaskID derives the ID of the task emailing about a change to the appointment,
+// so the same change isn't told of twice.
+func notifyTaskID(kind emailKind, aptID string, scheduledOn time.Time) string {
+	return fmt.Sprintf("%s:%s:%d", kind.taskType, aptID, scheduledOn.Unix())
+}
+
+func (t *Task) newSendEmailTask(kind emailKind, p emailPayload, opts ...asynq.Option) error {
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("creating a new send email task: %w", err)
+	}
+
+	opts = append(opts, asynq.Timeout(time.Minute*1))
+	task := asynq.NewTask(kind.taskType, payload, opts...)
+
+	if _, err := t.client.Enqueue(task); err != nil {
+		return fmt.Errorf("enqueue task[%s]: %w", kind.taskType, err)
+	}
+
+	return nil
+}
+
+// newReminderEmailTask schedules the email reminders of the appointment, due along
+// with its SMS reminders.
+func (t *Task) newReminderEmailTask(apt Appointment, loc *time.Location, offsets []time.Duration) error {
+	p := newEmailPayload(apt, loc, time.Time{}, "")
+
+	now := time.Now()
+	for _, offset := range offsets {
+		fireAt := reminderAt(apt.ScheduledOn, offset, loc)
+		if !fireAt.After(now) {
+			continue
+		}
+
+		err := t.newSendEmailTask(
+			emailReminder,
+			p,
+			asynq.TaskID(reminderTaskID(TypeSendReminderEmail, apt.ID.String(), offset)),
+			asynq.ProcessAt(afterCommit(fireAt, now).UTC()),
+		)
+		if err != nil {
+			return fmt.Errorf("offset[%s]: %w", offset, err)
+		}
+	}
+
+	return nil
+}
+
+func (t *Task) cancelReminderEmailTask(aptID string, offsets []time.Duration) error {
+	for _, offset := range offsets {
+		if err := t.deleteTask(reminderTaskID(TypeSendReminderEmail, aptID, offset)); err != nil {
+			return fmt.Errorf("delete scheduled reminder email task: offset[%s]: %w", offset, err)
+		}
+	}
+
+	return nil
+}
+
+type releaseHoldPayload struct {
+	AppointmentID uuid.UUID
+}
+
+func releaseHoldTaskID(aptID string) string {
+	return TypeReleaseHold + ":" + aptID
+}
+
+// newReleaseHoldTask schedules the release of the hold once it lapses.
+func (t *Task) newReleaseHoldTask(aptID uuid.UUID, heldUntil time.Time) error {
+	payload, err := json.Marshal(releaseHoldPayload{AppointmentID: aptID})
+	if err != nil {
+		return fmt.Errorf("creating a new release hold task: %w", err)
 	}
 
 	task := asynq.NewTask(
-		TypeSendSMS,
+		TypeReleaseHold,
 		payload,
-		asynq.TaskID(taskID),
-		asynq.ProcessAt(fireAt),
+		asynq.TaskID(releaseHoldTaskID(aptID.String())),
+		asynq.ProcessAt(afterCommit(heldUntil, time.Now()).UTC()),
 		asynq.Timeout(time.Minute*1),
 	)
 
-	info, err := t.client.Enqueue(task)
-	if err != nil {
-		return nil, fmt.Errorf("enqueue task[%s]: %w", TypeSendSMS, err)
+	if _, err := t.client.Enqueue(task); err != nil {
+		return fmt.Errorf("enqueue task[%s]: %w", TypeReleaseHold, err)
 	}
 
-	return info, err
+	return nil
 }
 
-func (t *Task) cancelSendSMSTask(taskID string) error {
-	if err := t.inspector.DeleteTask("default", taskID); err != nil {
-		return fmt.Errorf("delete scheduled sms task: %w", err)
+func (t *Task) cancelReleaseHoldTask(aptID string) error {
+	if err := t.deleteTask(releaseHoldTaskID(aptID)); err != nil {
+		return fmt.Errorf("delete scheduled release hold task: %w", err)
 	}
 
 	return nil
 }
 
-func (t *Task) updateSendSMSTask(userID uuid.UUID, newFireAt time.Time, taskID string) (*asynq.TaskInfo, error) {
-	if err := t.cancelSendSMSTask(taskID); err != nil {
-		return nil, fmt.Errorf("delete scheduled sms task: %w", err)
+type releaseSlotPayload struct {
+	AppointmentID uuid.UUID
+	BusinessID    uuid.UUID
+	UserID        uuid.UUID
+	ServiceID     uuid.UUID
+	ResourceID    uuid.UUID
+	ScheduledOn   time.Time
+	Duration      time.Duration
+}
+
+func releaseSlotTaskID(aptID string) string {
+	return TypeReleaseSlot + ":" + aptID
+}
+
+// deleteTask deletes the scheduled task of the id. A task already processed, or
+// cancelled before, is gone by now and needs no deleting.
+func (t *Task) deleteTask(id string) error {
+	if err := t.inspector.DeleteTask("default", id); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+		return err
 	}
 
-	ti, err := t.NewSendSMSTask(userID, newFireAt, taskID)
+	return nil
+}
+
+// inZone returns the time in the named time zone of the business. Times decoded
+// from a payload keep their offset but lose their location, which TimeZone brings
+// back for them to read with the zone abbreviation. Zones unknown to this host
+// leave the time at its offset.
+func inZone(t time.Time, zone string) time.Time {
+	loc, err := time.LoadLocation(zone)
 	if err != nil {
-		return nil, err
+		return t
+	}
+
+	return t.In(loc)
+}
+
+// commitDelay gives the transaction of a change time to commit before the tasks
+// telling of it run. They check the change still holds once they do, those of a
+// change rolled back finding it didn't.
+const commitDelay = 5 * time.Second
+
+// afterCommit returns when a task due at the given time runs, no sooner than the
+// commit delay from now.
+func afterCommit(at time.Time, now time.Time) time.Time {
+	if earliest := now.Add(commitDelay); at.Before(earliest) {
+		return earliest
+	}
+
+	return at
+}
+
+// newReleaseSlotTask has the slot the appointment no longer holds offered to
+// whoever waits for it. The payload keeps the slot for appointments deleted by then.
+func (t *Task) newReleaseSlotTask(apt Appointment) error {
+	data := releaseSlotPayload{
+		AppointmentID: apt.ID,
+		BusinessID:    apt.BusinessID,
+		UserID:        apt.UserID,
+		ServiceID:     apt.ServiceID,
+		ResourceID:    apt.ResourceID,
+		ScheduledOn:   apt.ScheduledOn,
+		Duration:      apt.Duration,
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("creating a new release slot task: %w", err)
+	}
+
+	task := asynq.NewTask(
+		TypeReleaseSlot,
+		payload,
+		asynq.TaskID(releaseSlotTaskID(apt.ID.String())),
+		asynq.ProcessIn(commitDelay),
+		asynq.Timeout(time.Minute*1),
+	)
+
+	// The slot is released once, however many changes free it meanwhile.
+	if _, err := t.client.Enqueue(task); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return fmt.Errorf("enqueue task[%s]: %w", TypeReleaseSlot, err)
 	}
 
-	return ti, nil
+	return nil
+}
+
+// postpone runs the task again at the given time. The task running now still holds
+// its ID, so the postponed one takes an ID of its own.
+func (t *Task) postpone(ctx context.Context, tsk *asynq.Task, at time.Time) error {
+	opts := []asynq.Option{
+		asynq.ProcessAt(at.UTC()),
+		asynq.Timeout(time.Minute * 1),
+	}
+
+	if id, ok := asynq.GetTaskID(ctx); ok {
+		opts = append(opts, asynq.TaskID(id+":postponed"))
+	}
+
+	// A task postponed already runs later anyway.
+	if _, err := t.client.Enqueue(asynq.NewTask(tsk.Type(), tsk.Payload(), opts...)); err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return fmt.Errorf("enqueue task[%s]: %w", tsk.Type(), err)
+	}
+
+	return nil
 }
 
 // ----------------------------------------------------------------------------------------------------------
 
+// Unsubscriber makes the links users follow to stop being notified over a channel.
+type Unsubscriber interface {
+	UnsubscribeURL(userID uuid.UUID, ch user.Channel) (string, error)
+}
+
+// Releaser offers the slot an appointment no longer holds to whoever waits for it.
+type Releaser interface {
+	Release(ctx context.Context, apt Appointment) error
+}
+
 type TaskHandlers struct {
-	log     *logger.Logger
-	usrCore *user.Core
+	log      *logger.Logger
+	usrCore  *user.Core
+	aptCore  *Core
+	sender   sms.Sender
+	mailer   email.Sender
+	links    Unsubscriber
+	releaser Releaser
 }
 
-func NewTaskHandlers(log *logger.Logger, usrCore *user.Core) *TaskHandlers {
+func NewTaskHandlers(log *logger.Logger, usrCore *user.Core, aptCore *Core, sender sms.Sender, mailer email.Sender, links Unsubscriber, releaser Releaser) *TaskHandlers {
 	return &TaskHandlers{
-		log:     log,
-		usrCore: usrCore,
+		log:      log,
+		usrCore:  usrCore,
+		aptCore:  aptCore,
+		sender:   sender,
+		mailer:   mailer,
+		links:    links,
+		releaser: releaser,
 	}
 }
 
@@ -97,10 +352,246 @@ func (t *TaskHandlers) HandleSendSMS(ctx context.Context, tsk *asynq.Task) error
 		return err
 	}
 
-	// Query user domain using core objects in TaskHandlers
+	usr, err := t.usrCore.QueryByID(ctx, s.UserID)
+	if err != nil {
+		// Users deleted since booking are no longer reminded.
+		if errors.Is(err, user.ErrNotFound) {
+			return fmt.Errorf("user.querybyid: %s: %w: %w", s.UserID, err, asynq.SkipRetry)
+		}
+		return fmt.Errorf("user.querybyid: %s: %w", s.UserID, err)
+	}
+
+	if !usr.Enabled || usr.PhoneNo.Number() == "" || !usr.Preferences.Allows(user.ChannelSMS) {
+		t.log.Info(ctx, "skipping reminder", "userID", usr.ID, "enabled", usr.Enabled)
+		return nil
+	}
+
+	outdated, err := t.outdated(ctx, s.AppointmentID, s.ScheduledOn)
+	if err != nil {
+		return err
+	}
+
+	if outdated {
+		t.log.Info(ctx, "skipping outdated reminder", "appointmentID", s.AppointmentID)
+		return nil
+	}
+
+	scheduledOn := inZone(s.ScheduledOn, s.TimeZone)
+
+	quiet, err := t.quiet(ctx, tsk, usr, s.AppointmentID, scheduledOn)
+	if err != nil || quiet {
+		return err
+	}
+
+	body, err := reminderBody(usr.Preferences.Language, usr.Name, scheduledOn)
+	if err != nil {
+		return fmt.Errorf("render: %w: %w", err, asynq.SkipRetry)
+	}
+
+	msg := sms.Message{
+		To:   usr.PhoneNo.Number(),
+		Body: body,
+	}
+
+	if err := t.sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send: userID[%s]: %w", usr.ID, err)
+	}
+
+	t.log.Info(ctx, "sent reminder", "userID", usr.ID, "scheduledOn", s.ScheduledOn.Format(time.RFC3339), "timeZone", s.TimeZone)
+
+	return nil
+}
+
+// quiet holds back the reminder of the appointment scheduled on the given time when
+// it's due within the quiet hours of the user. Users have no time zone of their own,
+// so their quiet hours are kept by the wall clock of the business, the location of
+// scheduledOn. Reminders due within them wait until they end, unless the appointment
+// starts by then. It reports whether the reminder was held back.
+func (t *TaskHandlers) quiet(ctx context.Context, tsk *asynq.Task, usr user.User, aptID uuid.UUID, scheduledOn time.Time) (bool, error) {
+	now := time.Now().In(scheduledOn.Location())
+
+	until := usr.Preferences.QuietHours.Until(now)
+	if !until.After(now) {
+		return false, nil
+	}
+
+	if !until.Before(scheduledOn) {
+		t.log.Info(ctx, "skipping reminder in quiet hours", "type", tsk.Type(), "userID", usr.ID, "appointmentID", aptID)
+		return true, nil
+	}
+
+	if err := t.aptCore.task.postpone(ctx, tsk, until); err != nil {
+		return false, fmt.Errorf("postpone: %w", err)
+	}
+
+	t.log.Info(ctx, "postponed reminder past quiet hours", "type", tsk.Type(), "userID", usr.ID, "until", until.Format(time.RFC3339))
+
+	return true, nil
+}
+
+// outdated reports whether a reminder of the appointment scheduled on the given
+// time no longer applies, the appointment being gone, moved or no longer holding
+// its slot. Such reminders are left behind when the business changes its reminder
+// offsets in between.
+func (t *TaskHandlers) outdated(ctx context.Context, aptID uuid.UUID, scheduledOn time.Time) (bool, error) {
+	// Reminders scheduled before they carried their appointment can't tell.
+	if aptID == uuid.Nil {
+		return false, nil
+	}
+
+	apt, err := t.aptCore.QueryByID(ctx, aptID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return true, nil
+		}
+		return false, fmt.Errorf("querybyid: %s: %w", aptID, err)
+	}
+
+	return !apt.Status.Holds() || !apt.ScheduledOn.Equal(scheduledOn), nil
+}
+
+// outdatedEmail reports whether the email of the kind no longer applies to its
+// appointment, the change it tells of being rolled back or undone since.
+func (t *TaskHandlers) outdatedEmail(ctx context.Context, kind emailKind, p emailPayload) (bool, error) {
+	if kind == emailReminder {
+		return t.outdated(ctx, p.AppointmentID, p.ScheduledOn)
+	}
+
+	apt, err := t.aptCore.QueryByID(ctx, p.AppointmentID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return true, nil
+		}
+		return false, fmt.Errorf("querybyid: %s: %w", p.AppointmentID, err)
+	}
+
+	switch kind {
+	case emailCancellation:
+		return apt.Status != StatusCancelled, nil
+	case emailFlagged:
+		return apt.FlaggedAt.IsZero() || !apt.ScheduledOn.Equal(p.ScheduledOn), nil
+	default:
+		// Holds are only confirmed to customers once they check out.
+		return !apt.Status.Holds() || apt.Held() || !apt.ScheduledOn.Equal(p.ScheduledOn), nil
+	}
+}
+
+func (t *TaskHandlers) HandleReleaseHold(ctx context.Context, tsk *asynq.Task) error {
+	var p releaseHoldPayload
+	if err := json.Unmarshal(tsk.Payload(), &p); err != nil {
+		return err
+	}
+
+	if err := t.aptCore.ReleaseHold(ctx, p.AppointmentID); err != nil {
+		return fmt.Errorf("releasehold: %w", err)
+	}
+
+	return nil
+}
+
+// HandleReleaseSlot offers the slot of the appointment to whoever waits for it. An
+// appointment still holding its slot had the change freeing it rolled back.
+func (t *TaskHandlers) HandleReleaseSlot(ctx context.Context, tsk *asynq.Task) error {
+	var p releaseSlotPayload
+	if err := json.Unmarshal(tsk.Payload(), &p); err != nil {
+		return err
+	}
+
+	apt, err := t.aptCore.QueryByID(ctx, p.AppointmentID)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		apt = Appointment{
+			ID:          p.AppointmentID,
+			BusinessID:  p.BusinessID,
+			UserID:      p.UserID,
+			ServiceID:   p.ServiceID,
+			ResourceID:  p.ResourceID,
+			ScheduledOn: p.ScheduledOn,
+			Duration:    p.Duration,
+		}
+	case err != nil:
+		return fmt.Errorf("querybyid: %s: %w", p.AppointmentID, err)
+	case apt.Status.Holds():
+		t.log.Info(ctx, "skipping release of a held slot", "appointmentID", apt.ID)
+		return nil
+	}
+
+	if err := t.releaser.Release(ctx, apt); err != nil {
+		return fmt.Errorf("release: appointmentID[%s]: %w", apt.ID, err)
+	}
+
+	return nil
+}
+
+// HandleSendEmail emails the customer of an appointment. It handles every type of
+// email task, rendering the email of the type.
+func (t *TaskHandlers) HandleSendEmail(ctx context.Context, tsk *asynq.Task) error {
+	kind, exists := emailKinds[tsk.Type()]
+	if !exists {
+		return fmt.Errorf("unknown email task type %q: %w", tsk.Type(), asynq.SkipRetry)
+	}
+
+	var p emailPayload
+	if err := json.Unmarshal(tsk.Payload(), &p); err != nil {
+		return err
+	}
+
+	usr, err := t.usrCore.QueryByID(ctx, p.UserID)
+	if err != nil {
+		// Users deleted since booking are no longer emailed.
+		if errors.Is(err, user.ErrNotFound) {
+			return fmt.Errorf("user.querybyid: %s: %w: %w", p.UserID, err, asynq.SkipRetry)
+		}
+		return fmt.Errorf("user.querybyid: %s: %w", p.UserID, err)
+	}
+
+	if !usr.Enabled || usr.Email.Address == "" || !usr.Preferences.Allows(user.ChannelEmail) {
+		t.log.Info(ctx, "skipping email", "type", kind.taskType, "userID", usr.ID, "enabled", usr.Enabled)
+		return nil
+	}
+
+	outdated, err := t.outdatedEmail(ctx, kind, p)
+	if err != nil {
+		return err
+	}
+
+	if outdated {
+		t.log.Info(ctx, "skipping outdated email", "type", kind.taskType, "appointmentID", p.AppointmentID)
+		return nil
+	}
+
+	// Reminders keep to the quiet hours of the user like their SMS counterparts.
+	// Other emails tell of changes as they happen.
+	if kind == emailReminder {
+		quiet, err := t.quiet(ctx, tsk, usr, p.AppointmentID, inZone(p.ScheduledOn, p.TimeZone))
+		if err != nil || quiet {
+			return err
+		}
+	}
+
+	unsubscribe, err := t.links.UnsubscribeURL(usr.ID, user.ChannelEmail)
+	if err != nil {
+		return fmt.Errorf("unsubscribeurl: %w", err)
+	}
+
+	text, html, err := renderEmail(kind, usr.Preferences.Language, usr.Name, unsubscribe, p)
+	if err != nil {
+		return fmt.Errorf("render: %w: %w", err, asynq.SkipRetry)
+	}
+
+	msg := email.Message{
+		To:          usr.Email,
+		Subject:     kind.subject,
+		Text:        text,
+		HTML:        html,
+		Unsubscribe: unsubscribe,
+	}
+
+	if err := t.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send: userID[%s]: %w", usr.ID, err)
+	}
 
-	// Simulate sending an early notification(reminder) to user
-	t.log.Info(ctx, "sending reminder", "to userID[%s]", s.UserID)
+	t.log.Info(ctx, "sent email", "type", kind.taskType, "userID", usr.ID, "appointmentID", p.AppointmentID)
 
 	return nil
 }
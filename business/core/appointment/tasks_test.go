@@ -0,0 +1,83 @@
+package appointment
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func Test_ReminderAt(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Should be able to load location: %s", err)
+	}
+
+	// DST starts on 2024-03-31 in Berlin, so the day before the appointment is
+	// 23 hours long.
+	scheduledOn := time.Date(2024, time.April, 1, 9, 0, 0, 0, loc)
+
+	tests := []struct {
+		name   string
+		offset time.Duration
+		exp    time.Time
+	}{
+		{"none", 0, scheduledOn},
+		{"hour", time.Hour, time.Date(2024, time.April, 1, 8, 0, 0, 0, loc)},
+		{"day", 24 * time.Hour, time.Date(2024, time.March, 31, 9, 0, 0, 0, loc)},
+		{"days", 49 * time.Hour, time.Date(2024, time.March, 30, 8, 0, 0, 0, loc)},
+	}
+
+	for _, tt := range tests {
+		got := reminderAt(scheduledOn.UTC(), tt.offset, loc)
+		if !got.Equal(tt.exp) {
+			t.Errorf("%s: Should get the reminder time: got %s, exp %s", tt.name, got, tt.exp)
+		}
+	}
+}
+
+func Test_AfterCommit(t *testing.T) {
+	now := time.Date(2024, time.April, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		at   time.Time
+		exp  time.Time
+	}{
+		{"past", now.Add(-time.Hour), now.Add(commitDelay)},
+		{"now", now, now.Add(commitDelay)},
+		{"within delay", now.Add(commitDelay / 2), now.Add(commitDelay)},
+		{"later", now.Add(time.Hour), now.Add(time.Hour)},
+	}
+
+	for _, tt := range tests {
+		if got := afterCommit(tt.at, now); !got.Equal(tt.exp) {
+			t.Errorf("%s: Should run no sooner than the commit delay: got %s, exp %s", tt.name, got, tt.exp)
+		}
+	}
+}
+
+func Test_InZone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Should be able to load location: %s", err)
+	}
+
+	data, err := json.Marshal(time.Date(2024, time.July, 15, 9, 30, 0, 0, loc))
+	if err != nil {
+		t.Fatalf("Should be able to marshal the time: %s", err)
+	}
+
+	var decoded time.Time
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Should be able to unmarshal the time: %s", err)
+	}
+
+	got := inZone(decoded, "Europe/Berlin")
+	if !got.Equal(decoded) || got.Format("15:04 MST") != "09:30 CEST" {
+		t.Errorf("Should get the time in the zone: got %s", got)
+	}
+
+	if got := inZone(decoded, "Mars/Olympus"); !got.Equal(decoded) || got.Format("15:04") != "09:30" {
+		t.Errorf("Should keep the offset in an unknown zone: got %s", got)
+	}
+}
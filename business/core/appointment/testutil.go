@@ -8,22 +8,22 @@ import (
 	"github.com/google/uuid"
 )
 
-func TestGenerateNewAppointment(n int, usrID uuid.UUID, bsnID uuid.UUID) []NewAppointment {
+func TestGenerateNewAppointment(n int, usrID uuid.UUID, bsnID uuid.UUID, svcID uuid.UUID) []NewAppointment {
 	na := make([]NewAppointment, n)
 	for i := 0; i < n; i++ {
 		na[i] = NewAppointment{
 			BusinessID:  bsnID,
 			UserID:      usrID,
-			Status:      StatusScheduled,
-			ScheduledOn: time.Now().Add(2 * time.Hour),
+			ServiceID:   svcID,
+			ScheduledOn: time.Now().Add(time.Duration(2+i) * time.Hour),
 		}
 	}
 
 	return na
 }
 
-func TestGenerateSeedAppointments(n int, api *Core, usrID uuid.UUID, bsnID uuid.UUID) ([]Appointment, error) {
-	newApts := TestGenerateNewAppointment(n, usrID, bsnID)
+func TestGenerateSeedAppointments(n int, api *Core, usrID uuid.UUID, bsnID uuid.UUID, svcID uuid.UUID) ([]Appointment, error) {
+	newApts := TestGenerateNewAppointment(n, usrID, bsnID, svcID)
 
 	apts := make([]Appointment, n)
 	for i, na := range newApts {
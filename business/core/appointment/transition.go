@@ -0,0 +1,82 @@
+package appointment
+
+import "time"
+
+// Party is who asks for the status of an appointment to change: the customer who
+// booked it, the owner of the business, or an admin.
+type Party struct {
+	name string
+}
+
+var (
+	PartyCustomer = Party{"CUSTOMER"}
+	PartyOwner    = Party{"OWNER"}
+	PartyAdmin    = Party{"ADMIN"}
+)
+
+func (p Party) Name() string {
+	return p.name
+}
+
+type transition struct {
+	from Status
+	to   Status
+}
+
+// transitions holds every allowed change of status along with the parties who may
+// make it. Admins may make any of them.
+var transitions = map[transition][]Party{
+	{StatusPending, StatusConfirmed}:   {PartyOwner},
+	{StatusPending, StatusRejected}:    {PartyOwner},
+	{StatusPending, StatusCancelled}:   {PartyCustomer, PartyOwner},
+	{StatusConfirmed, StatusCheckedIn}: {PartyOwner},
+	{StatusConfirmed, StatusNoShow}:    {PartyOwner},
+	{StatusConfirmed, StatusCancelled}: {PartyCustomer, PartyOwner},
+	{StatusCheckedIn, StatusCompleted}: {PartyOwner},
+}
+
+// checkTransition returns ErrInvalidTransition if an appointment can't go from one
+// status to the other, and ErrTransitionForbidden if the party may not make it.
+func checkTransition(from Status, to Status, party Party) error {
+	parties, exists := transitions[transition{from, to}]
+	if !exists {
+		return ErrInvalidTransition
+	}
+
+	if party == PartyAdmin {
+		return nil
+	}
+
+	for _, p := range parties {
+		if p == party {
+			return nil
+		}
+	}
+
+	return ErrTransitionForbidden
+}
+
+// withinNotice reports whether the appointment starts sooner than the given notice.
+// Customers may not cancel or move appointments within the notice of their business,
+// owners and admins may.
+func withinNotice(apt Appointment, notice time.Duration, now time.Time) bool {
+	return notice > 0 && apt.ScheduledOn.Sub(now) < notice
+}
+
+// stamp records the time the appointment took the given status.
+func (a *Appointment) stamp(status Status, now time.Time) {
+	switch status {
+	case StatusConfirmed:
+		a.ConfirmedAt = now
+	case StatusCheckedIn:
+		a.CheckedInAt = now
+	case StatusCompleted:
+		a.CompletedAt = now
+	case StatusNoShow:
+		a.NoShowAt = now
+	case StatusCancelled:
+		a.CancelledAt = now
+	case StatusRejected:
+		a.RejectedAt = now
+	}
+}
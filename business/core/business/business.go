@@ -16,8 +16,11 @@ import (
 )
 
 var (
-	ErrNotFound     = errors.New("product not found")
-	ErrUserDisabled = errors.New("user disabled")
+	ErrNotFound      = errors.New("product not found")
+	ErrUserDisabled  = errors.New("user disabled")
+	ErrInvalidNotice = errors.New("notice must not be negative")
+	ErrInvalidWindow = errors.New("lead time must not be negative and horizon must exceed it")
+	ErrInvalidOffset = errors.New("reminder offsets must be distinct, zero or at least a minute and at most 5")
 )
 
 type Storer interface {
@@ -69,6 +72,10 @@ func (c *Core) Create(ctx context.Context, nb NewBusiness) (Business, error) {
 	ctx, span := otel.AddSpan(ctx, "business.business.create")
 	defer span.End()
 
+	if nb.TimeZone.loc == nil {
+		nb.TimeZone = TimeZoneUTC
+	}
+
 	usr, err := c.usrCore.QueryByID(ctx, nb.OwnerID)
 	if err != nil {
 		return Business{}, fmt.Errorf("user.querybyid: %s: %w", nb.OwnerID, err)
@@ -78,15 +85,38 @@ func (c *Core) Create(ctx context.Context, nb NewBusiness) (Business, error) {
 		return Business{}, ErrUserDisabled
 	}
 
+	if nb.CancellationNotice < 0 || nb.RescheduleNotice < 0 {
+		return Business{}, ErrInvalidNotice
+	}
+
+	if !ValidWindow(nb.LeadTime, nb.Horizon) {
+		return Business{}, ErrInvalidWindow
+	}
+
+	offsets := nb.ReminderOffsets
+	if offsets == nil {
+		offsets = DefaultReminderOffsets
+	}
+
+	if !ValidReminderOffsets(offsets) {
+		return Business{}, ErrInvalidOffset
+	}
+
 	now := time.Now()
 
 	bsn := Business{
-		ID:          uuid.New(),
-		OwnerID:     nb.OwnerID,
-		Name:        nb.Name,
-		Desc:        nb.Desc,
-		DateCreated: now,
-		DateUpdated: now,
+		ID:                 uuid.New(),
+		OwnerID:            nb.OwnerID,
+		Name:               nb.Name,
+		Desc:               nb.Desc,
+		TimeZone:           nb.TimeZone,
+		CancellationNotice: nb.CancellationNotice,
+		RescheduleNotice:   nb.RescheduleNotice,
+		LeadTime:           nb.LeadTime,
+		Horizon:            nb.Horizon,
+		ReminderOffsets:    sortReminderOffsets(offsets),
+		DateCreated:        now,
+		DateUpdated:        now,
 	}
 
 	if err := c.storer.Create(ctx, bsn); err != nil {
@@ -108,6 +138,44 @@ func (c *Core) Update(ctx context.Context, b Business, ub UpdateBusiness) (Busin
 		b.Desc = *ub.Desc
 	}
 
+	if ub.TimeZone != nil {
+		b.TimeZone = *ub.TimeZone
+	}
+
+	if ub.CancellationNotice != nil {
+		if *ub.CancellationNotice < 0 {
+			return Business{}, ErrInvalidNotice
+		}
+		b.CancellationNotice = *ub.CancellationNotice
+	}
+
+	if ub.RescheduleNotice != nil {
+		if *ub.RescheduleNotice < 0 {
+			return Business{}, ErrInvalidNotice
+		}
+		b.RescheduleNotice = *ub.RescheduleNotice
+	}
+
+	if ub.LeadTime != nil {
+		b.LeadTime = *ub.LeadTime
+	}
+
+	if ub.Horizon != nil {
+		b.Horizon = *ub.Horizon
+	}
+
+	if !ValidWindow(b.LeadTime, b.Horizon) {
+		return Business{}, ErrInvalidWindow
+	}
+
+	// New offsets apply to appointments booked or moved from now on.
+	if ub.ReminderOffsets != nil {
+		if !ValidReminderOffsets(*ub.ReminderOffsets) {
+			return Business{}, ErrInvalidOffset
+		}
+		b.ReminderOffsets = sortReminderOffsets(*ub.ReminderOffsets)
+	}
+
 	b.DateUpdated = time.Now()
 
 	if err := c.storer.Update(ctx, b); err != nil {
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"slices"
 	"testing"
 	"time"
 
@@ -43,6 +44,36 @@ func TestMain(m *testing.M) {
 
 func Test_Business(t *testing.T) {
 	t.Run("crud", crud)
+	t.Run("timezone", timezone)
+}
+
+func timezone(t *testing.T) {
+	tz, err := business.ParseTimeZone("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Should be able to parse time zone: %s", err)
+	}
+
+	if tz.Name() != "Europe/Berlin" {
+		t.Errorf("Should get back the same time zone name: got %s", tz.Name())
+	}
+
+	// 2024-01-15 is in CET (UTC+1), 2024-07-15 is in CEST (UTC+2).
+	for _, tt := range []struct {
+		date   time.Time
+		offset int
+	}{
+		{time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC), 60 * 60},
+		{time.Date(2024, time.July, 15, 12, 0, 0, 0, time.UTC), 2 * 60 * 60},
+	} {
+		_, offset := tt.date.In(tz.Location()).Zone()
+		if offset != tt.offset {
+			t.Errorf("Should get the zone offset for %s: got %d, exp %d", tt.date.Format(time.DateOnly), offset, tt.offset)
+		}
+	}
+
+	if _, err := business.ParseTimeZone("Mars/Olympus"); err == nil {
+		t.Errorf("Should not be able to parse an unknown time zone")
+	}
 }
 
 func crud(t *testing.T) {
@@ -161,6 +192,11 @@ func crud(t *testing.T) {
 		t.Errorf("GOT: %s\n", b.Desc)
 		t.Errorf("EXP: %s\n", nb.Desc)
 	}
+	if !b.TimeZone.Equal(business.TimeZoneUTC) {
+		t.Error("Should default to UTC time zone.")
+		t.Errorf("GOT: %s\n", b.TimeZone.Name())
+		t.Errorf("EXP: %s\n", business.TimeZoneUTC.Name())
+	}
 	if time.Now().UnixMilli()-b.DateCreated.UnixMilli() > time.Second.Milliseconds() {
 		t.Error("Should be created just recently.")
 		t.Errorf("GOT: %s\n", b.DateCreated)
@@ -200,6 +236,95 @@ func crud(t *testing.T) {
 		t.Errorf("GOT: %s\n", b.Name)
 	}
 
+	tz, err := business.ParseTimeZone("Asia/Tehran")
+	if err != nil {
+		t.Fatalf("Should be able to parse time zone: %s", err)
+	}
+
+	b, err = api.Business.Update(ctx, b, business.UpdateBusiness{TimeZone: &tz})
+	if err != nil {
+		t.Fatalf("Should be able to update business time zone: %s", err)
+	}
+
+	saved, err = api.Business.QueryByID(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve business by ID: %s", err)
+	}
+
+	if !saved.TimeZone.Equal(tz) {
+		t.Error("Should have the new time zone")
+		t.Errorf("EXP: %s\n", tz.Name())
+		t.Errorf("GOT: %s\n", saved.TimeZone.Name())
+	}
+
+	negative := -time.Hour
+	if _, err := api.Business.Update(ctx, b, business.UpdateBusiness{CancellationNotice: &negative}); !errors.Is(err, business.ErrInvalidNotice) {
+		t.Error("Should reject a negative notice")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", business.ErrInvalidNotice)
+	}
+
+	notice := 12 * time.Hour
+	b, err = api.Business.Update(ctx, b, business.UpdateBusiness{CancellationNotice: &notice, RescheduleNotice: &notice})
+	if err != nil {
+		t.Fatalf("Should be able to update business notice: %s", err)
+	}
+
+	saved, err = api.Business.QueryByID(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve business by ID: %s", err)
+	}
+
+	if saved.CancellationNotice != notice || saved.RescheduleNotice != notice {
+		t.Error("Should have the new notice")
+		t.Errorf("EXP: %s\n", notice)
+		t.Errorf("GOT: %s %s\n", saved.CancellationNotice, saved.RescheduleNotice)
+	}
+
+	lead, horizon := 48*time.Hour, 24*time.Hour
+	if _, err := api.Business.Update(ctx, b, business.UpdateBusiness{LeadTime: &lead, Horizon: &horizon}); !errors.Is(err, business.ErrInvalidWindow) {
+		t.Error("Should reject a horizon before the lead time")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", business.ErrInvalidWindow)
+	}
+
+	if !slices.Equal(b.ReminderOffsets, business.DefaultReminderOffsets) {
+		t.Error("Should default to reminding as the appointment starts")
+		t.Errorf("EXP: %v\n", business.DefaultReminderOffsets)
+		t.Errorf("GOT: %v\n", b.ReminderOffsets)
+	}
+
+	offsets := []time.Duration{time.Hour, 24 * time.Hour}
+	b, err = api.Business.Update(ctx, b, business.UpdateBusiness{ReminderOffsets: &offsets})
+	if err != nil {
+		t.Fatalf("Should be able to update business reminder offsets: %s", err)
+	}
+
+	saved, err = api.Business.QueryByID(ctx, b.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve business by ID: %s", err)
+	}
+
+	if exp := []time.Duration{24 * time.Hour, time.Hour}; !slices.Equal(saved.ReminderOffsets, exp) {
+		t.Error("Should have the new reminder offsets, earliest first")
+		t.Errorf("EXP: %v\n", exp)
+		t.Errorf("GOT: %v\n", saved.ReminderOffsets)
+	}
+
+	duplicate := []time.Duration{time.Hour, time.Hour}
+	if _, err := api.Business.Update(ctx, b, business.UpdateBusiness{ReminderOffsets: &duplicate}); !errors.Is(err, business.ErrInvalidOffset) {
+		t.Error("Should reject duplicate reminder offsets")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", business.ErrInvalidOffset)
+	}
+
+	short := []time.Duration{0, 30 * time.Second}
+	if _, err := api.Business.Update(ctx, b, business.UpdateBusiness{ReminderOffsets: &short}); !errors.Is(err, business.ErrInvalidOffset) {
+		t.Error("Should reject reminder offsets under a minute other than zero")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", business.ErrInvalidOffset)
+	}
+
 	// -------------------------------------------------------------------
 	// Delete
 
@@ -1,27 +1,91 @@
 package business
 
 import (
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Business is who appointments are booked with. Customers may cancel or reschedule
+// an appointment only up to CancellationNotice or RescheduleNotice before it starts.
+// LeadTime and Horizon bound how far ahead appointments may be booked, a zero horizon
+// meaning no limit. Customers are reminded of their appointments ReminderOffsets
+// before each starts, earliest first.
 type Business struct {
-	ID          uuid.UUID
-	OwnerID     uuid.UUID
-	Name        string
-	Desc        string
-	DateCreated time.Time
-	DateUpdated time.Time
+	ID                 uuid.UUID
+	OwnerID            uuid.UUID
+	Name               string
+	Desc               string
+	TimeZone           TimeZone
+	CancellationNotice time.Duration
+	RescheduleNotice   time.Duration
+	LeadTime           time.Duration
+	Horizon            time.Duration
+	ReminderOffsets    []time.Duration
+	DateCreated        time.Time
+	DateUpdated        time.Time
 }
 
 type NewBusiness struct {
-	OwnerID uuid.UUID
-	Name    string
-	Desc    string
+	OwnerID            uuid.UUID
+	Name               string
+	Desc               string
+	TimeZone           TimeZone
+	CancellationNotice time.Duration
+	RescheduleNotice   time.Duration
+	LeadTime           time.Duration
+	Horizon            time.Duration
+	ReminderOffsets    []time.Duration
 }
 
 type UpdateBusiness struct {
-	Name *string
-	Desc *string
+	Name               *string
+	Desc               *string
+	TimeZone           *TimeZone
+	CancellationNotice *time.Duration
+	RescheduleNotice   *time.Duration
+	LeadTime           *time.Duration
+	Horizon            *time.Duration
+	ReminderOffsets    *[]time.Duration
+}
+
+// ValidWindow reports whether lead and horizon bound a window to book in: the lead
+// time is not negative and a horizon, if any, lies beyond it.
+func ValidWindow(lead time.Duration, horizon time.Duration) bool {
+	return lead >= 0 && horizon >= 0 && (horizon == 0 || horizon > lead)
+}
+
+// MaxReminders is the most reminders customers get of an appointment.
+const MaxReminders = 5
+
+// DefaultReminderOffsets remind customers as their appointment starts, the single
+// reminder they always had, unless their business sets its own.
+var DefaultReminderOffsets = []time.Duration{0}
+
+// ValidReminderOffsets reports whether offsets remind at distinct times, as an
+// appointment starts or at least a minute before, at most MaxReminders of them. No
+// offsets means no reminders.
+func ValidReminderOffsets(offsets []time.Duration) bool {
+	if len(offsets) > MaxReminders {
+		return false
+	}
+
+	for i, o := range offsets {
+		if (o != 0 && o < time.Minute) || slices.Contains(offsets[:i], o) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortReminderOffsets returns a copy of offsets, earliest reminder first.
+func sortReminderOffsets(offsets []time.Duration) []time.Duration {
+	sorted := slices.Clone(offsets)
+	slices.SortFunc(sorted, func(a, b time.Duration) int {
+		return int(b - a)
+	})
+
+	return sorted
 }
@@ -45,9 +45,9 @@ func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (business.St
 func (s *Store) Create(ctx context.Context, b business.Business) error {
 	const q = `
 	INSERT INTO businesses
-		(business_id, owner_id, name, description, date_created, date_updated)
+		(business_id, owner_id, name, description, time_zone, cancellation_notice, reschedule_notice, lead_time, horizon, reminder_offsets, date_created, date_updated)
 	VALUES
-		(:business_id, :owner_id, :name, :description, :date_created, :date_updated)	
+		(:business_id, :owner_id, :name, :description, :time_zone, :cancellation_notice, :reschedule_notice, :lead_time, :horizon, :reminder_offsets, :date_created, :date_updated)	
 	`
 
 	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBBusiness(b)); err != nil {
@@ -64,6 +64,12 @@ func (s *Store) Update(ctx context.Context, b business.Business) error {
 	SET
 		"name" = :name,
 		"description" = :description,
+		"time_zone" = :time_zone,
+		"cancellation_notice" = :cancellation_notice,
+		"reschedule_notice" = :reschedule_notice,
+		"lead_time" = :lead_time,
+		"horizon" = :horizon,
+		"reminder_offsets" = :reminder_offsets,
 		"date_updated" = :date_updated
 	WHERE
 		business_id = :business_id
@@ -104,7 +110,7 @@ func (s *Store) Query(ctx context.Context, filter business.QueryFilter, orderBy
 
 	const q = `
 	SELECT
-		business_id, owner_id, name, description, date_created, date_updated
+		business_id, owner_id, name, description, time_zone, cancellation_notice, reschedule_notice, lead_time, horizon, reminder_offsets, date_created, date_updated
 	FROM
 		businesses
 	`
@@ -125,7 +131,10 @@ func (s *Store) Query(ctx context.Context, filter business.QueryFilter, orderBy
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
-	bsns := toCoreBusinessSlice(dbBsns)
+	bsns, err := toCoreBusinessSlice(dbBsns)
+	if err != nil {
+		return nil, err
+	}
 
 	return bsns, nil
 }
@@ -162,7 +171,7 @@ func (s *Store) QueryByID(ctx context.Context, bsnID uuid.UUID) (business.Busine
 
 	const q = `
 	SELECT
-		business_id, owner_id, name, description, date_created, date_updated
+		business_id, owner_id, name, description, time_zone, cancellation_notice, reschedule_notice, lead_time, horizon, reminder_offsets, date_created, date_updated
 	FROM
 		businesses
 	WHERE
@@ -177,7 +186,12 @@ func (s *Store) QueryByID(ctx context.Context, bsnID uuid.UUID) (business.Busine
 		return business.Business{}, fmt.Errorf("namedquerystruct: %w", err)
 	}
 
-	return toCoreBusiness(dbBsn), nil
+	bsn, err := toCoreBusiness(dbBsn)
+	if err != nil {
+		return business.Business{}, err
+	}
+
+	return bsn, nil
 }
 
 func (s *Store) QueryByOwnerID(ctx context.Context, owrID uuid.UUID) ([]business.Business, error) {
@@ -189,7 +203,7 @@ func (s *Store) QueryByOwnerID(ctx context.Context, owrID uuid.UUID) ([]business
 
 	const q = `
 	SELECT
-		business_id, owner_id, name, description, date_created, date_updated
+		business_id, owner_id, name, description, time_zone, cancellation_notice, reschedule_notice, lead_time, horizon, reminder_offsets, date_created, date_updated
 	FROM
 		businesses
 	WHERE
@@ -201,5 +215,10 @@ func (s *Store) QueryByOwnerID(ctx context.Context, owrID uuid.UUID) ([]business
 		return nil, fmt.Errorf("namedqueryslice: %w", err)
 	}
 
-	return toCoreBusinessSlice(dbBsns), nil
+	bsns, err := toCoreBusinessSlice(dbBsns)
+	if err != nil {
+		return nil, err
+	}
+
+	return bsns, nil
 }
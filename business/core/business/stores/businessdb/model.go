@@ -1,50 +1,97 @@
 package businessdb
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/data/dbsql/pgx/dbarray"
 	"github.com/google/uuid"
 )
 
 type dbBusiness struct {
-	ID          uuid.UUID `db:"business_id"`
-	OwnerID     uuid.UUID `db:"owner_id"`
-	Name        string    `db:"name"`
-	Desc        string    `db:"description"`
-	DateCreated time.Time `db:"date_created"`
-	DateUpdated time.Time `db:"date_updated"`
+	ID                 uuid.UUID     `db:"business_id"`
+	OwnerID            uuid.UUID     `db:"owner_id"`
+	Name               string        `db:"name"`
+	Desc               string        `db:"description"`
+	TimeZone           string        `db:"time_zone"`
+	CancellationNotice int           `db:"cancellation_notice"`
+	RescheduleNotice   int           `db:"reschedule_notice"`
+	LeadTime           int           `db:"lead_time"`
+	Horizon            int           `db:"horizon"`
+	ReminderOffsets    dbarray.Int64 `db:"reminder_offsets"`
+	DateCreated        time.Time     `db:"date_created"`
+	DateUpdated        time.Time     `db:"date_updated"`
 }
 
 func toDBBusiness(b business.Business) dbBusiness {
 	return dbBusiness{
-		ID:          b.ID,
-		OwnerID:     b.OwnerID,
-		Name:        b.Name,
-		Desc:        b.Desc,
-		DateCreated: b.DateCreated.UTC(),
-		DateUpdated: b.DateUpdated.UTC(),
+		ID:                 b.ID,
+		OwnerID:            b.OwnerID,
+		Name:               b.Name,
+		Desc:               b.Desc,
+		TimeZone:           b.TimeZone.Name(),
+		CancellationNotice: int(b.CancellationNotice / time.Second),
+		RescheduleNotice:   int(b.RescheduleNotice / time.Second),
+		LeadTime:           int(b.LeadTime / time.Second),
+		Horizon:            int(b.Horizon / time.Second),
+		ReminderOffsets:    toDBSeconds(b.ReminderOffsets),
+		DateCreated:        b.DateCreated.UTC(),
+		DateUpdated:        b.DateUpdated.UTC(),
 	}
 }
 
-func toCoreBusiness(dbBsn dbBusiness) business.Business {
+func toCoreBusiness(dbBsn dbBusiness) (business.Business, error) {
+	tz, err := business.ParseTimeZone(dbBsn.TimeZone)
+	if err != nil {
+		return business.Business{}, fmt.Errorf("parse time zone: %w", err)
+	}
+
 	b := business.Business{
-		ID:          dbBsn.ID,
-		OwnerID:     dbBsn.OwnerID,
-		Name:        dbBsn.Name,
-		Desc:        dbBsn.Desc,
-		DateCreated: dbBsn.DateCreated.In(time.Local),
-		DateUpdated: dbBsn.DateUpdated.In(time.Local),
+		ID:                 dbBsn.ID,
+		OwnerID:            dbBsn.OwnerID,
+		Name:               dbBsn.Name,
+		Desc:               dbBsn.Desc,
+		TimeZone:           tz,
+		CancellationNotice: time.Duration(dbBsn.CancellationNotice) * time.Second,
+		RescheduleNotice:   time.Duration(dbBsn.RescheduleNotice) * time.Second,
+		LeadTime:           time.Duration(dbBsn.LeadTime) * time.Second,
+		Horizon:            time.Duration(dbBsn.Horizon) * time.Second,
+		ReminderOffsets:    toCoreSeconds(dbBsn.ReminderOffsets),
+		DateCreated:        dbBsn.DateCreated.In(time.Local),
+		DateUpdated:        dbBsn.DateUpdated.In(time.Local),
 	}
 
-	return b
+	return b, nil
 }
 
-func toCoreBusinessSlice(dbBsns []dbBusiness) []business.Business {
+func toCoreBusinessSlice(dbBsns []dbBusiness) ([]business.Business, error) {
 	bsns := make([]business.Business, len(dbBsns))
 	for i, b := range dbBsns {
-		bsns[i] = toCoreBusiness(b)
+		var err error
+		bsns[i], err = toCoreBusiness(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bsns, nil
+}
+
+func toDBSeconds(ds []time.Duration) dbarray.Int64 {
+	secs := make(dbarray.Int64, len(ds))
+	for i, d := range ds {
+		secs[i] = int64(d / time.Second)
+	}
+
+	return secs
+}
+
+func toCoreSeconds(secs dbarray.Int64) []time.Duration {
+	ds := make([]time.Duration, len(secs))
+	for i, s := range secs {
+		ds[i] = time.Duration(s) * time.Second
 	}
 
-	return bsns
+	return ds
 }
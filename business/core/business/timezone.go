@@ -0,0 +1,61 @@
+package business
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeZoneUTC is the time zone of businesses which didn't specify one.
+var TimeZoneUTC = TimeZone{loc: time.UTC}
+
+// TimeZone is the IANA time zone a business operates in. Agendas are interpreted
+// as wall clock time of this zone.
+type TimeZone struct {
+	loc *time.Location
+}
+
+func ParseTimeZone(value string) (TimeZone, error) {
+	if value == "" || value == "Local" {
+		return TimeZone{}, errors.New("time zone must be a valid IANA name")
+	}
+
+	loc, err := time.LoadLocation(value)
+	if err != nil {
+		return TimeZone{}, fmt.Errorf("invalid time zone: %q: %w", value, err)
+	}
+
+	return TimeZone{loc: loc}, nil
+}
+
+// Location returns the location of the time zone, falling back to UTC when
+// it's not set.
+func (tz TimeZone) Location() *time.Location {
+	if tz.loc == nil {
+		return time.UTC
+	}
+
+	return tz.loc
+}
+
+func (tz TimeZone) Name() string {
+	return tz.Location().String()
+}
+
+func (tz *TimeZone) UnmarshalText(data []byte) error {
+	t, err := ParseTimeZone(string(data))
+	if err != nil {
+		return err
+	}
+
+	tz.loc = t.loc
+	return nil
+}
+
+func (tz TimeZone) MarshalText() ([]byte, error) {
+	return []byte(tz.Name()), nil
+}
+
+func (tz TimeZone) Equal(tz2 TimeZone) bool {
+	return tz.Name() == tz2.Name()
+}
@@ -0,0 +1,61 @@
+package resource
+
+import "fmt"
+
+var (
+	KindStaff = Kind{"STAFF"}
+	KindRoom  = Kind{"ROOM"}
+)
+
+var kinds = map[string]Kind{
+	KindStaff.name: KindStaff,
+	KindRoom.name:  KindRoom,
+}
+
+// Kind tells what a resource is: a member of the staff, or a chair, room or any
+// other place an appointment is held in.
+type Kind struct {
+	name string
+}
+
+func ParseKind(value string) (Kind, error) {
+	kind, exists := kinds[value]
+	if !exists {
+		return Kind{}, fmt.Errorf("invalid kind %q", value)
+	}
+
+	return kind, nil
+}
+
+// MustParseKind parses the string value and returns a kind if one exists. If
+// an error occurs the function panics. ONLY use it when writing TESTS.
+func MustParseKind(value string) Kind {
+	kind, err := ParseKind(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return kind
+}
+
+func (k Kind) Name() string {
+	return k.name
+}
+
+func (k *Kind) UnmarshalText(data []byte) error {
+	kind, err := ParseKind(string(data))
+	if err != nil {
+		return err
+	}
+
+	k.name = kind.name
+	return nil
+}
+
+func (k Kind) MarshalText() ([]byte, error) {
+	return []byte(k.name), nil
+}
+
+func (k Kind) Equal(k2 Kind) bool {
+	return k.name == k2.name
+}
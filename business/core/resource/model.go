@@ -0,0 +1,29 @@
+package resource
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Resource is a member of the staff, or a chair or room, of a business that
+// appointments are booked against. Each resource takes one appointment at a time.
+type Resource struct {
+	ID          uuid.UUID
+	BusinessID  uuid.UUID
+	Name        string
+	Kind        Kind
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type NewResource struct {
+	BusinessID uuid.UUID
+	Name       string
+	Kind       Kind
+}
+
+type UpdateResource struct {
+	Name *string
+	Kind *Kind
+}
@@ -0,0 +1,12 @@
+package resource
+
+import "github.com/ameghdadian/service/business/data/order"
+
+var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
+
+const (
+	OrderByID         = "resource_id"
+	OrderByBusinessID = "business_id"
+	OrderByName       = "name"
+	OrderByKind       = "kind"
+)
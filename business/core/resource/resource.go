@@ -0,0 +1,169 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/data/order"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound   = errors.New("resource not found")
+	ErrUniqueName = errors.New("resource name already exists for this business")
+	ErrInUse      = errors.New("resource still has appointments")
+)
+
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, rsc Resource) error
+	Update(ctx context.Context, rsc Resource) error
+	Delete(ctx context.Context, rsc Resource) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Resource, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, rscID uuid.UUID) (Resource, error)
+	QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]Resource, error)
+}
+
+type Core struct {
+	storer  Storer
+	log     *logger.Logger
+	bsnCore *business.Core
+}
+
+func NewCore(log *logger.Logger, bsnCore *business.Core, storer Storer) *Core {
+	return &Core{
+		storer:  storer,
+		log:     log,
+		bsnCore: bsnCore,
+	}
+}
+
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bsnCore, err := c.bsnCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		storer:  storer,
+		log:     c.log,
+		bsnCore: bsnCore,
+	}
+
+	return c, nil
+}
+
+func (c *Core) Create(ctx context.Context, nr NewResource) (Resource, error) {
+	ctx, span := otel.AddSpan(ctx, "business.resource.create")
+	defer span.End()
+
+	bsn, err := c.bsnCore.QueryByID(ctx, nr.BusinessID)
+	if err != nil {
+		return Resource{}, fmt.Errorf("business.querybyid: %s: %w", nr.BusinessID, err)
+	}
+
+	now := time.Now()
+
+	rsc := Resource{
+		ID:          uuid.New(),
+		BusinessID:  bsn.ID,
+		Name:        nr.Name,
+		Kind:        nr.Kind,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, rsc); err != nil {
+		return Resource{}, fmt.Errorf("create: %w", err)
+	}
+
+	return rsc, nil
+}
+
+func (c *Core) Update(ctx context.Context, rsc Resource, ur UpdateResource) (Resource, error) {
+	ctx, span := otel.AddSpan(ctx, "business.resource.update")
+	defer span.End()
+
+	if ur.Name != nil {
+		rsc.Name = *ur.Name
+	}
+
+	if ur.Kind != nil {
+		rsc.Kind = *ur.Kind
+	}
+
+	rsc.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, rsc); err != nil {
+		return Resource{}, fmt.Errorf("update: %w", err)
+	}
+
+	return rsc, nil
+}
+
+func (c *Core) Delete(ctx context.Context, rsc Resource) error {
+	ctx, span := otel.AddSpan(ctx, "business.resource.delete")
+	defer span.End()
+
+	if err := c.storer.Delete(ctx, rsc); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Resource, error) {
+	ctx, span := otel.AddSpan(ctx, "business.resource.query")
+	defer span.End()
+
+	rscs, err := c.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return rscs, nil
+}
+
+func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.resource.count")
+	defer span.End()
+
+	return c.storer.Count(ctx, filter)
+}
+
+func (c *Core) QueryByID(ctx context.Context, rscID uuid.UUID) (Resource, error) {
+	ctx, span := otel.AddSpan(ctx, "business.resource.querybyid")
+	defer span.End()
+
+	rsc, err := c.storer.QueryByID(ctx, rscID)
+	if err != nil {
+		return Resource{}, fmt.Errorf("query: resourceID[%s]: %w", rscID, err)
+	}
+
+	return rsc, nil
+}
+
+func (c *Core) QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]Resource, error) {
+	ctx, span := otel.AddSpan(ctx, "business.resource.querybybusinessid")
+	defer span.End()
+
+	rscs, err := c.storer.QueryByBusinessID(ctx, bsnID)
+	if err != nil {
+		return nil, fmt.Errorf("query: businessID[%s]: %w", bsnID, err)
+	}
+
+	return rscs, nil
+}
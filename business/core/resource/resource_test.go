@@ -0,0 +1,211 @@
+package resource_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/data/dbtest"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/business/data/redistest"
+	"github.com/ameghdadian/service/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+var c *docker.Container
+var rc *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	fmt.Println("Starting a new database")
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	fmt.Println("Starting a new redis")
+	rc, err = redistest.StartRedis()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer redistest.StopRedis(rc)
+	m.Run()
+}
+
+func Test_Resource(t *testing.T) {
+	t.Run("crud", crud)
+}
+
+func crud(t *testing.T) {
+	seed := func(ctx context.Context, rscCore *resource.Core, bsnCore *business.Core, usrCore *user.Core) ([]resource.Resource, error) {
+		var filter user.QueryFilter
+		filter.WithName("Admin Gopher")
+
+		pagination := page.MustParse("1", "1")
+		usrs, err := usrCore.Query(ctx, filter, user.DefaultOrderBy, pagination)
+		if err != nil {
+			return nil, fmt.Errorf("seeding users: %w", err)
+		}
+
+		bsns, err := business.TestGenerateSeedBusinesses(1, bsnCore, usrs[0].ID)
+		if err != nil {
+			return nil, fmt.Errorf("seeding businesses: %w", err)
+		}
+
+		rscs, err := resource.TestGenerateSeedResources(2, rscCore, bsns[0].ID)
+		if err != nil {
+			return nil, fmt.Errorf("seeding resources: %w", err)
+		}
+
+		return rscs, nil
+	}
+
+	// -------------------------------------------------------------------
+
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Log("Go seeding ...")
+
+	rscs, err := seed(ctx, api.Resource, api.Business, api.User)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------
+	// Count
+
+	var filter resource.QueryFilter
+	filter.WithBusinessID(rscs[0].BusinessID)
+	n, err := api.Resource.Count(ctx, filter)
+	if err != nil {
+		t.Fatalf("Should be able to count resources")
+	}
+
+	if n != 2 {
+		t.Error("Should have the correct number of resources")
+		t.Errorf("GOT: %d\n", n)
+		t.Errorf("EXP: %d\n", 2)
+	}
+
+	// -------------------------------------------------------------------
+	// QueryByID
+
+	saved, err := api.Resource.QueryByID(ctx, rscs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve resource by ID: %s", err)
+	}
+
+	if rscs[0].DateCreated.UnixMilli() != saved.DateCreated.UnixMilli() {
+		t.Logf("GOT: %v", saved.DateCreated)
+		t.Logf("EXP: %v", rscs[0].DateCreated)
+		t.Errorf("Should get back the same date created")
+	}
+
+	if rscs[0].DateUpdated.UnixMilli() != saved.DateUpdated.UnixMilli() {
+		t.Logf("GOT: %v", saved.DateUpdated)
+		t.Logf("EXP: %v", rscs[0].DateUpdated)
+		t.Errorf("Should get back the same date updated")
+	}
+
+	rscs[0].DateCreated = time.Time{}
+	rscs[0].DateUpdated = time.Time{}
+	saved.DateCreated = time.Time{}
+	saved.DateUpdated = time.Time{}
+
+	if diff := cmp.Diff(rscs[0], saved); diff != "" {
+		t.Errorf("Should get back the same resource, diff:\n%s", diff)
+	}
+
+	// -------------------------------------------------------------------
+	// Create
+
+	nr := resource.NewResource{
+		BusinessID: rscs[0].BusinessID,
+		Name:       "Chair 1",
+		Kind:       resource.KindRoom,
+	}
+
+	rsc, err := api.Resource.Create(ctx, nr)
+	if err != nil {
+		t.Fatalf("Should be able to create a resource: %s", err)
+	}
+
+	if rsc.ID == uuid.Nil {
+		t.Error("Should have a valid resource id")
+	}
+
+	if _, err := api.Resource.Create(ctx, nr); !errors.Is(err, resource.ErrUniqueName) {
+		t.Error("Should reject a second resource with the same name")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", resource.ErrUniqueName)
+	}
+
+	// -------------------------------------------------------------------
+	// QueryByBusinessID
+
+	savedRscs, err := api.Resource.QueryByBusinessID(ctx, rscs[0].BusinessID)
+	if err != nil {
+		t.Fatalf("Should be able to query resources by business ID: %s", err)
+	}
+
+	if len(savedRscs) != 3 {
+		t.Errorf("Should have 3 resources: BusinessID[%s]\n", rscs[0].BusinessID)
+		t.Errorf("GOT: %d\n", len(savedRscs))
+		t.Errorf("EXP: %d\n", 3)
+	}
+
+	// -------------------------------------------------------------------
+	// Update
+
+	name := "Chair 2"
+	ur := resource.UpdateResource{Name: &name}
+	rsc, err = api.Resource.Update(ctx, rsc, ur)
+	if err != nil {
+		t.Fatalf("Should be able to update resource: %s", err)
+	}
+
+	saved, err = api.Resource.QueryByID(ctx, rsc.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve resource by ID: %s", err)
+	}
+
+	if saved.Name != name {
+		t.Error("Should be able to see updates to Name")
+		t.Errorf("GOT: %s\n", saved.Name)
+		t.Errorf("EXP: %s\n", name)
+	}
+
+	// -------------------------------------------------------------------
+	// Delete
+
+	if err := api.Resource.Delete(ctx, rsc); err != nil {
+		t.Fatalf("Should be able to delete resource: %s", err)
+	}
+
+	_, err = api.Resource.QueryByID(ctx, rsc.ID)
+	if !errors.Is(err, resource.ErrNotFound) {
+		t.Errorf("Should NOT be able to retrieve resource: %s", err)
+	}
+}
@@ -0,0 +1,48 @@
+package resourcedb
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ameghdadian/service/business/core/resource"
+)
+
+func (s *Store) applyFilter(filter resource.QueryFilter, data map[string]any, buf *bytes.Buffer) {
+	var wc []string
+
+	if filter.ID != nil {
+		data["resource_id"] = *filter.ID
+		wc = append(wc, "resource_id = :resource_id")
+	}
+
+	if filter.BusinessID != nil {
+		data["business_id"] = *filter.BusinessID
+		wc = append(wc, "business_id = :business_id")
+	}
+
+	if filter.Name != nil {
+		data["name"] = fmt.Sprintf("%%%s%%", *filter.Name)
+		wc = append(wc, "name LIKE :name")
+	}
+
+	if filter.Kind != nil {
+		data["kind"] = filter.Kind.Name()
+		wc = append(wc, "kind = :kind")
+	}
+
+	if filter.StartCreatedDate != nil {
+		data["start_date_created"] = *filter.StartCreatedDate
+		wc = append(wc, "date_created >= :start_date_created")
+	}
+
+	if filter.EndCreatedDate != nil {
+		data["end_date_created"] = *filter.EndCreatedDate
+		wc = append(wc, "date_created <= :end_date_created")
+	}
+
+	if len(wc) > 0 {
+		buf.WriteString(" WHERE ")
+		buf.WriteString(strings.Join(wc, " AND "))
+	}
+}
@@ -0,0 +1,60 @@
+package resourcedb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/google/uuid"
+)
+
+type dbResource struct {
+	ID          uuid.UUID `db:"resource_id"`
+	BusinessID  uuid.UUID `db:"business_id"`
+	Name        string    `db:"name"`
+	Kind        string    `db:"kind"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBResource(rsc resource.Resource) dbResource {
+	return dbResource{
+		ID:          rsc.ID,
+		BusinessID:  rsc.BusinessID,
+		Name:        rsc.Name,
+		Kind:        rsc.Kind.Name(),
+		DateCreated: rsc.DateCreated.UTC(),
+		DateUpdated: rsc.DateUpdated.UTC(),
+	}
+}
+
+func toCoreResource(dbRsc dbResource) (resource.Resource, error) {
+	kind, err := resource.ParseKind(dbRsc.Kind)
+	if err != nil {
+		return resource.Resource{}, fmt.Errorf("parse kind: %w", err)
+	}
+
+	rsc := resource.Resource{
+		ID:          dbRsc.ID,
+		BusinessID:  dbRsc.BusinessID,
+		Name:        dbRsc.Name,
+		Kind:        kind,
+		DateCreated: dbRsc.DateCreated.In(time.Local),
+		DateUpdated: dbRsc.DateUpdated.In(time.Local),
+	}
+
+	return rsc, nil
+}
+
+func toCoreResourceSlice(dbRscs []dbResource) ([]resource.Resource, error) {
+	rscs := make([]resource.Resource, len(dbRscs))
+	for i, r := range dbRscs {
+		var err error
+		rscs[i], err = toCoreResource(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rscs, nil
+}
@@ -0,0 +1,24 @@
+package resourcedb
+
+import (
+	"fmt"
+
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	resource.OrderByID:         "resource_id",
+	resource.OrderByBusinessID: "business_id",
+	resource.OrderByName:       "name",
+	resource.OrderByKind:       "kind",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
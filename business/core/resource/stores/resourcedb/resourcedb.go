@@ -0,0 +1,231 @@
+package resourcedb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ameghdadian/service/business/core/resource"
+	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
+	"github.com/ameghdadian/service/business/data/order"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (resource.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		db:  ec,
+		log: s.log,
+	}
+
+	return s, nil
+}
+
+func (s *Store) Create(ctx context.Context, rsc resource.Resource) error {
+	const q = `
+	INSERT INTO resources
+		(resource_id, business_id, name, kind, date_created, date_updated)
+	VALUES
+		(:resource_id, :business_id, :name, :kind, :date_created, :date_updated)
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBResource(rsc)); err != nil {
+		if errors.Is(err, db.ErrDBDuplicateEntry) {
+			return fmt.Errorf("namedexeccontext: %w", resource.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Update(ctx context.Context, rsc resource.Resource) error {
+	const q = `
+	UPDATE
+		resources
+	SET
+		"name" = :name,
+		"kind" = :kind,
+		"date_updated" = :date_updated
+	WHERE
+		resource_id = :resource_id
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBResource(rsc)); err != nil {
+		if errors.Is(err, db.ErrDBDuplicateEntry) {
+			return fmt.Errorf("namedexeccontext: %w", resource.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Delete(ctx context.Context, rsc resource.Resource) error {
+	data := struct {
+		ResourceID string `db:"resource_id"`
+	}{
+		ResourceID: rsc.ID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		resources
+	WHERE
+		resource_id = :resource_id
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		if errors.Is(err, db.ErrDBForeignKey) {
+			return fmt.Errorf("namedexeccontext: %w", resource.ErrInUse)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Query(ctx context.Context, filter resource.QueryFilter, orderBy order.By, page page.Page) ([]resource.Resource, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		resource_id, business_id, name, kind, date_created, date_updated
+	FROM
+		resources
+	`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbRscs []dbResource
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbRscs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	rscs, err := toCoreResourceSlice(dbRscs)
+	if err != nil {
+		return nil, err
+	}
+
+	return rscs, nil
+}
+
+func (s *Store) Count(ctx context.Context, filter resource.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		COUNT(1)
+	FROM
+		resources
+	`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+func (s *Store) QueryByID(ctx context.Context, rscID uuid.UUID) (resource.Resource, error) {
+	data := struct {
+		ResourceID string `db:"resource_id"`
+	}{
+		ResourceID: rscID.String(),
+	}
+
+	const q = `
+	SELECT
+		resource_id, business_id, name, kind, date_created, date_updated
+	FROM
+		resources
+	WHERE
+		resource_id = :resource_id
+	`
+
+	var dbRsc dbResource
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbRsc); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return resource.Resource{}, fmt.Errorf("namedquerystruct: %w", resource.ErrNotFound)
+		}
+		return resource.Resource{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	rsc, err := toCoreResource(dbRsc)
+	if err != nil {
+		return resource.Resource{}, err
+	}
+
+	return rsc, nil
+}
+
+func (s *Store) QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]resource.Resource, error) {
+	data := struct {
+		BusinessID string `db:"business_id"`
+	}{
+		BusinessID: bsnID.String(),
+	}
+
+	const q = `
+	SELECT
+		resource_id, business_id, name, kind, date_created, date_updated
+	FROM
+		resources
+	WHERE
+		business_id = :business_id
+	ORDER BY
+		name
+	`
+
+	var dbRscs []dbResource
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbRscs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	rscs, err := toCoreResourceSlice(dbRscs)
+	if err != nil {
+		return nil, err
+	}
+
+	return rscs, nil
+}
@@ -0,0 +1,38 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateNewResources(n int, bsnID uuid.UUID) []NewResource {
+	newRscs := make([]NewResource, n)
+
+	for i := 0; i < n; i++ {
+		newRscs[i] = NewResource{
+			BusinessID: bsnID,
+			Name:       fmt.Sprintf("Stylist%d", i),
+			Kind:       KindStaff,
+		}
+	}
+
+	return newRscs
+}
+
+func TestGenerateSeedResources(n int, api *Core, bsnID uuid.UUID) ([]Resource, error) {
+	newRscs := TestGenerateNewResources(n, bsnID)
+
+	rscs := make([]Resource, len(newRscs))
+	for i, nr := range newRscs {
+		rsc, err := api.Create(context.Background(), nr)
+		if err != nil {
+			return nil, fmt.Errorf("seeding resource: idx: %d: %w", i, err)
+		}
+
+		rscs[i] = rsc
+	}
+
+	return rscs, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import "fmt"
+
+// Currency is an ISO 4217 alphabetic currency code, such as "EUR".
+type Currency struct {
+	code string
+}
+
+// ParseCurrency parses a three upper case letters currency code.
+func ParseCurrency(value string) (Currency, error) {
+	if len(value) != 3 {
+		return Currency{}, fmt.Errorf("invalid currency: %q", value)
+	}
+
+	for _, r := range value {
+		if r < 'A' || r > 'Z' {
+			return Currency{}, fmt.Errorf("invalid currency: %q", value)
+		}
+	}
+
+	return Currency{code: value}, nil
+}
+
+// MustParseCurrency parses a currency and panics if it's not valid.
+func MustParseCurrency(value string) Currency {
+	c, err := ParseCurrency(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+func (c Currency) Code() string {
+	return c.code
+}
+
+func (c *Currency) UnmarshalText(data []byte) error {
+	cur, err := ParseCurrency(string(data))
+	if err != nil {
+		return err
+	}
+
+	c.code = cur.code
+	return nil
+}
+
+func (c Currency) MarshalText() ([]byte, error) {
+	return []byte(c.code), nil
+}
+
+func (c Currency) Equal(c2 Currency) bool {
+	return c.code == c2.code
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"time"
+
+	"github.com/ameghdadian/service/business/core/business"
+
+	"github.com/google/uuid"
+)
+
+// Service is something a business offers to be booked, such as a haircut.
+// Price is in the minor unit of its currency, e.g. cents for EUR. Capacity is the
+// number of people a single slot of the service holds, such as the seats of a class.
+// LeadTime and Horizon, when set, bound how far ahead the service may be booked in
+// place of those of its business.
+type Service struct {
+	ID          uuid.UUID
+	BusinessID  uuid.UUID
+	Name        string
+	Duration    time.Duration
+	Price       int64
+	Currency    Currency
+	Capacity    int
+	LeadTime    time.Duration
+	Horizon     time.Duration
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type NewService struct {
+	BusinessID uuid.UUID
+	Name       string
+	Duration   time.Duration
+	Price      int64
+	Currency   Currency
+	Capacity   int
+	LeadTime   time.Duration
+	Horizon    time.Duration
+}
+
+type UpdateService struct {
+	Name     *string
+	Duration *time.Duration
+	Price    *int64
+	Currency *Currency
+	Capacity *int
+	LeadTime *time.Duration
+	Horizon  *time.Duration
+}
+
+// BookingWindow returns how far ahead the service may be booked with the business
+// offering it: no sooner than lead and, unless horizon is zero, no later than
+// horizon from now.
+func (s Service) BookingWindow(bsn business.Business) (lead time.Duration, horizon time.Duration) {
+	lead, horizon = bsn.LeadTime, bsn.Horizon
+	if s.LeadTime != 0 {
+		lead = s.LeadTime
+	}
+	if s.Horizon != 0 {
+		horizon = s.Horizon
+	}
+
+	return lead, horizon
+}
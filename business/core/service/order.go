@@ -0,0 +1,13 @@
+package service
+
+import "github.com/ameghdadian/service/business/data/order"
+
+var DefaultOrderBy = order.NewBy(OrderByID, order.ASC)
+
+const (
+	OrderByID         = "service_id"
+	OrderByBusinessID = "business_id"
+	OrderByName       = "name"
+	OrderByDuration   = "duration"
+	OrderByPrice      = "price"
+)
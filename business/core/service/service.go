@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/data/order"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrNotFound        = errors.New("service not found")
+	ErrUniqueName      = errors.New("service name already exists for this business")
+	ErrInvalidDuration = errors.New("duration must be positive")
+	ErrInvalidPrice    = errors.New("price must not be negative")
+	ErrInvalidCapacity = errors.New("capacity must be positive")
+	ErrInvalidWindow   = errors.New("lead time must not be negative and horizon must exceed it")
+)
+
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, svc Service) error
+	Update(ctx context.Context, svc Service) error
+	Delete(ctx context.Context, svc Service) error
+	Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Service, error)
+	Count(ctx context.Context, filter QueryFilter) (int, error)
+	QueryByID(ctx context.Context, svcID uuid.UUID) (Service, error)
+	QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]Service, error)
+}
+
+type Core struct {
+	storer  Storer
+	log     *logger.Logger
+	bsnCore *business.Core
+}
+
+func NewCore(log *logger.Logger, bsnCore *business.Core, storer Storer) *Core {
+	return &Core{
+		storer:  storer,
+		log:     log,
+		bsnCore: bsnCore,
+	}
+}
+
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bsnCore, err := c.bsnCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		storer:  storer,
+		log:     c.log,
+		bsnCore: bsnCore,
+	}
+
+	return c, nil
+}
+
+func (c *Core) Create(ctx context.Context, ns NewService) (Service, error) {
+	ctx, span := otel.AddSpan(ctx, "business.service.create")
+	defer span.End()
+
+	if ns.Duration <= 0 {
+		return Service{}, ErrInvalidDuration
+	}
+
+	if ns.Price < 0 {
+		return Service{}, ErrInvalidPrice
+	}
+
+	if ns.Capacity <= 0 {
+		return Service{}, ErrInvalidCapacity
+	}
+
+	if !business.ValidWindow(ns.LeadTime, ns.Horizon) {
+		return Service{}, ErrInvalidWindow
+	}
+
+	bsn, err := c.bsnCore.QueryByID(ctx, ns.BusinessID)
+	if err != nil {
+		return Service{}, fmt.Errorf("business.querybyid: %s: %w", ns.BusinessID, err)
+	}
+
+	now := time.Now()
+
+	svc := Service{
+		ID:          uuid.New(),
+		BusinessID:  bsn.ID,
+		Name:        ns.Name,
+		Duration:    ns.Duration,
+		Price:       ns.Price,
+		Currency:    ns.Currency,
+		Capacity:    ns.Capacity,
+		LeadTime:    ns.LeadTime,
+		Horizon:     ns.Horizon,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, svc); err != nil {
+		return Service{}, fmt.Errorf("create: %w", err)
+	}
+
+	return svc, nil
+}
+
+func (c *Core) Update(ctx context.Context, svc Service, us UpdateService) (Service, error) {
+	ctx, span := otel.AddSpan(ctx, "business.service.update")
+	defer span.End()
+
+	if us.Name != nil {
+		svc.Name = *us.Name
+	}
+
+	if us.Duration != nil {
+		if *us.Duration <= 0 {
+			return Service{}, ErrInvalidDuration
+		}
+		svc.Duration = *us.Duration
+	}
+
+	if us.Price != nil {
+		if *us.Price < 0 {
+			return Service{}, ErrInvalidPrice
+		}
+		svc.Price = *us.Price
+	}
+
+	if us.Currency != nil {
+		svc.Currency = *us.Currency
+	}
+
+	if us.Capacity != nil {
+		if *us.Capacity <= 0 {
+			return Service{}, ErrInvalidCapacity
+		}
+		svc.Capacity = *us.Capacity
+	}
+
+	if us.LeadTime != nil {
+		svc.LeadTime = *us.LeadTime
+	}
+
+	if us.Horizon != nil {
+		svc.Horizon = *us.Horizon
+	}
+
+	if !business.ValidWindow(svc.LeadTime, svc.Horizon) {
+		return Service{}, ErrInvalidWindow
+	}
+
+	svc.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, svc); err != nil {
+		return Service{}, fmt.Errorf("update: %w", err)
+	}
+
+	return svc, nil
+}
+
+func (c *Core) Delete(ctx context.Context, svc Service) error {
+	ctx, span := otel.AddSpan(ctx, "business.service.delete")
+	defer span.End()
+
+	if err := c.storer.Delete(ctx, svc); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Core) Query(ctx context.Context, filter QueryFilter, orderBy order.By, page page.Page) ([]Service, error) {
+	ctx, span := otel.AddSpan(ctx, "business.service.query")
+	defer span.End()
+
+	svcs, err := c.storer.Query(ctx, filter, orderBy, page)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
+	}
+
+	return svcs, nil
+}
+
+func (c *Core) Count(ctx context.Context, filter QueryFilter) (int, error) {
+	ctx, span := otel.AddSpan(ctx, "business.service.count")
+	defer span.End()
+
+	return c.storer.Count(ctx, filter)
+}
+
+func (c *Core) QueryByID(ctx context.Context, svcID uuid.UUID) (Service, error) {
+	ctx, span := otel.AddSpan(ctx, "business.service.querybyid")
+	defer span.End()
+
+	svc, err := c.storer.QueryByID(ctx, svcID)
+	if err != nil {
+		return Service{}, fmt.Errorf("query: serviceID[%s]: %w", svcID, err)
+	}
+
+	return svc, nil
+}
+
+func (c *Core) QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]Service, error) {
+	ctx, span := otel.AddSpan(ctx, "business.service.querybybusinessid")
+	defer span.End()
+
+	svcs, err := c.storer.QueryByBusinessID(ctx, bsnID)
+	if err != nil {
+		return nil, fmt.Errorf("query: businessID[%s]: %w", bsnID, err)
+	}
+
+	return svcs, nil
+}
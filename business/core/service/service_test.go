@@ -0,0 +1,235 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/data/dbtest"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/business/data/redistest"
+	"github.com/ameghdadian/service/foundation/docker"
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/uuid"
+)
+
+var c *docker.Container
+var rc *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	fmt.Println("Starting a new database")
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	fmt.Println("Starting a new redis")
+	rc, err = redistest.StartRedis()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer redistest.StopRedis(rc)
+	m.Run()
+}
+
+func Test_Service(t *testing.T) {
+	t.Run("crud", crud)
+}
+
+func crud(t *testing.T) {
+	seed := func(ctx context.Context, svcCore *service.Core, bsnCore *business.Core, usrCore *user.Core) ([]service.Service, error) {
+		var filter user.QueryFilter
+		filter.WithName("Admin Gopher")
+
+		pagination := page.MustParse("1", "1")
+		usrs, err := usrCore.Query(ctx, filter, user.DefaultOrderBy, pagination)
+		if err != nil {
+			return nil, fmt.Errorf("seeding users: %w", err)
+		}
+
+		bsns, err := business.TestGenerateSeedBusinesses(1, bsnCore, usrs[0].ID)
+		if err != nil {
+			return nil, fmt.Errorf("seeding businesses: %w", err)
+		}
+
+		svcs, err := service.TestGenerateSeedServices(2, svcCore, bsns[0].ID)
+		if err != nil {
+			return nil, fmt.Errorf("seeding services: %w", err)
+		}
+
+		return svcs, nil
+	}
+
+	// -------------------------------------------------------------------
+
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	t.Log("Go seeding ...")
+
+	svcs, err := seed(ctx, api.Service, api.Business, api.User)
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+
+	// -------------------------------------------------------------------
+	// Count
+
+	var filter service.QueryFilter
+	filter.WithBusinessID(svcs[0].BusinessID)
+	n, err := api.Service.Count(ctx, filter)
+	if err != nil {
+		t.Fatalf("Should be able to count services")
+	}
+
+	if n != 2 {
+		t.Error("Should have the correct number of services")
+		t.Errorf("GOT: %d\n", n)
+		t.Errorf("EXP: %d\n", 2)
+	}
+
+	// -------------------------------------------------------------------
+	// QueryByID
+
+	saved, err := api.Service.QueryByID(ctx, svcs[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve service by ID: %s", err)
+	}
+
+	if svcs[0].DateCreated.UnixMilli() != saved.DateCreated.UnixMilli() {
+		t.Logf("GOT: %v", saved.DateCreated)
+		t.Logf("EXP: %v", svcs[0].DateCreated)
+		t.Errorf("Should get back the same date created")
+	}
+
+	if svcs[0].DateUpdated.UnixMilli() != saved.DateUpdated.UnixMilli() {
+		t.Logf("GOT: %v", saved.DateUpdated)
+		t.Logf("EXP: %v", svcs[0].DateUpdated)
+		t.Errorf("Should get back the same date updated")
+	}
+
+	svcs[0].DateCreated = time.Time{}
+	svcs[0].DateUpdated = time.Time{}
+	saved.DateCreated = time.Time{}
+	saved.DateUpdated = time.Time{}
+
+	if diff := cmp.Diff(svcs[0], saved); diff != "" {
+		t.Errorf("Should get back the same service, diff:\n%s", diff)
+	}
+
+	// -------------------------------------------------------------------
+	// Create
+
+	ns := service.NewService{
+		BusinessID: svcs[0].BusinessID,
+		Name:       "Haircut",
+		Duration:   30 * time.Minute,
+		Price:      2500,
+		Currency:   service.MustParseCurrency("EUR"),
+		Capacity:   1,
+	}
+
+	svc, err := api.Service.Create(ctx, ns)
+	if err != nil {
+		t.Fatalf("Should be able to create a service: %s", err)
+	}
+
+	if svc.ID == uuid.Nil {
+		t.Error("Should have a valid service id")
+	}
+	if svc.Name != ns.Name || svc.Duration != ns.Duration || svc.Price != ns.Price || !svc.Currency.Equal(ns.Currency) {
+		t.Error("Should have the given name, duration and price.")
+		t.Errorf("GOT: %s %s %d %s\n", svc.Name, svc.Duration, svc.Price, svc.Currency.Code())
+		t.Errorf("EXP: %s %s %d %s\n", ns.Name, ns.Duration, ns.Price, ns.Currency.Code())
+	}
+
+	if _, err := api.Service.Create(ctx, ns); !errors.Is(err, service.ErrUniqueName) {
+		t.Error("Should reject a second service with the same name")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", service.ErrUniqueName)
+	}
+
+	ns.Name = "Free lunch"
+	ns.Duration = 0
+	if _, err := api.Service.Create(ctx, ns); !errors.Is(err, service.ErrInvalidDuration) {
+		t.Error("Should reject a service without duration")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", service.ErrInvalidDuration)
+	}
+
+	ns.Duration = 30 * time.Minute
+	ns.Capacity = 0
+	if _, err := api.Service.Create(ctx, ns); !errors.Is(err, service.ErrInvalidCapacity) {
+		t.Error("Should reject a service holding nobody")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", service.ErrInvalidCapacity)
+	}
+
+	// -------------------------------------------------------------------
+	// QueryByBusinessID
+
+	savedSvcs, err := api.Service.QueryByBusinessID(ctx, svcs[0].BusinessID)
+	if err != nil {
+		t.Fatalf("Should be able to query services by business ID: %s", err)
+	}
+
+	if len(savedSvcs) != 3 {
+		t.Errorf("Should have 3 services: BusinessID[%s]\n", svcs[0].BusinessID)
+		t.Errorf("GOT: %d\n", len(savedSvcs))
+		t.Errorf("EXP: %d\n", 3)
+	}
+
+	// -------------------------------------------------------------------
+	// Update
+
+	dur := 45 * time.Minute
+	us := service.UpdateService{Duration: &dur}
+	svc, err = api.Service.Update(ctx, svc, us)
+	if err != nil {
+		t.Fatalf("Should be able to update service: %s", err)
+	}
+
+	saved, err = api.Service.QueryByID(ctx, svc.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve service by ID: %s", err)
+	}
+
+	if saved.Duration != dur {
+		t.Error("Should be able to see updates to Duration")
+		t.Errorf("GOT: %s\n", saved.Duration)
+		t.Errorf("EXP: %s\n", dur)
+	}
+
+	// -------------------------------------------------------------------
+	// Delete
+
+	if err := api.Service.Delete(ctx, svc); err != nil {
+		t.Fatalf("Should be able to delete service: %s", err)
+	}
+
+	_, err = api.Service.QueryByID(ctx, svc.ID)
+	if !errors.Is(err, service.ErrNotFound) {
+		t.Errorf("Should NOT be able to retrieve service: %s", err)
+	}
+}
@@ -0,0 +1,75 @@
+package servicedb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/google/uuid"
+)
+
+type dbService struct {
+	ID          uuid.UUID `db:"service_id"`
+	BusinessID  uuid.UUID `db:"business_id"`
+	Name        string    `db:"name"`
+	Duration    int       `db:"duration"`
+	Price       int64     `db:"price"`
+	Currency    string    `db:"currency"`
+	Capacity    int       `db:"capacity"`
+	LeadTime    int       `db:"lead_time"`
+	Horizon     int       `db:"horizon"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+func toDBService(svc service.Service) dbService {
+	return dbService{
+		ID:          svc.ID,
+		BusinessID:  svc.BusinessID,
+		Name:        svc.Name,
+		Duration:    int(svc.Duration / time.Second),
+		Price:       svc.Price,
+		Currency:    svc.Currency.Code(),
+		Capacity:    svc.Capacity,
+		LeadTime:    int(svc.LeadTime / time.Second),
+		Horizon:     int(svc.Horizon / time.Second),
+		DateCreated: svc.DateCreated.UTC(),
+		DateUpdated: svc.DateUpdated.UTC(),
+	}
+}
+
+func toCoreService(dbSvc dbService) (service.Service, error) {
+	cur, err := service.ParseCurrency(dbSvc.Currency)
+	if err != nil {
+		return service.Service{}, fmt.Errorf("parse currency: %w", err)
+	}
+
+	svc := service.Service{
+		ID:          dbSvc.ID,
+		BusinessID:  dbSvc.BusinessID,
+		Name:        dbSvc.Name,
+		Duration:    time.Duration(dbSvc.Duration) * time.Second,
+		Price:       dbSvc.Price,
+		Currency:    cur,
+		Capacity:    dbSvc.Capacity,
+		LeadTime:    time.Duration(dbSvc.LeadTime) * time.Second,
+		Horizon:     time.Duration(dbSvc.Horizon) * time.Second,
+		DateCreated: dbSvc.DateCreated.In(time.Local),
+		DateUpdated: dbSvc.DateUpdated.In(time.Local),
+	}
+
+	return svc, nil
+}
+
+func toCoreServiceSlice(dbSvcs []dbService) ([]service.Service, error) {
+	svcs := make([]service.Service, len(dbSvcs))
+	for i, s := range dbSvcs {
+		var err error
+		svcs[i], err = toCoreService(s)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return svcs, nil
+}
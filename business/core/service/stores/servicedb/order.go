@@ -0,0 +1,25 @@
+package servicedb
+
+import (
+	"fmt"
+
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/data/order"
+)
+
+var orderByFields = map[string]string{
+	service.OrderByID:         "service_id",
+	service.OrderByBusinessID: "business_id",
+	service.OrderByName:       "name",
+	service.OrderByDuration:   "duration",
+	service.OrderByPrice:      "price",
+}
+
+func orderByClause(orderBy order.By) (string, error) {
+	by, exists := orderByFields[orderBy.Field]
+	if !exists {
+		return "", fmt.Errorf("field %q does not exist", orderBy.Field)
+	}
+
+	return " ORDER BY " + by + " " + orderBy.Direction, nil
+}
@@ -0,0 +1,231 @@
+package servicedb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ameghdadian/service/business/core/service"
+	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
+	"github.com/ameghdadian/service/business/data/order"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (service.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		db:  ec,
+		log: s.log,
+	}
+
+	return s, nil
+}
+
+func (s *Store) Create(ctx context.Context, svc service.Service) error {
+	const q = `
+	INSERT INTO services
+		(service_id, business_id, name, duration, price, currency, capacity, lead_time, horizon, date_created, date_updated)
+	VALUES
+		(:service_id, :business_id, :name, :duration, :price, :currency, :capacity, :lead_time, :horizon, :date_created, :date_updated)
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBService(svc)); err != nil {
+		if errors.Is(err, db.ErrDBDuplicateEntry) {
+			return fmt.Errorf("namedexeccontext: %w", service.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Update(ctx context.Context, svc service.Service) error {
+	const q = `
+	UPDATE
+		services
+	SET
+		"name" = :name,
+		"duration" = :duration,
+		"price" = :price,
+		"currency" = :currency,
+		"capacity" = :capacity,
+		"lead_time" = :lead_time,
+		"horizon" = :horizon,
+		"date_updated" = :date_updated
+	WHERE
+		service_id = :service_id
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBService(svc)); err != nil {
+		if errors.Is(err, db.ErrDBDuplicateEntry) {
+			return fmt.Errorf("namedexeccontext: %w", service.ErrUniqueName)
+		}
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Delete(ctx context.Context, svc service.Service) error {
+	data := struct {
+		ServiceID string `db:"service_id"`
+	}{
+		ServiceID: svc.ID.String(),
+	}
+
+	const q = `
+	DELETE FROM
+		services
+	WHERE
+		service_id = :service_id
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, data); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Query(ctx context.Context, filter service.QueryFilter, orderBy order.By, page page.Page) ([]service.Service, error) {
+	data := map[string]any{
+		"offset":        (page.Number() - 1) * page.RowsPerPage(),
+		"rows_per_page": page.RowsPerPage(),
+	}
+
+	const q = `
+	SELECT
+		service_id, business_id, name, duration, price, currency, capacity, lead_time, horizon, date_created, date_updated
+	FROM
+		services
+	`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	orderByClause, err := orderByClause(orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	buf.WriteString(orderByClause)
+	buf.WriteString(" OFFSET :offset ROWS FETCH NEXT :rows_per_page ROWS ONLY")
+
+	var dbSvcs []dbService
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, buf.String(), data, &dbSvcs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	svcs, err := toCoreServiceSlice(dbSvcs)
+	if err != nil {
+		return nil, err
+	}
+
+	return svcs, nil
+}
+
+func (s *Store) Count(ctx context.Context, filter service.QueryFilter) (int, error) {
+	data := map[string]any{}
+
+	const q = `
+	SELECT
+		COUNT(1)
+	FROM
+		services
+	`
+
+	buf := bytes.NewBufferString(q)
+	s.applyFilter(filter, data, buf)
+
+	var count struct {
+		Count int `db:"count"`
+	}
+
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, buf.String(), data, &count); err != nil {
+		return 0, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return count.Count, nil
+}
+
+func (s *Store) QueryByID(ctx context.Context, svcID uuid.UUID) (service.Service, error) {
+	data := struct {
+		ServiceID string `db:"service_id"`
+	}{
+		ServiceID: svcID.String(),
+	}
+
+	const q = `
+	SELECT
+		service_id, business_id, name, duration, price, currency, capacity, lead_time, horizon, date_created, date_updated
+	FROM
+		services
+	WHERE
+		service_id = :service_id
+	`
+
+	var dbSvc dbService
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbSvc); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return service.Service{}, fmt.Errorf("namedquerystruct: %w", service.ErrNotFound)
+		}
+		return service.Service{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	svc, err := toCoreService(dbSvc)
+	if err != nil {
+		return service.Service{}, err
+	}
+
+	return svc, nil
+}
+
+func (s *Store) QueryByBusinessID(ctx context.Context, bsnID uuid.UUID) ([]service.Service, error) {
+	data := struct {
+		BusinessID string `db:"business_id"`
+	}{
+		BusinessID: bsnID.String(),
+	}
+
+	const q = `
+	SELECT
+		service_id, business_id, name, duration, price, currency, capacity, lead_time, horizon, date_created, date_updated
+	FROM
+		services
+	WHERE
+		business_id = :business_id
+	`
+
+	var dbSvcs []dbService
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbSvcs); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	svcs, err := toCoreServiceSlice(dbSvcs)
+	if err != nil {
+		return nil, err
+	}
+
+	return svcs, nil
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGenerateNewServices(n int, bsnID uuid.UUID) []NewService {
+	newSvcs := make([]NewService, n)
+
+	for i := 0; i < n; i++ {
+		newSvcs[i] = NewService{
+			BusinessID: bsnID,
+			Name:       fmt.Sprintf("Service%d", i),
+			Duration:   time.Duration(i+1) * 30 * time.Minute,
+			Price:      int64(i+1) * 1500,
+			Currency:   Currency{code: "EUR"},
+			Capacity:   1,
+		}
+	}
+
+	return newSvcs
+}
+
+func TestGenerateSeedServices(n int, api *Core, bsnID uuid.UUID) ([]Service, error) {
+	newSvcs := TestGenerateNewServices(n, bsnID)
+
+	svcs := make([]Service, len(newSvcs))
+	for i, ns := range newSvcs {
+		svc, err := api.Create(context.Background(), ns)
+		if err != nil {
+			return nil, fmt.Errorf("seeding service: idx: %d: %w", i, err)
+		}
+
+		svcs[i] = svc
+	}
+
+	return svcs, nil
+}
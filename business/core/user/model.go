@@ -1,22 +1,29 @@
 package user
 
 import (
+	"crypto/sha256"
+	"crypto/subtle"
 	"net/mail"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// User is someone using the service. FeedTokenHash is the hash of the secret token
+// calendar apps subscribe to the feeds of the user with, nil until the user asks
+// for one.
 type User struct {
-	ID           uuid.UUID
-	Name         string
-	Email        mail.Address
-	Roles        []Role
-	PasswordHash []byte
-	Enabled      bool
-	PhoneNo      PhoneNumber
-	DateCreated  time.Time
-	DateUpdated  time.Time
+	ID            uuid.UUID
+	Name          string
+	Email         mail.Address
+	Roles         []Role
+	PasswordHash  []byte
+	Enabled       bool
+	PhoneNo       PhoneNumber
+	Preferences   Preferences
+	FeedTokenHash []byte
+	DateCreated   time.Time
+	DateUpdated   time.Time
 }
 
 type NewUser struct {
@@ -36,3 +43,13 @@ type UpdateUser struct {
 	PasswordConfirm *string
 	Enabled         *bool
 }
+
+// ValidFeedToken reports whether the token is the feed token of the user.
+func (u User) ValidFeedToken(token string) bool {
+	if u.FeedTokenHash == nil {
+		return false
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	return subtle.ConstantTimeCompare(hash[:], u.FeedTokenHash) == 1
+}
@@ -0,0 +1,152 @@
+package user
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+var (
+	ChannelSMS   = Channel{"SMS"}
+	ChannelEmail = Channel{"EMAIL"}
+)
+
+var channels = map[string]Channel{
+	ChannelSMS.name:   ChannelSMS,
+	ChannelEmail.name: ChannelEmail,
+}
+
+// Channel is a way users are notified.
+type Channel struct {
+	name string
+}
+
+func ParseChannel(value string) (Channel, error) {
+	ch, exists := channels[value]
+	if !exists {
+		return Channel{}, fmt.Errorf("invalid channel %q", value)
+	}
+
+	return ch, nil
+}
+
+// MustParseChannel parses the string value and returns a channel if one exists. If
+// an error occurs the function panics. ONLY use it when writing TESTS.
+func MustParseChannel(value string) Channel {
+	ch, err := ParseChannel(value)
+	if err != nil {
+		panic(err)
+	}
+
+	return ch
+}
+
+func (ch Channel) Name() string {
+	return ch.name
+}
+
+// UnmarshalText implement the unmarshal interface for JSON conversions
+func (ch *Channel) UnmarshalText(data []byte) error {
+	c, err := ParseChannel(string(data))
+	if err != nil {
+		return err
+	}
+
+	ch.name = c.name
+	return nil
+}
+
+// MarshalText implement the marshal interface for JSON conversions.
+func (ch Channel) MarshalText() ([]byte, error) {
+	return []byte(ch.name), nil
+}
+
+func (ch Channel) Equal(ch2 Channel) bool {
+	return ch.name == ch2.name
+}
+
+// =============================================================================
+
+// QuietHours is a daily stretch of wall clock time users aren't to be disturbed
+// in, which may span midnight. Start and End are offsets from midnight. Equal ones
+// mean no quiet hours.
+type QuietHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Valid reports whether both ends of the quiet hours fall within a day.
+func (q QuietHours) Valid() bool {
+	const day = 24 * time.Hour
+	return q.Start >= 0 && q.Start < day && q.End >= 0 && q.End < day
+}
+
+// IsZero reports whether there are no quiet hours.
+func (q QuietHours) IsZero() bool {
+	return q.Start == q.End
+}
+
+// Until returns when the quiet hours t falls in end, by the wall clock of its
+// location. It returns t itself when t is outside the quiet hours.
+func (q QuietHours) Until(t time.Time) time.Time {
+	if q.IsZero() {
+		return t
+	}
+
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	since := t.Sub(midnight)
+
+	switch {
+	case q.Start < q.End && since >= q.Start && since < q.End:
+		return midnight.Add(q.End)
+	case q.Start > q.End && since >= q.Start:
+		return midnight.AddDate(0, 0, 1).Add(q.End)
+	case q.Start > q.End && since < q.End:
+		return midnight.Add(q.End)
+	}
+
+	return t
+}
+
+// =============================================================================
+
+// Preferences are how users like to be notified. Users not wanting notifications
+// at all have no channels.
+type Preferences struct {
+	Channels   []Channel
+	QuietHours QuietHours
+	Language   language.Tag
+}
+
+// DefaultPreferences notify users on every channel, in English, at any time.
+func DefaultPreferences() Preferences {
+	return Preferences{
+		Channels: []Channel{ChannelSMS, ChannelEmail},
+		Language: language.English,
+	}
+}
+
+// Allows reports whether users may be notified over the channel.
+func (p Preferences) Allows(ch Channel) bool {
+	return slices.ContainsFunc(p.Channels, ch.Equal)
+}
+
+func (p Preferences) Equal(p2 Preferences) bool {
+	return slices.EqualFunc(p.Channels, p2.Channels, Channel.Equal) &&
+		p.QuietHours == p2.QuietHours &&
+		p.Language == p2.Language
+}
+
+// without returns the preferences, the channel dropped.
+func (p Preferences) without(ch Channel) Preferences {
+	p.Channels = slices.DeleteFunc(slices.Clone(p.Channels), ch.Equal)
+	return p
+}
+
+type UpdatePreferences struct {
+	Channels   []Channel
+	QuietHours *QuietHours
+	Language   *language.Tag
+}
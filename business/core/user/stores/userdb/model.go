@@ -8,18 +8,24 @@ import (
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/business/data/dbsql/pgx/dbarray"
 	"github.com/google/uuid"
+	"golang.org/x/text/language"
 )
 
 type dbUser struct {
-	ID           uuid.UUID      `db:"user_id"`
-	Name         string         `db:"name"`
-	Email        string         `db:"email"`
-	Roles        dbarray.String `db:"roles"`
-	PasswordHash []byte         `db:"password_hash"`
-	Enabled      bool           `db:"enabled"`
-	PhoneNo      string         `db:"phone_no"`
-	DateCreated  time.Time      `db:"date_created"`
-	DateUpdated  time.Time      `db:"date_updated"`
+	ID            uuid.UUID      `db:"user_id"`
+	Name          string         `db:"name"`
+	Email         string         `db:"email"`
+	Roles         dbarray.String `db:"roles"`
+	PasswordHash  []byte         `db:"password_hash"`
+	Enabled       bool           `db:"enabled"`
+	PhoneNo       string         `db:"phone_no"`
+	Channels      dbarray.String `db:"channels"`
+	QuietStart    int            `db:"quiet_start"`
+	QuietEnd      int            `db:"quiet_end"`
+	Language      string         `db:"language"`
+	FeedTokenHash []byte         `db:"feed_token_hash"`
+	DateCreated   time.Time      `db:"date_created"`
+	DateUpdated   time.Time      `db:"date_updated"`
 }
 
 func toDBUser(usr user.User) dbUser {
@@ -28,16 +34,26 @@ func toDBUser(usr user.User) dbUser {
 		roles[i] = role.Name()
 	}
 
+	channels := make([]string, len(usr.Preferences.Channels))
+	for i, ch := range usr.Preferences.Channels {
+		channels[i] = ch.Name()
+	}
+
 	return dbUser{
-		ID:           usr.ID,
-		Name:         usr.Name,
-		Email:        usr.Email.Address,
-		Roles:        roles,
-		PasswordHash: usr.PasswordHash,
-		Enabled:      usr.Enabled,
-		PhoneNo:      usr.PhoneNo.Number(),
-		DateCreated:  usr.DateCreated.UTC(),
-		DateUpdated:  usr.DateUpdated.UTC(),
+		ID:            usr.ID,
+		Name:          usr.Name,
+		Email:         usr.Email.Address,
+		Roles:         roles,
+		PasswordHash:  usr.PasswordHash,
+		Enabled:       usr.Enabled,
+		PhoneNo:       usr.PhoneNo.Number(),
+		Channels:      channels,
+		QuietStart:    int(usr.Preferences.QuietHours.Start / time.Second),
+		QuietEnd:      int(usr.Preferences.QuietHours.End / time.Second),
+		Language:      usr.Preferences.Language.String(),
+		FeedTokenHash: usr.FeedTokenHash,
+		DateCreated:   usr.DateCreated.UTC(),
+		DateUpdated:   usr.DateUpdated.UTC(),
 	}
 }
 
@@ -60,16 +76,40 @@ func toCoreUser(dbUsr dbUser) (user.User, error) {
 		return user.User{}, fmt.Errorf("convert db user to core user: %w", err)
 	}
 
+	channels := make([]user.Channel, len(dbUsr.Channels))
+	for i, value := range dbUsr.Channels {
+		channels[i], err = user.ParseChannel(value)
+		if err != nil {
+			return user.User{}, fmt.Errorf("parse channel: %w", err)
+		}
+	}
+
+	lang, err := language.Parse(dbUsr.Language)
+	if err != nil {
+		return user.User{}, fmt.Errorf("parse language: %w", err)
+	}
+
+	prefs := user.Preferences{
+		Channels: channels,
+		QuietHours: user.QuietHours{
+			Start: time.Duration(dbUsr.QuietStart) * time.Second,
+			End:   time.Duration(dbUsr.QuietEnd) * time.Second,
+		},
+		Language: lang,
+	}
+
 	usr := user.User{
-		ID:           dbUsr.ID,
-		Name:         dbUsr.Name,
-		Email:        addr,
-		Roles:        roles,
-		PasswordHash: dbUsr.PasswordHash,
-		Enabled:      dbUsr.Enabled,
-		PhoneNo:      phoneNo,
-		DateCreated:  dbUsr.DateCreated.In(time.Local),
-		DateUpdated:  dbUsr.DateUpdated.In(time.Local),
+		ID:            dbUsr.ID,
+		Name:          dbUsr.Name,
+		Email:         addr,
+		Roles:         roles,
+		PasswordHash:  dbUsr.PasswordHash,
+		Enabled:       dbUsr.Enabled,
+		PhoneNo:       phoneNo,
+		Preferences:   prefs,
+		FeedTokenHash: dbUsr.FeedTokenHash,
+		DateCreated:   dbUsr.DateCreated.In(time.Local),
+		DateUpdated:   dbUsr.DateUpdated.In(time.Local),
 	}
 
 	return usr, nil
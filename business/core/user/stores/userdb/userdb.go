@@ -47,9 +47,9 @@ func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (user.Storer
 func (s *Store) Create(ctx context.Context, usr user.User) error {
 	const q = `
 	INSERT INTO users
-		(user_id, name, email, password_hash, roles, phone_no, enabled, date_created, date_updated)	
+		(user_id, name, email, password_hash, roles, phone_no, enabled, channels, quiet_start, quiet_end, language, feed_token_hash, date_created, date_updated)	
 	VALUES
-		(:user_id, :name, :email, :password_hash, :roles, :phone_no, :enabled, :date_created, :date_updated)
+		(:user_id, :name, :email, :password_hash, :roles, :phone_no, :enabled, :channels, :quiet_start, :quiet_end, :language, :feed_token_hash, :date_created, :date_updated)
 	`
 
 	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBUser(usr)); err != nil {
@@ -72,6 +72,11 @@ func (s *Store) Update(ctx context.Context, usr user.User) error {
 		"roles" = :roles,
 		"password_hash" = :password_hash,
 		"enabled" = :enabled,
+		"channels" = :channels,
+		"quiet_start" = :quiet_start,
+		"quiet_end" = :quiet_end,
+		"language" = :language,
+		"feed_token_hash" = :feed_token_hash,
 		"date_updated" = :date_updated
 	WHERE
 		user_id = :user_id
@@ -114,7 +119,7 @@ func (s *Store) Query(ctx context.Context, filter user.QueryFilter, orderBy orde
 
 	const q = `
 	SELECT	
-		user_id, name, email, password_hash, roles, phone_no, enabled, date_created, date_updated	
+		user_id, name, email, password_hash, roles, phone_no, enabled, channels, quiet_start, quiet_end, language, feed_token_hash, date_created, date_updated	
 	FROM
 		users
 	`
@@ -174,7 +179,7 @@ func (s *Store) QueryByEmail(ctx context.Context, email mail.Address) (user.User
 
 	const q = `
 		SELECT 	
-			user_id, name, email, password_hash, roles, phone_no, enabled, date_created, date_updated	
+			user_id, name, email, password_hash, roles, phone_no, enabled, channels, quiet_start, quiet_end, language, feed_token_hash, date_created, date_updated	
 		FROM
 			users
 		WHERE
@@ -206,7 +211,7 @@ func (s *Store) QueryByIDs(ctx context.Context, userID []uuid.UUID) ([]user.User
 
 	const q = `
 	SELECT	
-		user_id, name, email, password_hash, roles, phone_no, enabled, date_created, date_updated	
+		user_id, name, email, password_hash, roles, phone_no, enabled, channels, quiet_start, quiet_end, language, feed_token_hash, date_created, date_updated	
 	FROM
 		users
 	WHERE
@@ -239,7 +244,7 @@ func (s *Store) QueryByID(ctx context.Context, userID uuid.UUID) (user.User, err
 
 	const q = `
 	SELECT 
-		user_id, name, email, password_hash, roles, phone_no, enabled, date_created, date_updated	
+		user_id, name, email, password_hash, roles, phone_no, enabled, channels, quiet_start, quiet_end, language, feed_token_hash, date_created, date_updated	
 	FROM
 		users
 	WHERE
@@ -2,9 +2,13 @@ package user
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"net/mail"
+	"slices"
 	"time"
 
 	"github.com/ameghdadian/service/business/data/order"
@@ -20,6 +24,7 @@ var (
 	ErrNotFound              = errors.New("user not found")
 	ErrUniqueEmailOrPhoneNo  = errors.New("email or phone number is not unique")
 	ErrAuthenticationFailure = errors.New("authentication failed")
+	ErrInvalidQuietHours     = errors.New("quiet hours must fall within a day")
 )
 
 type Storer interface {
@@ -79,6 +84,7 @@ func (c *Core) Create(ctx context.Context, nu NewUser) (User, error) {
 		Roles:        nu.Roles,
 		Enabled:      false,
 		PhoneNo:      nu.PhoneNo,
+		Preferences:  DefaultPreferences(),
 		DateCreated:  now,
 		DateUpdated:  now,
 	}
@@ -127,6 +133,84 @@ func (c *Core) Update(ctx context.Context, usr User, uu UpdateUser) (User, error
 	return usr, nil
 }
 
+// UpdatePreferences changes how the user likes to be notified.
+func (c *Core) UpdatePreferences(ctx context.Context, usr User, up UpdatePreferences) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.user.updatepreferences")
+	defer span.End()
+
+	if up.Channels != nil {
+		chs := make([]Channel, 0, len(up.Channels))
+		for _, ch := range up.Channels {
+			if !slices.ContainsFunc(chs, ch.Equal) {
+				chs = append(chs, ch)
+			}
+		}
+		usr.Preferences.Channels = chs
+	}
+
+	if up.QuietHours != nil {
+		if !up.QuietHours.Valid() {
+			return User{}, ErrInvalidQuietHours
+		}
+		usr.Preferences.QuietHours = *up.QuietHours
+	}
+
+	if up.Language != nil {
+		usr.Preferences.Language = *up.Language
+	}
+
+	usr.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("update: %w", err)
+	}
+
+	return usr, nil
+}
+
+// RotateFeedToken gives the user a new secret token to subscribe to their calendar
+// feeds with, revoking the one before. Only its hash is kept, so the token is
+// returned this once.
+func (c *Core) RotateFeedToken(ctx context.Context, usr User) (User, string, error) {
+	ctx, span := otel.AddSpan(ctx, "business.user.rotatefeedtoken")
+	defer span.End()
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return User{}, "", fmt.Errorf("generating token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	hash := sha256.Sum256([]byte(token))
+	usr.FeedTokenHash = hash[:]
+	usr.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, usr); err != nil {
+		return User{}, "", fmt.Errorf("update: %w", err)
+	}
+
+	return usr, token, nil
+}
+
+// Unsubscribe stops notifying the user over the channel.
+func (c *Core) Unsubscribe(ctx context.Context, usr User, ch Channel) (User, error) {
+	ctx, span := otel.AddSpan(ctx, "business.user.unsubscribe")
+	defer span.End()
+
+	if !usr.Preferences.Allows(ch) {
+		return usr, nil
+	}
+
+	usr.Preferences = usr.Preferences.without(ch)
+	usr.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, usr); err != nil {
+		return User{}, fmt.Errorf("update: %w", err)
+	}
+
+	return usr, nil
+}
+
 func (c *Core) Delete(ctx context.Context, usr User) error {
 	ctx, span := otel.AddSpan(ctx, "business.user.delete")
 	defer span.End()
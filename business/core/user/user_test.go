@@ -17,6 +17,7 @@ import (
 	"github.com/ameghdadian/service/foundation/docker"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/uuid"
+	"golang.org/x/text/language"
 )
 
 var c *docker.Container
@@ -44,6 +45,8 @@ func TestMain(m *testing.M) {
 
 func Test_User(t *testing.T) {
 	t.Run("crud", crud)
+	t.Run("preferences", preferences)
+	t.Run("feedtoken", feedToken)
 }
 
 // =======================================================
@@ -225,3 +228,151 @@ func crud(t *testing.T) {
 
 	// QueryByIDs
 }
+
+func preferences(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	usrs, err := api.User.Query(ctx, user.QueryFilter{}, order.By{Field: user.OrderByName, Direction: order.ASC}, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+	usr := usrs[0]
+
+	if !usr.Preferences.Equal(user.DefaultPreferences()) {
+		t.Error("Should notify users on every channel by default")
+		t.Errorf("GOT: %+v\n", usr.Preferences)
+	}
+
+	bad := user.QuietHours{Start: 22 * time.Hour, End: 25 * time.Hour}
+	if _, err := api.User.UpdatePreferences(ctx, usr, user.UpdatePreferences{QuietHours: &bad}); !errors.Is(err, user.ErrInvalidQuietHours) {
+		t.Error("Should reject quiet hours beyond a day")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", user.ErrInvalidQuietHours)
+	}
+
+	quiet := user.QuietHours{Start: 22 * time.Hour, End: 7 * time.Hour}
+	lang := language.German
+	up := user.UpdatePreferences{
+		Channels:   []user.Channel{user.ChannelEmail, user.ChannelEmail},
+		QuietHours: &quiet,
+		Language:   &lang,
+	}
+
+	if _, err := api.User.UpdatePreferences(ctx, usr, up); err != nil {
+		t.Fatalf("Should be able to update preferences: %s", err)
+	}
+
+	saved, err := api.User.QueryByID(ctx, usr.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve user by ID: %s", err)
+	}
+
+	exp := user.Preferences{
+		Channels:   []user.Channel{user.ChannelEmail},
+		QuietHours: quiet,
+		Language:   lang,
+	}
+	if !saved.Preferences.Equal(exp) {
+		t.Error("Should have the new preferences")
+		t.Errorf("GOT: %+v\n", saved.Preferences)
+		t.Errorf("EXP: %+v\n", exp)
+	}
+
+	saved, err = api.User.Unsubscribe(ctx, saved, user.ChannelEmail)
+	if err != nil {
+		t.Fatalf("Should be able to unsubscribe: %s", err)
+	}
+
+	saved, err = api.User.QueryByID(ctx, usr.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve user by ID: %s", err)
+	}
+
+	if len(saved.Preferences.Channels) != 0 {
+		t.Error("Should no longer notify the user unsubscribed from every channel")
+		t.Errorf("GOT: %+v\n", saved.Preferences.Channels)
+	}
+
+	night := time.Date(2024, time.March, 1, 23, 30, 0, 0, time.UTC)
+	if got, want := quiet.Until(night), time.Date(2024, time.March, 2, 7, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Error("Should hold back past midnight until quiet hours end")
+		t.Errorf("GOT: %s\n", got)
+		t.Errorf("EXP: %s\n", want)
+	}
+
+	noon := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	if got := quiet.Until(noon); !got.Equal(noon) {
+		t.Error("Should not hold back outside quiet hours")
+		t.Errorf("GOT: %s\n", got)
+	}
+}
+
+func feedToken(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	usrs, err := api.User.Query(ctx, user.QueryFilter{}, order.By{Field: user.OrderByName, Direction: order.ASC}, page.MustParse("1", "1"))
+	if err != nil {
+		t.Fatalf("Seeding error: %s", err)
+	}
+	usr := usrs[0]
+
+	if usr.ValidFeedToken("") {
+		t.Error("Should reject every token before one is issued")
+	}
+
+	_, first, err := api.User.RotateFeedToken(ctx, usr)
+	if err != nil {
+		t.Fatalf("Should be able to rotate the feed token: %s", err)
+	}
+
+	usr, err = api.User.QueryByID(ctx, usr.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve user by ID: %s", err)
+	}
+
+	if !usr.ValidFeedToken(first) {
+		t.Error("Should accept the issued feed token")
+	}
+
+	_, second, err := api.User.RotateFeedToken(ctx, usr)
+	if err != nil {
+		t.Fatalf("Should be able to rotate the feed token again: %s", err)
+	}
+
+	usr, err = api.User.QueryByID(ctx, usr.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve user by ID: %s", err)
+	}
+
+	if usr.ValidFeedToken(first) {
+		t.Error("Should revoke the previous feed token")
+	}
+
+	if !usr.ValidFeedToken(second) {
+		t.Error("Should accept the new feed token")
+	}
+}
@@ -0,0 +1,66 @@
+package waitlist
+
+import (
+	"time"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/google/uuid"
+)
+
+// Entry is a customer waiting for a slot of a business to free up, starting between
+// From and To. ServiceID and ResourceID narrow the slots the customer waits for,
+// any of them when uuid.Nil. Once a slot frees up, the entry is offered it: the
+// slot is held for the customer until HeldUntil.
+type Entry struct {
+	ID          uuid.UUID
+	BusinessID  uuid.UUID
+	UserID      uuid.UUID
+	ServiceID   uuid.UUID
+	ResourceID  uuid.UUID
+	From        time.Time
+	To          time.Time
+	Status      Status
+	Held        Slot
+	HeldUntil   time.Time
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type NewEntry struct {
+	BusinessID uuid.UUID
+	UserID     uuid.UUID
+	ServiceID  uuid.UUID
+	ResourceID uuid.UUID
+	From       time.Time
+	To         time.Time
+}
+
+// Slot is a stretch of time of a business freed up by an appointment being
+// cancelled or deleted.
+type Slot struct {
+	ServiceID  uuid.UUID
+	ResourceID uuid.UUID
+	StartsAt   time.Time
+	EndsAt     time.Time
+}
+
+func slotOf(apt appointment.Appointment) Slot {
+	return Slot{
+		ServiceID:  apt.ServiceID,
+		ResourceID: apt.ResourceID,
+		StartsAt:   apt.ScheduledOn,
+		EndsAt:     apt.EndsOn(),
+	}
+}
+
+// appointment returns an appointment booking the slot of the business, for the
+// appointment core to tell whether it's free.
+func (s Slot) appointment(bsnID uuid.UUID) appointment.Appointment {
+	return appointment.Appointment{
+		BusinessID:  bsnID,
+		ServiceID:   s.ServiceID,
+		ResourceID:  s.ResourceID,
+		ScheduledOn: s.StartsAt,
+		Duration:    s.EndsAt.Sub(s.StartsAt),
+	}
+}
@@ -0,0 +1,98 @@
+package waitlist
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// The offer has a plain text and an HTML template for its email, and an SMS one
+// for its text message. Templates in other languages than English add the base
+// language to the name, such as offer.de.txt, falling back to English.
+var (
+	textTemplates = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.txt"))
+	htmlTemplates = htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/*.html"))
+	smsTemplates  = texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/*.sms"))
+)
+
+// templateName returns the name of the template in the language, if there's one,
+// or else in English.
+func templateName(lookup func(string) bool, name string, lang language.Tag, ext string) string {
+	base, _ := lang.Base()
+	if localized := name + "." + base.String() + ext; base.String() != "en" && lookup(localized) {
+		return localized
+	}
+
+	return name + ext
+}
+
+const offerSubject = "A slot you're waiting for freed up"
+
+type offerData struct {
+	Name        string
+	StartsAt    string
+	HeldUntil   string
+	Day         string
+	Time        string
+	Zone        string
+	Unsubscribe string
+}
+
+// newOfferData tells the customer of the given name about the slot starting at
+// startsAt, held for them until heldUntil, at the wall clock time of the business.
+func newOfferData(name string, startsAt time.Time, heldUntil time.Time, unsubscribe string) offerData {
+	return offerData{
+		Name:        name,
+		StartsAt:    formatWhen(startsAt),
+		HeldUntil:   heldUntil.Format("15:04 MST"),
+		Day:         startsAt.Format("Mon, Jan 2"),
+		Time:        startsAt.Format("15:04"),
+		Zone:        startsAt.Format("MST"),
+		Unsubscribe: unsubscribe,
+	}
+}
+
+// renderOfferEmail renders the plain text and HTML bodies of the offer email, in
+// the language of the customer.
+func renderOfferEmail(lang language.Tag, data offerData) (string, string, error) {
+	textName := templateName(func(n string) bool { return textTemplates.Lookup(n) != nil }, "offer", lang, ".txt")
+	htmlName := templateName(func(n string) bool { return htmlTemplates.Lookup(n) != nil }, "offer", lang, ".html")
+
+	var text bytes.Buffer
+	if err := textTemplates.ExecuteTemplate(&text, textName, data); err != nil {
+		return "", "", fmt.Errorf("rendering offer text: %w", err)
+	}
+
+	var html bytes.Buffer
+	if err := htmlTemplates.ExecuteTemplate(&html, htmlName, data); err != nil {
+		return "", "", fmt.Errorf("rendering offer html: %w", err)
+	}
+
+	return text.String(), html.String(), nil
+}
+
+// renderOfferSMS renders the text message of the offer, in the language of the
+// customer.
+func renderOfferSMS(lang language.Tag, data offerData) (string, error) {
+	tmpl := templateName(func(n string) bool { return smsTemplates.Lookup(n) != nil }, "offer", lang, ".sms")
+
+	var body bytes.Buffer
+	if err := smsTemplates.ExecuteTemplate(&body, tmpl, data); err != nil {
+		return "", fmt.Errorf("rendering offer sms: %w", err)
+	}
+
+	return strings.TrimSpace(body.String()), nil
+}
+
+func formatWhen(t time.Time) string {
+	return t.Format("Mon, Jan 2 2006 at 15:04 MST")
+}
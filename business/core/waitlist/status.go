@@ -0,0 +1,59 @@
+package waitlist
+
+import "fmt"
+
+var (
+	StatusWaiting   = Status{"Waiting"}
+	StatusOffered   = Status{"Offered"}
+	StatusBooked    = Status{"Booked"}
+	StatusExpired   = Status{"Expired"}
+	StatusWithdrawn = Status{"Withdrawn"}
+)
+
+var statuses = map[string]Status{
+	StatusWaiting.status:   StatusWaiting,
+	StatusOffered.status:   StatusOffered,
+	StatusBooked.status:    StatusBooked,
+	StatusExpired.status:   StatusExpired,
+	StatusWithdrawn.status: StatusWithdrawn,
+}
+
+type Status struct {
+	status string
+}
+
+func ParseStatus(value string) (Status, error) {
+	status, exists := statuses[value]
+	if !exists {
+		return Status{}, fmt.Errorf("invalid status: %q", value)
+	}
+
+	return status, nil
+}
+
+func (ws Status) Status() string {
+	return ws.status
+}
+
+// Active reports whether an entry of the status is still on the waitlist.
+func (ws Status) Active() bool {
+	return ws == StatusWaiting || ws == StatusOffered
+}
+
+func (ws *Status) UnmarshalText(data []byte) error {
+	status, err := ParseStatus(string(data))
+	if err != nil {
+		return err
+	}
+
+	ws.status = status.status
+	return nil
+}
+
+func (ws Status) MarshalText() ([]byte, error) {
+	return []byte(ws.status), nil
+}
+
+func (ws Status) Equal(ws2 Status) bool {
+	return ws.status == ws2.status
+}
@@ -0,0 +1,108 @@
+package waitlistdb
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/waitlist"
+	"github.com/google/uuid"
+)
+
+type dbEntry struct {
+	ID             uuid.UUID     `db:"entry_id"`
+	BusinessID     uuid.UUID     `db:"business_id"`
+	UserID         uuid.UUID     `db:"user_id"`
+	ServiceID      uuid.NullUUID `db:"service_id"`
+	ResourceID     uuid.NullUUID `db:"resource_id"`
+	StartsOn       time.Time     `db:"starts_on"`
+	EndsOn         time.Time     `db:"ends_on"`
+	Status         string        `db:"status"`
+	HeldServiceID  uuid.NullUUID `db:"held_service_id"`
+	HeldResourceID uuid.NullUUID `db:"held_resource_id"`
+	HeldStartsAt   sql.NullTime  `db:"held_starts_at"`
+	HeldEndsAt     sql.NullTime  `db:"held_ends_at"`
+	HeldUntil      sql.NullTime  `db:"held_until"`
+	DateCreated    time.Time     `db:"date_created"`
+	DateUpdated    time.Time     `db:"date_updated"`
+}
+
+// toDBTime converts a time which may be zero, such as the hold of an entry not
+// offered a slot yet, to a nullable column.
+func toDBTime(t time.Time) sql.NullTime {
+	if t.IsZero() {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{Time: t.UTC(), Valid: true}
+}
+
+func toCoreTime(t sql.NullTime) time.Time {
+	if !t.Valid {
+		return time.Time{}
+	}
+
+	return t.Time.In(time.Local)
+}
+
+func toDBEntry(e waitlist.Entry) dbEntry {
+	return dbEntry{
+		ID:             e.ID,
+		BusinessID:     e.BusinessID,
+		UserID:         e.UserID,
+		ServiceID:      uuid.NullUUID{UUID: e.ServiceID, Valid: e.ServiceID != uuid.Nil},
+		ResourceID:     uuid.NullUUID{UUID: e.ResourceID, Valid: e.ResourceID != uuid.Nil},
+		StartsOn:       e.From.UTC(),
+		EndsOn:         e.To.UTC(),
+		Status:         e.Status.Status(),
+		HeldServiceID:  uuid.NullUUID{UUID: e.Held.ServiceID, Valid: e.Held.ServiceID != uuid.Nil},
+		HeldResourceID: uuid.NullUUID{UUID: e.Held.ResourceID, Valid: e.Held.ResourceID != uuid.Nil},
+		HeldStartsAt:   toDBTime(e.Held.StartsAt),
+		HeldEndsAt:     toDBTime(e.Held.EndsAt),
+		HeldUntil:      toDBTime(e.HeldUntil),
+		DateCreated:    e.DateCreated.UTC(),
+		DateUpdated:    e.DateUpdated.UTC(),
+	}
+}
+
+func toCoreEntry(dbE dbEntry) (waitlist.Entry, error) {
+	status, err := waitlist.ParseStatus(dbE.Status)
+	if err != nil {
+		return waitlist.Entry{}, fmt.Errorf("parse status: %w", err)
+	}
+
+	e := waitlist.Entry{
+		ID:         dbE.ID,
+		BusinessID: dbE.BusinessID,
+		UserID:     dbE.UserID,
+		ServiceID:  dbE.ServiceID.UUID,
+		ResourceID: dbE.ResourceID.UUID,
+		From:       dbE.StartsOn.In(time.Local),
+		To:         dbE.EndsOn.In(time.Local),
+		Status:     status,
+		Held: waitlist.Slot{
+			ServiceID:  dbE.HeldServiceID.UUID,
+			ResourceID: dbE.HeldResourceID.UUID,
+			StartsAt:   toCoreTime(dbE.HeldStartsAt),
+			EndsAt:     toCoreTime(dbE.HeldEndsAt),
+		},
+		HeldUntil:   toCoreTime(dbE.HeldUntil),
+		DateCreated: dbE.DateCreated.In(time.Local),
+		DateUpdated: dbE.DateUpdated.In(time.Local),
+	}
+
+	return e, nil
+}
+
+func toCoreEntrySlice(dbEntries []dbEntry) ([]waitlist.Entry, error) {
+	entries := make([]waitlist.Entry, len(dbEntries))
+	for i, dbE := range dbEntries {
+		var err error
+		entries[i], err = toCoreEntry(dbE)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
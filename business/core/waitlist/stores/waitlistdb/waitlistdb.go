@@ -0,0 +1,215 @@
+// Package waitlistdb contains waitlist related CRUD functionality.
+package waitlistdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/waitlist"
+	db "github.com/ameghdadian/service/business/data/dbsql/pgx"
+	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type Store struct {
+	log *logger.Logger
+	db  sqlx.ExtContext
+}
+
+func NewStore(log *logger.Logger, db *sqlx.DB) *Store {
+	return &Store{
+		log: log,
+		db:  db,
+	}
+}
+
+func (s *Store) ExecuteUnderTransaction(tx transaction.Transaction) (waitlist.Storer, error) {
+	ec, err := db.GetExtContext(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	s = &Store{
+		db:  ec,
+		log: s.log,
+	}
+
+	return s, nil
+}
+
+func (s *Store) Create(ctx context.Context, e waitlist.Entry) error {
+	const q = `
+	INSERT INTO waitlist_entries
+		(entry_id, business_id, user_id, service_id, resource_id, starts_on, ends_on, status,
+		held_service_id, held_resource_id, held_starts_at, held_ends_at, held_until, date_created, date_updated)
+	VALUES
+		(:entry_id, :business_id, :user_id, :service_id, :resource_id, :starts_on, :ends_on, :status,
+		:held_service_id, :held_resource_id, :held_starts_at, :held_ends_at, :held_until, :date_created, :date_updated)
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBEntry(e)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Update(ctx context.Context, e waitlist.Entry) error {
+	const q = `
+	UPDATE
+		waitlist_entries
+	SET
+		"status" = :status,
+		"held_service_id" = :held_service_id,
+		"held_resource_id" = :held_resource_id,
+		"held_starts_at" = :held_starts_at,
+		"held_ends_at" = :held_ends_at,
+		"held_until" = :held_until,
+		"date_updated" = :date_updated
+	WHERE
+		entry_id = :entry_id
+	`
+
+	if err := db.NamedExecContext(ctx, s.log, s.db, q, toDBEntry(e)); err != nil {
+		return fmt.Errorf("namedexeccontext: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) QueryByID(ctx context.Context, entryID uuid.UUID) (waitlist.Entry, error) {
+	data := struct {
+		EntryID string `db:"entry_id"`
+	}{
+		EntryID: entryID.String(),
+	}
+
+	const q = `
+	SELECT
+		entry_id, business_id, user_id, service_id, resource_id, starts_on, ends_on, status,
+		held_service_id, held_resource_id, held_starts_at, held_ends_at, held_until, date_created, date_updated
+	FROM
+		waitlist_entries
+	WHERE
+		entry_id = :entry_id
+	`
+
+	var dbE dbEntry
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbE); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return waitlist.Entry{}, fmt.Errorf("namedquerystruct: %w", waitlist.ErrNotFound)
+		}
+		return waitlist.Entry{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreEntry(dbE)
+}
+
+func (s *Store) QueryByUserID(ctx context.Context, usrID uuid.UUID) ([]waitlist.Entry, error) {
+	data := struct {
+		UserID string `db:"user_id"`
+	}{
+		UserID: usrID.String(),
+	}
+
+	const q = `
+	SELECT
+		entry_id, business_id, user_id, service_id, resource_id, starts_on, ends_on, status,
+		held_service_id, held_resource_id, held_starts_at, held_ends_at, held_until, date_created, date_updated
+	FROM
+		waitlist_entries
+	WHERE
+		user_id = :user_id
+	ORDER BY
+		date_created
+	`
+
+	var dbEntries []dbEntry
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEntries); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreEntrySlice(dbEntries)
+}
+
+// QueryNextWaiting returns the entry waiting the longest for the slot, locking it
+// so two releases of the same slot don't offer it to the same customer.
+func (s *Store) QueryNextWaiting(ctx context.Context, bsnID uuid.UUID, slot waitlist.Slot, exclUsrID uuid.UUID) (waitlist.Entry, error) {
+	data := map[string]any{
+		"business_id": bsnID,
+		"service_id":  uuid.NullUUID{UUID: slot.ServiceID, Valid: slot.ServiceID != uuid.Nil},
+		"resource_id": uuid.NullUUID{UUID: slot.ResourceID, Valid: slot.ResourceID != uuid.Nil},
+		"starts_at":   slot.StartsAt.UTC(),
+		"user_id":     exclUsrID,
+		"waiting":     waitlist.StatusWaiting.Status(),
+	}
+
+	const q = `
+	SELECT
+		entry_id, business_id, user_id, service_id, resource_id, starts_on, ends_on, status,
+		held_service_id, held_resource_id, held_starts_at, held_ends_at, held_until, date_created, date_updated
+	FROM
+		waitlist_entries
+	WHERE
+		business_id = :business_id AND
+		status = :waiting AND
+		user_id <> :user_id AND
+		starts_on <= :starts_at AND
+		ends_on > :starts_at AND
+		(service_id IS NULL OR service_id = :service_id) AND
+		(resource_id IS NULL OR resource_id = :resource_id)
+	ORDER BY
+		date_created
+	LIMIT 1
+	FOR UPDATE SKIP LOCKED
+	`
+
+	var dbE dbEntry
+	if err := db.NamedQueryStruct(ctx, s.log, s.db, q, data, &dbE); err != nil {
+		if errors.Is(err, db.ErrDBNotFound) {
+			return waitlist.Entry{}, fmt.Errorf("namedquerystruct: %w", waitlist.ErrNotFound)
+		}
+		return waitlist.Entry{}, fmt.Errorf("namedquerystruct: %w", err)
+	}
+
+	return toCoreEntry(dbE)
+}
+
+// QueryHolding returns the entries holding a slot of the business, or of its
+// resource, which overlaps with the given range.
+func (s *Store) QueryHolding(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, from time.Time, to time.Time, now time.Time) ([]waitlist.Entry, error) {
+	data := map[string]any{
+		"business_id": bsnID,
+		"resource_id": uuid.NullUUID{UUID: rscID, Valid: rscID != uuid.Nil},
+		"from":        from.UTC(),
+		"to":          to.UTC(),
+		"now":         now.UTC(),
+		"offered":     waitlist.StatusOffered.Status(),
+	}
+
+	const q = `
+	SELECT
+		entry_id, business_id, user_id, service_id, resource_id, starts_on, ends_on, status,
+		held_service_id, held_resource_id, held_starts_at, held_ends_at, held_until, date_created, date_updated
+	FROM
+		waitlist_entries
+	WHERE
+		business_id = :business_id AND
+		status = :offered AND
+		held_resource_id IS NOT DISTINCT FROM :resource_id AND
+		held_starts_at < :to AND
+		held_ends_at > :from AND
+		held_until > :now
+	`
+
+	var dbEntries []dbEntry
+	if err := db.NamedQuerySlice(ctx, s.log, s.db, q, data, &dbEntries); err != nil {
+		return nil, fmt.Errorf("namedqueryslice: %w", err)
+	}
+
+	return toCoreEntrySlice(dbEntries)
+}
@@ -0,0 +1,294 @@
+package waitlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/foundation/email"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/sms"
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TypeNotifyOffer = "waitlist:offer"
+	TypeEmailOffer  = "waitlist:offer:email"
+	TypeExpireOffer = "waitlist:expire"
+)
+
+type Task struct {
+	client    *asynq.Client
+	inspector *asynq.Inspector
+}
+
+func NewTask(client *asynq.Client, inspector *asynq.Inspector) *Task {
+	return &Task{
+		client:    client,
+		inspector: inspector,
+	}
+}
+
+type notifyOfferPayload struct {
+	EntryID   uuid.UUID
+	UserID    uuid.UUID
+	StartsAt  time.Time
+	HeldUntil time.Time
+}
+
+type expireOfferPayload struct {
+	EntryID uuid.UUID
+}
+
+func expireOfferTaskID(entryID string) string {
+	return TypeExpireOffer + ":" + entryID
+}
+
+// newNotifyOfferTask tells the customer of the entry about the slot held for them,
+// by SMS and email, each sent by a task of its own.
+func (t *Task) newNotifyOfferTask(e Entry) error {
+	data := notifyOfferPayload{
+		EntryID:   e.ID,
+		UserID:    e.UserID,
+		StartsAt:  e.Held.StartsAt,
+		HeldUntil: e.HeldUntil,
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("creating a new notify offer task: %w", err)
+	}
+
+	for _, typ := range []string{TypeNotifyOffer, TypeEmailOffer} {
+		task := asynq.NewTask(
+			typ,
+			payload,
+			asynq.Timeout(time.Minute*1),
+		)
+
+		if _, err := t.client.Enqueue(task); err != nil {
+			return fmt.Errorf("enqueue task[%s]: %w", typ, err)
+		}
+	}
+
+	return nil
+}
+
+// newExpireOfferTask schedules the end of the hold of the entry.
+func (t *Task) newExpireOfferTask(e Entry) error {
+	payload, err := json.Marshal(expireOfferPayload{EntryID: e.ID})
+	if err != nil {
+		return fmt.Errorf("creating a new expire offer task: %w", err)
+	}
+
+	task := asynq.NewTask(
+		TypeExpireOffer,
+		payload,
+		asynq.TaskID(expireOfferTaskID(e.ID.String())),
+		asynq.ProcessAt(e.HeldUntil.UTC()),
+		asynq.Timeout(time.Minute*1),
+	)
+
+	if _, err := t.client.Enqueue(task); err != nil {
+		return fmt.Errorf("enqueue task[%s]: %w", TypeExpireOffer, err)
+	}
+
+	return nil
+}
+
+func (t *Task) cancelExpireOfferTask(entryID string) error {
+	// A task already processed, or cancelled before, is gone by now.
+	if err := t.inspector.DeleteTask("default", expireOfferTaskID(entryID)); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+		return fmt.Errorf("delete scheduled expire offer task: %w", err)
+	}
+
+	return nil
+}
+
+// ----------------------------------------------------------------------------------------------------------
+
+type TaskHandlers struct {
+	log     *logger.Logger
+	usrCore *user.Core
+	bsnCore *business.Core
+	wlCore  *Core
+	sender  sms.Sender
+	mailer  email.Sender
+	links   appointment.Unsubscriber
+}
+
+func NewTaskHandlers(log *logger.Logger, usrCore *user.Core, bsnCore *business.Core, wlCore *Core, sender sms.Sender, mailer email.Sender, links appointment.Unsubscriber) *TaskHandlers {
+	return &TaskHandlers{
+		log:     log,
+		usrCore: usrCore,
+		bsnCore: bsnCore,
+		wlCore:  wlCore,
+		sender:  sender,
+		mailer:  mailer,
+		links:   links,
+	}
+}
+
+// HandleNotifyOffer texts the waitlisted customer about the slot held for them.
+// Offers made within the quiet hours of the customer aren't texted, the hold
+// lapsing long before they end; the email of the offer still tells of it.
+func (t *TaskHandlers) HandleNotifyOffer(ctx context.Context, tsk *asynq.Task) error {
+	var p notifyOfferPayload
+	if err := json.Unmarshal(tsk.Payload(), &p); err != nil {
+		return err
+	}
+
+	n, ok, err := t.offered(ctx, p)
+	if err != nil || !ok {
+		return err
+	}
+
+	if n.usr.PhoneNo.Number() == "" || !n.usr.Preferences.Allows(user.ChannelSMS) {
+		t.log.Info(ctx, "skipping offer sms", "entryID", p.EntryID, "userID", n.usr.ID)
+		return nil
+	}
+
+	// Users have no time zone of their own, so their quiet hours are kept by the
+	// wall clock of the business.
+	now := time.Now().In(n.loc)
+	if n.usr.Preferences.QuietHours.Until(now).After(now) {
+		t.log.Info(ctx, "skipping offer sms in quiet hours", "entryID", p.EntryID, "userID", n.usr.ID)
+		return nil
+	}
+
+	body, err := renderOfferSMS(n.usr.Preferences.Language, n.data)
+	if err != nil {
+		return fmt.Errorf("render: %w: %w", err, asynq.SkipRetry)
+	}
+
+	msg := sms.Message{
+		To:   n.usr.PhoneNo.Number(),
+		Body: body,
+	}
+
+	if err := t.sender.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send: userID[%s]: %w", n.usr.ID, err)
+	}
+
+	t.log.Info(ctx, "sent offer sms", "entryID", p.EntryID, "userID", n.usr.ID, "startsAt", p.StartsAt.Format(time.RFC3339))
+
+	return nil
+}
+
+// HandleEmailOffer emails the waitlisted customer about the slot held for them.
+func (t *TaskHandlers) HandleEmailOffer(ctx context.Context, tsk *asynq.Task) error {
+	var p notifyOfferPayload
+	if err := json.Unmarshal(tsk.Payload(), &p); err != nil {
+		return err
+	}
+
+	n, ok, err := t.offered(ctx, p)
+	if err != nil || !ok {
+		return err
+	}
+
+	if n.usr.Email.Address == "" || !n.usr.Preferences.Allows(user.ChannelEmail) {
+		t.log.Info(ctx, "skipping offer email", "entryID", p.EntryID, "userID", n.usr.ID)
+		return nil
+	}
+
+	unsubscribe, err := t.links.UnsubscribeURL(n.usr.ID, user.ChannelEmail)
+	if err != nil {
+		return fmt.Errorf("unsubscribeurl: %w", err)
+	}
+	n.data.Unsubscribe = unsubscribe
+
+	text, html, err := renderOfferEmail(n.usr.Preferences.Language, n.data)
+	if err != nil {
+		return fmt.Errorf("render: %w: %w", err, asynq.SkipRetry)
+	}
+
+	msg := email.Message{
+		To:          n.usr.Email,
+		Subject:     offerSubject,
+		Text:        text,
+		HTML:        html,
+		Unsubscribe: unsubscribe,
+	}
+
+	if err := t.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("send: userID[%s]: %w", n.usr.ID, err)
+	}
+
+	t.log.Info(ctx, "sent offer email", "entryID", p.EntryID, "userID", n.usr.ID, "startsAt", p.StartsAt.Format(time.RFC3339))
+
+	return nil
+}
+
+// offerNotice is the customer told of an offer, what they're told, and the
+// location of the business the offer is told in.
+type offerNotice struct {
+	usr  user.User
+	loc  *time.Location
+	data offerData
+}
+
+// offered returns the notice of the offer of the payload, for the customer still
+// offered the slot. It reports false for offers since withdrawn, booked or
+// expired, and for customers disabled meanwhile, who aren't told of them.
+func (t *TaskHandlers) offered(ctx context.Context, p notifyOfferPayload) (offerNotice, bool, error) {
+	e, err := t.wlCore.QueryByID(ctx, p.EntryID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return offerNotice{}, false, fmt.Errorf("querybyid: %s: %w: %w", p.EntryID, err, asynq.SkipRetry)
+		}
+		return offerNotice{}, false, fmt.Errorf("querybyid: %s: %w", p.EntryID, err)
+	}
+
+	if e.Status != StatusOffered || !e.HeldUntil.Equal(p.HeldUntil) {
+		t.log.Info(ctx, "skipping outdated offer", "entryID", e.ID, "status", e.Status.Status())
+		return offerNotice{}, false, nil
+	}
+
+	usr, err := t.usrCore.QueryByID(ctx, e.UserID)
+	if err != nil {
+		// Users deleted since joining the waitlist are no longer told of offers.
+		if errors.Is(err, user.ErrNotFound) {
+			return offerNotice{}, false, fmt.Errorf("user.querybyid: %s: %w: %w", e.UserID, err, asynq.SkipRetry)
+		}
+		return offerNotice{}, false, fmt.Errorf("user.querybyid: %s: %w", e.UserID, err)
+	}
+
+	if !usr.Enabled {
+		t.log.Info(ctx, "skipping offer", "entryID", e.ID, "userID", usr.ID, "enabled", usr.Enabled)
+		return offerNotice{}, false, nil
+	}
+
+	bsn, err := t.bsnCore.QueryByID(ctx, e.BusinessID)
+	if err != nil {
+		return offerNotice{}, false, fmt.Errorf("business.querybyid: %s: %w", e.BusinessID, err)
+	}
+
+	loc := bsn.TimeZone.Location()
+
+	n := offerNotice{
+		usr:  usr,
+		loc:  loc,
+		data: newOfferData(usr.Name, e.Held.StartsAt.In(loc), e.HeldUntil.In(loc), ""),
+	}
+
+	return n, true, nil
+}
+
+func (t *TaskHandlers) HandleExpireOffer(ctx context.Context, tsk *asynq.Task) error {
+	var p expireOfferPayload
+	if err := json.Unmarshal(tsk.Payload(), &p); err != nil {
+		return err
+	}
+
+	if err := t.wlCore.Expire(ctx, p.EntryID); err != nil {
+		return fmt.Errorf("expire: %w", err)
+	}
+
+	return nil
+}
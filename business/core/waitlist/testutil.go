@@ -0,0 +1,42 @@
+package waitlist
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TestGenerateNewEntries generates entries waiting for any slot of the business
+// within the given range.
+func TestGenerateNewEntries(n int, usrID uuid.UUID, bsnID uuid.UUID, from time.Time, to time.Time) []NewEntry {
+	newEntries := make([]NewEntry, n)
+
+	for i := 0; i < n; i++ {
+		newEntries[i] = NewEntry{
+			BusinessID: bsnID,
+			UserID:     usrID,
+			From:       from,
+			To:         to,
+		}
+	}
+
+	return newEntries
+}
+
+func TestGenerateSeedEntries(n int, api *Core, usrID uuid.UUID, bsnID uuid.UUID, from time.Time, to time.Time) ([]Entry, error) {
+	newEntries := TestGenerateNewEntries(n, usrID, bsnID, from, to)
+
+	entries := make([]Entry, len(newEntries))
+	for i, ne := range newEntries {
+		e, err := api.Create(context.Background(), ne)
+		if err != nil {
+			return nil, fmt.Errorf("seeding waitlist entry: idx: %d: %w", i, err)
+		}
+
+		entries[i] = e
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,339 @@
+// Package waitlist provides support for customers waiting for booked up slots of a
+// business to free up.
+package waitlist
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/data/transaction"
+	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/otel"
+	"github.com/google/uuid"
+)
+
+// HoldDuration is how long a freed slot is held for the customer it's offered to.
+const HoldDuration = 15 * time.Minute
+
+var (
+	ErrNotFound     = errors.New("waitlist entry not found")
+	ErrUserDisabled = errors.New("user disabled")
+	ErrInvalidRange = errors.New("range must end after it starts")
+	ErrPastRange    = errors.New("range is already past")
+	ErrNotActive    = errors.New("entry is no longer on the waitlist")
+	ErrHeld         = errors.New("slot is held for a waitlisted customer")
+	ErrNoneWaiting  = errors.New("no one is waiting for the slot")
+)
+
+type Storer interface {
+	ExecuteUnderTransaction(tx transaction.Transaction) (Storer, error)
+	Create(ctx context.Context, e Entry) error
+	Update(ctx context.Context, e Entry) error
+	QueryByID(ctx context.Context, entryID uuid.UUID) (Entry, error)
+	QueryByUserID(ctx context.Context, usrID uuid.UUID) ([]Entry, error)
+	QueryNextWaiting(ctx context.Context, bsnID uuid.UUID, slot Slot, exclUsrID uuid.UUID) (Entry, error)
+	QueryHolding(ctx context.Context, bsnID uuid.UUID, rscID uuid.UUID, from time.Time, to time.Time, now time.Time) ([]Entry, error)
+}
+
+type Core struct {
+	storer  Storer
+	log     *logger.Logger
+	usrCore *user.Core
+	bsnCore *business.Core
+	aptCore *appointment.Core
+	task    *Task
+}
+
+func NewCore(log *logger.Logger, usrCore *user.Core, bsnCore *business.Core, aptCore *appointment.Core, storer Storer, task *Task) *Core {
+	return &Core{
+		storer:  storer,
+		log:     log,
+		usrCore: usrCore,
+		bsnCore: bsnCore,
+		aptCore: aptCore,
+		task:    task,
+	}
+}
+
+func (c *Core) ExecuteUnderTransaction(tx transaction.Transaction) (*Core, error) {
+	storer, err := c.storer.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	usrCore, err := c.usrCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	bsnCore, err := c.bsnCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	aptCore, err := c.aptCore.ExecuteUnderTransaction(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	c = &Core{
+		storer:  storer,
+		log:     c.log,
+		usrCore: usrCore,
+		bsnCore: bsnCore,
+		aptCore: aptCore,
+		task:    c.task,
+	}
+
+	return c, nil
+}
+
+func (c *Core) Create(ctx context.Context, ne NewEntry) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.waitlist.create")
+	defer span.End()
+
+	if !ne.To.After(ne.From) {
+		return Entry{}, ErrInvalidRange
+	}
+
+	now := time.Now()
+
+	if !ne.To.After(now) {
+		return Entry{}, ErrPastRange
+	}
+
+	usr, err := c.usrCore.QueryByID(ctx, ne.UserID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("user.querybyid: %s: %w", ne.UserID, err)
+	}
+
+	if !usr.Enabled {
+		return Entry{}, ErrUserDisabled
+	}
+
+	bsn, err := c.bsnCore.QueryByID(ctx, ne.BusinessID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("business.querybyid: %s: %w", ne.BusinessID, err)
+	}
+
+	e := Entry{
+		ID:          uuid.New(),
+		BusinessID:  bsn.ID,
+		UserID:      usr.ID,
+		ServiceID:   ne.ServiceID,
+		ResourceID:  ne.ResourceID,
+		From:        ne.From,
+		To:          ne.To,
+		Status:      StatusWaiting,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+
+	if err := c.storer.Create(ctx, e); err != nil {
+		return Entry{}, fmt.Errorf("create: %w", err)
+	}
+
+	return e, nil
+}
+
+// Withdraw takes the entry off the waitlist. A slot offered to it is offered to
+// whoever is waiting next.
+func (c *Core) Withdraw(ctx context.Context, e Entry) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.waitlist.withdraw")
+	defer span.End()
+
+	if !e.Status.Active() {
+		return Entry{}, ErrNotActive
+	}
+
+	offered := e.Status == StatusOffered
+
+	e.Status = StatusWithdrawn
+	e.DateUpdated = time.Now()
+
+	if err := c.storer.Update(ctx, e); err != nil {
+		return Entry{}, fmt.Errorf("update: %w", err)
+	}
+
+	if offered {
+		if err := c.task.cancelExpireOfferTask(e.ID.String()); err != nil {
+			return Entry{}, fmt.Errorf("cancelexpireoffertask: %w", err)
+		}
+
+		if _, err := c.offer(ctx, e.BusinessID, e.Held, e.UserID); err != nil && !errors.Is(err, ErrNoneWaiting) {
+			return Entry{}, err
+		}
+	}
+
+	return e, nil
+}
+
+// Release offers the slot freed by the appointment, cancelled or deleted, to the
+// first customer waiting for it. The slot is held for the customer for HoldDuration,
+// and the customer is notified. It returns ErrNoneWaiting if nobody waits for it.
+func (c *Core) Release(ctx context.Context, apt appointment.Appointment) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.waitlist.release")
+	defer span.End()
+
+	return c.offer(ctx, apt.BusinessID, slotOf(apt), apt.UserID)
+}
+
+// Expire ends the hold of the entry once it's due, offering the slot to whoever is
+// waiting next. Entries no longer offered the slot are left as they are.
+func (c *Core) Expire(ctx context.Context, entryID uuid.UUID) error {
+	ctx, span := otel.AddSpan(ctx, "business.waitlist.expire")
+	defer span.End()
+
+	e, err := c.storer.QueryByID(ctx, entryID)
+	if err != nil {
+		return fmt.Errorf("query: entryID[%s]: %w", entryID, err)
+	}
+
+	now := time.Now()
+
+	if e.Status != StatusOffered || e.HeldUntil.After(now) {
+		return nil
+	}
+
+	e.Status = StatusExpired
+	e.DateUpdated = now
+
+	if err := c.storer.Update(ctx, e); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	if _, err := c.offer(ctx, e.BusinessID, e.Held, e.UserID); err != nil && !errors.Is(err, ErrNoneWaiting) {
+		return err
+	}
+
+	return nil
+}
+
+// CheckHold returns ErrHeld if the appointment overlaps with a slot of its
+// business, or its resource, held for a customer other than its own.
+func (c *Core) CheckHold(ctx context.Context, apt appointment.Appointment) error {
+	ctx, span := otel.AddSpan(ctx, "business.waitlist.checkhold")
+	defer span.End()
+
+	entries, err := c.storer.QueryHolding(ctx, apt.BusinessID, apt.ResourceID, apt.ScheduledOn, apt.EndsOn(), time.Now())
+	if err != nil {
+		return fmt.Errorf("queryholding: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.UserID != apt.UserID {
+			return ErrHeld
+		}
+	}
+
+	return nil
+}
+
+// Fulfil takes the customer who booked the appointment off the waitlist, if it was
+// offered the slot of the appointment.
+func (c *Core) Fulfil(ctx context.Context, apt appointment.Appointment) error {
+	ctx, span := otel.AddSpan(ctx, "business.waitlist.fulfil")
+	defer span.End()
+
+	now := time.Now()
+
+	entries, err := c.storer.QueryHolding(ctx, apt.BusinessID, apt.ResourceID, apt.ScheduledOn, apt.EndsOn(), now)
+	if err != nil {
+		return fmt.Errorf("queryholding: %w", err)
+	}
+
+	for _, e := range entries {
+		if e.UserID != apt.UserID {
+			continue
+		}
+
+		e.Status = StatusBooked
+		e.DateUpdated = now
+
+		if err := c.storer.Update(ctx, e); err != nil {
+			return fmt.Errorf("update: %w", err)
+		}
+
+		if err := c.task.cancelExpireOfferTask(e.ID.String()); err != nil {
+			return fmt.Errorf("cancelexpireoffertask: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c *Core) QueryByID(ctx context.Context, entryID uuid.UUID) (Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.waitlist.querybyid")
+	defer span.End()
+
+	e, err := c.storer.QueryByID(ctx, entryID)
+	if err != nil {
+		return Entry{}, fmt.Errorf("query: entryID[%s]: %w", entryID, err)
+	}
+
+	return e, nil
+}
+
+func (c *Core) QueryByUserID(ctx context.Context, usrID uuid.UUID) ([]Entry, error) {
+	ctx, span := otel.AddSpan(ctx, "business.waitlist.querybyuserid")
+	defer span.End()
+
+	entries, err := c.storer.QueryByUserID(ctx, usrID)
+	if err != nil {
+		return nil, fmt.Errorf("query: userID[%s]: %w", usrID, err)
+	}
+
+	return entries, nil
+}
+
+// offer holds the slot for the first customer waiting for it, other than the
+// given user, and notifies the customer. Slots booked again meanwhile, such as by
+// the booking that lapsed the hold freeing them, aren't offered.
+func (c *Core) offer(ctx context.Context, bsnID uuid.UUID, slot Slot, exclUsrID uuid.UUID) (Entry, error) {
+	now := time.Now()
+
+	if !slot.StartsAt.After(now) {
+		return Entry{}, ErrNoneWaiting
+	}
+
+	free, err := c.aptCore.SlotFree(ctx, slot.appointment(bsnID))
+	if err != nil {
+		return Entry{}, fmt.Errorf("slotfree: %w", err)
+	}
+
+	if !free {
+		return Entry{}, ErrNoneWaiting
+	}
+
+	e, err := c.storer.QueryNextWaiting(ctx, bsnID, slot, exclUsrID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return Entry{}, ErrNoneWaiting
+		}
+		return Entry{}, fmt.Errorf("querynextwaiting: %w", err)
+	}
+
+	e.Status = StatusOffered
+	e.Held = slot
+	e.HeldUntil = now.Add(HoldDuration)
+	e.DateUpdated = now
+
+	if err := c.storer.Update(ctx, e); err != nil {
+		return Entry{}, fmt.Errorf("update: %w", err)
+	}
+
+	if err := c.task.newNotifyOfferTask(e); err != nil {
+		return Entry{}, fmt.Errorf("newnotifyoffertask: %w", err)
+	}
+
+	if err := c.task.newExpireOfferTask(e); err != nil {
+		return Entry{}, fmt.Errorf("newexpireoffertask: %w", err)
+	}
+
+	return e, nil
+}
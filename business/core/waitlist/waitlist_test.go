@@ -0,0 +1,211 @@
+package waitlist_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/appointment"
+	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/core/waitlist"
+	"github.com/ameghdadian/service/business/data/dbtest"
+	"github.com/ameghdadian/service/business/data/page"
+	"github.com/ameghdadian/service/business/data/redistest"
+	"github.com/ameghdadian/service/foundation/docker"
+)
+
+var c *docker.Container
+var rc *docker.Container
+
+func TestMain(m *testing.M) {
+	var err error
+	fmt.Println("Starting a new database")
+	c, err = dbtest.StartDB()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer dbtest.StopDB(c)
+
+	fmt.Println("Starting a new redis")
+	rc, err = redistest.StartRedis()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer redistest.StopRedis(rc)
+	m.Run()
+}
+
+func Test_Waitlist(t *testing.T) {
+	t.Run("release", release)
+}
+
+func release(t *testing.T) {
+	test := dbtest.NewTest(t, c, rc)
+	defer func() {
+		if r := recover(); r != nil {
+			t.Log(r)
+			t.Error(string(debug.Stack()))
+		}
+		test.Teardown()
+	}()
+
+	api := test.CoreAPIs
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	queryUser := func(name string) user.User {
+		var filter user.QueryFilter
+		filter.WithName(name)
+
+		usrs, err := api.User.Query(ctx, filter, user.DefaultOrderBy, page.MustParse("1", "1"))
+		if err != nil || len(usrs) == 0 {
+			t.Fatalf("Should be able to query user %q: %v", name, err)
+		}
+
+		return usrs[0]
+	}
+
+	admin := queryUser("Admin Gopher")
+	usr := queryUser("User Gopher")
+
+	bsns, err := business.TestGenerateSeedBusinesses(1, api.Business, admin.ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed businesses: %s", err)
+	}
+
+	svcs, err := service.TestGenerateSeedServices(1, api.Service, bsns[0].ID)
+	if err != nil {
+		t.Fatalf("Should be able to seed services: %s", err)
+	}
+
+	sch := time.Now().Add(48 * time.Hour).Truncate(time.Minute)
+
+	apt, err := api.Appointment.Create(ctx, appointment.NewAppointment{
+		BusinessID:  bsns[0].ID,
+		UserID:      admin.ID,
+		ServiceID:   svcs[0].ID,
+		ScheduledOn: sch,
+	})
+	if err != nil {
+		t.Fatalf("Should be able to book the slot: %s", err)
+	}
+
+	// -------------------------------------------------------------------
+	// Create
+
+	from, to := sch.Add(-time.Hour), sch.Add(time.Hour)
+
+	if _, err := api.Waitlist.Create(ctx, waitlist.NewEntry{BusinessID: bsns[0].ID, UserID: usr.ID, From: to, To: from}); !errors.Is(err, waitlist.ErrInvalidRange) {
+		t.Error("Should reject a range ending before it starts")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", waitlist.ErrInvalidRange)
+	}
+
+	entries, err := waitlist.TestGenerateSeedEntries(2, api.Waitlist, usr.ID, bsns[0].ID, from, to)
+	if err != nil {
+		t.Fatalf("Should be able to join the waitlist: %s", err)
+	}
+
+	// -------------------------------------------------------------------
+	// Release
+
+	apt, err = api.Appointment.Transition(ctx, apt, appointment.StatusCancelled, appointment.PartyCustomer, "")
+	if err != nil {
+		t.Fatalf("Should be able to cancel the appointment: %s", err)
+	}
+
+	offered, err := api.Waitlist.Release(ctx, apt)
+	if err != nil {
+		t.Fatalf("Should be able to release the slot: %s", err)
+	}
+
+	if offered.ID != entries[0].ID || offered.Status != waitlist.StatusOffered {
+		t.Error("Should offer the slot to the entry waiting the longest")
+		t.Errorf("GOT: %s %s\n", offered.ID, offered.Status.Status())
+		t.Errorf("EXP: %s %s\n", entries[0].ID, waitlist.StatusOffered.Status())
+	}
+
+	if !offered.Held.StartsAt.Equal(sch) {
+		t.Error("Should hold the released slot")
+		t.Errorf("GOT: %s\n", offered.Held.StartsAt)
+		t.Errorf("EXP: %s\n", sch)
+	}
+
+	// -------------------------------------------------------------------
+	// CheckHold
+
+	other := apt
+	other.UserID = admin.ID
+
+	if err := api.Waitlist.CheckHold(ctx, other); !errors.Is(err, waitlist.ErrHeld) {
+		t.Error("Should keep others from booking a held slot")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", waitlist.ErrHeld)
+	}
+
+	// A booking starting before the held slot still overlaps with it.
+	other.ScheduledOn = sch.Add(-apt.Duration / 2)
+
+	if err := api.Waitlist.CheckHold(ctx, other); !errors.Is(err, waitlist.ErrHeld) {
+		t.Error("Should keep others from booking into a held slot")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", waitlist.ErrHeld)
+	}
+
+	own := apt
+	own.UserID = usr.ID
+
+	if err := api.Waitlist.CheckHold(ctx, own); err != nil {
+		t.Errorf("Should let the customer book the slot held for them: %s", err)
+	}
+
+	// -------------------------------------------------------------------
+	// Fulfil
+
+	booked, err := api.Appointment.Create(ctx, appointment.NewAppointment{
+		BusinessID:  bsns[0].ID,
+		UserID:      usr.ID,
+		ServiceID:   svcs[0].ID,
+		ScheduledOn: sch,
+	})
+	if err != nil {
+		t.Fatalf("Should be able to book the held slot: %s", err)
+	}
+
+	if err := api.Waitlist.Fulfil(ctx, booked); err != nil {
+		t.Fatalf("Should be able to fulfil the entry: %s", err)
+	}
+
+	saved, err := api.Waitlist.QueryByID(ctx, offered.ID)
+	if err != nil {
+		t.Fatalf("Should be able to retrieve entry by ID: %s", err)
+	}
+
+	if saved.Status != waitlist.StatusBooked {
+		t.Error("Should take the customer off the waitlist once booked")
+		t.Errorf("GOT: %s\n", saved.Status.Status())
+		t.Errorf("EXP: %s\n", waitlist.StatusBooked.Status())
+	}
+
+	// -------------------------------------------------------------------
+	// Withdraw
+
+	withdrawn, err := api.Waitlist.Withdraw(ctx, entries[1])
+	if err != nil {
+		t.Fatalf("Should be able to withdraw from the waitlist: %s", err)
+	}
+
+	if _, err := api.Waitlist.Withdraw(ctx, withdrawn); !errors.Is(err, waitlist.ErrNotActive) {
+		t.Error("Should not withdraw an entry twice")
+		t.Errorf("GOT: %v\n", err)
+		t.Errorf("EXP: %s\n", waitlist.ErrNotActive)
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE businesses
+    ADD COLUMN IF NOT EXISTS time_zone TEXT NOT NULL DEFAULT 'UTC';
\ No newline at end of file
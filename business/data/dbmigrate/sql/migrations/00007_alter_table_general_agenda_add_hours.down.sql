@@ -0,0 +1,23 @@
+ALTER TABLE general_agenda
+    ADD COLUMN IF NOT EXISTS opens_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS interval INTEGER CHECK(interval > 0 AND interval <= 86400),
+    ADD COLUMN IF NOT EXISTS working_days INTEGER[];
+
+-- A single pair of opening and closing hours can't hold several ranges per day, so
+-- the earliest opening, the latest closing and the shortest interval are kept.
+UPDATE general_agenda ga
+SET
+    opens_at = ((CURRENT_DATE + COALESCE((SELECT min((h->>'opens_at')::TIME) FROM jsonb_array_elements(ga.hours) h), '00:00'::TIME)) AT TIME ZONE b.time_zone) AT TIME ZONE 'UTC',
+    closed_at = ((CURRENT_DATE + COALESCE((SELECT max((h->>'closed_at')::TIME) FROM jsonb_array_elements(ga.hours) h), '00:00'::TIME)) AT TIME ZONE b.time_zone) AT TIME ZONE 'UTC',
+    interval = COALESCE((SELECT min((h->>'interval')::INTEGER) FROM jsonb_array_elements(ga.hours) h), 3600),
+    working_days = COALESCE((SELECT array_agg(DISTINCT (h->>'day')::INTEGER) FROM jsonb_array_elements(ga.hours) h), '{}')
+FROM businesses b
+WHERE b.business_id = ga.business_id;
+
+ALTER TABLE general_agenda
+    ALTER COLUMN opens_at SET NOT NULL,
+    ALTER COLUMN closed_at SET NOT NULL,
+    ALTER COLUMN interval SET NOT NULL,
+    ALTER COLUMN working_days SET NOT NULL,
+    DROP COLUMN IF EXISTS hours;
@@ -0,0 +1,22 @@
+ALTER TABLE general_agenda ADD COLUMN IF NOT EXISTS hours JSONB NOT NULL DEFAULT '[]';
+
+-- Existing agendas keep the same hours on each of their working days. Their opening and
+-- closing hours were kept in UTC, while hours are wall clock times of the business.
+UPDATE general_agenda ga
+SET hours = (
+    SELECT COALESCE(jsonb_agg(jsonb_build_object(
+        'day', d,
+        'opens_at', to_char((ga.opens_at AT TIME ZONE 'UTC') AT TIME ZONE b.time_zone, 'HH24:MI:SS'),
+        'closed_at', to_char((ga.closed_at AT TIME ZONE 'UTC') AT TIME ZONE b.time_zone, 'HH24:MI:SS'),
+        'interval', ga.interval
+    ) ORDER BY d), '[]'::jsonb)
+    FROM unnest(ga.working_days) AS d
+)
+FROM businesses b
+WHERE b.business_id = ga.business_id;
+
+ALTER TABLE general_agenda
+    DROP COLUMN IF EXISTS opens_at,
+    DROP COLUMN IF EXISTS closed_at,
+    DROP COLUMN IF EXISTS interval,
+    DROP COLUMN IF EXISTS working_days;
@@ -0,0 +1,37 @@
+ALTER TABLE daily_agenda RENAME TO daily_agenda_windows;
+ALTER TABLE daily_agenda_windows RENAME CONSTRAINT daily_agenda_pkey TO daily_agenda_windows_pkey;
+ALTER TABLE daily_agenda_windows RENAME CONSTRAINT daily_agenda_business_id_date_key TO daily_agenda_windows_business_id_date_key;
+
+CREATE TABLE IF NOT EXISTS daily_agenda (
+    id                  UUID        NOT NULL,
+    business_id         UUID        NOT NULL,
+    opens_at            TIMESTAMP   NULL,
+    closed_at           TIMESTAMP   NULL,
+    interval            INTEGER     NULL    CHECK(interval > 0 AND interval <= 86400),
+    availability        BOOLEAN     NOT NULL,
+    date_created        TIMESTAMP   NOT NULL,
+    date_updated        TIMESTAMP   NOT NULL,
+
+    PRIMARY KEY(id),
+    FOREIGN KEY (business_id) REFERENCES businesses(business_id) ON DELETE CASCADE
+);
+
+-- Every window becomes a row of its own, the first one keeping the id of its daily agenda.
+INSERT INTO daily_agenda (id, business_id, opens_at, closed_at, interval, availability, date_created, date_updated)
+SELECT
+    CASE WHEN w.ord = 1 THEN daw.id ELSE gen_random_uuid() END,
+    daw.business_id,
+    ((daw.date + (w.win->>'opens_at')::TIME) AT TIME ZONE b.time_zone) AT TIME ZONE 'UTC',
+    ((daw.date + (w.win->>'closed_at')::TIME) AT TIME ZONE b.time_zone) AT TIME ZONE 'UTC',
+    (w.win->>'interval')::INTEGER,
+    daw.availability,
+    daw.date_created,
+    daw.date_updated
+FROM
+    daily_agenda_windows daw
+JOIN
+    businesses b ON b.business_id = daw.business_id
+CROSS JOIN LATERAL
+    jsonb_array_elements(daw.windows) WITH ORDINALITY AS w(win, ord);
+
+DROP TABLE daily_agenda_windows;
@@ -0,0 +1,51 @@
+ALTER TABLE daily_agenda RENAME TO daily_agenda_rows;
+ALTER TABLE daily_agenda_rows RENAME CONSTRAINT daily_agenda_pkey TO daily_agenda_rows_pkey;
+
+CREATE TABLE IF NOT EXISTS daily_agenda (
+    id                  UUID        NOT NULL,
+    business_id         UUID        NOT NULL,
+    date                DATE        NOT NULL,
+    availability        BOOLEAN     NOT NULL,
+    windows             JSONB       NOT NULL DEFAULT '[]',
+    date_created        TIMESTAMP   NOT NULL,
+    date_updated        TIMESTAMP   NOT NULL,
+
+    UNIQUE (business_id, date),
+
+    PRIMARY KEY(id),
+    FOREIGN KEY (business_id) REFERENCES businesses(business_id) ON DELETE CASCADE
+);
+
+-- Rows of the same business and date, in the business time zone, become a single daily
+-- agenda whose windows are the available rows. Rows without opening hour had no date
+-- to be found by and are left out.
+INSERT INTO daily_agenda (id, business_id, date, availability, windows, date_created, date_updated)
+SELECT
+    (array_agg(r.id ORDER BY r.date_created))[1],
+    r.business_id,
+    r.local_date,
+    bool_or(r.availability AND r.closed_at IS NOT NULL),
+    COALESCE(jsonb_agg(jsonb_build_object(
+        'opens_at', to_char(r.local_opens_at, 'HH24:MI:SS'),
+        'closed_at', to_char(r.local_closed_at, 'HH24:MI:SS'),
+        'interval', r.interval
+    ) ORDER BY r.local_opens_at) FILTER (WHERE r.availability AND r.closed_at IS NOT NULL), '[]'::jsonb),
+    min(r.date_created),
+    max(r.date_updated)
+FROM (
+    SELECT
+        dar.*,
+        ((dar.opens_at AT TIME ZONE 'UTC') AT TIME ZONE b.time_zone) AS local_opens_at,
+        ((dar.closed_at AT TIME ZONE 'UTC') AT TIME ZONE b.time_zone) AS local_closed_at,
+        ((dar.opens_at AT TIME ZONE 'UTC') AT TIME ZONE b.time_zone)::DATE AS local_date
+    FROM
+        daily_agenda_rows dar
+    JOIN
+        businesses b ON b.business_id = dar.business_id
+    WHERE
+        dar.opens_at IS NOT NULL
+) r
+GROUP BY
+    r.business_id, r.local_date;
+
+DROP TABLE daily_agenda_rows;
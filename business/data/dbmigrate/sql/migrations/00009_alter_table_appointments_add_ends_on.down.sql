@@ -0,0 +1,5 @@
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_business_id_period_excl,
+    DROP CONSTRAINT IF EXISTS appointments_ends_on_check,
+    DROP COLUMN IF EXISTS ends_on,
+    ADD CONSTRAINT appointments_business_id_scheduled_on_key UNIQUE (business_id, scheduled_on);
@@ -0,0 +1,28 @@
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE appointments
+    ADD COLUMN IF NOT EXISTS ends_on TIMESTAMP;
+
+-- Existing appointments had no duration. Give them half an hour, cut short by the
+-- next appointment of the same business so that none of them overlap.
+UPDATE appointments a
+SET
+    ends_on = LEAST(a.scheduled_on + INTERVAL '30 minutes', COALESCE(n.next_on, 'infinity'))
+FROM (
+    SELECT
+        appointment_id,
+        lead(scheduled_on) OVER (PARTITION BY business_id ORDER BY scheduled_on) AS next_on
+    FROM
+        appointments
+) n
+WHERE
+    n.appointment_id = a.appointment_id;
+
+ALTER TABLE appointments
+    ALTER COLUMN ends_on SET NOT NULL,
+    DROP CONSTRAINT IF EXISTS appointments_business_id_scheduled_on_key,
+    ADD CONSTRAINT appointments_ends_on_check CHECK (ends_on > scheduled_on),
+    ADD CONSTRAINT appointments_business_id_period_excl EXCLUDE USING gist (
+        business_id WITH =,
+        tsrange(scheduled_on, ends_on) WITH &&
+    ) WHERE (status <> 0);
@@ -0,0 +1,6 @@
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS currency,
+    DROP COLUMN IF EXISTS price,
+    DROP COLUMN IF EXISTS service_id;
+
+DROP TABLE IF EXISTS services;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS services (
+    service_id      UUID        NOT NULL,
+    business_id     UUID        NOT NULL,
+    name            TEXT        NOT NULL,
+    duration        INTEGER     NOT NULL CHECK (duration > 0),
+    price           BIGINT      NOT NULL CHECK (price >= 0),
+    currency        CHAR(3)     NOT NULL,
+    date_created    TIMESTAMP   NOT NULL,
+    date_updated    TIMESTAMP   NOT NULL,
+
+    UNIQUE (business_id, name),
+
+    PRIMARY KEY(service_id),
+    FOREIGN KEY (business_id) REFERENCES businesses(business_id) ON DELETE CASCADE
+);
+
+-- Appointments keep the price they were booked at, so later changes to the
+-- catalog don't rewrite history. Appointments booked before the catalog have
+-- no service.
+ALTER TABLE appointments
+    ADD COLUMN IF NOT EXISTS service_id UUID REFERENCES services(service_id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS price BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,25 @@
+DELETE FROM appointments WHERE resource_id IS NOT NULL;
+
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_business_id_resource_id_period_excl,
+    DROP COLUMN IF EXISTS resource_id,
+    ADD CONSTRAINT appointments_business_id_period_excl EXCLUDE USING gist (
+        business_id WITH =,
+        tsrange(scheduled_on, ends_on) WITH &&
+    ) WHERE (status <> 0);
+
+DELETE FROM daily_agenda WHERE resource_id IS NOT NULL;
+
+ALTER TABLE daily_agenda
+    DROP CONSTRAINT IF EXISTS daily_agenda_business_id_resource_id_date_key,
+    DROP COLUMN IF EXISTS resource_id,
+    ADD CONSTRAINT daily_agenda_business_id_date_key UNIQUE (business_id, date);
+
+DELETE FROM general_agenda WHERE resource_id IS NOT NULL;
+
+ALTER TABLE general_agenda
+    DROP CONSTRAINT IF EXISTS general_agenda_business_id_resource_id_key,
+    DROP COLUMN IF EXISTS resource_id,
+    ADD CONSTRAINT general_agenda_business_id_key UNIQUE (business_id);
+
+DROP TABLE IF EXISTS resources;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS resources (
+    resource_id     UUID        NOT NULL,
+    business_id     UUID        NOT NULL,
+    name            TEXT        NOT NULL,
+    kind            TEXT        NOT NULL,
+    date_created    TIMESTAMP   NOT NULL,
+    date_updated    TIMESTAMP   NOT NULL,
+
+    UNIQUE (business_id, name),
+
+    PRIMARY KEY(resource_id),
+    FOREIGN KEY (business_id) REFERENCES businesses(business_id) ON DELETE CASCADE
+);
+
+-- Agendas without a resource belong to the business as a whole. A business, and each
+-- of its resources, has at most one general agenda and one daily agenda per date.
+ALTER TABLE general_agenda
+    ADD COLUMN IF NOT EXISTS resource_id UUID REFERENCES resources(resource_id) ON DELETE CASCADE,
+    DROP CONSTRAINT IF EXISTS general_agenda_business_id_key,
+    ADD CONSTRAINT general_agenda_business_id_resource_id_key UNIQUE NULLS NOT DISTINCT (business_id, resource_id);
+
+ALTER TABLE daily_agenda
+    ADD COLUMN IF NOT EXISTS resource_id UUID REFERENCES resources(resource_id) ON DELETE CASCADE,
+    DROP CONSTRAINT IF EXISTS daily_agenda_business_id_date_key,
+    ADD CONSTRAINT daily_agenda_business_id_resource_id_date_key UNIQUE NULLS NOT DISTINCT (business_id, resource_id, date);
+
+-- Each resource takes one appointment at a time. Appointments without a resource are
+-- booked against the business as a whole, which then takes one at a time. A resource
+-- can't be deleted while it still has appointments.
+ALTER TABLE appointments
+    ADD COLUMN IF NOT EXISTS resource_id UUID REFERENCES resources(resource_id),
+    DROP CONSTRAINT IF EXISTS appointments_business_id_period_excl,
+    ADD CONSTRAINT appointments_business_id_resource_id_period_excl EXCLUDE USING gist (
+        business_id WITH =,
+        (COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::UUID)) WITH =,
+        tsrange(scheduled_on, ends_on) WITH &&
+    ) WHERE (status <> 0);
@@ -0,0 +1,13 @@
+DELETE FROM appointments WHERE capacity > 1;
+
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_business_id_resource_id_period_excl,
+    DROP COLUMN IF EXISTS capacity,
+    ADD CONSTRAINT appointments_business_id_resource_id_period_excl EXCLUDE USING gist (
+        business_id WITH =,
+        (COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::UUID)) WITH =,
+        tsrange(scheduled_on, ends_on) WITH &&
+    ) WHERE (status <> 0);
+
+ALTER TABLE services
+    DROP COLUMN IF EXISTS capacity;
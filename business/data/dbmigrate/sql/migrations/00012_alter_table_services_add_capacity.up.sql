@@ -0,0 +1,15 @@
+ALTER TABLE services
+    ADD COLUMN IF NOT EXISTS capacity INTEGER NOT NULL DEFAULT 1 CHECK (capacity > 0);
+
+-- Appointments keep the capacity of their service at the time of booking. Bookings
+-- of a service holding more than one person share its slot with each other, but
+-- still can't overlap with anything else booked against the same resource.
+ALTER TABLE appointments
+    ADD COLUMN IF NOT EXISTS capacity INTEGER NOT NULL DEFAULT 1,
+    DROP CONSTRAINT IF EXISTS appointments_business_id_resource_id_period_excl,
+    ADD CONSTRAINT appointments_business_id_resource_id_period_excl EXCLUDE USING gist (
+        business_id WITH =,
+        (COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::UUID)) WITH =,
+        tsrange(scheduled_on, ends_on) WITH &&,
+        (CASE WHEN capacity > 1 AND service_id IS NOT NULL THEN service_id ELSE appointment_id END) WITH <>
+    ) WHERE (status <> 0);
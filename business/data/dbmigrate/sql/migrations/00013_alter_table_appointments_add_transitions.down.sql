@@ -0,0 +1,21 @@
+-- Rejected appointments are taken as cancelled, and every other one still to
+-- take place as scheduled.
+UPDATE appointments SET status = 0 WHERE status = 6;
+UPDATE appointments SET status = 1 WHERE status IN (2, 3, 4, 5);
+
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_business_id_resource_id_period_excl,
+    ADD CONSTRAINT appointments_business_id_resource_id_period_excl EXCLUDE USING gist (
+        business_id WITH =,
+        (COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::UUID)) WITH =,
+        tsrange(scheduled_on, ends_on) WITH &&,
+        (CASE WHEN capacity > 1 AND service_id IS NOT NULL THEN service_id ELSE appointment_id END) WITH <>
+    ) WHERE (status <> 0);
+
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS rejected_at,
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS no_show_at,
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS checked_in_at,
+    DROP COLUMN IF EXISTS confirmed_at;
@@ -0,0 +1,20 @@
+-- Status is one of cancelled (0), confirmed (1), pending (2), checked in (3),
+-- completed (4), no-show (5) or rejected (6). Appointments scheduled before are
+-- confirmed already. Each transition is stamped with the time it took place.
+ALTER TABLE appointments
+    ADD COLUMN IF NOT EXISTS confirmed_at   TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS checked_in_at  TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS completed_at   TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS no_show_at     TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS cancelled_at   TIMESTAMP NULL,
+    ADD COLUMN IF NOT EXISTS rejected_at    TIMESTAMP NULL;
+
+-- Cancelled and rejected appointments don't hold their slot.
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_business_id_resource_id_period_excl,
+    ADD CONSTRAINT appointments_business_id_resource_id_period_excl EXCLUDE USING gist (
+        business_id WITH =,
+        (COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::UUID)) WITH =,
+        tsrange(scheduled_on, ends_on) WITH &&,
+        (CASE WHEN capacity > 1 AND service_id IS NOT NULL THEN service_id ELSE appointment_id END) WITH <>
+    ) WHERE (status NOT IN (0, 6));
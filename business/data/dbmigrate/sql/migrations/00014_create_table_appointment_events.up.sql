@@ -0,0 +1,22 @@
+-- The history of appointments outlives them, so events don't reference the
+-- appointments table. Statuses and times are empty where an appointment is created
+-- or deleted, and actors are empty where unknown.
+CREATE TABLE IF NOT EXISTS appointment_events (
+    event_id            UUID        NOT NULL,
+    appointment_id      UUID        NOT NULL,
+    actor_id            UUID        NULL,
+    old_status          SMALLINT    NULL,
+    new_status          SMALLINT    NULL,
+    old_scheduled_on    TIMESTAMP   NULL,
+    new_scheduled_on    TIMESTAMP   NULL,
+    reason              TEXT        NOT NULL DEFAULT '',
+    date_created        TIMESTAMP   NOT NULL,
+
+    PRIMARY KEY(event_id)
+);
+
+CREATE INDEX IF NOT EXISTS appointment_events_appointment_id_idx ON appointment_events (appointment_id, date_created);
+
+-- Events are only ever appended.
+CREATE OR REPLACE RULE appointment_events_no_update AS ON UPDATE TO appointment_events DO INSTEAD NOTHING;
+CREATE OR REPLACE RULE appointment_events_no_delete AS ON DELETE TO appointment_events DO INSTEAD NOTHING;
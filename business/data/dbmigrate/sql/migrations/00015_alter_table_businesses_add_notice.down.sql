@@ -0,0 +1,3 @@
+ALTER TABLE businesses
+    DROP COLUMN IF EXISTS reschedule_notice,
+    DROP COLUMN IF EXISTS cancellation_notice;
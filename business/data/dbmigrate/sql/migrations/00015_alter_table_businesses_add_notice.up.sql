@@ -0,0 +1,5 @@
+-- Minimum notice, in seconds, a customer must give before the start of an
+-- appointment to cancel or reschedule it. Zero means no restriction.
+ALTER TABLE businesses
+    ADD COLUMN IF NOT EXISTS cancellation_notice INTEGER NOT NULL DEFAULT 0 CHECK (cancellation_notice >= 0),
+    ADD COLUMN IF NOT EXISTS reschedule_notice INTEGER NOT NULL DEFAULT 0 CHECK (reschedule_notice >= 0);
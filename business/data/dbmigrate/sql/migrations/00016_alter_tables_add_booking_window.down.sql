@@ -0,0 +1,7 @@
+ALTER TABLE services
+    DROP COLUMN IF EXISTS horizon,
+    DROP COLUMN IF EXISTS lead_time;
+
+ALTER TABLE businesses
+    DROP COLUMN IF EXISTS horizon,
+    DROP COLUMN IF EXISTS lead_time;
@@ -0,0 +1,10 @@
+-- How far ahead appointments may be booked, in seconds: at least lead_time and at
+-- most horizon from now. Zero means no limit. Services may set their own, taking
+-- precedence over those of their business.
+ALTER TABLE businesses
+    ADD COLUMN IF NOT EXISTS lead_time INTEGER NOT NULL DEFAULT 0 CHECK (lead_time >= 0),
+    ADD COLUMN IF NOT EXISTS horizon INTEGER NOT NULL DEFAULT 0 CHECK (horizon >= 0);
+
+ALTER TABLE services
+    ADD COLUMN IF NOT EXISTS lead_time INTEGER NOT NULL DEFAULT 0 CHECK (lead_time >= 0),
+    ADD COLUMN IF NOT EXISTS horizon INTEGER NOT NULL DEFAULT 0 CHECK (horizon >= 0);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS appointments_series_id_idx;
+
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS series_id;
+
+DROP TABLE IF EXISTS appointment_series;
@@ -0,0 +1,19 @@
+-- A series is a recurring booking, expanded into an appointment for each occurrence
+-- of its rule, a subset of the RRULE of RFC 5545.
+CREATE TABLE IF NOT EXISTS appointment_series (
+    series_id       UUID        NOT NULL,
+    business_id     UUID        NOT NULL,
+    user_id         UUID        NOT NULL,
+    rule            TEXT        NOT NULL,
+    starts_on       TIMESTAMP   NOT NULL,
+    date_created    TIMESTAMP   NOT NULL,
+
+    PRIMARY KEY(series_id),
+    FOREIGN KEY (business_id) REFERENCES businesses(business_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+ALTER TABLE appointments
+    ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES appointment_series(series_id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS appointments_series_id_idx ON appointments (series_id, scheduled_on);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS waitlist_entries_business_id_status_idx;
+
+DROP TABLE IF EXISTS waitlist_entries;
@@ -0,0 +1,28 @@
+-- Customers wait for a slot of a business starting between starts_on and ends_on,
+-- of a service and resource where given. An entry offered a freed up slot holds it
+-- until held_until.
+CREATE TABLE IF NOT EXISTS waitlist_entries (
+    entry_id            UUID        NOT NULL,
+    business_id         UUID        NOT NULL,
+    user_id             UUID        NOT NULL,
+    service_id          UUID        NULL REFERENCES services(service_id) ON DELETE CASCADE,
+    resource_id         UUID        NULL REFERENCES resources(resource_id) ON DELETE CASCADE,
+    starts_on           TIMESTAMP   NOT NULL,
+    ends_on             TIMESTAMP   NOT NULL,
+    status              TEXT        NOT NULL,
+    held_service_id     UUID        NULL,
+    held_resource_id    UUID        NULL,
+    held_starts_at      TIMESTAMP   NULL,
+    held_ends_at        TIMESTAMP   NULL,
+    held_until          TIMESTAMP   NULL,
+    date_created        TIMESTAMP   NOT NULL,
+    date_updated        TIMESTAMP   NOT NULL,
+
+    CHECK (ends_on > starts_on),
+
+    PRIMARY KEY(entry_id),
+    FOREIGN KEY (business_id) REFERENCES businesses(business_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS waitlist_entries_business_id_status_idx ON waitlist_entries (business_id, status, date_created);
@@ -0,0 +1,2 @@
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS held_until;
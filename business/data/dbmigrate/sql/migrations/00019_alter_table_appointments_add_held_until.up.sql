@@ -0,0 +1,4 @@
+-- A hold is a pending appointment taken while the customer checks out. It occupies
+-- its slot until held_until, unless confirmed before then, which clears it.
+ALTER TABLE appointments
+    ADD COLUMN IF NOT EXISTS held_until TIMESTAMP NULL;
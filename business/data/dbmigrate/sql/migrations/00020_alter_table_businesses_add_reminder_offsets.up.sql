@@ -0,0 +1,5 @@
+-- How long before an appointment starts its customer is reminded of it, in seconds,
+-- earliest first. Empty means no reminders. Businesses keep the single reminder
+-- they had, at the time the appointment starts, until they set their own.
+ALTER TABLE businesses
+    ADD COLUMN IF NOT EXISTS reminder_offsets BIGINT[] NOT NULL DEFAULT '{0}';
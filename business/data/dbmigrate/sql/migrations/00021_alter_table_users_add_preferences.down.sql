@@ -0,0 +1,5 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS language,
+    DROP COLUMN IF EXISTS quiet_end,
+    DROP COLUMN IF EXISTS quiet_start,
+    DROP COLUMN IF EXISTS channels;
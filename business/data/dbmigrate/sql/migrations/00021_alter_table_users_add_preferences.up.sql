@@ -0,0 +1,8 @@
+-- How users like to be notified: over which channels, never between quiet_start
+-- and quiet_end, in seconds from midnight, and in which language. No channels means
+-- no notifications at all.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS channels TEXT[] NOT NULL DEFAULT '{SMS,EMAIL}',
+    ADD COLUMN IF NOT EXISTS quiet_start INTEGER NOT NULL DEFAULT 0 CHECK (quiet_start >= 0 AND quiet_start < 86400),
+    ADD COLUMN IF NOT EXISTS quiet_end INTEGER NOT NULL DEFAULT 0 CHECK (quiet_end >= 0 AND quiet_end < 86400),
+    ADD COLUMN IF NOT EXISTS language TEXT NOT NULL DEFAULT 'en';
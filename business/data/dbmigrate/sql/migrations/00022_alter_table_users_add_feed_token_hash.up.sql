@@ -0,0 +1,4 @@
+-- The SHA-256 hash of the secret token calendar apps subscribe to the feeds of the
+-- user with. NULL until the user asks for one.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS feed_token_hash BYTEA NULL;
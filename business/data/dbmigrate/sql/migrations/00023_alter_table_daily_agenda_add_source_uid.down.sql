@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS daily_agenda_business_id_source_uid_idx;
+
+ALTER TABLE daily_agenda
+    DROP COLUMN IF EXISTS source_uid;
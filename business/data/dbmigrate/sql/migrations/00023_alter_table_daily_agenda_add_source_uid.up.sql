@@ -0,0 +1,9 @@
+-- The UID of the calendar event a closure was imported from, so importing the
+-- calendar again updates its closures instead of adding them anew. NULL for daily
+-- agendas set by hand.
+ALTER TABLE daily_agenda
+    ADD COLUMN IF NOT EXISTS source_uid TEXT NULL;
+
+CREATE INDEX IF NOT EXISTS daily_agenda_business_id_source_uid_idx
+    ON daily_agenda (business_id, source_uid)
+    WHERE source_uid IS NOT NULL;
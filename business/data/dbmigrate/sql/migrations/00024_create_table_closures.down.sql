@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS closures_business_id_idx;
+
+DROP TABLE IF EXISTS closures;
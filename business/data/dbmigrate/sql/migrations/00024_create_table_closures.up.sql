@@ -0,0 +1,21 @@
+-- A closure closes a business, or one of its resources when given, on every date
+-- from start_date to end_date, in the business time zone. Yearly closures recur on
+-- the same dates every year from start_date on, and may span the new year.
+CREATE TABLE IF NOT EXISTS closures (
+    closure_id          UUID        NOT NULL,
+    business_id         UUID        NOT NULL,
+    resource_id         UUID        NULL REFERENCES resources(resource_id) ON DELETE CASCADE,
+    start_date          DATE        NOT NULL,
+    end_date            DATE        NOT NULL,
+    yearly              BOOLEAN     NOT NULL DEFAULT FALSE,
+    reason              TEXT        NOT NULL DEFAULT '',
+    date_created        TIMESTAMP   NOT NULL,
+    date_updated        TIMESTAMP   NOT NULL,
+
+    CHECK (end_date >= start_date),
+
+    PRIMARY KEY(closure_id),
+    FOREIGN KEY (business_id) REFERENCES businesses(business_id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS closures_business_id_idx ON closures (business_id, resource_id);
@@ -0,0 +1,18 @@
+-- Only the agenda in effect today, or the latest one otherwise, is kept.
+DELETE FROM general_agenda ga
+WHERE ga.id <> (
+    SELECT id
+    FROM general_agenda g
+    WHERE g.business_id = ga.business_id AND g.resource_id IS NOT DISTINCT FROM ga.resource_id
+    ORDER BY
+        daterange(g.effective_from, g.effective_to, '[]') @> CURRENT_DATE DESC,
+        g.effective_from DESC NULLS LAST
+    LIMIT 1
+);
+
+ALTER TABLE general_agenda
+    DROP CONSTRAINT IF EXISTS general_agenda_business_id_resource_id_effective_excl,
+    DROP CONSTRAINT IF EXISTS general_agenda_effective_check,
+    DROP COLUMN IF EXISTS effective_from,
+    DROP COLUMN IF EXISTS effective_to,
+    ADD CONSTRAINT general_agenda_business_id_resource_id_key UNIQUE NULLS NOT DISTINCT (business_id, resource_id);
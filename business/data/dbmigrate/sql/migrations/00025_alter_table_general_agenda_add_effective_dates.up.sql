@@ -0,0 +1,14 @@
+-- A business, and each of its resources, may have several general agendas, each in
+-- effect from effective_from to effective_to, in the business time zone. A missing
+-- bound leaves the agenda in effect indefinitely on that side. The effective dates of
+-- the agendas of the same business or resource may not overlap.
+ALTER TABLE general_agenda
+    ADD COLUMN IF NOT EXISTS effective_from DATE NULL,
+    ADD COLUMN IF NOT EXISTS effective_to DATE NULL,
+    DROP CONSTRAINT IF EXISTS general_agenda_business_id_resource_id_key,
+    ADD CONSTRAINT general_agenda_effective_check CHECK (effective_to >= effective_from),
+    ADD CONSTRAINT general_agenda_business_id_resource_id_effective_excl EXCLUDE USING gist (
+        business_id WITH =,
+        (COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::UUID)) WITH =,
+        daterange(effective_from, effective_to, '[]') WITH &&
+    );
@@ -0,0 +1,2 @@
+ALTER TABLE appointments
+    DROP COLUMN IF EXISTS flagged_at;
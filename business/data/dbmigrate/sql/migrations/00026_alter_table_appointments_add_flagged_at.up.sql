@@ -0,0 +1,4 @@
+-- An appointment no longer fitting the agendas of its business after they change may
+-- be flagged for the customer to pick another time. Rescheduling it clears the flag.
+ALTER TABLE appointments
+    ADD COLUMN IF NOT EXISTS flagged_at TIMESTAMP NULL;
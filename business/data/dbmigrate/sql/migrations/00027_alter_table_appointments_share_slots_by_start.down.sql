@@ -0,0 +1,8 @@
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_business_id_resource_id_period_excl,
+    ADD CONSTRAINT appointments_business_id_resource_id_period_excl EXCLUDE USING gist (
+        business_id WITH =,
+        (COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::UUID)) WITH =,
+        tsrange(scheduled_on, ends_on) WITH &&,
+        (CASE WHEN capacity > 1 AND service_id IS NOT NULL THEN service_id ELSE appointment_id END) WITH <>
+    ) WHERE (status NOT IN (0, 6));
@@ -0,0 +1,15 @@
+-- Bookings of a service holding more than one person share its slot only when
+-- they start at the same time. Those starting at another time overlapping with
+-- it take up the resource like any other appointment.
+ALTER TABLE appointments
+    DROP CONSTRAINT IF EXISTS appointments_business_id_resource_id_period_excl,
+    ADD CONSTRAINT appointments_business_id_resource_id_period_excl EXCLUDE USING gist (
+        business_id WITH =,
+        (COALESCE(resource_id, '00000000-0000-0000-0000-000000000000'::UUID)) WITH =,
+        tsrange(scheduled_on, ends_on) WITH &&,
+        (CASE
+            WHEN capacity > 1 AND service_id IS NOT NULL
+            THEN service_id::TEXT || '@' || EXTRACT(EPOCH FROM scheduled_on)::TEXT
+            ELSE appointment_id::TEXT
+        END) WITH <>
+    ) WHERE (status NOT IN (0, 6));
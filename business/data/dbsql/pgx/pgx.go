@@ -19,15 +19,19 @@ import (
 )
 
 const (
-	uniqueViolation = "23505"
-	undefinedTable  = "42P01"
+	uniqueViolation     = "23505"
+	exclusionViolation  = "23P01"
+	foreignKeyViolation = "23503"
+	undefinedTable      = "42P01"
 )
 
 // Set of error variables for CRUD operations.
 var (
-	ErrDBNotFound       = sql.ErrNoRows
-	ErrDBDuplicateEntry = errors.New("duplicated entry")
-	ErrUndefinedTable   = errors.New("undefined table")
+	ErrDBNotFound           = sql.ErrNoRows
+	ErrDBDuplicateEntry     = errors.New("duplicated entry")
+	ErrDBExclusionViolation = errors.New("conflicting entry")
+	ErrDBForeignKey         = errors.New("entry still referenced")
+	ErrUndefinedTable       = errors.New("undefined table")
 )
 
 // Config is the required properties to use the database.
@@ -127,6 +131,10 @@ func NamedExecContext(ctx context.Context, log *logger.Logger, db sqlx.ExtContex
 				return ErrUndefinedTable
 			case uniqueViolation:
 				return ErrDBDuplicateEntry
+			case exclusionViolation:
+				return ErrDBExclusionViolation
+			case foreignKeyViolation:
+				return ErrDBForeignKey
 			}
 		}
 		return err
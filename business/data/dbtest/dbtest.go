@@ -15,8 +15,14 @@ import (
 	"github.com/ameghdadian/service/business/core/appointment/stores/appointmentdb"
 	"github.com/ameghdadian/service/business/core/business"
 	"github.com/ameghdadian/service/business/core/business/stores/businessdb"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/resource/stores/resourcedb"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/service/stores/servicedb"
 	"github.com/ameghdadian/service/business/core/user"
 	"github.com/ameghdadian/service/business/core/user/stores/userdb"
+	"github.com/ameghdadian/service/business/core/waitlist"
+	"github.com/ameghdadian/service/business/core/waitlist/stores/waitlistdb"
 	"github.com/ameghdadian/service/business/data/dbmigrate"
 	"github.com/ameghdadian/service/business/web/v1/auth"
 	"github.com/ameghdadian/service/foundation/docker"
@@ -245,23 +251,33 @@ func FloatPointer(f float64) *float64 {
 type CoreAPIs struct {
 	User        *user.Core
 	Business    *business.Core
+	Service     *service.Core
+	Resource    *resource.Core
 	Appointment *appointment.Core
 	Agenda      *agenda.Core
+	Waitlist    *waitlist.Core
 }
 
 func newCoreAPIs(log *logger.Logger, db *sqlx.DB, taskClient *asynq.Client, taskInspector *asynq.Inspector) CoreAPIs {
 	aptTask := appointment.NewTask(taskClient, taskInspector)
+	wlTask := waitlist.NewTask(taskClient, taskInspector)
 
 	usrCore := user.NewCore(log, userdb.NewStore(log, db))
 	bsnCore := business.NewCore(log, usrCore, businessdb.NewStore(log, db))
-	aptCore := appointment.NewCore(log, usrCore, bsnCore, appointmentdb.NewStore(log, db), aptTask)
-	agdCore := agenda.NewCore(log, bsnCore, agendadb.NewStore(log, db))
+	svcCore := service.NewCore(log, bsnCore, servicedb.NewStore(log, db))
+	rscCore := resource.NewCore(log, bsnCore, resourcedb.NewStore(log, db))
+	aptCore := appointment.NewCore(log, usrCore, bsnCore, svcCore, rscCore, appointmentdb.NewStore(log, db), aptTask)
+	agdCore := agenda.NewCore(log, bsnCore, rscCore, aptCore, agendadb.NewStore(log, db))
+	wlCore := waitlist.NewCore(log, usrCore, bsnCore, aptCore, waitlistdb.NewStore(log, db), wlTask)
 
 	return CoreAPIs{
 		User:        usrCore,
 		Business:    bsnCore,
+		Service:     svcCore,
+		Resource:    rscCore,
 		Appointment: aptCore,
 		Agenda:      agdCore,
+		Waitlist:    wlCore,
 	}
 }
 
@@ -74,6 +74,11 @@ func New(cfg Config) (*Auth, error) {
 
 // GenerateToken generate a signed JWT token string representing the user Claims
 func (a *Auth) GenerateToken(kid string, claims Claims) (string, error) {
+	return a.sign(kid, claims)
+}
+
+// sign signs the claims with the private key of the kid.
+func (a *Auth) sign(kid string, claims jwt.Claims) (string, error) {
 	token := jwt.NewWithClaims(a.method, claims)
 	token.Header["kid"] = kid
 
@@ -109,6 +114,10 @@ func (a *Auth) Authenticate(ctx context.Context, bearerToken string) (Claims, er
 		return Claims{}, fmt.Errorf("error parsing token: %w", err)
 	}
 
+	if claims.VerifyAudience(audienceUnsubscribe, true) {
+		return Claims{}, errors.New("unsubscribe tokens don't authenticate")
+	}
+
 	// Perform an extra level of authentication verification with OPA.
 	kidRaw, exists := token.Header["kid"]
 	if !exists {
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/ameghdadian/service/business/core/user"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+)
+
+// audienceUnsubscribe is the audience of unsubscribe tokens. Authenticate rejects
+// tokens meant for it, so following a link from an email logs no one in.
+const audienceUnsubscribe = "unsubscribe"
+
+// UnsubscribeClaims let the user of the subject stop being notified over the
+// channel, without logging in. They don't expire, being in emails kept for long.
+type UnsubscribeClaims struct {
+	jwt.RegisteredClaims
+	Channel user.Channel `json:"channel"`
+}
+
+// GenerateUnsubscribeToken generates a token signed with the key of the kid that
+// unsubscribes the user from the channel.
+func (a *Auth) GenerateUnsubscribeToken(kid string, userID uuid.UUID, ch user.Channel) (string, error) {
+	claims := UnsubscribeClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:  userID.String(),
+			Issuer:   a.issuer,
+			Audience: jwt.ClaimStrings{audienceUnsubscribe},
+			IssuedAt: jwt.NewNumericDate(time.Now().UTC()),
+		},
+		Channel: ch,
+	}
+
+	return a.sign(kid, claims)
+}
+
+// ParseUnsubscribeToken verifies the token, returning the user and the channel it
+// unsubscribes from.
+func (a *Auth) ParseUnsubscribeToken(token string) (uuid.UUID, user.Channel, error) {
+	var claims UnsubscribeClaims
+	if _, err := a.parser.ParseWithClaims(token, &claims, a.verificationKey); err != nil {
+		return uuid.UUID{}, user.Channel{}, fmt.Errorf("parsing token: %w", err)
+	}
+
+	if !claims.VerifyAudience(audienceUnsubscribe, true) {
+		return uuid.UUID{}, user.Channel{}, errors.New("not an unsubscribe token")
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.UUID{}, user.Channel{}, fmt.Errorf("parsing subject: %w", err)
+	}
+
+	return userID, claims.Channel, nil
+}
+
+// verificationKey returns the public key of the kid the token was signed with.
+func (a *Auth) verificationKey(token *jwt.Token) (any, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, errors.New("kid missing from header")
+	}
+
+	pem, err := a.publicKeyLookup(kid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch public key: %w", err)
+	}
+
+	return jwt.ParseRSAPublicKeyFromPEM([]byte(pem))
+}
+
+// =============================================================================
+
+// Unsubscriber makes the links users follow to stop being notified over a channel,
+// pointing at the unsubscribe endpoint under the base URL.
+type Unsubscriber struct {
+	auth    *Auth
+	kid     string
+	baseURL string
+}
+
+func NewUnsubscriber(auth *Auth, kid string, baseURL string) *Unsubscriber {
+	return &Unsubscriber{
+		auth:    auth,
+		kid:     kid,
+		baseURL: baseURL,
+	}
+}
+
+func (u *Unsubscriber) UnsubscribeURL(userID uuid.UUID, ch user.Channel) (string, error) {
+	token, err := u.auth.GenerateUnsubscribeToken(u.kid, userID, ch)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s/v1/unsubscribe?token=%s", u.baseURL, url.QueryEscape(token)), nil
+}
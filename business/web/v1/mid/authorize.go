@@ -9,6 +9,9 @@ import (
 	"github.com/ameghdadian/service/business/core/agenda"
 	"github.com/ameghdadian/service/business/core/appointment"
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/service"
+	"github.com/ameghdadian/service/business/core/waitlist"
 	"github.com/ameghdadian/service/business/web/v1/auth"
 	"github.com/ameghdadian/service/foundation/errs"
 	"github.com/ameghdadian/service/foundation/logger"
@@ -141,6 +144,66 @@ func AuthorizeAppointment(log *logger.Logger, ath *auth.Auth, aptCore *appointme
 	return m
 }
 
+// AuthorizeAppointmentParty lets through the customer who booked the appointment,
+// the owner of its business and admins, telling the handler which party is asking.
+func AuthorizeAppointmentParty(log *logger.Logger, ath *auth.Auth, aptCore *appointment.Core, bsnCore *business.Core) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			aptID, err := uuid.Parse(web.Param(r, "appointment_id"))
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			apt, err := aptCore.QueryByID(ctx, aptID)
+			if err != nil {
+				if errors.Is(err, appointment.ErrNotFound) {
+					return errs.New(errs.Unauthenticated, err)
+				}
+
+				return errs.Newf(errs.Internal, "querybyid: aptID[%s]: %s", aptID, err)
+			}
+
+			bsn, err := bsnCore.QueryByID(ctx, apt.BusinessID)
+			if err != nil {
+				if errors.Is(err, business.ErrNotFound) {
+					return errs.New(errs.Unauthenticated, err)
+				}
+
+				return errs.Newf(errs.Internal, "querybyid: bsnID[%s]: %s", apt.BusinessID, err)
+			}
+
+			ctx = setAppointment(ctx, apt)
+			ctx = setBusiness(ctx, bsn)
+
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			claims := auth.GetClaims(ctx)
+
+			var party appointment.Party
+			switch {
+			case ath.Authorize(ctx, claims, uuid.Nil, auth.RuleAdminOnly) == nil:
+				party = appointment.PartyAdmin
+			case claims.Subject == bsn.OwnerID.String():
+				party = appointment.PartyOwner
+			case claims.Subject == apt.UserID.String():
+				party = appointment.PartyCustomer
+			default:
+				return errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[%v]: %s", claims.Roles, auth.ErrForbidden)
+			}
+
+			ctx = setParty(ctx, party)
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
 func AuthorizeGeneralAgenda(log *logger.Logger, ath *auth.Auth, agdCore *agenda.Core, bsnCore *business.Core) web.MidFunc {
 	m := func(next web.HandlerFunc) web.HandlerFunc {
 
@@ -244,3 +307,197 @@ func AuthorizeDailyAgenda(log *logger.Logger, ath *auth.Auth, agdCore *agenda.Co
 
 	return m
 }
+
+func AuthorizeClosure(log *logger.Logger, ath *auth.Auth, agdCore *agenda.Core, bsnCore *business.Core) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			var userID uuid.UUID
+			id := web.Param(r, "closure_id")
+
+			if id != "" {
+				clID, err := uuid.Parse(id)
+				if err != nil {
+					return errs.New(errs.Unauthenticated, ErrInvalidID)
+				}
+
+				cl, err := agdCore.QueryClosureByID(ctx, clID)
+				if err != nil {
+					if errors.Is(err, agenda.ErrClosureNotFound) {
+						return errs.New(errs.Unauthenticated, err)
+					}
+
+					return errs.Newf(errs.Internal, "querybyid: closureID[%s]: %s", clID, err)
+				}
+				bsn, err := bsnCore.QueryByID(ctx, cl.BusinessID)
+				if err != nil {
+					if errors.Is(err, business.ErrNotFound) {
+						return errs.Newf(errs.Unauthenticated, "you are not a business owner: %s", err)
+					}
+
+					return errs.Newf(errs.Internal, "querybyid: bsnID[%s]: %s", cl.BusinessID, err)
+				}
+
+				userID = bsn.OwnerID
+				ctx = setClosure(ctx, cl)
+				ctx = setBusiness(ctx, bsn)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			claims := auth.GetClaims(ctx)
+			if err := ath.Authorize(ctx, claims, userID, auth.RuleAdminOrSubject); err != nil {
+				return errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", claims.Roles, auth.RuleAdminOrSubject, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+func AuthorizeService(log *logger.Logger, ath *auth.Auth, svcCore *service.Core, bsnCore *business.Core) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			var userID uuid.UUID
+			id := web.Param(r, "service_id")
+
+			if id != "" {
+				svcID, err := uuid.Parse(id)
+				if err != nil {
+					return errs.New(errs.Unauthenticated, ErrInvalidID)
+				}
+
+				svc, err := svcCore.QueryByID(ctx, svcID)
+				if err != nil {
+					if errors.Is(err, service.ErrNotFound) {
+						return errs.New(errs.Unauthenticated, err)
+					}
+
+					return errs.Newf(errs.Internal, "querybyid: svcID[%s]: %s", svcID, err)
+				}
+				bsn, err := bsnCore.QueryByID(ctx, svc.BusinessID)
+				if err != nil {
+					if errors.Is(err, business.ErrNotFound) {
+						return errs.New(errs.Unauthenticated, err)
+					}
+
+					return errs.Newf(errs.Internal, "querybyid: bsnID[%s]: %s", svc.BusinessID, err)
+				}
+
+				userID = bsn.OwnerID
+				ctx = setService(ctx, svc)
+				ctx = setBusiness(ctx, bsn)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			claims := auth.GetClaims(ctx)
+			if err := ath.Authorize(ctx, claims, userID, auth.RuleAdminOrSubject); err != nil {
+				return errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", claims.Roles, auth.RuleAdminOrSubject, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+func AuthorizeResource(log *logger.Logger, ath *auth.Auth, rscCore *resource.Core, bsnCore *business.Core) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			var userID uuid.UUID
+			id := web.Param(r, "resource_id")
+
+			if id != "" {
+				rscID, err := uuid.Parse(id)
+				if err != nil {
+					return errs.New(errs.Unauthenticated, ErrInvalidID)
+				}
+
+				rsc, err := rscCore.QueryByID(ctx, rscID)
+				if err != nil {
+					if errors.Is(err, resource.ErrNotFound) {
+						return errs.New(errs.Unauthenticated, err)
+					}
+
+					return errs.Newf(errs.Internal, "querybyid: rscID[%s]: %s", rscID, err)
+				}
+				bsn, err := bsnCore.QueryByID(ctx, rsc.BusinessID)
+				if err != nil {
+					if errors.Is(err, business.ErrNotFound) {
+						return errs.New(errs.Unauthenticated, err)
+					}
+
+					return errs.Newf(errs.Internal, "querybyid: bsnID[%s]: %s", rsc.BusinessID, err)
+				}
+
+				userID = bsn.OwnerID
+				ctx = setResource(ctx, rsc)
+				ctx = setBusiness(ctx, bsn)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			claims := auth.GetClaims(ctx)
+			if err := ath.Authorize(ctx, claims, userID, auth.RuleAdminOrSubject); err != nil {
+				return errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", claims.Roles, auth.RuleAdminOrSubject, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
+
+// AuthorizeWaitlistEntry lets through the customer waiting on the entry and admins.
+func AuthorizeWaitlistEntry(log *logger.Logger, ath *auth.Auth, wlCore *waitlist.Core) web.MidFunc {
+	m := func(next web.HandlerFunc) web.HandlerFunc {
+
+		h := func(ctx context.Context, r *http.Request) web.Encoder {
+			entryID, err := uuid.Parse(web.Param(r, "entry_id"))
+			if err != nil {
+				return errs.New(errs.Unauthenticated, ErrInvalidID)
+			}
+
+			e, err := wlCore.QueryByID(ctx, entryID)
+			if err != nil {
+				if errors.Is(err, waitlist.ErrNotFound) {
+					return errs.New(errs.Unauthenticated, err)
+				}
+
+				return errs.Newf(errs.Internal, "querybyid: entryID[%s]: %s", entryID, err)
+			}
+
+			ctx = setWaitlistEntry(ctx, e)
+
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			claims := auth.GetClaims(ctx)
+			if err := ath.Authorize(ctx, claims, e.UserID, auth.RuleAdminOrSubject); err != nil {
+				return errs.Newf(errs.Unauthenticated, "authorize: you are not authorized for that action, claims[%v] rule[%v]: %s", claims.Roles, auth.RuleAdminOrSubject, err)
+			}
+
+			return next(ctx, r)
+		}
+
+		return h
+	}
+
+	return m
+}
@@ -7,7 +7,10 @@ import (
 	"github.com/ameghdadian/service/business/core/agenda"
 	"github.com/ameghdadian/service/business/core/appointment"
 	"github.com/ameghdadian/service/business/core/business"
+	"github.com/ameghdadian/service/business/core/resource"
+	"github.com/ameghdadian/service/business/core/service"
 	"github.com/ameghdadian/service/business/core/user"
+	"github.com/ameghdadian/service/business/core/waitlist"
 	"github.com/ameghdadian/service/foundation/web"
 )
 
@@ -31,6 +34,11 @@ const (
 	appointmentKey
 	generalAgendaKey
 	dailyAgendaKey
+	serviceKey
+	resourceKey
+	partyKey
+	waitlistKey
+	closureKey
 )
 
 func setUser(ctx context.Context, usr user.User) context.Context {
@@ -72,6 +80,19 @@ func GetAppointment(ctx context.Context) (appointment.Appointment, error) {
 	return v, nil
 }
 
+func setParty(ctx context.Context, party appointment.Party) context.Context {
+	return context.WithValue(ctx, partyKey, party)
+}
+
+func GetParty(ctx context.Context) (appointment.Party, error) {
+	v, ok := ctx.Value(partyKey).(appointment.Party)
+	if !ok {
+		return appointment.Party{}, errors.New("party not found in context")
+	}
+
+	return v, nil
+}
+
 func setGeneralAgenda(ctx context.Context, agd agenda.GeneralAgenda) context.Context {
 	return context.WithValue(ctx, generalAgendaKey, agd)
 }
@@ -97,3 +118,55 @@ func GetDailyAgenda(ctx context.Context) (agenda.DailyAgenda, error) {
 
 	return v, nil
 }
+
+func setClosure(ctx context.Context, cl agenda.Closure) context.Context {
+	return context.WithValue(ctx, closureKey, cl)
+}
+
+func GetClosure(ctx context.Context) (agenda.Closure, error) {
+	v, ok := ctx.Value(closureKey).(agenda.Closure)
+	if !ok {
+		return agenda.Closure{}, errors.New("closure not found in context")
+	}
+
+	return v, nil
+}
+
+func setService(ctx context.Context, svc service.Service) context.Context {
+	return context.WithValue(ctx, serviceKey, svc)
+}
+
+func GetService(ctx context.Context) (service.Service, error) {
+	v, ok := ctx.Value(serviceKey).(service.Service)
+	if !ok {
+		return service.Service{}, errors.New("service not found in context")
+	}
+
+	return v, nil
+}
+
+func setResource(ctx context.Context, rsc resource.Resource) context.Context {
+	return context.WithValue(ctx, resourceKey, rsc)
+}
+
+func GetResource(ctx context.Context) (resource.Resource, error) {
+	v, ok := ctx.Value(resourceKey).(resource.Resource)
+	if !ok {
+		return resource.Resource{}, errors.New("resource not found in context")
+	}
+
+	return v, nil
+}
+
+func setWaitlistEntry(ctx context.Context, e waitlist.Entry) context.Context {
+	return context.WithValue(ctx, waitlistKey, e)
+}
+
+func GetWaitlistEntry(ctx context.Context) (waitlist.Entry, error) {
+	v, ok := ctx.Value(waitlistKey).(waitlist.Entry)
+	if !ok {
+		return waitlist.Entry{}, errors.New("waitlist entry not found in context")
+	}
+
+	return v, nil
+}
@@ -5,7 +5,9 @@ import (
 
 	"github.com/ameghdadian/service/business/web/v1/auth"
 	"github.com/ameghdadian/service/business/web/v1/mid"
+	"github.com/ameghdadian/service/foundation/email"
 	"github.com/ameghdadian/service/foundation/logger"
+	"github.com/ameghdadian/service/foundation/sms"
 	"github.com/ameghdadian/service/foundation/web"
 	"github.com/hibiken/asynq"
 	"github.com/jmoiron/sqlx"
@@ -46,9 +48,14 @@ func APIMux(cfg APIMuxConfig, routeAdder RouterAdder) *web.App {
 }
 
 type TaskMuxConfig struct {
-	DB  *sqlx.DB
-	Log *logger.Logger
-	Mux *asynq.ServeMux
+	DB            *sqlx.DB
+	Log           *logger.Logger
+	Mux           *asynq.ServeMux
+	TaskClient    *asynq.Client
+	TaskInspector *asynq.Inspector
+	SMS           sms.Sender
+	Email         email.Sender
+	Unsubscriber  *auth.Unsubscriber
 }
 
 type TaskRouter interface {
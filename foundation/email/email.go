@@ -0,0 +1,230 @@
+// Package email provides support for sending email over SMTP. Any SMTP server
+// works, including local stand-ins such as Mailpit or MailHog listening on
+// localhost:1025 while developing.
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is an email carrying both a plain text and an HTML body, letting the
+// client of the recipient pick one. Unsubscribe, if set, is the URL recipients
+// unsubscribe at with a single click, posting to it as RFC 8058 has it.
+type Message struct {
+	To          mail.Address
+	Subject     string
+	Text        string
+	HTML        string
+	Unsubscribe string
+}
+
+// Sender sends email.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// =============================================================================
+
+// SMTPConfig configures an SMTP server. Username is left empty for servers not
+// requiring authentication.
+type SMTPConfig struct {
+	Addr     string
+	Username string
+	Password string
+	From     mail.Address
+}
+
+// SMTPSender sends email through an SMTP server, upgrading the connection with
+// STARTTLS when the server offers it.
+type SMTPSender struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from mail.Address
+}
+
+func NewSMTPSender(cfg SMTPConfig) (*SMTPSender, error) {
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("parsing smtp address: %w", err)
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, host)
+	}
+
+	s := SMTPSender{
+		addr: cfg.Addr,
+		host: host,
+		auth: auth,
+		from: cfg.From,
+	}
+
+	return &s, nil
+}
+
+// Send sends the message, giving up once ctx is done however far it got.
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	data, err := s.compose(msg)
+	if err != nil {
+		return fmt.Errorf("composing message: %w", err)
+	}
+
+	if err := s.send(ctx, msg.To.Address, data); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("sending message: %w: %w", ctxErr, err)
+		}
+		return fmt.Errorf("sending message: %w", err)
+	}
+
+	return nil
+}
+
+// send delivers the data to the recipient the way smtp.SendMail does, over a
+// connection bound to ctx: a server stalling past the deadline of ctx, or ctx
+// being cancelled, fails the exchange instead of blocking it.
+func (s *SMTPSender) send(ctx context.Context, to string, data []byte) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+
+	if s.auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(s.auth); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.from.Address); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+// compose renders the message as a multipart/alternative MIME message, plain text
+// first as the least preferred alternative.
+func (s *SMTPSender) compose(msg Message) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	parts := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(p.content)); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To.String())
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", uuid.NewString(), domainOf(s.from.Address))
+	if msg.Unsubscribe != "" {
+		fmt.Fprintf(&buf, "List-Unsubscribe: <%s>\r\n", msg.Unsubscribe)
+		fmt.Fprintf(&buf, "List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n")
+	}
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n", mw.Boundary())
+	fmt.Fprintf(&buf, "\r\n")
+	buf.Write(body.Bytes())
+
+	return buf.Bytes(), nil
+}
+
+func domainOf(address string) string {
+	if i := strings.LastIndex(address, "@"); i >= 0 {
+		return address[i+1:]
+	}
+
+	return "localhost"
+}
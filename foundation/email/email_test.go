@@ -0,0 +1,280 @@
+package email
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_Compose(t *testing.T) {
+	s := SMTPSender{
+		from: mail.Address{Name: "Reservations", Address: "noreply@example.com"},
+	}
+
+	msg := Message{
+		To:          mail.Address{Name: "Ann", Address: "ann@example.com"},
+		Subject:     "Your appointment is booked – café",
+		Text:        "Hi Ann, see you at the café.",
+		HTML:        "<p>Hi Ann, see you at the <strong>café</strong>.</p>",
+		Unsubscribe: "https://example.com/v1/unsubscribe?token=abc",
+	}
+
+	data, err := s.compose(msg)
+	if err != nil {
+		t.Fatalf("Should be able to compose the message: %s", err)
+	}
+
+	m, err := mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("Should be able to read the message back: %s", err)
+	}
+
+	if !strings.HasPrefix(m.Header.Get("Subject"), "=?utf-8?q?") {
+		t.Errorf("Should Q-encode the subject: got %q", m.Header.Get("Subject"))
+	}
+
+	var dec mime.WordDecoder
+	subject, err := dec.DecodeHeader(m.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("Should be able to decode the subject: %s", err)
+	}
+	if subject != msg.Subject {
+		t.Errorf("Should get back the subject: got %q, exp %q", subject, msg.Subject)
+	}
+
+	if got, exp := m.Header.Get("List-Unsubscribe"), "<"+msg.Unsubscribe+">"; got != exp {
+		t.Errorf("Should set List-Unsubscribe: got %q, exp %q", got, exp)
+	}
+	if got, exp := m.Header.Get("List-Unsubscribe-Post"), "List-Unsubscribe=One-Click"; got != exp {
+		t.Errorf("Should set List-Unsubscribe-Post: got %q, exp %q", got, exp)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(m.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("Should be able to parse the content type: %s", err)
+	}
+	if mediaType != "multipart/alternative" {
+		t.Fatalf("Should be a multipart/alternative message: got %s", mediaType)
+	}
+
+	exp := []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	}
+
+	mr := multipart.NewReader(m.Body, params["boundary"])
+	for i, e := range exp {
+		// NextRawPart keeps the quoted-printable encoding for it to be checked.
+		p, err := mr.NextRawPart()
+		if err != nil {
+			t.Fatalf("Should be able to read part %d: %s", i, err)
+		}
+
+		if got := p.Header.Get("Content-Type"); got != e.contentType {
+			t.Errorf("Should get the content type of part %d: got %q, exp %q", i, got, e.contentType)
+		}
+		if got := p.Header.Get("Content-Transfer-Encoding"); got != "quoted-printable" {
+			t.Errorf("Should encode part %d as quoted-printable: got %q", i, got)
+		}
+
+		content, err := io.ReadAll(quotedprintable.NewReader(p))
+		if err != nil {
+			t.Fatalf("Should be able to decode part %d: %s", i, err)
+		}
+		if string(content) != e.content {
+			t.Errorf("Should get back the content of part %d: got %q, exp %q", i, content, e.content)
+		}
+	}
+
+	if _, err := mr.NextPart(); !errors.Is(err, io.EOF) {
+		t.Errorf("Should have no other parts: %v", err)
+	}
+
+	msg.Unsubscribe = ""
+	data, err = s.compose(msg)
+	if err != nil {
+		t.Fatalf("Should be able to compose the message: %s", err)
+	}
+
+	m, err = mail.ReadMessage(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("Should be able to read the message back: %s", err)
+	}
+
+	if m.Header.Get("List-Unsubscribe") != "" || m.Header.Get("List-Unsubscribe-Post") != "" {
+		t.Errorf("Should not set List-Unsubscribe without an unsubscribe link")
+	}
+}
+
+func Test_SMTPSend(t *testing.T) {
+	srv := startSMTPStub(t, true)
+
+	s, err := NewSMTPSender(SMTPConfig{
+		Addr: srv.addr,
+		From: mail.Address{Address: "noreply@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Should be able to construct the sender: %s", err)
+	}
+
+	msg := Message{
+		To:      mail.Address{Name: "Ann", Address: "ann@example.com"},
+		Subject: "Your appointment is booked",
+		Text:    "Hi Ann",
+		HTML:    "<p>Hi Ann</p>",
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.Send(ctx, msg); err != nil {
+		t.Fatalf("Should be able to send the message: %s", err)
+	}
+
+	got := <-srv.received
+	if got.from != "noreply@example.com" {
+		t.Errorf("Should send from the sender address: got %q", got.from)
+	}
+	if got.to != "ann@example.com" {
+		t.Errorf("Should send to the recipient address: got %q", got.to)
+	}
+	if !strings.Contains(got.data, "Subject: Your appointment is booked") {
+		t.Errorf("Should send the composed message: got %q", got.data)
+	}
+}
+
+func Test_SMTPSendTimeout(t *testing.T) {
+	srv := startSMTPStub(t, false)
+
+	s, err := NewSMTPSender(SMTPConfig{
+		Addr: srv.addr,
+		From: mail.Address{Address: "noreply@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Should be able to construct the sender: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = s.Send(ctx, Message{To: mail.Address{Address: "ann@example.com"}})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Should give up once the context is done: got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Should not wait on a stalled server past the deadline: took %s", elapsed)
+	}
+}
+
+// =============================================================================
+
+type smtpReceived struct {
+	from string
+	to   string
+	data string
+}
+
+type smtpStub struct {
+	addr     string
+	received chan smtpReceived
+}
+
+// startSMTPStub starts an SMTP server taking a single message, or stalling without
+// ever greeting the client when responsive is false.
+func startSMTPStub(t *testing.T, responsive bool) smtpStub {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Should be able to listen: %s", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	srv := smtpStub{
+		addr:     ln.Addr().String(),
+		received: make(chan smtpReceived, 1),
+	}
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		if !responsive {
+			io.Copy(io.Discard, conn)
+			return
+		}
+
+		serveSMTP(conn, srv.received)
+	}()
+
+	return srv
+}
+
+func serveSMTP(conn net.Conn, received chan<- smtpReceived) {
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		io.WriteString(conn, line+"\r\n")
+	}
+
+	var got smtpReceived
+	reply("220 localhost ESMTP stub")
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(line)
+
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(cmd, "MAIL FROM:"):
+			got.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(cmd, "RCPT TO:"):
+			got.to = strings.Trim(line[len("RCPT TO:"):], "<>")
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			got.data = data.String()
+			reply("250 OK")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			received <- got
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
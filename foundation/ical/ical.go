@@ -0,0 +1,148 @@
+// Package ical provides support for rendering calendars in the iCalendar format of
+// RFC 5545, the way calendar apps subscribe to them.
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the media type of iCalendar data.
+const ContentType = "text/calendar; charset=utf-8"
+
+// Status is the status of an event.
+type Status string
+
+const (
+	StatusTentative Status = "TENTATIVE"
+	StatusConfirmed Status = "CONFIRMED"
+	StatusCancelled Status = "CANCELLED"
+)
+
+// Event is a VEVENT. UID identifies the event across every version of the calendar,
+// so clients update it in place rather than adding it anew.
+//
+// All-day events take whole dates, Start and End being midnight UTC of their first
+// date and of the date after their last one. RRule is the recurrence rule of a
+// recurring event, such as FREQ=YEARLY, as is; Start and End are its first
+// occurrence.
+type Event struct {
+	UID          string
+	Start        time.Time
+	End          time.Time
+	AllDay       bool
+	Summary      string
+	Description  string
+	Status       Status
+	RRule        string
+	LastModified time.Time
+}
+
+// Calendar is a VCALENDAR of events. It's a web.Encoder, responding with the
+// iCalendar data of the calendar.
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Encode implements the web.Encoder interface.
+func (c Calendar) Encode() ([]byte, string, error) {
+	return c.Marshal(), ContentType, nil
+}
+
+// Marshal renders the calendar, stamping its events with the current time.
+func (c Calendar) Marshal() []byte {
+	var b bytes.Buffer
+	stamp := time.Now()
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+escape(c.ProdID))
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+escape(c.Name))
+	}
+
+	for _, e := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+escape(e.UID))
+		writeLine(&b, "DTSTAMP:"+formatTime(stamp))
+		if e.AllDay {
+			writeLine(&b, "DTSTART;VALUE=DATE:"+formatDate(e.Start))
+			writeLine(&b, "DTEND;VALUE=DATE:"+formatDate(e.End))
+		} else {
+			writeLine(&b, "DTSTART:"+formatTime(e.Start))
+			writeLine(&b, "DTEND:"+formatTime(e.End))
+		}
+		writeLine(&b, "SUMMARY:"+escape(e.Summary))
+		if e.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+escape(e.Description))
+		}
+		if e.Status != "" {
+			writeLine(&b, "STATUS:"+string(e.Status))
+		}
+		if e.RRule != "" {
+			writeLine(&b, "RRULE:"+e.RRule)
+		}
+		if !e.LastModified.IsZero() {
+			writeLine(&b, "LAST-MODIFIED:"+formatTime(e.LastModified))
+		}
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+
+	return b.Bytes()
+}
+
+// formatTime formats the time as a UTC date-time.
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// formatDate formats the date of the time, as it reads in its own location.
+func formatDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+// escape escapes the text value special characters of the RFC.
+func escape(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+
+	return r.Replace(s)
+}
+
+// writeLine writes the content line, folded so no line is longer than 75 octets,
+// continuation lines starting with a space. Lines are never split within a UTF-8
+// sequence.
+func writeLine(b *bytes.Buffer, line string) {
+	const limit = 75
+
+	width := limit
+	for len(line) > width {
+		cut := width
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+
+		// The leading space of continuation lines counts toward their length.
+		width = limit - 1
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,280 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_WriteLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Haircut"},
+		{"exact", "SUMMARY:" + strings.Repeat("a", 75-len("SUMMARY:"))},
+		{"long", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"multibyte", "SUMMARY:" + strings.Repeat("café ", 40)},
+	}
+
+	for _, tt := range tests {
+		var b bytes.Buffer
+		writeLine(&b, tt.line)
+
+		folded := strings.TrimSuffix(b.String(), "\r\n")
+		for i, l := range strings.Split(folded, "\r\n") {
+			if len(l) > 75 {
+				t.Errorf("%s: Should fold lines to 75 octets: line %d is %d", tt.name, i, len(l))
+			}
+			if i > 0 && !strings.HasPrefix(l, " ") {
+				t.Errorf("%s: Should start continuation lines with a space: got %q", tt.name, l)
+			}
+		}
+
+		lines, err := unfold(&b)
+		if err != nil {
+			t.Fatalf("%s: Should be able to unfold the line: %s", tt.name, err)
+		}
+
+		if len(lines) != 1 || lines[0] != tt.line {
+			t.Errorf("%s: Should get back the line unfolded: got %q, exp %q", tt.name, lines, tt.line)
+		}
+	}
+}
+
+func Test_Escape(t *testing.T) {
+	tests := []struct {
+		text    string
+		escaped string
+	}{
+		{"Haircut", "Haircut"},
+		{"Cut, wash; dry", `Cut\, wash\; dry`},
+		{`C:\salon`, `C:\\salon`},
+		{"First line\nsecond line", `First line\nsecond line`},
+		{"First line\r\nsecond line", `First line\nsecond line`},
+	}
+
+	for _, tt := range tests {
+		if got := escape(tt.text); got != tt.escaped {
+			t.Errorf("Should escape %q: got %q, exp %q", tt.text, got, tt.escaped)
+		}
+
+		if got, exp := unescape(tt.escaped), strings.ReplaceAll(tt.text, "\r\n", "\n"); got != exp {
+			t.Errorf("Should unescape %q: got %q, exp %q", tt.escaped, got, exp)
+		}
+	}
+}
+
+func Test_Parse(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("Should be able to load location: %s", err)
+	}
+
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("Should be able to load location: %s", err)
+	}
+
+	calendar := func(props ...string) string {
+		lines := []string{"BEGIN:VCALENDAR", "PRODID:-//Example//EN", "BEGIN:VEVENT", "UID:1@example.com"}
+		lines = append(lines, props...)
+		lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+		return strings.Join(lines, "\r\n")
+	}
+
+	tests := []struct {
+		name string
+		ics  string
+		exp  Event
+	}{
+		{
+			name: "allday",
+			ics:  calendar("DTSTART;VALUE=DATE:20241225", "DTEND;VALUE=DATE:20241227", "SUMMARY:Christmas"),
+			exp: Event{
+				Start:   time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC),
+				End:     time.Date(2024, time.December, 27, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+				Summary: "Christmas",
+			},
+		},
+		{
+			name: "allday without end",
+			ics:  calendar("DTSTART;VALUE=DATE:20241225"),
+			exp: Event{
+				Start:  time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC),
+				End:    time.Date(2024, time.December, 26, 0, 0, 0, 0, time.UTC),
+				AllDay: true,
+			},
+		},
+		{
+			name: "utc",
+			ics:  calendar("DTSTART:20240715T073000Z", "DTEND:20240715T083000Z"),
+			exp: Event{
+				Start: time.Date(2024, time.July, 15, 7, 30, 0, 0, time.UTC),
+				End:   time.Date(2024, time.July, 15, 8, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "floating",
+			ics:  calendar("DTSTART:20240715T093000", "DTEND:20240715T103000"),
+			exp: Event{
+				Start: time.Date(2024, time.July, 15, 9, 30, 0, 0, loc),
+				End:   time.Date(2024, time.July, 15, 10, 30, 0, 0, loc),
+			},
+		},
+		{
+			name: "tzid",
+			ics:  calendar(`DTSTART;TZID="America/New_York":20240715T093000`, "DTEND;TZID=America/New_York:20240715T103000"),
+			exp: Event{
+				Start: time.Date(2024, time.July, 15, 9, 30, 0, 0, ny),
+				End:   time.Date(2024, time.July, 15, 10, 30, 0, 0, ny),
+			},
+		},
+		{
+			name: "duration",
+			ics:  calendar("DTSTART:20240715T073000Z", "DURATION:P1DT1H30M"),
+			exp: Event{
+				Start: time.Date(2024, time.July, 15, 7, 30, 0, 0, time.UTC),
+				End:   time.Date(2024, time.July, 16, 9, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name: "duration in weeks",
+			ics:  calendar("DTSTART;VALUE=DATE:20240801", "DURATION:P2W"),
+			exp: Event{
+				Start:  time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC),
+				End:    time.Date(2024, time.August, 15, 0, 0, 0, 0, time.UTC),
+				AllDay: true,
+			},
+		},
+		{
+			name: "escaped and folded",
+			ics:  calendar("DTSTART;VALUE=DATE:20241231", `SUMMARY:Closed\, inventory\; back`, "  soon", `DESCRIPTION:Line one\nline two`),
+			exp: Event{
+				Start:       time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC),
+				End:         time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+				AllDay:      true,
+				Summary:     "Closed, inventory; back soon",
+				Description: "Line one\nline two",
+			},
+		},
+		{
+			name: "cancelled",
+			ics:  calendar("DTSTART;VALUE=DATE:20241225", "STATUS:cancelled"),
+			exp: Event{
+				Start:  time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC),
+				End:    time.Date(2024, time.December, 26, 0, 0, 0, 0, time.UTC),
+				AllDay: true,
+				Status: StatusCancelled,
+			},
+		},
+		{
+			name: "recurring",
+			ics:  calendar("DTSTART;VALUE=DATE:20241225", "RRULE:FREQ=YEARLY"),
+			exp: Event{
+				Start:  time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC),
+				End:    time.Date(2024, time.December, 26, 0, 0, 0, 0, time.UTC),
+				AllDay: true,
+				RRule:  "FREQ=YEARLY",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		cal, err := Parse(strings.NewReader(tt.ics), loc)
+		if err != nil {
+			t.Fatalf("%s: Should be able to parse the calendar: %s", tt.name, err)
+		}
+
+		if cal.ProdID != "-//Example//EN" {
+			t.Errorf("%s: Should get the product id: got %q", tt.name, cal.ProdID)
+		}
+
+		if len(cal.Events) != 1 {
+			t.Fatalf("%s: Should get the event: got %d events", tt.name, len(cal.Events))
+		}
+
+		got := cal.Events[0]
+		tt.exp.UID = "1@example.com"
+
+		if !got.Start.Equal(tt.exp.Start) || !got.End.Equal(tt.exp.End) {
+			t.Errorf("%s: Should get the time of the event: got %s - %s, exp %s - %s", tt.name, got.Start, got.End, tt.exp.Start, tt.exp.End)
+		}
+
+		got.Start, got.End, tt.exp.Start, tt.exp.End = time.Time{}, time.Time{}, time.Time{}, time.Time{}
+		if got != tt.exp {
+			t.Errorf("%s: Should get the event: got %+v, exp %+v", tt.name, got, tt.exp)
+		}
+	}
+}
+
+func Test_ParseMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		ics  string
+	}{
+		{"continuation first", " BEGIN:VCALENDAR"},
+		{"no colon", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID\r\nEND:VEVENT\r\nEND:VCALENDAR"},
+		{"unterminated", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nDTSTART:20240715T073000Z"},
+		{"mismatched end", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR"},
+		{"missing uid", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nDTSTART:20240715T073000Z\r\nEND:VEVENT\r\nEND:VCALENDAR"},
+		{"missing start", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nEND:VEVENT\r\nEND:VCALENDAR"},
+		{"invalid date", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nDTSTART;VALUE=DATE:20241332\r\nEND:VEVENT\r\nEND:VCALENDAR"},
+		{"invalid date-time", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nDTSTART:20240715T7300Z\r\nEND:VEVENT\r\nEND:VCALENDAR"},
+		{"unknown zone", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nDTSTART;TZID=Mars/Olympus:20240715T073000\r\nEND:VEVENT\r\nEND:VCALENDAR"},
+		{"invalid duration", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nDTSTART:20240715T073000Z\r\nDURATION:PT1X\r\nEND:VEVENT\r\nEND:VCALENDAR"},
+		{"ends before start", "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:1\r\nDTSTART:20240715T073000Z\r\nDTEND:20240715T063000Z\r\nEND:VEVENT\r\nEND:VCALENDAR"},
+	}
+
+	for _, tt := range tests {
+		if _, err := Parse(strings.NewReader(tt.ics), time.UTC); err == nil {
+			t.Errorf("%s: Should fail to parse the calendar", tt.name)
+		}
+	}
+}
+
+func Test_MarshalParse(t *testing.T) {
+	cal := Calendar{
+		ProdID: "-//Reservations//EN",
+		Name:   "Appointments, all of them",
+		Events: []Event{
+			{
+				UID:          "apt-1@example.com",
+				Start:        time.Date(2024, time.July, 15, 7, 30, 0, 0, time.UTC),
+				End:          time.Date(2024, time.July, 15, 8, 30, 0, 0, time.UTC),
+				Summary:      "Haircut; with " + strings.Repeat("extras, ", 10),
+				Description:  "Bring the voucher\nthanks",
+				Status:       StatusConfirmed,
+				LastModified: time.Date(2024, time.July, 1, 12, 0, 0, 0, time.UTC),
+			},
+			{
+				UID:     "closed@example.com",
+				Start:   time.Date(2024, time.December, 25, 0, 0, 0, 0, time.UTC),
+				End:     time.Date(2024, time.December, 26, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+				Summary: "Closed",
+			},
+		},
+	}
+
+	got, err := Parse(bytes.NewReader(cal.Marshal()), time.UTC)
+	if err != nil {
+		t.Fatalf("Should be able to parse the marshaled calendar: %s", err)
+	}
+
+	if got.ProdID != cal.ProdID || got.Name != cal.Name {
+		t.Errorf("Should get back the calendar: got %q %q", got.ProdID, got.Name)
+	}
+
+	if len(got.Events) != len(cal.Events) {
+		t.Fatalf("Should get back the events: got %d, exp %d", len(got.Events), len(cal.Events))
+	}
+
+	for i, e := range cal.Events {
+		if g := got.Events[i]; g != e {
+			t.Errorf("Should get back event %d: got %+v, exp %+v", i, g, e)
+		}
+	}
+}
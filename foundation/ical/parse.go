@@ -0,0 +1,328 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxLine limits the length of unfolded content lines.
+const maxLine = 64 * 1024
+
+// Parse reads the events of a calendar. Floating times, bound to no time zone, are
+// taken in loc. Events lacking DTEND last a day when all-day, their DURATION
+// otherwise. Recurrence rules aren't expanded: recurring events keep their rule in
+// RRule, for callers to expand it or turn the event down.
+func Parse(r io.Reader, loc *time.Location) (Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	var cal Calendar
+	var components []string
+	var event *Event
+	var duration time.Duration
+	var hasDuration bool
+
+	for i, line := range lines {
+		name, params, value, err := splitLine(line)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(value))
+			if len(components) == 2 && components[1] == "VEVENT" {
+				event = &Event{}
+				duration, hasDuration = 0, false
+			}
+			continue
+
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(value) {
+				return Calendar{}, fmt.Errorf("line %d: unexpected END:%s", i+1, value)
+			}
+
+			if len(components) == 2 && event != nil {
+				if err := completeEvent(event, duration, hasDuration); err != nil {
+					return Calendar{}, fmt.Errorf("line %d: event %q: %w", i+1, event.UID, err)
+				}
+				cal.Events = append(cal.Events, *event)
+				event = nil
+			}
+			components = components[:len(components)-1]
+			continue
+		}
+
+		switch {
+		case len(components) == 1 && components[0] == "VCALENDAR":
+			switch name {
+			case "PRODID":
+				cal.ProdID = unescape(value)
+			case "X-WR-CALNAME":
+				cal.Name = unescape(value)
+			}
+
+		case len(components) == 2 && event != nil:
+			if err := setProperty(event, name, params, value, loc, &duration, &hasDuration); err != nil {
+				return Calendar{}, fmt.Errorf("line %d: %s: %w", i+1, name, err)
+			}
+		}
+	}
+
+	if len(components) != 0 {
+		return Calendar{}, fmt.Errorf("unterminated %s", components[len(components)-1])
+	}
+
+	return cal, nil
+}
+
+// setProperty sets the property of the event. Unknown properties are ignored.
+func setProperty(e *Event, name string, params map[string]string, value string, loc *time.Location, duration *time.Duration, hasDuration *bool) error {
+	var err error
+
+	switch name {
+	case "UID":
+		e.UID = value
+	case "SUMMARY":
+		e.Summary = unescape(value)
+	case "DESCRIPTION":
+		e.Description = unescape(value)
+	case "STATUS":
+		e.Status = Status(strings.ToUpper(value))
+	case "RRULE":
+		e.RRule = value
+	case "DTSTART":
+		e.Start, e.AllDay, err = parseTime(params, value, loc)
+	case "DTEND":
+		e.End, _, err = parseTime(params, value, loc)
+	case "DURATION":
+		*duration, err = parseDuration(value)
+		*hasDuration = true
+	case "LAST-MODIFIED":
+		e.LastModified, _, err = parseTime(params, value, loc)
+	}
+
+	return err
+}
+
+// completeEvent checks the event is whole, working out its end when missing.
+func completeEvent(e *Event, duration time.Duration, hasDuration bool) error {
+	switch {
+	case e.UID == "":
+		return errors.New("missing UID")
+	case e.Start.IsZero():
+		return errors.New("missing DTSTART")
+	}
+
+	if e.End.IsZero() {
+		switch {
+		case hasDuration:
+			e.End = e.Start.Add(duration)
+		case e.AllDay:
+			e.End = e.Start.AddDate(0, 0, 1)
+		default:
+			e.End = e.Start
+		}
+	}
+
+	if e.End.Before(e.Start) {
+		return errors.New("ends before it starts")
+	}
+
+	return nil
+}
+
+// unfold reads the content lines, joining folded ones back together.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 4096), maxLine)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+
+		if len(line) > 0 && (line[0] == ' ' || line[0] == '\t') {
+			if len(lines) == 0 {
+				return nil, errors.New("calendar starts with a continuation line")
+			}
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading calendar: %w", err)
+	}
+
+	return lines, nil
+}
+
+// splitLine splits the content line into its upper cased name, its parameters and
+// its value. Colons and semicolons within quoted parameter values are kept.
+func splitLine(line string) (string, map[string]string, string, error) {
+	quoted := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			quoted = !quoted
+		}
+		if r == ':' && !quoted {
+			colon = i
+			break
+		}
+	}
+
+	if colon < 0 {
+		return "", nil, "", fmt.Errorf("malformed line %q", line)
+	}
+
+	head, value := line[:colon], line[colon+1:]
+
+	parts := splitParams(head)
+	params := make(map[string]string, len(parts)-1)
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, value, nil
+}
+
+// splitParams splits the name and parameters of a content line at semicolons
+// outside quotes.
+func splitParams(head string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i, r := range head {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ';' && !quoted:
+			parts = append(parts, head[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, head[start:])
+}
+
+// parseTime parses a date or date-time value, reporting whether it's a date. Dates
+// are taken at midnight UTC, date-times in their TZID, in UTC when ending in Z, or
+// else in loc.
+func parseTime(params map[string]string, value string, loc *time.Location) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return t, true, nil
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+		}
+		return t, false, nil
+	}
+
+	if tzid := params["TZID"]; tzid != "" {
+		var err error
+		loc, err = time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+
+	return t, false, nil
+}
+
+// parseDuration parses a duration value, such as P1D or PT1H30M. Days and weeks
+// are taken as exact multiples of 24 hours.
+func parseDuration(value string) (time.Duration, error) {
+	s := value
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	s = s[1:]
+
+	units := map[byte]time.Duration{
+		'W': 7 * 24 * time.Hour,
+		'D': 24 * time.Hour,
+	}
+	timeUnits := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+	}
+
+	var d time.Duration
+	for len(s) > 0 {
+		if s[0] == 'T' {
+			units = timeUnits
+			s = s[1:]
+			continue
+		}
+
+		end := 0
+		for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+			end++
+		}
+		if end == 0 || end == len(s) {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		n, err := strconv.Atoi(s[:end])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		unit, ok := units[s[end]]
+		if !ok {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		d += time.Duration(n) * unit
+		s = s[end+1:]
+	}
+
+	return sign * d, nil
+}
+
+// unescape reverses escape.
+func unescape(s string) string {
+	r := strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	)
+
+	return r.Replace(s)
+}
@@ -0,0 +1,76 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPConfig configures a provider taking messages as form posts to its messages
+// resource of an account, authenticated with the account id and token, the way
+// Twilio does.
+type HTTPConfig struct {
+	Client    *http.Client
+	BaseURL   string
+	AccountID string
+	AuthToken string
+	From      string
+}
+
+// HTTPSender sends messages through an HTTP provider.
+type HTTPSender struct {
+	client    *http.Client
+	endpoint  string
+	accountID string
+	authToken string
+	from      string
+}
+
+func NewHTTPSender(cfg HTTPConfig) *HTTPSender {
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", strings.TrimRight(cfg.BaseURL, "/"), url.PathEscape(cfg.AccountID))
+
+	return &HTTPSender{
+		client:    client,
+		endpoint:  endpoint,
+		accountID: cfg.AccountID,
+		authToken: cfg.AuthToken,
+		from:      cfg.From,
+	}
+}
+
+func (s *HTTPSender) Send(ctx context.Context, msg Message) error {
+	form := url.Values{
+		"To":   {msg.To},
+		"From": {s.from},
+		"Body": {msg.Body},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.SetBasicAuth(s.accountID, s.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sending message: status[%d]: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
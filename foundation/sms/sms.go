@@ -0,0 +1,47 @@
+// Package sms provides support for sending text messages through a provider.
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Message is a text message to a phone number in E.164 format.
+type Message struct {
+	To   string
+	Body string
+}
+
+// Sender sends text messages.
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// =============================================================================
+
+// WriterSender writes messages to a writer instead of sending them, such as to
+// stdout or a file while developing.
+type WriterSender struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSender(w io.Writer) *WriterSender {
+	return &WriterSender{
+		w: w,
+	}
+}
+
+func (s *WriterSender) Send(ctx context.Context, msg Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := fmt.Fprintf(s.w, "%s SMS to[%s]: %s\n", time.Now().UTC().Format(time.RFC3339), msg.To, msg.Body); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+
+	return nil
+}
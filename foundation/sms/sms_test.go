@@ -0,0 +1,112 @@
+package sms_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ameghdadian/service/foundation/sms"
+)
+
+func Test_HTTPSender(t *testing.T) {
+	var got struct {
+		path     string
+		user     string
+		password string
+		to       string
+		from     string
+		body     string
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.path = r.URL.Path
+		got.user, got.password, _ = r.BasicAuth()
+
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		got.to = r.PostForm.Get("To")
+		got.from = r.PostForm.Get("From")
+		got.body = r.PostForm.Get("Body")
+
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	s := sms.NewHTTPSender(sms.HTTPConfig{
+		Client:    srv.Client(),
+		BaseURL:   srv.URL + "/",
+		AccountID: "AC123",
+		AuthToken: "secret",
+		From:      "+15550000000",
+	})
+
+	msg := sms.Message{
+		To:   "+4915112345678",
+		Body: "Hi Ann, this is a reminder & more",
+	}
+
+	if err := s.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Should be able to send the message: %s", err)
+	}
+
+	if exp := "/2010-04-01/Accounts/AC123/Messages.json"; got.path != exp {
+		t.Errorf("Should post to the messages resource of the account: got %s, exp %s", got.path, exp)
+	}
+
+	if got.user != "AC123" || got.password != "secret" {
+		t.Errorf("Should authenticate with the account id and token: got %s:%s", got.user, got.password)
+	}
+
+	if got.to != msg.To {
+		t.Errorf("Should send To: got %q, exp %q", got.to, msg.To)
+	}
+
+	if got.from != "+15550000000" {
+		t.Errorf("Should send From: got %q", got.from)
+	}
+
+	if got.body != msg.Body {
+		t.Errorf("Should send Body: got %q, exp %q", got.body, msg.Body)
+	}
+}
+
+func Test_HTTPSenderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"invalid To number"}`, http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	s := sms.NewHTTPSender(sms.HTTPConfig{
+		Client:    srv.Client(),
+		BaseURL:   srv.URL,
+		AccountID: "AC123",
+		AuthToken: "secret",
+	})
+
+	err := s.Send(context.Background(), sms.Message{To: "nope", Body: "Hi"})
+	if err == nil {
+		t.Fatal("Should fail when the provider rejects the message")
+	}
+
+	if !strings.Contains(err.Error(), "400") || !strings.Contains(err.Error(), "invalid To number") {
+		t.Errorf("Should tell the status and the reason of the provider: got %s", err)
+	}
+}
+
+func Test_WriterSender(t *testing.T) {
+	var buf bytes.Buffer
+	s := sms.NewWriterSender(&buf)
+
+	if err := s.Send(context.Background(), sms.Message{To: "+4915112345678", Body: "Hi Ann"}); err != nil {
+		t.Fatalf("Should be able to write the message: %s", err)
+	}
+
+	if got := buf.String(); !strings.Contains(got, "SMS to[+4915112345678]: Hi Ann\n") {
+		t.Errorf("Should write the message: got %q", got)
+	}
+}